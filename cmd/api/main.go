@@ -1,13 +1,23 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
 	"github.com/cropflow/api/config"
-	"github.com/cropflow/api/internal/adapters/database/mysql"
+	"github.com/cropflow/api/internal/adapters/database"
+	"github.com/cropflow/api/internal/adapters/database/gormrepo"
 	"github.com/cropflow/api/internal/adapters/http/handlers"
 	"github.com/cropflow/api/internal/adapters/http/routes"
+	"github.com/cropflow/api/internal/domain/lockout"
+	"github.com/cropflow/api/internal/domain/mfa"
+	"github.com/cropflow/api/internal/domain/nutrition"
+	"github.com/cropflow/api/internal/domain/oidc"
+	"github.com/cropflow/api/internal/domain/passwordreset"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/policy"
+	"github.com/cropflow/api/internal/infrastructure/notification"
 	"github.com/cropflow/api/internal/infrastructure/security"
 	"github.com/cropflow/api/internal/usecases"
 	"github.com/gin-gonic/gin"
@@ -24,43 +34,193 @@ func main() {
 	cfg := config.NewConfig()
 
 	// Initialize database
-	db, err := mysql.NewMySQLConnection(cfg)
+	db, migrator, err := database.Open(cfg)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
 	// Run migrations
-	if err := mysql.RunMigrations(db); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	if cfg.DBMigrateOnStart {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to run migrations: %v", err)
+		}
+		for _, m := range applied {
+			log.Printf("Applied migration %04d_%s", m.Version, m.Name)
+		}
 	}
 
 	// Initialize repositories
-	farmRepo := mysql.NewFarmRepository(db)
-	cropRepo := mysql.NewCropRepository(db)
-	fertilizerRepo := mysql.NewFertilizerRepository(db)
-	personRepo := mysql.NewPersonRepository(db)
+	farmRepo := gormrepo.NewFarmRepository(db)
+	cropRepo := gormrepo.NewCropRepository(db)
+	fertilizerRepo := gormrepo.NewFertilizerRepository(db)
+	personRepo := gormrepo.NewPersonRepository(db)
+	sessionRepo := gormrepo.NewSessionRepository(db)
+	mfaRepo := gormrepo.NewMFARepository(db)
+	lockoutRepo := gormrepo.NewLockoutRepository(db)
+	passwordResetRepo := gormrepo.NewPasswordResetRepository(db)
+	apiKeyRepo := gormrepo.NewAPIKeyRepository(db)
+	oidcRepo := gormrepo.NewOIDCRepository(db)
+	auditRepo := gormrepo.NewAuditRepository(db)
+	txManager := gormrepo.NewTransactionManager(db)
 
 	// Initialize security services
-	jwtService := security.NewJWTService(cfg.JWTSecret, cfg.JWTIssuer)
-	passwordService := security.NewPasswordService()
+	signingKeys, err := security.LoadKeySet(cfg.JWTKeyFiles, cfg.JWTAlgorithm)
+	if err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+	log.Printf("Signing access tokens with %s key %s", signingKeys.Active().Algorithm(), signingKeys.Active().ID())
+
+	// Generated keys only live in memory, so they are rotated on a schedule;
+	// keys from PEM files are rotated by deploying a new first file
+	if len(cfg.JWTKeyFiles) == 0 {
+		rotator, err := security.NewKeyRotator(signingKeys, cfg.JWTAlgorithm, cfg.JWTKeyRotationInterval, cfg.JWTKeyOverlap, cfg.AccessTokenTTL)
+		if err != nil {
+			log.Fatalf("Invalid signing key rotation: %v", err)
+		}
+		rotator.Start(context.Background())
+	}
+	jwtService := security.NewJWTService(signingKeys, cfg.JWTIssuer, cfg.AccessTokenTTL)
+
+	rawTargets, err := cfg.LoadNutrientTargets()
+	if err != nil {
+		log.Fatalf("Failed to load nutrient targets: %v", err)
+	}
+	nutrientTargets, err := nutrition.NewTargets(rawTargets)
+	if err != nil {
+		log.Fatalf("Invalid nutrient targets: %v", err)
+	}
+
+	rawPolicy, err := cfg.LoadPermissionPolicy()
+	if err != nil {
+		log.Fatalf("Failed to load permission policy: %v", err)
+	}
+	permissions, err := policy.New(rawPolicy)
+	if err != nil {
+		log.Fatalf("Invalid permission policy: %v", err)
+	}
+
+	twoFactor, err := mfa.NewSettings(cfg.TOTPIssuer, cfg.TOTPRequiredRoles)
+	if err != nil {
+		log.Fatalf("Invalid two-factor settings: %v", err)
+	}
+
+	usernameLockout, err := lockout.NewPolicy(cfg.LoginMaxFailures, cfg.LoginLockoutBase, cfg.LoginLockoutMax, cfg.LoginFailureWindow)
+	if err != nil {
+		log.Fatalf("Invalid login lockout settings: %v", err)
+	}
+	ipLockout, err := lockout.NewPolicy(cfg.LoginMaxFailuresPerIP, cfg.LoginLockoutBase, cfg.LoginLockoutMax, cfg.LoginFailureWindow)
+	if err != nil {
+		log.Fatalf("Invalid login lockout settings: %v", err)
+	}
+	lockouts := lockout.Settings{Username: usernameLockout, IP: ipLockout}
+
+	if cfg.PasswordArgon2Parallelism > 255 {
+		log.Fatalf("Invalid argon2id parallelism %d: must be at most 255", cfg.PasswordArgon2Parallelism)
+	}
+	hashing, err := person.NewPasswordHashing(cfg.PasswordHashAlgorithm, cfg.PasswordBcryptCost, person.Argon2Params{
+		Memory:      uint32(cfg.PasswordArgon2Memory),
+		Iterations:  uint32(cfg.PasswordArgon2Iterations),
+		Parallelism: uint8(cfg.PasswordArgon2Parallelism),
+	})
+	if err != nil {
+		log.Fatalf("Invalid password hashing settings: %v", err)
+	}
+	denylist, err := cfg.LoadPasswordDenylist()
+	if err != nil {
+		log.Fatalf("Failed to load password denylist: %v", err)
+	}
+	passwords, err := person.NewPasswordPolicy(cfg.PasswordMinLength, cfg.PasswordMaxLength, cfg.PasswordRequiredClasses, denylist, hashing)
+	if err != nil {
+		log.Fatalf("Invalid password policy: %v", err)
+	}
+
+	var resetNotifier passwordreset.Notifier
+	switch cfg.PasswordResetNotifier {
+	case "log":
+		resetNotifier = notification.NewLogNotifier()
+	case "file":
+		resetNotifier = notification.NewFileNotifier(cfg.PasswordResetFile)
+	default:
+		log.Fatalf("Invalid password reset notifier %q: must be log or file", cfg.PasswordResetNotifier)
+	}
+
+	// OIDC logins are only enabled when an identity provider is configured
+	var oidcProvider oidc.Provider
+	if cfg.OIDCIssuerURL != "" {
+		if cfg.OIDCClientID == "" {
+			log.Fatalf("OIDC_CLIENT_ID is required when OIDC_ISSUER_URL is set")
+		}
+		oidcProvider = security.NewOIDCProvider(security.OIDCConfig{
+			IssuerURL:     cfg.OIDCIssuerURL,
+			ClientID:      cfg.OIDCClientID,
+			ClientSecret:  cfg.OIDCClientSecret,
+			RedirectURL:   cfg.OIDCRedirectURL,
+			Scopes:        cfg.OIDCScopes,
+			UsernameClaim: cfg.OIDCUsernameClaim,
+			GroupsClaim:   cfg.OIDCGroupsClaim,
+		}, nil)
+	}
+	oidcGroups, err := cfg.OIDCRoleGroups()
+	if err != nil {
+		log.Fatalf("Invalid OIDC settings: %v", err)
+	}
+	defaultRole := cfg.OIDCDefaultRole
+	if defaultRole == "none" {
+		defaultRole = ""
+	}
+	oidcRoles, err := oidc.NewRoleMapping(oidcGroups, defaultRole)
+	if err != nil {
+		log.Fatalf("Invalid OIDC settings: %v", err)
+	}
 
 	// Initialize use cases
-	farmUseCase := usecases.NewFarmUseCase(farmRepo)
-	cropUseCase := usecases.NewCropUseCase(cropRepo, farmRepo, fertilizerRepo)
-	fertilizerUseCase := usecases.NewFertilizerUseCase(fertilizerRepo)
-	personUseCase := usecases.NewPersonUseCase(personRepo, passwordService)
-	authUseCase := usecases.NewAuthUseCase(personRepo, passwordService, jwtService)
+	farmUseCase := usecases.NewFarmUseCase(farmRepo, personRepo, txManager)
+	cropUseCase := usecases.NewCropUseCase(cropRepo, farmRepo, fertilizerRepo, personRepo, txManager)
+	fertilizerUseCase := usecases.NewFertilizerUseCase(fertilizerRepo, txManager)
+	nutrientBalanceUseCase := usecases.NewNutrientBalanceUseCase(cropRepo, farmRepo, fertilizerRepo, nutrientTargets)
+	personUseCase := usecases.NewPersonUseCase(personRepo, passwords, txManager)
+	authUseCase := usecases.NewAuthUseCase(personRepo, sessionRepo, mfaRepo, lockoutRepo, jwtService, cfg.RefreshTokenTTL, twoFactor, lockouts, passwords, txManager)
+	twoFactorUseCase := usecases.NewTwoFactorUseCase(personRepo, mfaRepo, twoFactor, txManager)
+	passwordUseCase := usecases.NewPasswordUseCase(personRepo, sessionRepo, passwordResetRepo, resetNotifier, cfg.PasswordResetTTL, passwords, txManager)
+	apiKeyUseCase := usecases.NewAPIKeyUseCase(apiKeyRepo, personRepo, farmRepo, txManager)
+	authenticator := usecases.NewCredentialAuthenticator(authUseCase, apiKeyUseCase)
+	oidcUseCase := usecases.NewOIDCUseCase(oidcProvider, oidcRepo, personRepo, authUseCase, oidcRoles, passwords, txManager)
+	auditUseCase := usecases.NewAuditUseCase(auditRepo)
+
+	if cfg.BootstrapAdmin != "" {
+		promoted, err := personUseCase.BootstrapAdmin(context.Background(), cfg.BootstrapAdmin)
+		if err != nil {
+			log.Fatalf("Failed to bootstrap admin %s: %v", cfg.BootstrapAdmin, err)
+		}
+		if promoted {
+			log.Printf("Promoted %s to %s", cfg.BootstrapAdmin, person.RoleAdmin)
+		}
+	}
 
 	// Initialize handlers
 	farmHandler := handlers.NewFarmHandler(farmUseCase)
+	memberHandler := handlers.NewMemberHandler(farmUseCase)
 	cropHandler := handlers.NewCropHandler(cropUseCase)
+	harvestHandler := handlers.NewHarvestHandler(cropUseCase)
+	applicationHandler := handlers.NewApplicationHandler(cropUseCase)
+	nutrientBalanceHandler := handlers.NewNutrientBalanceHandler(nutrientBalanceUseCase)
 	fertilizerHandler := handlers.NewFertilizerHandler(fertilizerUseCase)
 	personHandler := handlers.NewPersonHandler(personUseCase)
 	authHandler := handlers.NewAuthHandler(authUseCase)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorUseCase)
+	passwordHandler := handlers.NewPasswordHandler(passwordUseCase)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyUseCase)
+	oidcHandler := handlers.NewOIDCHandler(oidcUseCase)
+	jwksHandler := handlers.NewJWKSHandler(jwtService)
+	auditHandler := handlers.NewAuditHandler(auditUseCase)
 
 	// Setup router
 	router := gin.Default()
-	routes.SetupRoutes(router, farmHandler, cropHandler, fertilizerHandler, personHandler, authHandler, jwtService)
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
+	routes.SetupRoutes(router, farmHandler, memberHandler, cropHandler, harvestHandler, applicationHandler, nutrientBalanceHandler, fertilizerHandler, personHandler, authHandler, twoFactorHandler, passwordHandler, apiKeyHandler, oidcHandler, jwksHandler, auditHandler, authenticator, permissions)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -0,0 +1,146 @@
+// Command cropflow runs maintenance tasks against the CropFlow database:
+//
+//	cropflow migrate up              apply every pending migration
+//	cropflow migrate down [n]        revert the latest n migrations (default 1)
+//	cropflow migrate status          list the migrations and when they were applied
+//	cropflow migrate create <name>   add empty up and down files for a new migration
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cropflow/api/config"
+	"github.com/cropflow/api/internal/adapters/database"
+	"github.com/cropflow/api/internal/infrastructure/migrations"
+	"github.com/joho/godotenv"
+)
+
+// defaultMigrationsDir is where create writes new files, relative to the repository root
+const defaultMigrationsDir = "internal/infrastructure/migrations"
+
+const usage = `usage:
+  cropflow migrate up
+  cropflow migrate down [n]
+  cropflow migrate status
+  cropflow migrate create [-dir path] <name>`
+
+var errUsage = errors.New(usage)
+
+func main() {
+	// The database settings may come from the environment alone
+	_ = godotenv.Load()
+
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) < 2 || args[0] != "migrate" {
+		return errUsage
+	}
+
+	command, args := args[1], args[2:]
+	if command == "create" {
+		return create(args)
+	}
+
+	migrator, err := newMigrator()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		if len(args) != 0 {
+			return errUsage
+		}
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) == 1 {
+			if steps, err = strconv.Atoi(args[0]); err != nil || steps <= 0 {
+				return errUsage
+			}
+		} else if len(args) > 1 {
+			return errUsage
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Println("no applied migrations")
+		}
+		return err
+	case "status":
+		if len(args) != 0 {
+			return errUsage
+		}
+		return status(ctx, migrator)
+	default:
+		return errUsage
+	}
+}
+
+func newMigrator() (*migrations.Migrator, error) {
+	_, migrator, err := database.Open(config.NewConfig())
+	return migrator, err
+}
+
+func status(ctx context.Context, migrator *migrations.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, s := range statuses {
+		applied := "pending"
+		if s.AppliedAt != nil {
+			applied = s.AppliedAt.Local().Format(time.RFC3339)
+		}
+		switch {
+		case s.Modified:
+			applied += " (modified since applied)"
+		case s.Missing:
+			applied += " (not in this build)"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+	}
+	return w.Flush()
+}
+
+func create(args []string) error {
+	flags := flag.NewFlagSet("create", flag.ContinueOnError)
+	dir := flags.String("dir", defaultMigrationsDir, "directory with one subdirectory of migrations per dialect")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+
+	paths, err := migrations.Create(*dir, flags.Arg(0))
+	for _, path := range paths {
+		fmt.Println("created", path)
+	}
+	return err
+}
@@ -1,29 +1,258 @@
 package config
 
-import "os"
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Config holds the application configuration
 type Config struct {
+	// DBDriver selects the database, "mysql" or "postgres" reached through the
+	// DB* settings, or "sqlite" stored in the file at DBPath. An empty DBPort
+	// is the standard port of the driver.
+	DBDriver string
+	DBPath   string
+
 	DBHost     string
 	DBPort     string
 	DBUser     string
 	DBPassword string
 	DBName     string
-	JWTSecret  string
 	JWTIssuer  string
+
+	// DBMigrateOnStart applies pending migrations when the API starts; when
+	// false they are applied with "cropflow migrate up" before deploying
+	DBMigrateOnStart bool
+
+	// JWTAlgorithm is the algorithm of generated signing keys, RS256 or EdDSA.
+	// JWTKeyFiles lists PEM private keys; the first one signs and the others
+	// are only accepted for verification. Without files a key is generated at
+	// startup and rotated every JWTKeyRotationInterval, keeping the previous
+	// key valid for verification during JWTKeyOverlap.
+	JWTAlgorithm           string
+	JWTKeyFiles            []string
+	JWTKeyRotationInterval time.Duration
+	JWTKeyOverlap          time.Duration
+
+	// AccessTokenTTL is how long a JWT access token is valid; RefreshTokenTTL
+	// is how long a refresh token can be exchanged for a new pair
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// TOTPIssuer names the service in authenticator apps; persons with one of
+	// TOTPRequiredRoles must use two-factor authentication to log in
+	TOTPIssuer        string
+	TOTPRequiredRoles []string
+
+	// LoginMaxFailures and LoginMaxFailuresPerIP are how many consecutive failed
+	// logins lock a username or a client IP address. The lockout starts at
+	// LoginLockoutBase, doubles with every further failure up to LoginLockoutMax,
+	// and failures are forgotten after LoginFailureWindow without new ones.
+	LoginMaxFailures      int
+	LoginMaxFailuresPerIP int
+	LoginLockoutBase      time.Duration
+	LoginLockoutMax       time.Duration
+	LoginFailureWindow    time.Duration
+
+	// TrustedProxies lists the proxies allowed to set X-Forwarded-For; the
+	// client IP used for lockouts is only read from the header behind them
+	TrustedProxies []string
+
+	// PasswordMinLength and PasswordMaxLength bound new passwords, the maximum
+	// in bytes since bcrypt ignores anything beyond 72. PasswordRequiredClasses
+	// lists character classes new passwords must contain (lower, upper, digit,
+	// symbol) and PasswordDenylistFile points to a file of rejected passwords,
+	// one per line.
+	PasswordMinLength       int
+	PasswordMaxLength       int
+	PasswordRequiredClasses []string
+	PasswordDenylistFile    string
+
+	// PasswordHashAlgorithm hashes new passwords, bcrypt or argon2id, with
+	// PasswordBcryptCost or the argon2id memory (KiB), iterations and parallelism.
+	// Hashes made with other settings are rehashed on the next login.
+	PasswordHashAlgorithm     string
+	PasswordBcryptCost        int
+	PasswordArgon2Memory      int
+	PasswordArgon2Iterations  int
+	PasswordArgon2Parallelism int
+
+	// PasswordResetTTL is how long an administrator issued reset token is
+	// valid. PasswordResetNotifier delivers the tokens: "log" writes them to the
+	// application log and "file" appends them to PasswordResetFile.
+	PasswordResetTTL      time.Duration
+	PasswordResetNotifier string
+	PasswordResetFile     string
+
+	// BootstrapAdmin names a registered person promoted to ROLE_ADMIN at
+	// startup while there is no admin, since registration only creates ROLE_USER
+	BootstrapAdmin string
+
+	// OIDCIssuerURL enables logins through an OpenID Connect identity provider,
+	// registered with OIDCClientID, OIDCClientSecret (empty for public clients)
+	// and OIDCRedirectURL, the public URL of /auth/oidc/callback. The username
+	// of new persons is read from OIDCUsernameClaim, and OIDCRoleMapping maps
+	// the groups in OIDCGroupsClaim to roles, e.g. "cropflow-admins=ROLE_ADMIN".
+	// Persons in no mapped group get OIDCDefaultRole, or cannot log in when it is "none".
+	OIDCIssuerURL     string
+	OIDCClientID      string
+	OIDCClientSecret  string
+	OIDCRedirectURL   string
+	OIDCScopes        []string
+	OIDCUsernameClaim string
+	OIDCGroupsClaim   string
+	OIDCRoleMapping   []string
+	OIDCDefaultRole   string
+
+	// NutrientTargetsFile points to a JSON file with the recommended nutrient
+	// inputs in kg/ha per crop type, e.g. {"milho": {"N": 150, "P2O5": 80}}
+	NutrientTargetsFile string
+
+	// PermissionPolicyFile points to a JSON file overriding the minimum role of
+	// resource actions, e.g. {"fertilizers": {"create": "ROLE_MANAGER"}}
+	PermissionPolicyFile string
 }
 
 // NewConfig creates a new configuration from environment variables
 func NewConfig() *Config {
 	return &Config{
+		DBDriver: getEnv("DB_DRIVER", "mysql"),
+		DBPath:   getEnv("DB_PATH", "cropflow.db"),
+
 		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBPort:     getEnv("DB_PORT", "3306"),
+		DBPort:     getEnv("DB_PORT", ""),
 		DBUser:     getEnv("DB_USER", "root"),
 		DBPassword: getEnv("DB_PASSWORD", ""),
 		DBName:     getEnv("DB_NAME", "cropflow"),
-		JWTSecret:  getEnv("JWT_SECRET", "your-secret-key"),
 		JWTIssuer:  getEnv("JWT_ISSUER", "cropflow"),
+
+		DBMigrateOnStart: getEnvBool("DB_MIGRATE_ON_START", true),
+
+		JWTAlgorithm:           getEnv("JWT_ALGORITHM", "RS256"),
+		JWTKeyFiles:            getEnvList("JWT_KEY_FILES"),
+		JWTKeyRotationInterval: getEnvDuration("JWT_KEY_ROTATION_INTERVAL", 24*time.Hour),
+		JWTKeyOverlap:          getEnvDuration("JWT_KEY_OVERLAP", time.Hour),
+
+		AccessTokenTTL:  getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		TOTPIssuer:        getEnv("TOTP_ISSUER", "CropFlow"),
+		TOTPRequiredRoles: getEnvList("TOTP_REQUIRED_ROLES"),
+
+		LoginMaxFailures:      getEnvInt("LOGIN_MAX_FAILURES", 5),
+		LoginMaxFailuresPerIP: getEnvInt("LOGIN_MAX_FAILURES_PER_IP", 20),
+		LoginLockoutBase:      getEnvDuration("LOGIN_LOCKOUT_BASE", time.Minute),
+		LoginLockoutMax:       getEnvDuration("LOGIN_LOCKOUT_MAX", time.Hour),
+		LoginFailureWindow:    getEnvDuration("LOGIN_FAILURE_WINDOW", 24*time.Hour),
+		TrustedProxies:        getEnvList("TRUSTED_PROXIES"),
+
+		PasswordMinLength:       getEnvInt("PASSWORD_MIN_LENGTH", 8),
+		PasswordMaxLength:       getEnvInt("PASSWORD_MAX_LENGTH", 72),
+		PasswordRequiredClasses: getEnvList("PASSWORD_REQUIRED_CLASSES"),
+		PasswordDenylistFile:    getEnv("PASSWORD_DENYLIST_FILE", ""),
+
+		PasswordHashAlgorithm:     getEnv("PASSWORD_HASH_ALGORITHM", "bcrypt"),
+		PasswordBcryptCost:        getEnvInt("PASSWORD_BCRYPT_COST", 10),
+		PasswordArgon2Memory:      getEnvInt("PASSWORD_ARGON2_MEMORY", 64*1024),
+		PasswordArgon2Iterations:  getEnvInt("PASSWORD_ARGON2_ITERATIONS", 3),
+		PasswordArgon2Parallelism: getEnvInt("PASSWORD_ARGON2_PARALLELISM", 4),
+
+		PasswordResetTTL:      getEnvDuration("PASSWORD_RESET_TTL", time.Hour),
+		PasswordResetNotifier: getEnv("PASSWORD_RESET_NOTIFIER", "log"),
+		PasswordResetFile:     getEnv("PASSWORD_RESET_FILE", "password-resets.jsonl"),
+
+		BootstrapAdmin: getEnv("BOOTSTRAP_ADMIN", ""),
+
+		OIDCIssuerURL:     getEnv("OIDC_ISSUER_URL", ""),
+		OIDCClientID:      getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:  getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:   getEnv("OIDC_REDIRECT_URL", "http://localhost:8080/auth/oidc/callback"),
+		OIDCScopes:        getEnvListOr("OIDC_SCOPES", []string{"openid", "profile", "email"}),
+		OIDCUsernameClaim: getEnv("OIDC_USERNAME_CLAIM", "preferred_username"),
+		OIDCGroupsClaim:   getEnv("OIDC_GROUPS_CLAIM", "groups"),
+		OIDCRoleMapping:   getEnvList("OIDC_ROLE_MAPPING"),
+		OIDCDefaultRole:   getEnv("OIDC_DEFAULT_ROLE", "ROLE_USER"),
+
+		NutrientTargetsFile:  getEnv("NUTRIENT_TARGETS_FILE", ""),
+		PermissionPolicyFile: getEnv("PERMISSION_POLICY_FILE", ""),
+	}
+}
+
+// LoadNutrientTargets reads the nutrient targets file; no file means no targets
+func (c *Config) LoadNutrientTargets() (map[string]map[string]float64, error) {
+	if c.NutrientTargetsFile == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(c.NutrientTargetsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read nutrient targets: %w", err)
 	}
+
+	var targets map[string]map[string]float64
+	if err := json.Unmarshal(data, &targets); err != nil {
+		return nil, fmt.Errorf("failed to parse nutrient targets: %w", err)
+	}
+	return targets, nil
+}
+
+// LoadPermissionPolicy reads the permission policy overrides; no file means the defaults apply
+func (c *Config) LoadPermissionPolicy() (map[string]map[string]string, error) {
+	if c.PermissionPolicyFile == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(c.PermissionPolicyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read permission policy: %w", err)
+	}
+
+	var rules map[string]map[string]string
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse permission policy: %w", err)
+	}
+	return rules, nil
+}
+
+// LoadPasswordDenylist reads the rejected passwords, one per line, skipping
+// blank lines and # comments; no file means no denylist
+func (c *Config) LoadPasswordDenylist() ([]string, error) {
+	if c.PasswordDenylistFile == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(c.PasswordDenylistFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password denylist: %w", err)
+	}
+
+	var passwords []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords = append(passwords, line)
+	}
+	return passwords, nil
+}
+
+// OIDCRoleGroups parses OIDCRoleMapping into group names and the role they map to
+func (c *Config) OIDCRoleGroups() (map[string]string, error) {
+	groups := make(map[string]string, len(c.OIDCRoleMapping))
+	for _, entry := range c.OIDCRoleMapping {
+		group, role, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid oidc role mapping %q: expected group=ROLE", entry)
+		}
+		groups[strings.TrimSpace(group)] = strings.TrimSpace(role)
+	}
+	return groups, nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -32,4 +261,65 @@ func getEnv(key, defaultValue string) string {
 		return defaultValue
 	}
 	return value
-}
\ No newline at end of file
+}
+
+// getEnvInt reads a positive integer; invalid values fall back to the default
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Printf("Invalid %s %q, using %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+// getEnvBool reads a boolean such as "true" or "0"; invalid values fall back to the default
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return b
+}
+
+// getEnvList reads a comma separated list, ignoring empty entries
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvListOr reads a comma separated list, or returns the default when it is empty
+func getEnvListOr(key string, defaultValues []string) []string {
+	if values := getEnvList(key); len(values) > 0 {
+		return values
+	}
+	return defaultValues
+}
+
+// getEnvDuration reads a duration such as "15m" or "720h"; invalid values fall back to the default
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid %s %q, using %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return d
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cropflow/api/config"
+	"github.com/cropflow/api/internal/adapters/database/mysql"
+	"github.com/cropflow/api/internal/adapters/database/postgres"
+	"github.com/cropflow/api/internal/adapters/database/sqlite"
+	"github.com/cropflow/api/internal/infrastructure/migrations"
+	"gorm.io/gorm"
+)
+
+// Supported values of DB_DRIVER
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+var ErrUnknownDriver = errors.New("unknown database driver")
+
+// Open connects to the database selected by cfg.DBDriver and returns it with
+// the migrator of its dialect. The repositories in gormrepo work on any of them.
+func Open(cfg *config.Config) (*gorm.DB, *migrations.Migrator, error) {
+	var (
+		db          *gorm.DB
+		err         error
+		newMigrator func(*gorm.DB) (*migrations.Migrator, error)
+	)
+	switch cfg.DBDriver {
+	case DriverMySQL:
+		db, err = mysql.NewMySQLConnection(cfg)
+		newMigrator = mysql.NewMigrator
+	case DriverPostgres:
+		db, err = postgres.NewPostgresConnection(cfg)
+		newMigrator = postgres.NewMigrator
+	case DriverSQLite:
+		db, err = sqlite.NewSQLiteConnection(cfg)
+		newMigrator = sqlite.NewMigrator
+	default:
+		return nil, nil, fmt.Errorf("%w %q: expected %s, %s or %s", ErrUnknownDriver, cfg.DBDriver, DriverMySQL, DriverPostgres, DriverSQLite)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, migrator, nil
+}
@@ -0,0 +1,77 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/apikey"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *gorm.DB) apikey.Repository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Save(ctx context.Context, k *apikey.Key) error {
+	model := persistence.ToAPIKeyModel(k)
+	if err := conn(ctx, r.db).Save(model).Error; err != nil {
+		return err
+	}
+	k.SetID(model.ID)
+	return nil
+}
+
+func (r *apiKeyRepository) FindByID(ctx context.Context, id int64) (*apikey.Key, error) {
+	var model persistence.APIKeyModel
+	err := conn(ctx, r.db).First(&model, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apikey.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	return persistence.ToAPIKeyDomain(&model), nil
+}
+
+func (r *apiKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*apikey.Key, error) {
+	var model persistence.APIKeyModel
+	err := conn(ctx, r.db).Where("prefix = ?", prefix).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apikey.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	return persistence.ToAPIKeyDomain(&model), nil
+}
+
+func (r *apiKeyRepository) List(ctx context.Context, filter apikey.Filter) ([]*apikey.Key, error) {
+	db := conn(ctx, r.db).Order("id")
+	if filter.OwnerID != 0 {
+		db = db.Where("person_id = ?", filter.OwnerID)
+	}
+
+	var models []persistence.APIKeyModel
+	if err := db.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	keys := make([]*apikey.Key, 0, len(models))
+	for i := range models {
+		keys = append(keys, persistence.ToAPIKeyDomain(&models[i]))
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Touch(ctx context.Context, id int64, at time.Time) error {
+	return conn(ctx, r.db).Model(&persistence.APIKeyModel{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+}
@@ -0,0 +1,101 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+	"gorm.io/gorm"
+)
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new audit log repository
+func NewAuditRepository(db *gorm.DB) audit.Repository {
+	return &auditRepository{db: db}
+}
+
+var auditSortColumns = map[string]sortColumn[persistence.AuditEntryModel]{
+	"id":         idColumn(func(m *persistence.AuditEntryModel) int64 { return m.ID }),
+	"occurredAt": timeColumn("occurred_at", func(m *persistence.AuditEntryModel) time.Time { return m.OccurredAt }),
+}
+
+func (r *auditRepository) List(ctx context.Context, filter audit.Filter, page query.Page) (query.Result[*audit.Entry], error) {
+	db := conn(ctx, r.db).Model(&persistence.AuditEntryModel{})
+	if filter.ActorID != 0 {
+		db = db.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		db = db.Where("action = ?", filter.Action.String())
+	}
+	if filter.ResourceType != "" {
+		db = db.Where("resource_type = ?", filter.ResourceType.String())
+	}
+	if filter.ResourceID != 0 {
+		db = db.Where("resource_id = ?", filter.ResourceID)
+	}
+	if filter.RequestID != "" {
+		db = db.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		db = db.Where("occurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		db = db.Where("occurred_at <= ?", *filter.To)
+	}
+
+	models, next, prev, err := paginate(db, page, auditSortColumns, func(m *persistence.AuditEntryModel) int64 { return m.ID })
+	if err != nil {
+		return query.Result[*audit.Entry]{}, err
+	}
+
+	entries := make([]*audit.Entry, 0, len(models))
+	for i := range models {
+		e, err := persistence.ToAuditEntryDomain(&models[i])
+		if err != nil {
+			return query.Result[*audit.Entry]{}, err
+		}
+		entries = append(entries, e)
+	}
+	return query.Result[*audit.Entry]{Items: entries, NextCursor: next, PrevCursor: prev}, nil
+}
+
+// recordAudit appends an audit entry for a change to a resource, within the
+// transaction that makes it, on behalf of the caller in ctx. before and after
+// are snapshots of the resource, nil when it does not exist; nothing is
+// recorded when they are equal.
+func recordAudit(ctx context.Context, tx *gorm.DB, resourceType audit.ResourceType, resourceID int64, before, after map[string]any) error {
+	entry, err := persistence.NewAuditEntry(ctx, resourceType, resourceID, before, after)
+	if err != nil || entry == nil {
+		return err
+	}
+	model, err := persistence.ToAuditEntryModel(entry)
+	if err != nil {
+		return err
+	}
+	if err := tx.Create(model).Error; err != nil {
+		return err
+	}
+	entry.SetID(model.ID)
+	return nil
+}
+
+// snapshotByID captures the stored row of a model with the given ID, nil when there is none
+func snapshotByID[M any](tx *gorm.DB, id int64) (map[string]any, error) {
+	if id == 0 {
+		return nil, nil
+	}
+	var model M
+	if err := tx.First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return persistence.Snapshot(&model)
+}
@@ -0,0 +1,306 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type cropRepository struct {
+	db *gorm.DB
+}
+
+// NewCropRepository creates a new crop repository
+func NewCropRepository(db *gorm.DB) crop.Repository {
+	return &cropRepository{db: db}
+}
+
+func (r *cropRepository) Save(ctx context.Context, c *crop.Crop) error {
+	model := persistence.ToCropModel(c)
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		before, err := snapshotByID[persistence.CropModel](tx, model.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Omit("Transitions", "Harvests", "Applications").Save(model).Error; err != nil {
+			if errors.Is(err, gorm.ErrForeignKeyViolated) {
+				return crop.ErrFarmNotFound
+			}
+			return err
+		}
+		after, err := persistence.Snapshot(model)
+		if err != nil {
+			return err
+		}
+		for _, t := range c.PendingTransitions() {
+			if err := tx.Create(persistence.ToCropTransitionModel(model.ID, t)).Error; err != nil {
+				return err
+			}
+		}
+		for _, h := range c.PendingHarvests() {
+			harvest := persistence.ToHarvestModel(h)
+			harvest.CropID = model.ID
+			if err := tx.Create(harvest).Error; err != nil {
+				return err
+			}
+			h.SetID(harvest.ID)
+			if after[persistence.HarvestField(harvest.ID)], err = persistence.Snapshot(harvest); err != nil {
+				return err
+			}
+		}
+		for _, a := range c.PendingApplications() {
+			application := persistence.ToApplicationModel(a)
+			application.CropID = model.ID
+			if err := tx.Create(application).Error; err != nil {
+				if errors.Is(err, gorm.ErrForeignKeyViolated) {
+					return crop.ErrFertilizerNotFound
+				}
+				return err
+			}
+			a.SetID(application.ID)
+			if after[persistence.ApplicationField(application.ID)], err = persistence.Snapshot(application); err != nil {
+				return err
+			}
+		}
+		return recordAudit(ctx, tx, audit.ResourceCrop, model.ID, before, after)
+	})
+	if err != nil {
+		return err
+	}
+	c.SetID(model.ID)
+	c.ClearPendingTransitions()
+	c.ClearPendingHarvests()
+	c.ClearPendingApplications()
+	return nil
+}
+
+func (r *cropRepository) FindByID(ctx context.Context, id int64) (*crop.Crop, error) {
+	return findCrop(conn(ctx, r.db), id)
+}
+
+func (r *cropRepository) FindByIDForUpdate(ctx context.Context, id int64) (*crop.Crop, error) {
+	return findCrop(conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), id)
+}
+
+func findCrop(db *gorm.DB, id int64) (*crop.Crop, error) {
+	var model persistence.CropModel
+	err := db.First(&model, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, crop.ErrCropNotFound
+		}
+		return nil, err
+	}
+	return persistence.ToCropDomain(&model), nil
+}
+
+var cropSortColumns = map[string]sortColumn[persistence.CropModel]{
+	"id":          idColumn(func(m *persistence.CropModel) int64 { return m.ID }),
+	"name":        stringColumn("name", func(m *persistence.CropModel) string { return m.Name }),
+	"plantedArea": floatColumn("planted_area", func(m *persistence.CropModel) float64 { return m.PlantedArea }),
+	"createdAt":   timeColumn("created_at", func(m *persistence.CropModel) time.Time { return m.CreatedAt }),
+}
+
+func (r *cropRepository) List(ctx context.Context, filter crop.Filter, page query.Page) (query.Result[*crop.Crop], error) {
+	db := conn(ctx, r.db).Model(&persistence.CropModel{})
+	if filter.FarmID != 0 {
+		db = db.Where("farm_id = ?", filter.FarmID)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status.String())
+	}
+	if filter.NamePrefix != "" {
+		db = whereHasPrefix(db, "name", filter.NamePrefix)
+	}
+	if filter.PlantedFrom != nil {
+		db = db.Where("planting_date >= ?", *filter.PlantedFrom)
+	}
+	if filter.PlantedTo != nil {
+		db = db.Where("planting_date <= ?", *filter.PlantedTo)
+	}
+	if filter.MinArea != nil {
+		db = db.Where("planted_area >= ?", *filter.MinArea)
+	}
+	if filter.MaxArea != nil {
+		db = db.Where("planted_area <= ?", *filter.MaxArea)
+	}
+	if filter.MemberID != 0 {
+		db = db.Where("farm_id IN (?)", memberFarmIDs(r.db, filter.MemberID))
+	}
+	if len(filter.FarmIDs) > 0 {
+		db = db.Where("farm_id IN ?", filter.FarmIDs)
+	}
+
+	models, next, prev, err := paginate(db, page, cropSortColumns, func(m *persistence.CropModel) int64 { return m.ID })
+	if err != nil {
+		return query.Result[*crop.Crop]{}, err
+	}
+	return query.Result[*crop.Crop]{Items: toCrops(models), NextCursor: next, PrevCursor: prev}, nil
+}
+
+func (r *cropRepository) Delete(ctx context.Context, id int64) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		before, err := snapshotByID[persistence.CropModel](tx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return crop.ErrCropNotFound
+		}
+
+		if err := tx.Where("crop_id = ?", id).Delete(&persistence.ApplicationModel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("crop_id = ?", id).Delete(&persistence.CropTransitionModel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("crop_id = ?", id).Delete(&persistence.HarvestModel{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&persistence.CropModel{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return crop.ErrCropNotFound
+		}
+		return recordAudit(ctx, tx, audit.ResourceCrop, id, before, nil)
+	})
+}
+
+func (r *cropRepository) FindByFarmID(ctx context.Context, farmID int64) ([]*crop.Crop, error) {
+	var models []persistence.CropModel
+	if err := conn(ctx, r.db).Where("farm_id = ?", farmID).Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return toCrops(models), nil
+}
+
+func (r *cropRepository) FindApplications(ctx context.Context, filter crop.ApplicationFilter) ([]*crop.Application, error) {
+	db := conn(ctx, r.db).Model(&persistence.ApplicationModel{})
+	if filter.CropID != 0 {
+		db = db.Where("fertilizer_application.crop_id = ?", filter.CropID)
+	}
+	if filter.FarmID != 0 {
+		db = db.Joins("JOIN crops ON crops.id = fertilizer_application.crop_id").
+			Where("crops.farm_id = ?", filter.FarmID)
+	}
+	if filter.AppliedFrom != nil {
+		db = db.Where("fertilizer_application.applied_at >= ?", *filter.AppliedFrom)
+	}
+	if filter.AppliedTo != nil {
+		db = db.Where("fertilizer_application.applied_at <= ?", *filter.AppliedTo)
+	}
+
+	var models []persistence.ApplicationModel
+	err := db.Order("fertilizer_application.applied_at, fertilizer_application.id").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	applications := make([]*crop.Application, len(models))
+	for i := range models {
+		applications[i] = persistence.ToApplicationDomain(&models[i])
+	}
+	return applications, nil
+}
+
+func (r *cropRepository) FindFertilizersByCropID(ctx context.Context, cropID int64) ([]int64, error) {
+	var ids []int64
+	err := conn(ctx, r.db).
+		Model(&persistence.ApplicationModel{}).
+		Distinct("fertilizer_id").
+		Where("crop_id = ?", cropID).
+		Order("fertilizer_id").
+		Pluck("fertilizer_id", &ids).Error
+	return ids, err
+}
+
+func (r *cropRepository) FindTransitions(ctx context.Context, cropID int64) ([]crop.Transition, error) {
+	var models []persistence.CropTransitionModel
+	err := conn(ctx, r.db).
+		Where("crop_id = ?", cropID).
+		Order("id").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	transitions := make([]crop.Transition, len(models))
+	for i := range models {
+		transitions[i] = persistence.ToCropTransitionDomain(&models[i])
+	}
+	return transitions, nil
+}
+
+func (r *cropRepository) FindHarvests(ctx context.Context, cropID int64) ([]*crop.Harvest, error) {
+	var models []persistence.HarvestModel
+	err := conn(ctx, r.db).
+		Where("crop_id = ?", cropID).
+		Order("harvested_at, id").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	harvests := make([]*crop.Harvest, len(models))
+	for i := range models {
+		harvests[i] = persistence.ToHarvestDomain(&models[i])
+	}
+	return harvests, nil
+}
+
+func (r *cropRepository) FindHarvestByID(ctx context.Context, cropID, harvestID int64) (*crop.Harvest, error) {
+	var model persistence.HarvestModel
+	err := conn(ctx, r.db).Where("crop_id = ?", cropID).First(&model, harvestID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, crop.ErrHarvestNotFound
+		}
+		return nil, err
+	}
+	return persistence.ToHarvestDomain(&model), nil
+}
+
+func (r *cropRepository) DeleteHarvest(ctx context.Context, cropID, harvestID int64) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var model persistence.HarvestModel
+		if err := tx.Where("crop_id = ?", cropID).First(&model, harvestID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return crop.ErrHarvestNotFound
+			}
+			return err
+		}
+		before, err := persistence.Snapshot(&model)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Where("crop_id = ?", cropID).Delete(&persistence.HarvestModel{}, harvestID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return crop.ErrHarvestNotFound
+		}
+
+		field := persistence.HarvestField(harvestID)
+		return recordAudit(ctx, tx, audit.ResourceCrop, cropID, map[string]any{field: before}, map[string]any{})
+	})
+}
+
+func toCrops(models []persistence.CropModel) []*crop.Crop {
+	crops := make([]*crop.Crop, len(models))
+	for i := range models {
+		crops[i] = persistence.ToCropDomain(&models[i])
+	}
+	return crops
+}
@@ -0,0 +1,245 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/farm"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type farmRepository struct {
+	db *gorm.DB
+}
+
+// NewFarmRepository creates a new farm repository
+func NewFarmRepository(db *gorm.DB) farm.Repository {
+	return &farmRepository{db: db}
+}
+
+func (r *farmRepository) Save(ctx context.Context, f *farm.Farm) error {
+	model := persistence.ToFarmModel(f)
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		before, err := snapshotByID[persistence.FarmModel](tx, model.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Omit("Crops", "Members").Save(model).Error; err != nil {
+			return err
+		}
+		after, err := persistence.Snapshot(model)
+		if err != nil {
+			return err
+		}
+		for _, m := range f.PendingMembers() {
+			m.SetFarmID(model.ID)
+			if err := tx.Create(persistence.ToFarmMemberModel(m)).Error; err != nil {
+				if errors.Is(err, gorm.ErrDuplicatedKey) {
+					return farm.ErrMemberAlreadyExists
+				}
+				if errors.Is(err, gorm.ErrForeignKeyViolated) {
+					return farm.ErrInvalidMemberPerson
+				}
+				return err
+			}
+			after[persistence.MemberField(m.PersonID())] = m.Role().String()
+		}
+		return recordAudit(ctx, tx, audit.ResourceFarm, model.ID, before, after)
+	})
+	if err != nil {
+		return err
+	}
+	f.SetID(model.ID)
+	f.ClearPendingMembers()
+	return nil
+}
+
+func (r *farmRepository) FindByID(ctx context.Context, id int64) (*farm.Farm, error) {
+	var model persistence.FarmModel
+	err := conn(ctx, r.db).First(&model, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, farm.ErrFarmNotFound
+		}
+		return nil, err
+	}
+	return persistence.ToFarmDomain(&model)
+}
+
+var farmSortColumns = map[string]sortColumn[persistence.FarmModel]{
+	"id":        idColumn(func(m *persistence.FarmModel) int64 { return m.ID }),
+	"name":      stringColumn("name", func(m *persistence.FarmModel) string { return m.Name }),
+	"size":      floatColumn("size", func(m *persistence.FarmModel) float64 { return m.Size }),
+	"createdAt": timeColumn("created_at", func(m *persistence.FarmModel) time.Time { return m.CreatedAt }),
+}
+
+func (r *farmRepository) List(ctx context.Context, filter farm.Filter, page query.Page) (query.Result[*farm.Farm], error) {
+	db := conn(ctx, r.db).Model(&persistence.FarmModel{})
+	if filter.NamePrefix != "" {
+		db = whereHasPrefix(db, "name", filter.NamePrefix)
+	}
+	if filter.MinSize != nil {
+		db = db.Where("size >= ?", *filter.MinSize)
+	}
+	if filter.MaxSize != nil {
+		db = db.Where("size <= ?", *filter.MaxSize)
+	}
+	if filter.MemberID != 0 {
+		db = db.Where("id IN (?)", memberFarmIDs(r.db, filter.MemberID))
+	}
+	if len(filter.FarmIDs) > 0 {
+		db = db.Where("id IN ?", filter.FarmIDs)
+	}
+
+	models, next, prev, err := paginate(db, page, farmSortColumns, func(m *persistence.FarmModel) int64 { return m.ID })
+	if err != nil {
+		return query.Result[*farm.Farm]{}, err
+	}
+
+	farms := make([]*farm.Farm, 0, len(models))
+	for i := range models {
+		f, err := persistence.ToFarmDomain(&models[i])
+		if err != nil {
+			return query.Result[*farm.Farm]{}, err
+		}
+		farms = append(farms, f)
+	}
+	return query.Result[*farm.Farm]{Items: farms, NextCursor: next, PrevCursor: prev}, nil
+}
+
+func (r *farmRepository) Delete(ctx context.Context, id int64) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		before, err := snapshotByID[persistence.FarmModel](tx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return farm.ErrFarmNotFound
+		}
+		var members []persistence.FarmMemberModel
+		if err := tx.Where("farm_id = ?", id).Find(&members).Error; err != nil {
+			return err
+		}
+		for _, m := range members {
+			before[persistence.MemberField(m.PersonID)] = m.Role
+		}
+
+		if err := tx.Where("farm_id = ?", id).Delete(&persistence.FarmMemberModel{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&persistence.FarmModel{}, id)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+				return farm.ErrFarmHasCrops
+			}
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return farm.ErrFarmNotFound
+		}
+		return recordAudit(ctx, tx, audit.ResourceFarm, id, before, nil)
+	})
+}
+
+func (r *farmRepository) ExistsByID(ctx context.Context, id int64) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&persistence.FarmModel{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *farmRepository) FindMember(ctx context.Context, farmID, personID int64) (*farm.Member, error) {
+	var model persistence.FarmMemberModel
+	err := conn(ctx, r.db).Where("farm_id = ? AND person_id = ?", farmID, personID).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, farm.ErrMemberNotFound
+		}
+		return nil, err
+	}
+	return persistence.ToFarmMemberDomain(&model), nil
+}
+
+func (r *farmRepository) FindMembers(ctx context.Context, farmID int64) ([]*farm.Member, error) {
+	var models []persistence.FarmMemberModel
+	if err := conn(ctx, r.db).Where("farm_id = ?", farmID).Order("created_at, person_id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	members := make([]*farm.Member, 0, len(models))
+	for i := range models {
+		members = append(members, persistence.ToFarmMemberDomain(&models[i]))
+	}
+	return members, nil
+}
+
+// LockOwners locks the owner rows in person order, the same in every caller,
+// so two members cannot each remove one of the last two owners
+func (r *farmRepository) LockOwners(ctx context.Context, farmID int64) ([]int64, error) {
+	var ids []int64
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Model(&persistence.FarmMemberModel{}).
+		Where("farm_id = ? AND role = ?", farmID, farm.MemberOwner.String()).
+		Order("person_id").
+		Pluck("person_id", &ids).Error
+	return ids, err
+}
+
+func (r *farmRepository) UpdateMember(ctx context.Context, m *farm.Member) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var model persistence.FarmMemberModel
+		if err := tx.Where("farm_id = ? AND person_id = ?", m.FarmID(), m.PersonID()).First(&model).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return farm.ErrMemberNotFound
+			}
+			return err
+		}
+
+		result := tx.Model(&persistence.FarmMemberModel{}).
+			Where("farm_id = ? AND person_id = ?", m.FarmID(), m.PersonID()).
+			Updates(map[string]interface{}{"role": m.Role().String(), "updated_at": m.UpdatedAt()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return farm.ErrMemberNotFound
+		}
+
+		field := persistence.MemberField(m.PersonID())
+		return recordAudit(ctx, tx, audit.ResourceFarm, m.FarmID(),
+			map[string]any{field: model.Role}, map[string]any{field: m.Role().String()})
+	})
+}
+
+func (r *farmRepository) DeleteMember(ctx context.Context, farmID, personID int64) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var model persistence.FarmMemberModel
+		if err := tx.Where("farm_id = ? AND person_id = ?", farmID, personID).First(&model).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return farm.ErrMemberNotFound
+			}
+			return err
+		}
+
+		result := tx.Where("farm_id = ? AND person_id = ?", farmID, personID).Delete(&persistence.FarmMemberModel{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return farm.ErrMemberNotFound
+		}
+
+		field := persistence.MemberField(personID)
+		return recordAudit(ctx, tx, audit.ResourceFarm, farmID, map[string]any{field: model.Role}, map[string]any{})
+	})
+}
+
+// memberFarmIDs selects the ids of the farms a person is a member of
+func memberFarmIDs(db *gorm.DB, personID int64) *gorm.DB {
+	return db.Model(&persistence.FarmMemberModel{}).Select("farm_id").Where("person_id = ?", personID)
+}
@@ -0,0 +1,130 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/fertilizer"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+	"gorm.io/gorm"
+)
+
+type fertilizerRepository struct {
+	db *gorm.DB
+}
+
+// NewFertilizerRepository creates a new fertilizer repository
+func NewFertilizerRepository(db *gorm.DB) fertilizer.Repository {
+	return &fertilizerRepository{db: db}
+}
+
+func (r *fertilizerRepository) Save(ctx context.Context, f *fertilizer.Fertilizer) error {
+	model := persistence.ToFertilizerModel(f)
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		before, err := snapshotByID[persistence.FertilizerModel](tx, model.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Save(model).Error; err != nil {
+			return err
+		}
+		after, err := persistence.Snapshot(model)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, audit.ResourceFertilizer, model.ID, before, after)
+	})
+	if err != nil {
+		return err
+	}
+	f.SetID(model.ID)
+	return nil
+}
+
+func (r *fertilizerRepository) FindByID(ctx context.Context, id int64) (*fertilizer.Fertilizer, error) {
+	var model persistence.FertilizerModel
+	err := conn(ctx, r.db).First(&model, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fertilizer.ErrFertilizerNotFound
+		}
+		return nil, err
+	}
+	return persistence.ToFertilizerDomain(&model), nil
+}
+
+func (r *fertilizerRepository) FindByIDs(ctx context.Context, ids []int64) ([]*fertilizer.Fertilizer, error) {
+	if len(ids) == 0 {
+		return []*fertilizer.Fertilizer{}, nil
+	}
+
+	var models []persistence.FertilizerModel
+	if err := conn(ctx, r.db).Where("id IN ?", ids).Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return toFertilizers(models), nil
+}
+
+var fertilizerSortColumns = map[string]sortColumn[persistence.FertilizerModel]{
+	"id":        idColumn(func(m *persistence.FertilizerModel) int64 { return m.ID }),
+	"name":      stringColumn("name", func(m *persistence.FertilizerModel) string { return m.Name }),
+	"brand":     stringColumn("brand", func(m *persistence.FertilizerModel) string { return m.Brand }),
+	"createdAt": timeColumn("created_at", func(m *persistence.FertilizerModel) time.Time { return m.CreatedAt }),
+}
+
+func (r *fertilizerRepository) List(ctx context.Context, filter fertilizer.Filter, page query.Page) (query.Result[*fertilizer.Fertilizer], error) {
+	db := conn(ctx, r.db).Model(&persistence.FertilizerModel{})
+	if filter.NamePrefix != "" {
+		db = whereHasPrefix(db, "name", filter.NamePrefix)
+	}
+	if filter.Brand != "" {
+		db = db.Where("brand = ?", filter.Brand)
+	}
+
+	models, next, prev, err := paginate(db, page, fertilizerSortColumns, func(m *persistence.FertilizerModel) int64 { return m.ID })
+	if err != nil {
+		return query.Result[*fertilizer.Fertilizer]{}, err
+	}
+	return query.Result[*fertilizer.Fertilizer]{Items: toFertilizers(models), NextCursor: next, PrevCursor: prev}, nil
+}
+
+func (r *fertilizerRepository) Delete(ctx context.Context, id int64) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		before, err := snapshotByID[persistence.FertilizerModel](tx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return fertilizer.ErrFertilizerNotFound
+		}
+
+		result := tx.Delete(&persistence.FertilizerModel{}, id)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+				return fertilizer.ErrFertilizerInUse
+			}
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fertilizer.ErrFertilizerNotFound
+		}
+		return recordAudit(ctx, tx, audit.ResourceFertilizer, id, before, nil)
+	})
+}
+
+func (r *fertilizerRepository) ExistsByID(ctx context.Context, id int64) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&persistence.FertilizerModel{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func toFertilizers(models []persistence.FertilizerModel) []*fertilizer.Fertilizer {
+	fertilizers := make([]*fertilizer.Fertilizer, len(models))
+	for i := range models {
+		fertilizers[i] = persistence.ToFertilizerDomain(&models[i])
+	}
+	return fertilizers
+}
@@ -0,0 +1,61 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cropflow/api/internal/domain/lockout"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type lockoutRepository struct {
+	db *gorm.DB
+}
+
+// NewLockoutRepository creates a new failed login repository
+func NewLockoutRepository(db *gorm.DB) lockout.Repository {
+	return &lockoutRepository{db: db}
+}
+
+// byKey matches the row of a key. KEY is reserved in MySQL, so the column is
+// quoted by the dialect instead of written out in the condition.
+func byKey(key string) clause.Eq {
+	return clause.Eq{Column: clause.Column{Name: "key"}, Value: key}
+}
+
+func (r *lockoutRepository) Find(ctx context.Context, key string) (lockout.Status, error) {
+	var model persistence.LoginAttemptModel
+	err := conn(ctx, r.db).Where(byKey(key)).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return lockout.Status{}, nil
+		}
+		return lockout.Status{}, err
+	}
+	return persistence.ToLoginAttemptDomain(&model), nil
+}
+
+func (r *lockoutRepository) Update(ctx context.Context, key string, fn func(lockout.Status) lockout.Status) (lockout.Status, error) {
+	var status lockout.Status
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Make sure the row exists so concurrent failures serialize on its lock
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&persistence.LoginAttemptModel{Key: key}).Error; err != nil {
+			return err
+		}
+
+		var model persistence.LoginAttemptModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(byKey(key)).First(&model).Error; err != nil {
+			return err
+		}
+
+		status = fn(persistence.ToLoginAttemptDomain(&model))
+		return tx.Save(persistence.ToLoginAttemptModel(key, status)).Error
+	})
+	return status, err
+}
+
+func (r *lockoutRepository) Delete(ctx context.Context, key string) error {
+	return conn(ctx, r.db).Where(byKey(key)).Delete(&persistence.LoginAttemptModel{}).Error
+}
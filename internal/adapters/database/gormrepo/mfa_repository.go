@@ -0,0 +1,113 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cropflow/api/internal/domain/mfa"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+	"gorm.io/gorm"
+)
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+// NewMFARepository creates a new two-factor repository
+func NewMFARepository(db *gorm.DB) mfa.Repository {
+	return &mfaRepository{db: db}
+}
+
+func (r *mfaRepository) SaveEnrollment(ctx context.Context, e *mfa.Enrollment) error {
+	model := persistence.ToTOTPEnrollmentModel(e)
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("RecoveryCodes").Save(model).Error; err != nil {
+			return err
+		}
+		for _, c := range e.UsedRecoveryCodes() {
+			// Only one of two concurrent logins with the same code may win
+			result := tx.Model(&persistence.RecoveryCodeModel{}).
+				Where("id = ? AND used_at IS NULL", c.ID()).
+				Update("used_at", c.UsedAt())
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return mfa.ErrInvalidCode
+			}
+		}
+		if pending := e.PendingRecoveryCodes(); len(pending) > 0 {
+			// New recovery codes replace all previous ones
+			if err := tx.Where("enrollment_id = ?", model.ID).Delete(&persistence.RecoveryCodeModel{}).Error; err != nil {
+				return err
+			}
+			for _, c := range pending {
+				c.SetEnrollmentID(model.ID)
+				code := persistence.ToRecoveryCodeModel(c)
+				if err := tx.Create(code).Error; err != nil {
+					return err
+				}
+				c.SetID(code.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	e.SetID(model.ID)
+	e.ClearPendingRecoveryCodes()
+	return nil
+}
+
+func (r *mfaRepository) FindEnrollmentByPersonID(ctx context.Context, personID int64) (*mfa.Enrollment, error) {
+	var model persistence.TOTPEnrollmentModel
+	err := conn(ctx, r.db).
+		Preload("RecoveryCodes", "used_at IS NULL").
+		Where("person_id = ?", personID).
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, mfa.ErrEnrollmentNotFound
+		}
+		return nil, err
+	}
+	return persistence.ToTOTPEnrollmentDomain(&model), nil
+}
+
+func (r *mfaRepository) DeleteEnrollment(ctx context.Context, personID int64) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var model persistence.TOTPEnrollmentModel
+		if err := tx.Where("person_id = ?", personID).First(&model).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return mfa.ErrEnrollmentNotFound
+			}
+			return err
+		}
+		if err := tx.Where("enrollment_id = ?", model.ID).Delete(&persistence.RecoveryCodeModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model).Error
+	})
+}
+
+func (r *mfaRepository) SaveChallenge(ctx context.Context, c *mfa.Challenge) error {
+	model := persistence.ToLoginChallengeModel(c)
+	if err := conn(ctx, r.db).Save(model).Error; err != nil {
+		return err
+	}
+	c.SetID(model.ID)
+	return nil
+}
+
+func (r *mfaRepository) FindChallenge(ctx context.Context, hash string) (*mfa.Challenge, error) {
+	var model persistence.LoginChallengeModel
+	err := conn(ctx, r.db).Where("hash = ?", hash).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, mfa.ErrInvalidChallenge
+		}
+		return nil, err
+	}
+	return persistence.ToLoginChallengeDomain(&model), nil
+}
@@ -0,0 +1,75 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cropflow/api/internal/domain/oidc"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+	"gorm.io/gorm"
+)
+
+type oidcRepository struct {
+	db *gorm.DB
+}
+
+// NewOIDCRepository creates a new OIDC login repository
+func NewOIDCRepository(db *gorm.DB) oidc.Repository {
+	return &oidcRepository{db: db}
+}
+
+func (r *oidcRepository) SaveLoginRequest(ctx context.Context, request *oidc.LoginRequest) error {
+	if request.ID() == 0 {
+		model := persistence.ToOIDCLoginRequestModel(request)
+		if err := conn(ctx, r.db).Create(model).Error; err != nil {
+			return err
+		}
+		request.SetID(model.ID)
+		return nil
+	}
+
+	// Only one of two concurrent callbacks with the same state may win
+	result := conn(ctx, r.db).Model(&persistence.OIDCLoginRequestModel{}).
+		Where("id = ? AND consumed_at IS NULL", request.ID()).
+		Update("consumed_at", request.ConsumedAt())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return oidc.ErrLoginRequestConsumed
+	}
+	return nil
+}
+
+func (r *oidcRepository) FindLoginRequest(ctx context.Context, stateHash string) (*oidc.LoginRequest, error) {
+	var model persistence.OIDCLoginRequestModel
+	err := conn(ctx, r.db).Where("state_hash = ?", stateHash).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, oidc.ErrInvalidState
+		}
+		return nil, err
+	}
+	return persistence.ToOIDCLoginRequestDomain(&model), nil
+}
+
+func (r *oidcRepository) SaveLink(ctx context.Context, link *oidc.Link) error {
+	model := persistence.ToOIDCLinkModel(link)
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return err
+	}
+	link.SetID(model.ID)
+	return nil
+}
+
+func (r *oidcRepository) FindLink(ctx context.Context, issuer, subject string) (*oidc.Link, error) {
+	var model persistence.OIDCLinkModel
+	err := conn(ctx, r.db).Where("issuer = ? AND subject = ?", issuer, subject).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, oidc.ErrLinkNotFound
+		}
+		return nil, err
+	}
+	return persistence.ToOIDCLinkDomain(&model), nil
+}
@@ -0,0 +1,135 @@
+package gormrepo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/query"
+	"gorm.io/gorm"
+)
+
+// sortColumn describes how an API sort field maps onto a table column
+type sortColumn[M any] struct {
+	column string
+	value  func(m *M) string
+	parse  func(value string) (interface{}, error)
+}
+
+func stringColumn[M any](column string, value func(m *M) string) sortColumn[M] {
+	return sortColumn[M]{
+		column: column,
+		value:  value,
+		parse:  func(v string) (interface{}, error) { return v, nil },
+	}
+}
+
+func floatColumn[M any](column string, value func(m *M) float64) sortColumn[M] {
+	return sortColumn[M]{
+		column: column,
+		value:  func(m *M) string { return strconv.FormatFloat(value(m), 'g', -1, 64) },
+		parse:  func(v string) (interface{}, error) { return strconv.ParseFloat(v, 64) },
+	}
+}
+
+func timeColumn[M any](column string, value func(m *M) time.Time) sortColumn[M] {
+	return sortColumn[M]{
+		column: column,
+		value:  func(m *M) string { return value(m).UTC().Format(time.RFC3339Nano) },
+		parse:  func(v string) (interface{}, error) { return time.Parse(time.RFC3339Nano, v) },
+	}
+}
+
+func idColumn[M any](value func(m *M) int64) sortColumn[M] {
+	return sortColumn[M]{
+		column: "id",
+		value:  func(m *M) string { return strconv.FormatInt(value(m), 10) },
+		parse:  func(v string) (interface{}, error) { return strconv.ParseInt(v, 10, 64) },
+	}
+}
+
+// paginate runs a keyset-paginated query ordered by the requested column with
+// the primary key as tie-breaker, and returns the rows with next/prev cursors
+func paginate[M any](db *gorm.DB, page query.Page, columns map[string]sortColumn[M], id func(m *M) int64) ([]M, string, string, error) {
+	col, ok := columns[page.Sort.Field]
+	if !ok {
+		return nil, "", "", query.ErrInvalidSort
+	}
+
+	backward := page.Cursor != nil && page.Cursor.Backward
+	// Walking back to the previous page scans the ordering in reverse
+	scanDesc := (page.Sort.Direction == query.Desc) != backward
+	op, order := ">", "ASC"
+	if scanDesc {
+		op, order = "<", "DESC"
+	}
+
+	if page.Cursor != nil {
+		if col.column == "id" {
+			db = db.Where(fmt.Sprintf("id %s ?", op), page.Cursor.ID)
+		} else {
+			value, err := col.parse(page.Cursor.Value)
+			if err != nil {
+				return nil, "", "", query.ErrInvalidCursor
+			}
+			db = db.Where(
+				fmt.Sprintf("((%[1]s %[2]s ?) OR (%[1]s = ? AND id %[2]s ?))", col.column, op),
+				value, value, page.Cursor.ID,
+			)
+		}
+	}
+
+	db = db.Order(col.column + " " + order)
+	if col.column != "id" {
+		db = db.Order("id " + order)
+	}
+
+	var rows []M
+	if err := db.Limit(page.Limit + 1).Find(&rows).Error; err != nil {
+		return nil, "", "", err
+	}
+
+	hasMore := len(rows) > page.Limit
+	if hasMore {
+		rows = rows[:page.Limit]
+	}
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	if len(rows) == 0 {
+		return rows, "", "", nil
+	}
+
+	cursorAt := func(m *M, backward bool) string {
+		return query.Cursor{Sort: page.Sort, Value: col.value(m), ID: id(m), Backward: backward}.Encode()
+	}
+
+	first, last := &rows[0], &rows[len(rows)-1]
+	var next, prev string
+	if backward {
+		next = cursorAt(last, false)
+		if hasMore {
+			prev = cursorAt(first, true)
+		}
+	} else {
+		if hasMore {
+			next = cursorAt(last, false)
+		}
+		if page.Cursor != nil {
+			prev = cursorAt(first, true)
+		}
+	}
+
+	return rows, next, prev, nil
+}
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// whereHasPrefix adds a LIKE condition matching values starting with prefix
+func whereHasPrefix(db *gorm.DB, column, prefix string) *gorm.DB {
+	return db.Where(column+" LIKE ? ESCAPE '!'", likeEscaper.Replace(prefix)+"%")
+}
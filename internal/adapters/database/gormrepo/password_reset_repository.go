@@ -0,0 +1,61 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/passwordreset"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+	"gorm.io/gorm"
+)
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetRepository creates a new password reset token repository
+func NewPasswordResetRepository(db *gorm.DB) passwordreset.Repository {
+	return &passwordResetRepository{db: db}
+}
+
+func (r *passwordResetRepository) Save(ctx context.Context, t *passwordreset.Token) error {
+	if t.ID() == 0 {
+		model := persistence.ToPasswordResetTokenModel(t)
+		if err := conn(ctx, r.db).Create(model).Error; err != nil {
+			return err
+		}
+		t.SetID(model.ID)
+		return nil
+	}
+
+	// Only one of two concurrent resets with the same token may win
+	result := conn(ctx, r.db).Model(&persistence.PasswordResetTokenModel{}).
+		Where("id = ? AND used_at IS NULL", t.ID()).
+		Update("used_at", t.UsedAt())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return passwordreset.ErrResetTokenUsed
+	}
+	return nil
+}
+
+func (r *passwordResetRepository) FindByHash(ctx context.Context, hash string) (*passwordreset.Token, error) {
+	var model persistence.PasswordResetTokenModel
+	err := conn(ctx, r.db).Where("hash = ?", hash).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, passwordreset.ErrInvalidResetToken
+		}
+		return nil, err
+	}
+	return persistence.ToPasswordResetTokenDomain(&model), nil
+}
+
+func (r *passwordResetRepository) InvalidateAllByPersonID(ctx context.Context, personID int64, at time.Time) error {
+	return conn(ctx, r.db).Model(&persistence.PasswordResetTokenModel{}).
+		Where("person_id = ? AND used_at IS NULL", personID).
+		Update("used_at", at).Error
+}
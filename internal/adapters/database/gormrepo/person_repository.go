@@ -0,0 +1,245 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type personRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonRepository creates a new person repository
+func NewPersonRepository(db *gorm.DB) person.Repository {
+	return &personRepository{db: db}
+}
+
+func (r *personRepository) Save(ctx context.Context, p *person.Person) error {
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		before, err := snapshotByID[persistence.PersonModel](tx, p.ID())
+		if err != nil {
+			return err
+		}
+		return savePerson(ctx, tx, p, before)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return person.ErrUsernameAlreadyExists
+		}
+		return err
+	}
+	p.ClearPendingRoleChanges()
+	return nil
+}
+
+// savePerson writes the person and the role changes made since it was loaded,
+// auditing the difference with before, the stored row
+func savePerson(ctx context.Context, tx *gorm.DB, p *person.Person, before map[string]any) error {
+	model := persistence.ToPersonModel(p)
+	if err := tx.Save(model).Error; err != nil {
+		return err
+	}
+	p.SetID(model.ID)
+	for _, c := range p.PendingRoleChanges() {
+		if err := tx.Create(persistence.ToRoleChangeModel(model.ID, c)).Error; err != nil {
+			return err
+		}
+	}
+
+	after, err := persistence.Snapshot(model)
+	if err != nil {
+		return err
+	}
+	return recordAudit(ctx, tx, audit.ResourcePerson, model.ID, before, after)
+}
+
+func (r *personRepository) FindByID(ctx context.Context, id int64) (*person.Person, error) {
+	var model persistence.PersonModel
+	err := conn(ctx, r.db).First(&model, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, person.ErrPersonNotFound
+		}
+		return nil, err
+	}
+	return persistence.ToPersonDomain(&model)
+}
+
+func (r *personRepository) FindByUsername(ctx context.Context, username string) (*person.Person, error) {
+	var model persistence.PersonModel
+	err := conn(ctx, r.db).Where("username = ?", username).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, person.ErrPersonNotFound
+		}
+		return nil, err
+	}
+	return persistence.ToPersonDomain(&model)
+}
+
+func (r *personRepository) Update(ctx context.Context, id int64, fn func(*person.Person) error) (*person.Person, error) {
+	var p *person.Person
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var before map[string]any
+		var err error
+		if p, before, err = lockPerson(tx, id); err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+		return savePerson(ctx, tx, p, before)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, person.ErrUsernameAlreadyExists
+		}
+		return nil, err
+	}
+	p.ClearPendingRoleChanges()
+	return p, nil
+}
+
+func (r *personRepository) UpdateRole(ctx context.Context, id int64, fn func(*person.Person, int) error) (*person.Person, error) {
+	var p *person.Person
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// The admins are locked before the person, in the same order by every
+		// role change, so two of them cannot each demote one of the last two admins
+		admins, err := lockAdmins(tx)
+		if err != nil {
+			return err
+		}
+		var before map[string]any
+		if p, before, err = lockPerson(tx, id); err != nil {
+			return err
+		}
+		if err := fn(p, len(admins)); err != nil {
+			return err
+		}
+		return savePerson(ctx, tx, p, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.ClearPendingRoleChanges()
+	return p, nil
+}
+
+// lockPerson loads the person locked against concurrent changes, along with
+// the snapshot of the row its changes are audited against
+func lockPerson(tx *gorm.DB, id int64) (*person.Person, map[string]any, error) {
+	var model persistence.PersonModel
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, person.ErrPersonNotFound
+		}
+		return nil, nil, err
+	}
+	before, err := persistence.Snapshot(&model)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := persistence.ToPersonDomain(&model)
+	return p, before, err
+}
+
+// lockAdmins locks the admin rows and returns their IDs
+func lockAdmins(tx *gorm.DB) ([]int64, error) {
+	var ids []int64
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Model(&persistence.PersonModel{}).
+		Where("role = ?", person.RoleAdmin.String()).
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+var personSortColumns = map[string]sortColumn[persistence.PersonModel]{
+	"id":        idColumn(func(m *persistence.PersonModel) int64 { return m.ID }),
+	"username":  stringColumn("username", func(m *persistence.PersonModel) string { return m.Username }),
+	"createdAt": timeColumn("created_at", func(m *persistence.PersonModel) time.Time { return m.CreatedAt }),
+}
+
+func (r *personRepository) List(ctx context.Context, filter person.Filter, page query.Page) (query.Result[*person.Person], error) {
+	db := conn(ctx, r.db).Model(&persistence.PersonModel{})
+	if filter.UsernamePrefix != "" {
+		db = whereHasPrefix(db, "username", filter.UsernamePrefix)
+	}
+	if filter.Role != "" {
+		db = db.Where("role = ?", filter.Role.String())
+	}
+
+	models, next, prev, err := paginate(db, page, personSortColumns, func(m *persistence.PersonModel) int64 { return m.ID })
+	if err != nil {
+		return query.Result[*person.Person]{}, err
+	}
+
+	persons := make([]*person.Person, 0, len(models))
+	for i := range models {
+		p, err := persistence.ToPersonDomain(&models[i])
+		if err != nil {
+			return query.Result[*person.Person]{}, err
+		}
+		persons = append(persons, p)
+	}
+	return query.Result[*person.Person]{Items: persons, NextCursor: next, PrevCursor: prev}, nil
+}
+
+func (r *personRepository) Delete(ctx context.Context, id int64) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		admins, err := lockAdmins(tx)
+		if err != nil {
+			return err
+		}
+		if len(admins) == 1 && admins[0] == id {
+			return person.ErrLastAdmin
+		}
+		before, err := snapshotByID[persistence.PersonModel](tx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return person.ErrPersonNotFound
+		}
+
+		result := tx.Delete(&persistence.PersonModel{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return person.ErrPersonNotFound
+		}
+		return recordAudit(ctx, tx, audit.ResourcePerson, id, before, nil)
+	})
+}
+
+func (r *personRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&persistence.PersonModel{}).Where("username = ?", username).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *personRepository) FindRoleChanges(ctx context.Context, personID int64) ([]person.RoleChange, error) {
+	var models []persistence.RoleChangeModel
+	err := conn(ctx, r.db).
+		Where("person_id = ?", personID).
+		Order("id").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]person.RoleChange, len(models))
+	for i := range models {
+		changes[i] = persistence.ToRoleChangeDomain(&models[i])
+	}
+	return changes, nil
+}
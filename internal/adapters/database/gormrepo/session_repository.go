@@ -0,0 +1,127 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/session"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *gorm.DB) session.Repository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Save(ctx context.Context, s *session.Session) error {
+	model := persistence.ToSessionModel(s)
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if model.ID == 0 {
+			if err := tx.Create(model).Error; err != nil {
+				return err
+			}
+		} else if err := updateSession(tx, model); err != nil {
+			return err
+		}
+		for _, t := range s.UsedTokens() {
+			// Only one of two concurrent refreshes with the same token may win
+			result := tx.Model(&persistence.RefreshTokenModel{}).
+				Where("id = ? AND used_at IS NULL", t.ID()).
+				Update("used_at", t.UsedAt())
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return session.ErrRefreshTokenReused
+			}
+		}
+		for _, t := range s.PendingTokens() {
+			t.SetSessionID(model.ID)
+			token := persistence.ToRefreshTokenModel(t)
+			if err := tx.Create(token).Error; err != nil {
+				return err
+			}
+			t.SetID(token.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.SetID(model.ID)
+	s.ClearPendingTokens()
+	return nil
+}
+
+// updateSession writes what changed in a stored session without undoing a
+// revocation committed since it was loaded: a revocation keeps the first
+// reason, and a session rotated after it was revoked is rejected
+func updateSession(tx *gorm.DB, model *persistence.SessionModel) error {
+	if model.RevokedAt != nil {
+		return tx.Model(&persistence.SessionModel{}).
+			Where("id = ? AND revoked_at IS NULL", model.ID).
+			Updates(map[string]interface{}{"revoked_at": model.RevokedAt, "revoked_reason": model.RevokedReason}).Error
+	}
+
+	var active []int64
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Model(&persistence.SessionModel{}).
+		Where("id = ? AND revoked_at IS NULL", model.ID).
+		Pluck("id", &active).Error
+	if err != nil {
+		return err
+	}
+	if len(active) == 0 {
+		return session.ErrSessionRevoked
+	}
+	return nil
+}
+
+func (r *sessionRepository) FindByID(ctx context.Context, id int64) (*session.Session, error) {
+	var model persistence.SessionModel
+	err := conn(ctx, r.db).First(&model, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, session.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return persistence.ToSessionDomain(&model), nil
+}
+
+func (r *sessionRepository) FindByRefreshToken(ctx context.Context, hash string) (*session.Session, *session.RefreshToken, error) {
+	var token persistence.RefreshTokenModel
+	err := conn(ctx, r.db).Where("hash = ?", hash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, session.ErrInvalidRefreshToken
+		}
+		return nil, nil, err
+	}
+
+	// The session stays locked until the transaction ends, so a revocation
+	// cannot slip in between the rotation and its save
+	var model persistence.SessionModel
+	err = conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&model, token.SessionID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, session.ErrSessionNotFound
+		}
+		return nil, nil, err
+	}
+	return persistence.ToSessionDomain(&model), persistence.ToRefreshTokenDomain(&token), nil
+}
+
+func (r *sessionRepository) RevokeAllByPersonID(ctx context.Context, personID int64, reason string, at time.Time) (int64, error) {
+	result := conn(ctx, r.db).Model(&persistence.SessionModel{}).
+		Where("person_id = ? AND revoked_at IS NULL", personID).
+		Updates(map[string]interface{}{"revoked_at": at, "revoked_reason": reason})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,39 @@
+package gormrepo
+
+import (
+	"context"
+
+	"github.com/cropflow/api/internal/domain/transaction"
+	"gorm.io/gorm"
+)
+
+// txKey is the context key of the transaction started by WithinTx
+type txKey struct{}
+
+type transactionManager struct {
+	db *gorm.DB
+}
+
+// NewTransactionManager creates a new transaction manager. The repositories of
+// this package run their statements in the transaction it puts in the context.
+func NewTransactionManager(db *gorm.DB) transaction.Manager {
+	return &transactionManager{db: db}
+}
+
+func (m *transactionManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the transaction carried by ctx, or db when there is none, bound
+// to ctx. Transactions a repository opens on it become savepoints of the one in ctx.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
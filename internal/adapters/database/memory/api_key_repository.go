@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/apikey"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+)
+
+type apiKeyRepository struct {
+	store *Store
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(store *Store) apikey.Repository {
+	return &apiKeyRepository{store: store}
+}
+
+func (r *apiKeyRepository) Save(ctx context.Context, k *apikey.Key) error {
+	model := persistence.ToAPIKeyModel(k)
+	// The farm IDs are stored apart from the key they were read from
+	model.FarmIDs = slices.Clone(model.FarmIDs)
+	err := r.store.write(ctx, func(d *tables) error {
+		if _, ok := d.persons.get(model.PersonID); !ok {
+			return errForeignKeyViolated
+		}
+		if d.apiKeys.exists(func(m *persistence.APIKeyModel) bool { return m.Prefix == model.Prefix && m.ID != model.ID }) {
+			return errDuplicatedKey
+		}
+		if model.ID == 0 {
+			model.ID = d.apiKeys.nextID()
+		}
+		touch(&model.CreatedAt, nil)
+		d.apiKeys.put(model.ID, *model)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	k.SetID(model.ID)
+	return nil
+}
+
+func (r *apiKeyRepository) FindByID(ctx context.Context, id int64) (*apikey.Key, error) {
+	var model persistence.APIKeyModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.apiKeys.get(id); !ok {
+			return apikey.ErrAPIKeyNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toAPIKey(model), nil
+}
+
+func (r *apiKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*apikey.Key, error) {
+	var model persistence.APIKeyModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.apiKeys.first(func(m *persistence.APIKeyModel) bool { return m.Prefix == prefix }); !ok {
+			return apikey.ErrInvalidAPIKey
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toAPIKey(model), nil
+}
+
+func (r *apiKeyRepository) List(ctx context.Context, filter apikey.Filter) ([]*apikey.Key, error) {
+	var models []persistence.APIKeyModel
+	err := r.store.read(ctx, func(d *tables) error {
+		models = d.apiKeys.where(func(m *persistence.APIKeyModel) bool {
+			return filter.OwnerID == 0 || m.PersonID == filter.OwnerID
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*apikey.Key, 0, len(models))
+	for _, m := range models {
+		keys = append(keys, toAPIKey(m))
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Touch(ctx context.Context, id int64, at time.Time) error {
+	return r.store.write(ctx, func(d *tables) error {
+		if model, ok := d.apiKeys.get(id); ok {
+			model.LastUsedAt = &at
+			d.apiKeys.put(id, model)
+		}
+		return nil
+	})
+}
+
+// toAPIKey maps a stored key to the entity, with farm IDs of its own
+func toAPIKey(model persistence.APIKeyModel) *apikey.Key {
+	model.FarmIDs = slices.Clone(model.FarmIDs)
+	return persistence.ToAPIKeyDomain(&model)
+}
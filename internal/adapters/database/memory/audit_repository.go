@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+)
+
+type auditRepository struct {
+	store *Store
+}
+
+// NewAuditRepository creates a new audit log repository. It lists the entries
+// the other repositories of the store record as they write.
+func NewAuditRepository(store *Store) audit.Repository {
+	return &auditRepository{store: store}
+}
+
+var auditSortColumns = map[string]sortColumn[persistence.AuditEntryModel]{
+	"id":         idColumn(func(m *persistence.AuditEntryModel) int64 { return m.ID }),
+	"occurredAt": timeColumn(func(m *persistence.AuditEntryModel) time.Time { return m.OccurredAt }),
+}
+
+func (r *auditRepository) List(ctx context.Context, filter audit.Filter, page query.Page) (query.Result[*audit.Entry], error) {
+	var models []persistence.AuditEntryModel
+	var next, prev string
+	err := r.store.read(ctx, func(d *tables) error {
+		rows := d.auditEntries.where(func(m *persistence.AuditEntryModel) bool {
+			return (filter.ActorID == 0 || (m.ActorID != nil && *m.ActorID == filter.ActorID)) &&
+				(filter.Action == "" || m.Action == filter.Action.String()) &&
+				(filter.ResourceType == "" || m.ResourceType == filter.ResourceType.String()) &&
+				(filter.ResourceID == 0 || m.ResourceID == filter.ResourceID) &&
+				(filter.RequestID == "" || m.RequestID == filter.RequestID) &&
+				(filter.From == nil || !m.OccurredAt.Before(*filter.From)) &&
+				(filter.To == nil || !m.OccurredAt.After(*filter.To))
+		})
+		var err error
+		models, next, prev, err = paginate(rows, page, auditSortColumns, func(m *persistence.AuditEntryModel) int64 { return m.ID })
+		return err
+	})
+	if err != nil {
+		return query.Result[*audit.Entry]{}, err
+	}
+
+	entries := make([]*audit.Entry, 0, len(models))
+	for i := range models {
+		e, err := persistence.ToAuditEntryDomain(&models[i])
+		if err != nil {
+			return query.Result[*audit.Entry]{}, err
+		}
+		entries = append(entries, e)
+	}
+	return query.Result[*audit.Entry]{Items: entries, NextCursor: next, PrevCursor: prev}, nil
+}
@@ -0,0 +1,287 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+)
+
+type cropRepository struct {
+	store *Store
+}
+
+// NewCropRepository creates a new crop repository
+func NewCropRepository(store *Store) crop.Repository {
+	return &cropRepository{store: store}
+}
+
+func (r *cropRepository) Save(ctx context.Context, c *crop.Crop) error {
+	model := persistence.ToCropModel(c)
+	err := r.store.write(ctx, func(d *tables) error {
+		before, err := snapshotByID(&d.crops, model.ID)
+		if err != nil {
+			return err
+		}
+		if _, ok := d.farms.get(model.FarmID); !ok {
+			return crop.ErrFarmNotFound
+		}
+		if model.ID == 0 {
+			model.ID = d.crops.nextID()
+		}
+		touch(&model.CreatedAt, &model.UpdatedAt)
+		d.crops.put(model.ID, *model)
+		after, err := persistence.Snapshot(model)
+		if err != nil {
+			return err
+		}
+		for _, t := range c.PendingTransitions() {
+			transition := persistence.ToCropTransitionModel(model.ID, t)
+			transition.ID = d.transitions.nextID()
+			touch(&transition.CreatedAt, nil)
+			d.transitions.put(transition.ID, *transition)
+		}
+		for _, h := range c.PendingHarvests() {
+			harvest := persistence.ToHarvestModel(h)
+			harvest.CropID = model.ID
+			harvest.ID = d.harvests.nextID()
+			touch(&harvest.CreatedAt, nil)
+			d.harvests.put(harvest.ID, *harvest)
+			h.SetID(harvest.ID)
+			if after[persistence.HarvestField(harvest.ID)], err = persistence.Snapshot(harvest); err != nil {
+				return err
+			}
+		}
+		for _, a := range c.PendingApplications() {
+			application := persistence.ToApplicationModel(a)
+			application.CropID = model.ID
+			if _, ok := d.fertilizers.get(application.FertilizerID); !ok {
+				return crop.ErrFertilizerNotFound
+			}
+			if application.OperatorID != nil {
+				if _, ok := d.persons.get(*application.OperatorID); !ok {
+					return errForeignKeyViolated
+				}
+			}
+			application.ID = d.applications.nextID()
+			touch(&application.CreatedAt, nil)
+			d.applications.put(application.ID, *application)
+			a.SetID(application.ID)
+			if after[persistence.ApplicationField(application.ID)], err = persistence.Snapshot(application); err != nil {
+				return err
+			}
+		}
+		return d.recordAudit(ctx, audit.ResourceCrop, model.ID, before, after)
+	})
+	if err != nil {
+		return err
+	}
+	c.SetID(model.ID)
+	c.ClearPendingTransitions()
+	c.ClearPendingHarvests()
+	c.ClearPendingApplications()
+	return nil
+}
+
+func (r *cropRepository) FindByID(ctx context.Context, id int64) (*crop.Crop, error) {
+	var model persistence.CropModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.crops.get(id); !ok {
+			return crop.ErrCropNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return persistence.ToCropDomain(&model), nil
+}
+
+// FindByIDForUpdate needs no lock of its own: transactions of the store already run one at a time
+func (r *cropRepository) FindByIDForUpdate(ctx context.Context, id int64) (*crop.Crop, error) {
+	return r.FindByID(ctx, id)
+}
+
+var cropSortColumns = map[string]sortColumn[persistence.CropModel]{
+	"id":          idColumn(func(m *persistence.CropModel) int64 { return m.ID }),
+	"name":        stringColumn(func(m *persistence.CropModel) string { return m.Name }),
+	"plantedArea": floatColumn(func(m *persistence.CropModel) float64 { return m.PlantedArea }),
+	"createdAt":   timeColumn(func(m *persistence.CropModel) time.Time { return m.CreatedAt }),
+}
+
+func (r *cropRepository) List(ctx context.Context, filter crop.Filter, page query.Page) (query.Result[*crop.Crop], error) {
+	var models []persistence.CropModel
+	var next, prev string
+	err := r.store.read(ctx, func(d *tables) error {
+		rows := d.crops.where(func(m *persistence.CropModel) bool {
+			return (filter.FarmID == 0 || m.FarmID == filter.FarmID) &&
+				(filter.Status == "" || m.Status == filter.Status.String()) &&
+				(filter.NamePrefix == "" || hasPrefix(m.Name, filter.NamePrefix)) &&
+				(filter.PlantedFrom == nil || (m.PlantedDate != nil && !m.PlantedDate.Before(*filter.PlantedFrom))) &&
+				(filter.PlantedTo == nil || (m.PlantedDate != nil && !m.PlantedDate.After(*filter.PlantedTo))) &&
+				(filter.MinArea == nil || m.PlantedArea >= *filter.MinArea) &&
+				(filter.MaxArea == nil || m.PlantedArea <= *filter.MaxArea) &&
+				(filter.MemberID == 0 || d.isMember(m.FarmID, filter.MemberID)) &&
+				(len(filter.FarmIDs) == 0 || slices.Contains(filter.FarmIDs, m.FarmID))
+		})
+		var err error
+		models, next, prev, err = paginate(rows, page, cropSortColumns, func(m *persistence.CropModel) int64 { return m.ID })
+		return err
+	})
+	if err != nil {
+		return query.Result[*crop.Crop]{}, err
+	}
+	return query.Result[*crop.Crop]{Items: toCrops(models), NextCursor: next, PrevCursor: prev}, nil
+}
+
+func (r *cropRepository) Delete(ctx context.Context, id int64) error {
+	return r.store.write(ctx, func(d *tables) error {
+		before, err := snapshotByID(&d.crops, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return crop.ErrCropNotFound
+		}
+
+		d.applications.deleteWhere(func(m *persistence.ApplicationModel) bool { return m.CropID == id })
+		d.transitions.deleteWhere(func(m *persistence.CropTransitionModel) bool { return m.CropID == id })
+		d.harvests.deleteWhere(func(m *persistence.HarvestModel) bool { return m.CropID == id })
+		d.crops.delete(id)
+		return d.recordAudit(ctx, audit.ResourceCrop, id, before, nil)
+	})
+}
+
+func (r *cropRepository) FindByFarmID(ctx context.Context, farmID int64) ([]*crop.Crop, error) {
+	var models []persistence.CropModel
+	err := r.store.read(ctx, func(d *tables) error {
+		models = d.crops.where(func(m *persistence.CropModel) bool { return m.FarmID == farmID })
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toCrops(models), nil
+}
+
+func (r *cropRepository) FindApplications(ctx context.Context, filter crop.ApplicationFilter) ([]*crop.Application, error) {
+	var models []persistence.ApplicationModel
+	err := r.store.read(ctx, func(d *tables) error {
+		models = d.applications.where(func(m *persistence.ApplicationModel) bool {
+			if filter.FarmID != 0 {
+				if c, ok := d.crops.get(m.CropID); !ok || c.FarmID != filter.FarmID {
+					return false
+				}
+			}
+			return (filter.CropID == 0 || m.CropID == filter.CropID) &&
+				(filter.AppliedFrom == nil || !m.AppliedAt.Before(*filter.AppliedFrom)) &&
+				(filter.AppliedTo == nil || !m.AppliedAt.After(*filter.AppliedTo))
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(models, func(i, j int) bool { return models[i].AppliedAt.Before(models[j].AppliedAt) })
+
+	applications := make([]*crop.Application, len(models))
+	for i := range models {
+		applications[i] = persistence.ToApplicationDomain(&models[i])
+	}
+	return applications, nil
+}
+
+func (r *cropRepository) FindFertilizersByCropID(ctx context.Context, cropID int64) ([]int64, error) {
+	var ids []int64
+	err := r.store.read(ctx, func(d *tables) error {
+		for _, m := range d.applications.where(func(m *persistence.ApplicationModel) bool { return m.CropID == cropID }) {
+			if !slices.Contains(ids, m.FertilizerID) {
+				ids = append(ids, m.FertilizerID)
+			}
+		}
+		return nil
+	})
+	slices.Sort(ids)
+	return ids, err
+}
+
+func (r *cropRepository) FindTransitions(ctx context.Context, cropID int64) ([]crop.Transition, error) {
+	var models []persistence.CropTransitionModel
+	err := r.store.read(ctx, func(d *tables) error {
+		models = d.transitions.where(func(m *persistence.CropTransitionModel) bool { return m.CropID == cropID })
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transitions := make([]crop.Transition, len(models))
+	for i := range models {
+		transitions[i] = persistence.ToCropTransitionDomain(&models[i])
+	}
+	return transitions, nil
+}
+
+func (r *cropRepository) FindHarvests(ctx context.Context, cropID int64) ([]*crop.Harvest, error) {
+	var models []persistence.HarvestModel
+	err := r.store.read(ctx, func(d *tables) error {
+		models = d.harvests.where(func(m *persistence.HarvestModel) bool { return m.CropID == cropID })
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(models, func(i, j int) bool { return models[i].HarvestedAt.Before(models[j].HarvestedAt) })
+
+	harvests := make([]*crop.Harvest, len(models))
+	for i := range models {
+		harvests[i] = persistence.ToHarvestDomain(&models[i])
+	}
+	return harvests, nil
+}
+
+func (r *cropRepository) FindHarvestByID(ctx context.Context, cropID, harvestID int64) (*crop.Harvest, error) {
+	var model persistence.HarvestModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.harvests.get(harvestID); !ok || model.CropID != cropID {
+			return crop.ErrHarvestNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return persistence.ToHarvestDomain(&model), nil
+}
+
+func (r *cropRepository) DeleteHarvest(ctx context.Context, cropID, harvestID int64) error {
+	return r.store.write(ctx, func(d *tables) error {
+		model, ok := d.harvests.get(harvestID)
+		if !ok || model.CropID != cropID {
+			return crop.ErrHarvestNotFound
+		}
+		before, err := persistence.Snapshot(&model)
+		if err != nil {
+			return err
+		}
+		d.harvests.delete(harvestID)
+
+		field := persistence.HarvestField(harvestID)
+		return d.recordAudit(ctx, audit.ResourceCrop, cropID, map[string]any{field: before}, map[string]any{})
+	})
+}
+
+func toCrops(models []persistence.CropModel) []*crop.Crop {
+	crops := make([]*crop.Crop, len(models))
+	for i := range models {
+		crops[i] = persistence.ToCropDomain(&models[i])
+	}
+	return crops
+}
@@ -0,0 +1,242 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/farm"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+)
+
+type farmRepository struct {
+	store *Store
+}
+
+// NewFarmRepository creates a new farm repository
+func NewFarmRepository(store *Store) farm.Repository {
+	return &farmRepository{store: store}
+}
+
+func (r *farmRepository) Save(ctx context.Context, f *farm.Farm) error {
+	model := persistence.ToFarmModel(f)
+	err := r.store.write(ctx, func(d *tables) error {
+		before, err := snapshotByID(&d.farms, model.ID)
+		if err != nil {
+			return err
+		}
+		if model.ID == 0 {
+			model.ID = d.farms.nextID()
+		}
+		touch(&model.CreatedAt, &model.UpdatedAt)
+		d.farms.put(model.ID, *model)
+		after, err := persistence.Snapshot(model)
+		if err != nil {
+			return err
+		}
+		for _, m := range f.PendingMembers() {
+			m.SetFarmID(model.ID)
+			member := persistence.ToFarmMemberModel(m)
+			key := memberKey{member.FarmID, member.PersonID}
+			if _, ok := d.members[key]; ok {
+				return farm.ErrMemberAlreadyExists
+			}
+			if _, ok := d.persons.get(member.PersonID); !ok {
+				return farm.ErrInvalidMemberPerson
+			}
+			touch(&member.CreatedAt, &member.UpdatedAt)
+			d.members[key] = *member
+			after[persistence.MemberField(m.PersonID())] = m.Role().String()
+		}
+		return d.recordAudit(ctx, audit.ResourceFarm, model.ID, before, after)
+	})
+	if err != nil {
+		return err
+	}
+	f.SetID(model.ID)
+	f.ClearPendingMembers()
+	return nil
+}
+
+func (r *farmRepository) FindByID(ctx context.Context, id int64) (*farm.Farm, error) {
+	var model persistence.FarmModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.farms.get(id); !ok {
+			return farm.ErrFarmNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return persistence.ToFarmDomain(&model)
+}
+
+var farmSortColumns = map[string]sortColumn[persistence.FarmModel]{
+	"id":        idColumn(func(m *persistence.FarmModel) int64 { return m.ID }),
+	"name":      stringColumn(func(m *persistence.FarmModel) string { return m.Name }),
+	"size":      floatColumn(func(m *persistence.FarmModel) float64 { return m.Size }),
+	"createdAt": timeColumn(func(m *persistence.FarmModel) time.Time { return m.CreatedAt }),
+}
+
+func (r *farmRepository) List(ctx context.Context, filter farm.Filter, page query.Page) (query.Result[*farm.Farm], error) {
+	var models []persistence.FarmModel
+	var next, prev string
+	err := r.store.read(ctx, func(d *tables) error {
+		rows := d.farms.where(func(m *persistence.FarmModel) bool {
+			return (filter.NamePrefix == "" || hasPrefix(m.Name, filter.NamePrefix)) &&
+				(filter.MinSize == nil || m.Size >= *filter.MinSize) &&
+				(filter.MaxSize == nil || m.Size <= *filter.MaxSize) &&
+				(filter.MemberID == 0 || d.isMember(m.ID, filter.MemberID)) &&
+				(len(filter.FarmIDs) == 0 || slices.Contains(filter.FarmIDs, m.ID))
+		})
+		var err error
+		models, next, prev, err = paginate(rows, page, farmSortColumns, func(m *persistence.FarmModel) int64 { return m.ID })
+		return err
+	})
+	if err != nil {
+		return query.Result[*farm.Farm]{}, err
+	}
+
+	farms := make([]*farm.Farm, 0, len(models))
+	for i := range models {
+		f, err := persistence.ToFarmDomain(&models[i])
+		if err != nil {
+			return query.Result[*farm.Farm]{}, err
+		}
+		farms = append(farms, f)
+	}
+	return query.Result[*farm.Farm]{Items: farms, NextCursor: next, PrevCursor: prev}, nil
+}
+
+func (r *farmRepository) Delete(ctx context.Context, id int64) error {
+	return r.store.write(ctx, func(d *tables) error {
+		before, err := snapshotByID(&d.farms, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return farm.ErrFarmNotFound
+		}
+		if d.crops.exists(func(m *persistence.CropModel) bool { return m.FarmID == id }) {
+			return farm.ErrFarmHasCrops
+		}
+
+		for key, m := range d.members {
+			if key.farmID == id {
+				before[persistence.MemberField(m.PersonID)] = m.Role
+				delete(d.members, key)
+			}
+		}
+		d.farms.delete(id)
+		return d.recordAudit(ctx, audit.ResourceFarm, id, before, nil)
+	})
+}
+
+func (r *farmRepository) ExistsByID(ctx context.Context, id int64) (bool, error) {
+	var exists bool
+	err := r.store.read(ctx, func(d *tables) error {
+		_, exists = d.farms.get(id)
+		return nil
+	})
+	return exists, err
+}
+
+func (r *farmRepository) FindMember(ctx context.Context, farmID, personID int64) (*farm.Member, error) {
+	var model persistence.FarmMemberModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.members[memberKey{farmID, personID}]; !ok {
+			return farm.ErrMemberNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return persistence.ToFarmMemberDomain(&model), nil
+}
+
+func (r *farmRepository) FindMembers(ctx context.Context, farmID int64) ([]*farm.Member, error) {
+	var models []persistence.FarmMemberModel
+	err := r.store.read(ctx, func(d *tables) error {
+		for key, m := range d.members {
+			if key.farmID == farmID {
+				models = append(models, m)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(models, func(i, j int) bool {
+		if c := models[i].CreatedAt.Compare(models[j].CreatedAt); c != 0 {
+			return c < 0
+		}
+		return models[i].PersonID < models[j].PersonID
+	})
+
+	members := make([]*farm.Member, 0, len(models))
+	for i := range models {
+		members = append(members, persistence.ToFarmMemberDomain(&models[i]))
+	}
+	return members, nil
+}
+
+// LockOwners needs no lock of its own: transactions of the store already run one at a time
+func (r *farmRepository) LockOwners(ctx context.Context, farmID int64) ([]int64, error) {
+	var ids []int64
+	err := r.store.read(ctx, func(d *tables) error {
+		for key, m := range d.members {
+			if key.farmID == farmID && m.Role == farm.MemberOwner.String() {
+				ids = append(ids, key.personID)
+			}
+		}
+		return nil
+	})
+	slices.Sort(ids)
+	return ids, err
+}
+
+func (r *farmRepository) UpdateMember(ctx context.Context, m *farm.Member) error {
+	return r.store.write(ctx, func(d *tables) error {
+		key := memberKey{m.FarmID(), m.PersonID()}
+		model, ok := d.members[key]
+		if !ok {
+			return farm.ErrMemberNotFound
+		}
+		before := model.Role
+		model.Role = m.Role().String()
+		model.UpdatedAt = m.UpdatedAt()
+		d.members[key] = model
+
+		field := persistence.MemberField(m.PersonID())
+		return d.recordAudit(ctx, audit.ResourceFarm, m.FarmID(),
+			map[string]any{field: before}, map[string]any{field: model.Role})
+	})
+}
+
+func (r *farmRepository) DeleteMember(ctx context.Context, farmID, personID int64) error {
+	return r.store.write(ctx, func(d *tables) error {
+		key := memberKey{farmID, personID}
+		model, ok := d.members[key]
+		if !ok {
+			return farm.ErrMemberNotFound
+		}
+		delete(d.members, key)
+
+		field := persistence.MemberField(personID)
+		return d.recordAudit(ctx, audit.ResourceFarm, farmID, map[string]any{field: model.Role}, map[string]any{})
+	})
+}
+
+// isMember reports whether a person is a member of a farm
+func (d *tables) isMember(farmID, personID int64) bool {
+	_, ok := d.members[memberKey{farmID, personID}]
+	return ok
+}
@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/fertilizer"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+)
+
+type fertilizerRepository struct {
+	store *Store
+}
+
+// NewFertilizerRepository creates a new fertilizer repository
+func NewFertilizerRepository(store *Store) fertilizer.Repository {
+	return &fertilizerRepository{store: store}
+}
+
+func (r *fertilizerRepository) Save(ctx context.Context, f *fertilizer.Fertilizer) error {
+	model := persistence.ToFertilizerModel(f)
+	err := r.store.write(ctx, func(d *tables) error {
+		before, err := snapshotByID(&d.fertilizers, model.ID)
+		if err != nil {
+			return err
+		}
+		if model.ID == 0 {
+			model.ID = d.fertilizers.nextID()
+		}
+		touch(&model.CreatedAt, &model.UpdatedAt)
+		d.fertilizers.put(model.ID, *model)
+		after, err := persistence.Snapshot(model)
+		if err != nil {
+			return err
+		}
+		return d.recordAudit(ctx, audit.ResourceFertilizer, model.ID, before, after)
+	})
+	if err != nil {
+		return err
+	}
+	f.SetID(model.ID)
+	return nil
+}
+
+func (r *fertilizerRepository) FindByID(ctx context.Context, id int64) (*fertilizer.Fertilizer, error) {
+	var model persistence.FertilizerModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.fertilizers.get(id); !ok {
+			return fertilizer.ErrFertilizerNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return persistence.ToFertilizerDomain(&model), nil
+}
+
+func (r *fertilizerRepository) FindByIDs(ctx context.Context, ids []int64) ([]*fertilizer.Fertilizer, error) {
+	var models []persistence.FertilizerModel
+	err := r.store.read(ctx, func(d *tables) error {
+		models = d.fertilizers.where(func(m *persistence.FertilizerModel) bool { return slices.Contains(ids, m.ID) })
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toFertilizers(models), nil
+}
+
+var fertilizerSortColumns = map[string]sortColumn[persistence.FertilizerModel]{
+	"id":        idColumn(func(m *persistence.FertilizerModel) int64 { return m.ID }),
+	"name":      stringColumn(func(m *persistence.FertilizerModel) string { return m.Name }),
+	"brand":     stringColumn(func(m *persistence.FertilizerModel) string { return m.Brand }),
+	"createdAt": timeColumn(func(m *persistence.FertilizerModel) time.Time { return m.CreatedAt }),
+}
+
+func (r *fertilizerRepository) List(ctx context.Context, filter fertilizer.Filter, page query.Page) (query.Result[*fertilizer.Fertilizer], error) {
+	var models []persistence.FertilizerModel
+	var next, prev string
+	err := r.store.read(ctx, func(d *tables) error {
+		rows := d.fertilizers.where(func(m *persistence.FertilizerModel) bool {
+			return (filter.NamePrefix == "" || hasPrefix(m.Name, filter.NamePrefix)) &&
+				(filter.Brand == "" || m.Brand == filter.Brand)
+		})
+		var err error
+		models, next, prev, err = paginate(rows, page, fertilizerSortColumns, func(m *persistence.FertilizerModel) int64 { return m.ID })
+		return err
+	})
+	if err != nil {
+		return query.Result[*fertilizer.Fertilizer]{}, err
+	}
+	return query.Result[*fertilizer.Fertilizer]{Items: toFertilizers(models), NextCursor: next, PrevCursor: prev}, nil
+}
+
+func (r *fertilizerRepository) Delete(ctx context.Context, id int64) error {
+	return r.store.write(ctx, func(d *tables) error {
+		before, err := snapshotByID(&d.fertilizers, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return fertilizer.ErrFertilizerNotFound
+		}
+		if d.applications.exists(func(m *persistence.ApplicationModel) bool { return m.FertilizerID == id }) {
+			return fertilizer.ErrFertilizerInUse
+		}
+
+		d.fertilizers.delete(id)
+		return d.recordAudit(ctx, audit.ResourceFertilizer, id, before, nil)
+	})
+}
+
+func (r *fertilizerRepository) ExistsByID(ctx context.Context, id int64) (bool, error) {
+	var exists bool
+	err := r.store.read(ctx, func(d *tables) error {
+		_, exists = d.fertilizers.get(id)
+		return nil
+	})
+	return exists, err
+}
+
+func toFertilizers(models []persistence.FertilizerModel) []*fertilizer.Fertilizer {
+	fertilizers := make([]*fertilizer.Fertilizer, len(models))
+	for i := range models {
+		fertilizers[i] = persistence.ToFertilizerDomain(&models[i])
+	}
+	return fertilizers
+}
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/cropflow/api/internal/domain/lockout"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+)
+
+type lockoutRepository struct {
+	store *Store
+}
+
+// NewLockoutRepository creates a new failed login repository
+func NewLockoutRepository(store *Store) lockout.Repository {
+	return &lockoutRepository{store: store}
+}
+
+func (r *lockoutRepository) Find(ctx context.Context, key string) (lockout.Status, error) {
+	var model persistence.LoginAttemptModel
+	var ok bool
+	err := r.store.read(ctx, func(d *tables) error {
+		model, ok = d.loginAttempts[key]
+		return nil
+	})
+	if err != nil || !ok {
+		return lockout.Status{}, err
+	}
+	return persistence.ToLoginAttemptDomain(&model), nil
+}
+
+func (r *lockoutRepository) Update(ctx context.Context, key string, fn func(lockout.Status) lockout.Status) (lockout.Status, error) {
+	var status lockout.Status
+	err := r.store.write(ctx, func(d *tables) error {
+		model := d.loginAttempts[key]
+		status = fn(persistence.ToLoginAttemptDomain(&model))
+		d.loginAttempts[key] = *persistence.ToLoginAttemptModel(key, status)
+		return nil
+	})
+	return status, err
+}
+
+func (r *lockoutRepository) Delete(ctx context.Context, key string) error {
+	return r.store.write(ctx, func(d *tables) error {
+		delete(d.loginAttempts, key)
+		return nil
+	})
+}
@@ -0,0 +1,28 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/cropflow/api/internal/adapters/database/memory"
+	"github.com/cropflow/api/internal/adapters/database/repotest"
+)
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		store := memory.NewStore()
+		return repotest.Repositories{
+			Farms:          memory.NewFarmRepository(store),
+			Crops:          memory.NewCropRepository(store),
+			Fertilizers:    memory.NewFertilizerRepository(store),
+			Persons:        memory.NewPersonRepository(store),
+			Sessions:       memory.NewSessionRepository(store),
+			MFA:            memory.NewMFARepository(store),
+			Lockouts:       memory.NewLockoutRepository(store),
+			PasswordResets: memory.NewPasswordResetRepository(store),
+			APIKeys:        memory.NewAPIKeyRepository(store),
+			OIDC:           memory.NewOIDCRepository(store),
+			Audit:          memory.NewAuditRepository(store),
+			Tx:             memory.NewTransactionManager(store),
+		}
+	})
+}
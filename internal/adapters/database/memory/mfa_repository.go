@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/cropflow/api/internal/domain/mfa"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+)
+
+type mfaRepository struct {
+	store *Store
+}
+
+// NewMFARepository creates a new two-factor repository
+func NewMFARepository(store *Store) mfa.Repository {
+	return &mfaRepository{store: store}
+}
+
+func (r *mfaRepository) SaveEnrollment(ctx context.Context, e *mfa.Enrollment) error {
+	model := persistence.ToTOTPEnrollmentModel(e)
+	err := r.store.write(ctx, func(d *tables) error {
+		if _, ok := d.persons.get(model.PersonID); !ok {
+			return errForeignKeyViolated
+		}
+		if d.enrollments.exists(func(m *persistence.TOTPEnrollmentModel) bool { return m.PersonID == model.PersonID && m.ID != model.ID }) {
+			return errDuplicatedKey
+		}
+		if model.ID == 0 {
+			model.ID = d.enrollments.nextID()
+		}
+		touch(&model.CreatedAt, &model.UpdatedAt)
+		d.enrollments.put(model.ID, *model)
+		for _, c := range e.UsedRecoveryCodes() {
+			// Only one of two concurrent logins with the same code may win
+			code, ok := d.recoveryCodes.get(c.ID())
+			if !ok || code.UsedAt != nil {
+				return mfa.ErrInvalidCode
+			}
+			code.UsedAt = c.UsedAt()
+			d.recoveryCodes.put(code.ID, code)
+		}
+		if pending := e.PendingRecoveryCodes(); len(pending) > 0 {
+			// New recovery codes replace all previous ones
+			d.recoveryCodes.deleteWhere(func(m *persistence.RecoveryCodeModel) bool { return m.EnrollmentID == model.ID })
+			for _, c := range pending {
+				c.SetEnrollmentID(model.ID)
+				code := persistence.ToRecoveryCodeModel(c)
+				code.ID = d.recoveryCodes.nextID()
+				touch(&code.CreatedAt, nil)
+				d.recoveryCodes.put(code.ID, *code)
+				c.SetID(code.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	e.SetID(model.ID)
+	e.ClearPendingRecoveryCodes()
+	return nil
+}
+
+func (r *mfaRepository) FindEnrollmentByPersonID(ctx context.Context, personID int64) (*mfa.Enrollment, error) {
+	var model persistence.TOTPEnrollmentModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.enrollments.first(func(m *persistence.TOTPEnrollmentModel) bool { return m.PersonID == personID }); !ok {
+			return mfa.ErrEnrollmentNotFound
+		}
+		model.RecoveryCodes = d.recoveryCodes.where(func(m *persistence.RecoveryCodeModel) bool {
+			return m.EnrollmentID == model.ID && m.UsedAt == nil
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return persistence.ToTOTPEnrollmentDomain(&model), nil
+}
+
+func (r *mfaRepository) DeleteEnrollment(ctx context.Context, personID int64) error {
+	return r.store.write(ctx, func(d *tables) error {
+		model, ok := d.enrollments.first(func(m *persistence.TOTPEnrollmentModel) bool { return m.PersonID == personID })
+		if !ok {
+			return mfa.ErrEnrollmentNotFound
+		}
+		d.recoveryCodes.deleteWhere(func(m *persistence.RecoveryCodeModel) bool { return m.EnrollmentID == model.ID })
+		d.enrollments.delete(model.ID)
+		return nil
+	})
+}
+
+func (r *mfaRepository) SaveChallenge(ctx context.Context, c *mfa.Challenge) error {
+	model := persistence.ToLoginChallengeModel(c)
+	err := r.store.write(ctx, func(d *tables) error {
+		if _, ok := d.persons.get(model.PersonID); !ok {
+			return errForeignKeyViolated
+		}
+		if d.challenges.exists(func(m *persistence.LoginChallengeModel) bool { return m.Hash == model.Hash && m.ID != model.ID }) {
+			return errDuplicatedKey
+		}
+		if model.ID == 0 {
+			model.ID = d.challenges.nextID()
+		}
+		touch(&model.CreatedAt, nil)
+		d.challenges.put(model.ID, *model)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	c.SetID(model.ID)
+	return nil
+}
+
+func (r *mfaRepository) FindChallenge(ctx context.Context, hash string) (*mfa.Challenge, error) {
+	var model persistence.LoginChallengeModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.challenges.first(func(m *persistence.LoginChallengeModel) bool { return m.Hash == hash }); !ok {
+			return mfa.ErrInvalidChallenge
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return persistence.ToLoginChallengeDomain(&model), nil
+}
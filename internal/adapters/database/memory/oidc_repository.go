@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/cropflow/api/internal/domain/oidc"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+)
+
+type oidcRepository struct {
+	store *Store
+}
+
+// NewOIDCRepository creates a new OIDC login repository
+func NewOIDCRepository(store *Store) oidc.Repository {
+	return &oidcRepository{store: store}
+}
+
+func (r *oidcRepository) SaveLoginRequest(ctx context.Context, request *oidc.LoginRequest) error {
+	if request.ID() == 0 {
+		model := persistence.ToOIDCLoginRequestModel(request)
+		err := r.store.write(ctx, func(d *tables) error {
+			if d.oidcRequests.exists(func(m *persistence.OIDCLoginRequestModel) bool { return m.StateHash == model.StateHash }) {
+				return errDuplicatedKey
+			}
+			model.ID = d.oidcRequests.nextID()
+			touch(&model.CreatedAt, nil)
+			d.oidcRequests.put(model.ID, *model)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		request.SetID(model.ID)
+		return nil
+	}
+
+	// Only one of two concurrent callbacks with the same state may win
+	return r.store.write(ctx, func(d *tables) error {
+		model, ok := d.oidcRequests.get(request.ID())
+		if !ok || model.ConsumedAt != nil {
+			return oidc.ErrLoginRequestConsumed
+		}
+		model.ConsumedAt = request.ConsumedAt()
+		d.oidcRequests.put(model.ID, model)
+		return nil
+	})
+}
+
+func (r *oidcRepository) FindLoginRequest(ctx context.Context, stateHash string) (*oidc.LoginRequest, error) {
+	var model persistence.OIDCLoginRequestModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.oidcRequests.first(func(m *persistence.OIDCLoginRequestModel) bool { return m.StateHash == stateHash }); !ok {
+			return oidc.ErrInvalidState
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return persistence.ToOIDCLoginRequestDomain(&model), nil
+}
+
+func (r *oidcRepository) SaveLink(ctx context.Context, link *oidc.Link) error {
+	model := persistence.ToOIDCLinkModel(link)
+	err := r.store.write(ctx, func(d *tables) error {
+		if _, ok := d.persons.get(model.PersonID); !ok {
+			return errForeignKeyViolated
+		}
+		if d.oidcLinks.exists(func(m *persistence.OIDCLinkModel) bool { return m.Issuer == model.Issuer && m.Subject == model.Subject }) {
+			return errDuplicatedKey
+		}
+		model.ID = d.oidcLinks.nextID()
+		touch(&model.CreatedAt, nil)
+		d.oidcLinks.put(model.ID, *model)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	link.SetID(model.ID)
+	return nil
+}
+
+func (r *oidcRepository) FindLink(ctx context.Context, issuer, subject string) (*oidc.Link, error) {
+	var model persistence.OIDCLinkModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.oidcLinks.first(func(m *persistence.OIDCLinkModel) bool { return m.Issuer == issuer && m.Subject == subject }); !ok {
+			return oidc.ErrLinkNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return persistence.ToOIDCLinkDomain(&model), nil
+}
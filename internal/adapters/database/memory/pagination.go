@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"cmp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/query"
+)
+
+// sortColumn describes how an API sort field orders the rows of a table
+type sortColumn[M any] struct {
+	compare func(a, b *M) int
+	value   func(m *M) string
+	// compareTo compares a row with the encoded value of a cursor
+	compareTo func(m *M, value string) (int, error)
+}
+
+func stringColumn[M any](key func(m *M) string) sortColumn[M] {
+	return sortColumn[M]{
+		compare:   func(a, b *M) int { return cmp.Compare(key(a), key(b)) },
+		value:     key,
+		compareTo: func(m *M, v string) (int, error) { return cmp.Compare(key(m), v), nil },
+	}
+}
+
+func floatColumn[M any](key func(m *M) float64) sortColumn[M] {
+	return sortColumn[M]{
+		compare: func(a, b *M) int { return cmp.Compare(key(a), key(b)) },
+		value:   func(m *M) string { return strconv.FormatFloat(key(m), 'g', -1, 64) },
+		compareTo: func(m *M, v string) (int, error) {
+			f, err := strconv.ParseFloat(v, 64)
+			return cmp.Compare(key(m), f), err
+		},
+	}
+}
+
+func timeColumn[M any](key func(m *M) time.Time) sortColumn[M] {
+	return sortColumn[M]{
+		compare: func(a, b *M) int { return key(a).Compare(key(b)) },
+		value:   func(m *M) string { return key(m).UTC().Format(time.RFC3339Nano) },
+		compareTo: func(m *M, v string) (int, error) {
+			t, err := time.Parse(time.RFC3339Nano, v)
+			return key(m).Compare(t), err
+		},
+	}
+}
+
+func idColumn[M any](key func(m *M) int64) sortColumn[M] {
+	return sortColumn[M]{
+		compare: func(a, b *M) int { return cmp.Compare(key(a), key(b)) },
+		value:   func(m *M) string { return strconv.FormatInt(key(m), 10) },
+		compareTo: func(m *M, v string) (int, error) {
+			id, err := strconv.ParseInt(v, 10, 64)
+			return cmp.Compare(key(m), id), err
+		},
+	}
+}
+
+// paginate returns a page of rows ordered by the requested column with the
+// primary key as tie-breaker, along with the next/prev cursors, the same way
+// the database repositories page through a keyset
+func paginate[M any](rows []M, page query.Page, columns map[string]sortColumn[M], id func(m *M) int64) ([]M, string, string, error) {
+	col, ok := columns[page.Sort.Field]
+	if !ok {
+		return nil, "", "", query.ErrInvalidSort
+	}
+
+	backward := page.Cursor != nil && page.Cursor.Backward
+	// Walking back to the previous page scans the ordering in reverse
+	scanDesc := (page.Sort.Direction == query.Desc) != backward
+	order := func(a, b *M) int {
+		c := col.compare(a, b)
+		if c == 0 {
+			c = cmp.Compare(id(a), id(b))
+		}
+		if scanDesc {
+			return -c
+		}
+		return c
+	}
+
+	if page.Cursor != nil {
+		remaining := rows[:0:0]
+		for i := range rows {
+			c, err := col.compareTo(&rows[i], page.Cursor.Value)
+			if err != nil {
+				return nil, "", "", query.ErrInvalidCursor
+			}
+			if c == 0 {
+				c = cmp.Compare(id(&rows[i]), page.Cursor.ID)
+			}
+			if (scanDesc && c < 0) || (!scanDesc && c > 0) {
+				remaining = append(remaining, rows[i])
+			}
+		}
+		rows = remaining
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return order(&rows[i], &rows[j]) < 0 })
+
+	hasMore := len(rows) > page.Limit
+	if hasMore {
+		rows = rows[:page.Limit]
+	}
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	if len(rows) == 0 {
+		return rows, "", "", nil
+	}
+
+	cursorAt := func(m *M, backward bool) string {
+		return query.Cursor{Sort: page.Sort, Value: col.value(m), ID: id(m), Backward: backward}.Encode()
+	}
+
+	first, last := &rows[0], &rows[len(rows)-1]
+	var next, prev string
+	if backward {
+		next = cursorAt(last, false)
+		if hasMore {
+			prev = cursorAt(first, true)
+		}
+	} else {
+		if hasMore {
+			next = cursorAt(last, false)
+		}
+		if page.Cursor != nil {
+			prev = cursorAt(first, true)
+		}
+	}
+
+	return rows, next, prev, nil
+}
+
+// hasPrefix matches values starting with prefix, ignoring case as LIKE does
+// under the default MySQL collation
+func hasPrefix(value, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(value), strings.ToLower(prefix))
+}
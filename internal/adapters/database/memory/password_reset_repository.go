@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/passwordreset"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+)
+
+type passwordResetRepository struct {
+	store *Store
+}
+
+// NewPasswordResetRepository creates a new password reset token repository
+func NewPasswordResetRepository(store *Store) passwordreset.Repository {
+	return &passwordResetRepository{store: store}
+}
+
+func (r *passwordResetRepository) Save(ctx context.Context, t *passwordreset.Token) error {
+	if t.ID() == 0 {
+		model := persistence.ToPasswordResetTokenModel(t)
+		err := r.store.write(ctx, func(d *tables) error {
+			if _, ok := d.persons.get(model.PersonID); !ok {
+				return errForeignKeyViolated
+			}
+			if d.resetTokens.exists(func(m *persistence.PasswordResetTokenModel) bool { return m.Hash == model.Hash }) {
+				return errDuplicatedKey
+			}
+			model.ID = d.resetTokens.nextID()
+			touch(&model.CreatedAt, nil)
+			d.resetTokens.put(model.ID, *model)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		t.SetID(model.ID)
+		return nil
+	}
+
+	// Only one of two concurrent resets with the same token may win
+	return r.store.write(ctx, func(d *tables) error {
+		model, ok := d.resetTokens.get(t.ID())
+		if !ok || model.UsedAt != nil {
+			return passwordreset.ErrResetTokenUsed
+		}
+		model.UsedAt = t.UsedAt()
+		d.resetTokens.put(model.ID, model)
+		return nil
+	})
+}
+
+func (r *passwordResetRepository) FindByHash(ctx context.Context, hash string) (*passwordreset.Token, error) {
+	var model persistence.PasswordResetTokenModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.resetTokens.first(func(m *persistence.PasswordResetTokenModel) bool { return m.Hash == hash }); !ok {
+			return passwordreset.ErrInvalidResetToken
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return persistence.ToPasswordResetTokenDomain(&model), nil
+}
+
+func (r *passwordResetRepository) InvalidateAllByPersonID(ctx context.Context, personID int64, at time.Time) error {
+	return r.store.write(ctx, func(d *tables) error {
+		for _, m := range d.resetTokens.where(func(m *persistence.PasswordResetTokenModel) bool { return m.PersonID == personID && m.UsedAt == nil }) {
+			m.UsedAt = &at
+			d.resetTokens.put(m.ID, m)
+		}
+		return nil
+	})
+}
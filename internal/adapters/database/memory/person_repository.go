@@ -0,0 +1,268 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+)
+
+type personRepository struct {
+	store *Store
+}
+
+// NewPersonRepository creates a new person repository
+func NewPersonRepository(store *Store) person.Repository {
+	return &personRepository{store: store}
+}
+
+func (r *personRepository) Save(ctx context.Context, p *person.Person) error {
+	err := r.store.write(ctx, func(d *tables) error {
+		before, err := snapshotByID(&d.persons, p.ID())
+		if err != nil {
+			return err
+		}
+		return d.savePerson(ctx, p, before)
+	})
+	if err != nil {
+		return err
+	}
+	p.ClearPendingRoleChanges()
+	return nil
+}
+
+// savePerson writes the person and the role changes made since it was loaded,
+// auditing the difference with before, the stored row
+func (d *tables) savePerson(ctx context.Context, p *person.Person, before map[string]any) error {
+	model := persistence.ToPersonModel(p)
+	if d.persons.exists(func(m *persistence.PersonModel) bool { return m.Username == model.Username && m.ID != model.ID }) {
+		return person.ErrUsernameAlreadyExists
+	}
+	if model.ID == 0 {
+		model.ID = d.persons.nextID()
+	}
+	touch(&model.CreatedAt, &model.UpdatedAt)
+	d.persons.put(model.ID, *model)
+	p.SetID(model.ID)
+	for _, c := range p.PendingRoleChanges() {
+		change := persistence.ToRoleChangeModel(model.ID, c)
+		change.ID = d.roleChanges.nextID()
+		d.roleChanges.put(change.ID, *change)
+	}
+
+	after, err := persistence.Snapshot(model)
+	if err != nil {
+		return err
+	}
+	return d.recordAudit(ctx, audit.ResourcePerson, model.ID, before, after)
+}
+
+func (r *personRepository) FindByID(ctx context.Context, id int64) (*person.Person, error) {
+	var model persistence.PersonModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.persons.get(id); !ok {
+			return person.ErrPersonNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return persistence.ToPersonDomain(&model)
+}
+
+func (r *personRepository) FindByUsername(ctx context.Context, username string) (*person.Person, error) {
+	var model persistence.PersonModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.persons.first(func(m *persistence.PersonModel) bool { return m.Username == username }); !ok {
+			return person.ErrPersonNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return persistence.ToPersonDomain(&model)
+}
+
+func (r *personRepository) Update(ctx context.Context, id int64, fn func(*person.Person) error) (*person.Person, error) {
+	var p *person.Person
+	err := r.store.write(ctx, func(d *tables) error {
+		var before map[string]any
+		var err error
+		if p, before, err = d.findPerson(id); err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+		return d.savePerson(ctx, p, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.ClearPendingRoleChanges()
+	return p, nil
+}
+
+func (r *personRepository) UpdateRole(ctx context.Context, id int64, fn func(*person.Person, int) error) (*person.Person, error) {
+	var p *person.Person
+	err := r.store.write(ctx, func(d *tables) error {
+		admins := d.admins()
+		var before map[string]any
+		var err error
+		if p, before, err = d.findPerson(id); err != nil {
+			return err
+		}
+		if err := fn(p, len(admins)); err != nil {
+			return err
+		}
+		return d.savePerson(ctx, p, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.ClearPendingRoleChanges()
+	return p, nil
+}
+
+// findPerson loads the person along with the snapshot of the row its changes
+// are audited against
+func (d *tables) findPerson(id int64) (*person.Person, map[string]any, error) {
+	model, ok := d.persons.get(id)
+	if !ok {
+		return nil, nil, person.ErrPersonNotFound
+	}
+	before, err := persistence.Snapshot(&model)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := persistence.ToPersonDomain(&model)
+	return p, before, err
+}
+
+// admins returns the IDs of the admins
+func (d *tables) admins() []int64 {
+	var ids []int64
+	for _, m := range d.persons.where(func(m *persistence.PersonModel) bool { return m.Role == person.RoleAdmin.String() }) {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+var personSortColumns = map[string]sortColumn[persistence.PersonModel]{
+	"id":        idColumn(func(m *persistence.PersonModel) int64 { return m.ID }),
+	"username":  stringColumn(func(m *persistence.PersonModel) string { return m.Username }),
+	"createdAt": timeColumn(func(m *persistence.PersonModel) time.Time { return m.CreatedAt }),
+}
+
+func (r *personRepository) List(ctx context.Context, filter person.Filter, page query.Page) (query.Result[*person.Person], error) {
+	var models []persistence.PersonModel
+	var next, prev string
+	err := r.store.read(ctx, func(d *tables) error {
+		rows := d.persons.where(func(m *persistence.PersonModel) bool {
+			return (filter.UsernamePrefix == "" || hasPrefix(m.Username, filter.UsernamePrefix)) &&
+				(filter.Role == "" || m.Role == filter.Role.String())
+		})
+		var err error
+		models, next, prev, err = paginate(rows, page, personSortColumns, func(m *persistence.PersonModel) int64 { return m.ID })
+		return err
+	})
+	if err != nil {
+		return query.Result[*person.Person]{}, err
+	}
+
+	persons := make([]*person.Person, 0, len(models))
+	for i := range models {
+		p, err := persistence.ToPersonDomain(&models[i])
+		if err != nil {
+			return query.Result[*person.Person]{}, err
+		}
+		persons = append(persons, p)
+	}
+	return query.Result[*person.Person]{Items: persons, NextCursor: next, PrevCursor: prev}, nil
+}
+
+func (r *personRepository) Delete(ctx context.Context, id int64) error {
+	return r.store.write(ctx, func(d *tables) error {
+		admins := d.admins()
+		if len(admins) == 1 && admins[0] == id {
+			return person.ErrLastAdmin
+		}
+		before, err := snapshotByID(&d.persons, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return person.ErrPersonNotFound
+		}
+		if err := d.deletePersonRows(id); err != nil {
+			return err
+		}
+		d.persons.delete(id)
+		return d.recordAudit(ctx, audit.ResourcePerson, id, before, nil)
+	})
+}
+
+// deletePersonRows applies the ON DELETE actions of the foreign keys to
+// person: the rows of the person are deleted and the applications they
+// operated lose their operator
+func (d *tables) deletePersonRows(id int64) error {
+	for key := range d.members {
+		if key.personID == id {
+			delete(d.members, key)
+		}
+	}
+	d.roleChanges.deleteWhere(func(m *persistence.RoleChangeModel) bool { return m.PersonID == id })
+	for _, s := range d.sessions.where(func(m *persistence.SessionModel) bool { return m.PersonID == id }) {
+		d.refreshTokens.deleteWhere(func(m *persistence.RefreshTokenModel) bool { return m.SessionID == s.ID })
+		d.sessions.delete(s.ID)
+	}
+	for _, e := range d.enrollments.where(func(m *persistence.TOTPEnrollmentModel) bool { return m.PersonID == id }) {
+		// Recovery codes are not deleted along with their enrolment
+		if d.recoveryCodes.exists(func(m *persistence.RecoveryCodeModel) bool { return m.EnrollmentID == e.ID }) {
+			return errForeignKeyViolated
+		}
+		d.enrollments.delete(e.ID)
+	}
+	d.challenges.deleteWhere(func(m *persistence.LoginChallengeModel) bool { return m.PersonID == id })
+	d.resetTokens.deleteWhere(func(m *persistence.PasswordResetTokenModel) bool { return m.PersonID == id })
+	d.apiKeys.deleteWhere(func(m *persistence.APIKeyModel) bool { return m.PersonID == id })
+	d.oidcLinks.deleteWhere(func(m *persistence.OIDCLinkModel) bool { return m.PersonID == id })
+	for _, a := range d.applications.where(func(m *persistence.ApplicationModel) bool { return m.OperatorID != nil && *m.OperatorID == id }) {
+		a.OperatorID = nil
+		d.applications.put(a.ID, a)
+	}
+	return nil
+}
+
+func (r *personRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
+	var exists bool
+	err := r.store.read(ctx, func(d *tables) error {
+		exists = d.persons.exists(func(m *persistence.PersonModel) bool { return m.Username == username })
+		return nil
+	})
+	return exists, err
+}
+
+func (r *personRepository) FindRoleChanges(ctx context.Context, personID int64) ([]person.RoleChange, error) {
+	var models []persistence.RoleChangeModel
+	err := r.store.read(ctx, func(d *tables) error {
+		models = d.roleChanges.where(func(m *persistence.RoleChangeModel) bool { return m.PersonID == personID })
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]person.RoleChange, len(models))
+	for i := range models {
+		changes[i] = persistence.ToRoleChangeDomain(&models[i])
+	}
+	return changes, nil
+}
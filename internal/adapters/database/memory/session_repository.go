@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/session"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+)
+
+type sessionRepository struct {
+	store *Store
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(store *Store) session.Repository {
+	return &sessionRepository{store: store}
+}
+
+func (r *sessionRepository) Save(ctx context.Context, s *session.Session) error {
+	model := persistence.ToSessionModel(s)
+	err := r.store.write(ctx, func(d *tables) error {
+		if _, ok := d.persons.get(model.PersonID); !ok {
+			return errForeignKeyViolated
+		}
+		if model.ID == 0 {
+			model.ID = d.sessions.nextID()
+		} else if stored, ok := d.sessions.get(model.ID); ok && stored.RevokedAt != nil {
+			// A revocation keeps the first reason and cannot be undone
+			if model.RevokedAt == nil {
+				return session.ErrSessionRevoked
+			}
+			model.RevokedAt, model.RevokedReason = stored.RevokedAt, stored.RevokedReason
+		}
+		touch(&model.CreatedAt, nil)
+		d.sessions.put(model.ID, *model)
+		for _, t := range s.UsedTokens() {
+			// Only one of two concurrent refreshes with the same token may win
+			token, ok := d.refreshTokens.get(t.ID())
+			if !ok || token.UsedAt != nil {
+				return session.ErrRefreshTokenReused
+			}
+			token.UsedAt = t.UsedAt()
+			d.refreshTokens.put(token.ID, token)
+		}
+		for _, t := range s.PendingTokens() {
+			t.SetSessionID(model.ID)
+			token := persistence.ToRefreshTokenModel(t)
+			if d.refreshTokens.exists(func(m *persistence.RefreshTokenModel) bool { return m.Hash == token.Hash }) {
+				return errDuplicatedKey
+			}
+			token.ID = d.refreshTokens.nextID()
+			touch(&token.CreatedAt, nil)
+			d.refreshTokens.put(token.ID, *token)
+			t.SetID(token.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.SetID(model.ID)
+	s.ClearPendingTokens()
+	return nil
+}
+
+func (r *sessionRepository) FindByID(ctx context.Context, id int64) (*session.Session, error) {
+	var model persistence.SessionModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if model, ok = d.sessions.get(id); !ok {
+			return session.ErrSessionNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return persistence.ToSessionDomain(&model), nil
+}
+
+func (r *sessionRepository) FindByRefreshToken(ctx context.Context, hash string) (*session.Session, *session.RefreshToken, error) {
+	var model persistence.SessionModel
+	var token persistence.RefreshTokenModel
+	err := r.store.read(ctx, func(d *tables) error {
+		var ok bool
+		if token, ok = d.refreshTokens.first(func(m *persistence.RefreshTokenModel) bool { return m.Hash == hash }); !ok {
+			return session.ErrInvalidRefreshToken
+		}
+		if model, ok = d.sessions.get(token.SessionID); !ok {
+			return session.ErrSessionNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return persistence.ToSessionDomain(&model), persistence.ToRefreshTokenDomain(&token), nil
+}
+
+func (r *sessionRepository) RevokeAllByPersonID(ctx context.Context, personID int64, reason string, at time.Time) (int64, error) {
+	var revoked int64
+	err := r.store.write(ctx, func(d *tables) error {
+		for _, m := range d.sessions.where(func(m *persistence.SessionModel) bool { return m.PersonID == personID && m.RevokedAt == nil }) {
+			m.RevokedAt, m.RevokedReason = &at, reason
+			d.sessions.put(m.ID, m)
+			revoked++
+		}
+		return nil
+	})
+	return revoked, err
+}
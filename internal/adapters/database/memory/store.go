@@ -0,0 +1,247 @@
+// Package memory provides repositories that keep their data in memory. They
+// enforce the constraints of the SQL schema and share the behaviour checked
+// by repotest, so use case and HTTP tests can run on them without a database.
+package memory
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/infrastructure/persistence"
+)
+
+// errDuplicatedKey and errForeignKeyViolated stand in for the constraint
+// violations a database reports where a repository passes them on as is
+var (
+	errDuplicatedKey      = errors.New("memory: duplicated key")
+	errForeignKeyViolated = errors.New("memory: foreign key violated")
+)
+
+// Store is an in-memory database shared by the repositories created on it.
+// It is safe for concurrent use; every write is atomic and leaves no partial
+// change behind when it fails.
+type Store struct {
+	mu   sync.Mutex
+	data *tables
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{data: &tables{
+		members:       map[memberKey]persistence.FarmMemberModel{},
+		loginAttempts: map[string]persistence.LoginAttemptModel{},
+	}}
+}
+
+// read runs fn on the tables, locked against concurrent writes, or on those of
+// the transaction in ctx
+func (s *Store) read(ctx context.Context, fn func(d *tables) error) error {
+	if tx := s.txFrom(ctx); tx != nil {
+		tx.mu.Lock()
+		defer tx.mu.Unlock()
+		return fn(tx.data)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.data)
+}
+
+// write runs fn on a copy of the tables that replaces them only when fn
+// succeeds. Within a transaction the copy is of the transaction tables, so a
+// failed write leaves the transaction as it was, like a savepoint.
+func (s *Store) write(ctx context.Context, fn func(d *tables) error) error {
+	if tx := s.txFrom(ctx); tx != nil {
+		tx.mu.Lock()
+		defer tx.mu.Unlock()
+		d := tx.data.clone()
+		if err := fn(d); err != nil {
+			return err
+		}
+		tx.data = d
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.data.clone()
+	if err := fn(d); err != nil {
+		return err
+	}
+	s.data = d
+	return nil
+}
+
+type memberKey struct {
+	farmID, personID int64
+}
+
+// tables holds the rows of every table of the schema
+type tables struct {
+	farms         table[persistence.FarmModel]
+	members       map[memberKey]persistence.FarmMemberModel
+	crops         table[persistence.CropModel]
+	transitions   table[persistence.CropTransitionModel]
+	harvests      table[persistence.HarvestModel]
+	applications  table[persistence.ApplicationModel]
+	fertilizers   table[persistence.FertilizerModel]
+	persons       table[persistence.PersonModel]
+	roleChanges   table[persistence.RoleChangeModel]
+	sessions      table[persistence.SessionModel]
+	refreshTokens table[persistence.RefreshTokenModel]
+	enrollments   table[persistence.TOTPEnrollmentModel]
+	recoveryCodes table[persistence.RecoveryCodeModel]
+	challenges    table[persistence.LoginChallengeModel]
+	loginAttempts map[string]persistence.LoginAttemptModel
+	resetTokens   table[persistence.PasswordResetTokenModel]
+	apiKeys       table[persistence.APIKeyModel]
+	oidcRequests  table[persistence.OIDCLoginRequestModel]
+	oidcLinks     table[persistence.OIDCLinkModel]
+	auditEntries  table[persistence.AuditEntryModel]
+}
+
+func (d *tables) clone() *tables {
+	c := *d
+	c.members = maps.Clone(d.members)
+	c.loginAttempts = maps.Clone(d.loginAttempts)
+	c.farms = d.farms.clone()
+	c.crops = d.crops.clone()
+	c.transitions = d.transitions.clone()
+	c.harvests = d.harvests.clone()
+	c.applications = d.applications.clone()
+	c.fertilizers = d.fertilizers.clone()
+	c.persons = d.persons.clone()
+	c.roleChanges = d.roleChanges.clone()
+	c.sessions = d.sessions.clone()
+	c.refreshTokens = d.refreshTokens.clone()
+	c.enrollments = d.enrollments.clone()
+	c.recoveryCodes = d.recoveryCodes.clone()
+	c.challenges = d.challenges.clone()
+	c.resetTokens = d.resetTokens.clone()
+	c.apiKeys = d.apiKeys.clone()
+	c.oidcRequests = d.oidcRequests.clone()
+	c.oidcLinks = d.oidcLinks.clone()
+	c.auditEntries = d.auditEntries.clone()
+	return &c
+}
+
+// recordAudit appends an audit entry for a change to a resource on behalf of
+// the caller in ctx, as the database repositories do
+func (d *tables) recordAudit(ctx context.Context, resourceType audit.ResourceType, resourceID int64, before, after map[string]any) error {
+	entry, err := persistence.NewAuditEntry(ctx, resourceType, resourceID, before, after)
+	if err != nil || entry == nil {
+		return err
+	}
+	model, err := persistence.ToAuditEntryModel(entry)
+	if err != nil {
+		return err
+	}
+	model.ID = d.auditEntries.nextID()
+	d.auditEntries.put(model.ID, *model)
+	entry.SetID(model.ID)
+	return nil
+}
+
+// snapshotByID captures the stored row with the given ID, nil when there is none
+func snapshotByID[M any](t *table[M], id int64) (map[string]any, error) {
+	model, ok := t.get(id)
+	if !ok {
+		return nil, nil
+	}
+	return persistence.Snapshot(&model)
+}
+
+// table holds the rows of a table with an auto-increment primary key. Rows are
+// stored by value and replaced as a whole, never changed in place.
+type table[M any] struct {
+	rows   map[int64]M
+	lastID int64
+}
+
+// nextID allocates the ID of a new row
+func (t *table[M]) nextID() int64 {
+	t.lastID++
+	return t.lastID
+}
+
+func (t *table[M]) get(id int64) (M, bool) {
+	m, ok := t.rows[id]
+	return m, ok
+}
+
+func (t *table[M]) put(id int64, m M) {
+	if t.rows == nil {
+		t.rows = map[int64]M{}
+	}
+	if id > t.lastID {
+		t.lastID = id
+	}
+	t.rows[id] = m
+}
+
+func (t *table[M]) delete(id int64) {
+	delete(t.rows, id)
+}
+
+// where returns the rows matching fn in ID order
+func (t *table[M]) where(fn func(m *M) bool) []M {
+	ids := make([]int64, 0, len(t.rows))
+	for id := range t.rows {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	rows := make([]M, 0, len(ids))
+	for _, id := range ids {
+		m := t.rows[id]
+		if fn(&m) {
+			rows = append(rows, m)
+		}
+	}
+	return rows
+}
+
+// first returns the row with the lowest ID matching fn
+func (t *table[M]) first(fn func(m *M) bool) (M, bool) {
+	var found M
+	var foundID int64
+	for id, m := range t.rows {
+		if (foundID == 0 || id < foundID) && fn(&m) {
+			found, foundID = m, id
+		}
+	}
+	return found, foundID != 0
+}
+
+// deleteWhere removes the rows matching fn
+func (t *table[M]) deleteWhere(fn func(m *M) bool) {
+	for id, m := range t.rows {
+		if fn(&m) {
+			delete(t.rows, id)
+		}
+	}
+}
+
+func (t *table[M]) exists(fn func(m *M) bool) bool {
+	_, ok := t.first(fn)
+	return ok
+}
+
+func (t table[M]) clone() table[M] {
+	return table[M]{rows: maps.Clone(t.rows), lastID: t.lastID}
+}
+
+// touch fills the timestamps the database maintains, createdAt on insert and
+// updatedAt, when the table has one, on every write
+func touch(createdAt, updatedAt *time.Time) {
+	now := time.Now()
+	if createdAt.IsZero() {
+		*createdAt = now
+	}
+	if updatedAt != nil {
+		*updatedAt = now
+	}
+}
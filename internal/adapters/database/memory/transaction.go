@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cropflow/api/internal/domain/transaction"
+)
+
+// txKey is the context key of the transaction started by WithinTx
+type txKey struct{}
+
+// storeTx is a transaction on a store. It works on a copy of the tables that
+// replaces those of the store on commit.
+type storeTx struct {
+	store *Store
+	mu    sync.Mutex
+	data  *tables
+}
+
+type transactionManager struct {
+	store *Store
+}
+
+// NewTransactionManager creates a new transaction manager. A transaction holds
+// the store for its whole duration, so the writes of other callers wait for it
+// and the repositories inside it must be called with the context it provides.
+func NewTransactionManager(store *Store) transaction.Manager {
+	return &transactionManager{store: store}
+}
+
+func (m *transactionManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.store.txFrom(ctx) != nil {
+		return fn(ctx)
+	}
+
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+	tx := &storeTx{store: m.store, data: m.store.data.clone()}
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	m.store.data = tx.data
+	return nil
+}
+
+// txFrom returns the transaction on the store carried by ctx, if any
+func (s *Store) txFrom(ctx context.Context) *storeTx {
+	tx, ok := ctx.Value(txKey{}).(*storeTx)
+	if !ok || tx.store != s {
+		return nil
+	}
+	return tx
+}
@@ -4,35 +4,31 @@ import (
 	"fmt"
 
 	"github.com/cropflow/api/config"
-	"github.com/cropflow/api/internal/domain/entities"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
+// defaultPort is used when DB_PORT is not set
+const defaultPort = "3306"
+
 // NewMySQLConnection creates a new MySQL database connection
 func NewMySQLConnection(cfg *config.Config) (*gorm.DB, error) {
+	port := cfg.DBPort
+	if port == "" {
+		port = defaultPort
+	}
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
 		cfg.DBUser,
 		cfg.DBPassword,
 		cfg.DBHost,
-		cfg.DBPort,
+		port,
 		cfg.DBName,
 	)
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	return db, nil
 }
-
-// RunMigrations runs database migrations
-func RunMigrations(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&entities.Farm{},
-		&entities.Crop{},
-		&entities.Fertilizer{},
-		&entities.Person{},
-	)
-}
@@ -0,0 +1,44 @@
+package mysql
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/cropflow/api/internal/infrastructure/migrations"
+	"gorm.io/gorm"
+)
+
+// migrationLockName names the MySQL user lock held while migrating
+const migrationLockName = "cropflow_schema_migrations"
+
+// migrationLockTimeout is how long a replica waits for another to finish migrating
+const migrationLockTimeout = 5 * time.Minute
+
+// NewMigrator creates a migrator for the embedded MySQL migration set
+func NewMigrator(db *gorm.DB) (*migrations.Migrator, error) {
+	set, err := migrations.Embedded(migrations.DialectMySQL)
+	if err != nil {
+		return nil, err
+	}
+	return migrations.NewMigrator(db, advisoryLock{}, set), nil
+}
+
+// advisoryLock serializes migrations with GET_LOCK, which MySQL releases by
+// itself if the holding connection dies
+type advisoryLock struct{}
+
+func (advisoryLock) Lock(conn *gorm.DB) error {
+	var acquired sql.NullInt64
+	if err := conn.Raw("SELECT GET_LOCK(?, ?)", migrationLockName, int(migrationLockTimeout.Seconds())).Scan(&acquired).Error; err != nil {
+		return err
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		return migrations.ErrLocked
+	}
+	return nil
+}
+
+func (advisoryLock) Unlock(conn *gorm.DB) error {
+	var released sql.NullInt64
+	return conn.Raw("SELECT RELEASE_LOCK(?)", migrationLockName).Scan(&released).Error
+}
@@ -0,0 +1,41 @@
+package mysql_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/cropflow/api/config"
+	"github.com/cropflow/api/internal/adapters/database/mysql"
+	"github.com/cropflow/api/internal/adapters/database/repotest"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRepositories runs against the database named by MYSQL_TEST_DATABASE on
+// the server of the DB_* settings. Every test drops and recreates its
+// schema, so it must be a database used only for tests.
+func TestRepositories(t *testing.T) {
+	name := os.Getenv("MYSQL_TEST_DATABASE")
+	if name == "" {
+		t.Skip("MYSQL_TEST_DATABASE is not set")
+	}
+	cfg := config.NewConfig()
+	cfg.DBName = name
+
+	db, err := mysql.NewMySQLConnection(cfg)
+	require.NoError(t, err)
+	migrator, err := mysql.NewMigrator(db)
+	require.NoError(t, err)
+
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		ctx := context.Background()
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		_, err = migrator.Down(ctx, len(statuses))
+		require.NoError(t, err)
+		_, err = migrator.Up(ctx)
+		require.NoError(t, err)
+
+		return repotest.Gorm(db)
+	})
+}
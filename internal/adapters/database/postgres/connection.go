@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/cropflow/api/config"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// defaultPort is used when DB_PORT is not set
+const defaultPort = "5432"
+
+// NewPostgresConnection creates a new PostgreSQL database connection. The
+// session runs in UTC; TLS and other libpq settings are read from the
+// standard PG* environment variables, such as PGSSLMODE.
+func NewPostgresConnection(cfg *config.Config) (*gorm.DB, error) {
+	port := cfg.DBPort
+	if port == "" {
+		port = defaultPort
+	}
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.DBUser, cfg.DBPassword),
+		Host:     net.JoinHostPort(cfg.DBHost, port),
+		Path:     cfg.DBName,
+		RawQuery: "TimeZone=UTC",
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn.String()), &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return db, nil
+}
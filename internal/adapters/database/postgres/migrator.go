@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/cropflow/api/internal/infrastructure/migrations"
+	"gorm.io/gorm"
+)
+
+// migrationLockName is hashed into the key of the advisory lock held while migrating
+const migrationLockName = "cropflow_schema_migrations"
+
+// migrationLockTimeout is how long a replica waits for another to finish migrating
+const migrationLockTimeout = 5 * time.Minute
+
+// migrationLockRetry is how often a waiting replica tries the lock again
+const migrationLockRetry = time.Second
+
+// NewMigrator creates a migrator for the embedded PostgreSQL migration set
+func NewMigrator(db *gorm.DB) (*migrations.Migrator, error) {
+	set, err := migrations.Embedded(migrations.DialectPostgres)
+	if err != nil {
+		return nil, err
+	}
+	return migrations.NewMigrator(db, advisoryLock{}, set), nil
+}
+
+// advisoryLock serializes migrations with a session advisory lock, which
+// PostgreSQL releases by itself if the holding connection dies. The lock is
+// polled, as pg_advisory_lock would wait without a timeout.
+type advisoryLock struct{}
+
+func (advisoryLock) Lock(conn *gorm.DB) error {
+	ctx := conn.Statement.Context
+	deadline := time.Now().Add(migrationLockTimeout)
+	for {
+		var acquired bool
+		if err := conn.Raw("SELECT pg_try_advisory_lock(hashtext(?))", migrationLockName).Scan(&acquired).Error; err != nil {
+			return err
+		}
+		if acquired {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return migrations.ErrLocked
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(migrationLockRetry):
+		}
+	}
+}
+
+func (advisoryLock) Unlock(conn *gorm.DB) error {
+	var released bool
+	return conn.Raw("SELECT pg_advisory_unlock(hashtext(?))", migrationLockName).Scan(&released).Error
+}
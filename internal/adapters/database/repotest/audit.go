@@ -0,0 +1,83 @@
+package repotest
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/farm"
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAudit(t *testing.T, open func(t *testing.T) Repositories) {
+	t.Run("should record the writes to a resource on behalf of the caller", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		admin := savePerson(t, repos, "admin", person.RoleAdmin.String())
+		ctx := identity.NewContext(audit.NewRequestContext(context.Background(), "req-1"),
+			identity.New(admin.ID(), 0, "admin", person.RoleAdmin))
+		f := newFarm(t, "Fazenda Boa Vista", 150.5)
+		require.NoError(t, repos.Farms.Save(ctx, f))
+		require.NoError(t, f.ChangeName("Fazenda Santa Rita"))
+		require.NoError(t, repos.Farms.Save(ctx, f))
+
+		// Act
+		err := repos.Farms.Delete(ctx, f.ID())
+
+		// Assert
+		require.NoError(t, err)
+		result, err := repos.Audit.List(ctx, audit.Filter{ResourceType: audit.ResourceFarm, ResourceID: f.ID()}, newPage(t, 0, "", "", audit.SortFields))
+		require.NoError(t, err)
+		require.Len(t, result.Items, 3)
+		assert.Equal(t, audit.ActionCreate, result.Items[0].Action())
+		assert.Equal(t, audit.ActionUpdate, result.Items[1].Action())
+		assert.Equal(t, audit.ActionDelete, result.Items[2].Action())
+		assert.Equal(t, audit.Change{Before: "Fazenda Boa Vista", After: "Fazenda Santa Rita"}, result.Items[1].Changes()["name"])
+		for _, e := range result.Items {
+			assert.Equal(t, admin.ID(), e.ActorID())
+			assert.Equal(t, "req-1", e.RequestID())
+		}
+	})
+
+	t.Run("should not record a write that changes nothing", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		ctx := context.Background()
+		f := newFarm(t, "Fazenda Boa Vista", 150.5)
+		require.NoError(t, repos.Farms.Save(ctx, f))
+
+		// Act
+		err := repos.Farms.Save(ctx, f)
+
+		// Assert
+		require.NoError(t, err)
+		result, err := repos.Audit.List(ctx, audit.Filter{ResourceType: audit.ResourceFarm}, newPage(t, 0, "", "", audit.SortFields))
+		require.NoError(t, err)
+		assert.Len(t, result.Items, 1)
+	})
+
+	t.Run("should record member changes on the farm", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		ctx := context.Background()
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		f := newFarm(t, "Fazenda Boa Vista", 150.5)
+		require.NoError(t, repos.Farms.Save(ctx, f))
+		_, err := f.AddMember(p.ID(), farm.MemberViewer)
+		require.NoError(t, err)
+
+		// Act
+		err = repos.Farms.Save(ctx, f)
+
+		// Assert
+		require.NoError(t, err)
+		result, err := repos.Audit.List(ctx, audit.Filter{ResourceType: audit.ResourceFarm, Action: audit.ActionUpdate}, newPage(t, 0, "", "", audit.SortFields))
+		require.NoError(t, err)
+		require.Len(t, result.Items, 1)
+		assert.Contains(t, result.Items[0].Changes(), "members."+strconv.FormatInt(p.ID(), 10))
+	})
+}
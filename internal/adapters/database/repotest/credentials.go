@@ -0,0 +1,500 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/apikey"
+	"github.com/cropflow/api/internal/domain/lockout"
+	"github.com/cropflow/api/internal/domain/mfa"
+	"github.com/cropflow/api/internal/domain/oidc"
+	"github.com/cropflow/api/internal/domain/passwordreset"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSessions(t *testing.T, open func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("should save a session and find it by refresh token", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		s, plain, err := session.NewSession(p.ID(), time.Hour, time.Now())
+		require.NoError(t, err)
+
+		// Act
+		err = repos.Sessions.Save(ctx, s)
+
+		// Assert
+		require.NoError(t, err)
+		require.NotZero(t, s.ID())
+		found, token, err := repos.Sessions.FindByRefreshToken(ctx, session.HashToken(plain))
+		require.NoError(t, err)
+		assert.Equal(t, s.ID(), found.ID())
+		assert.Equal(t, p.ID(), found.PersonID())
+		assert.Equal(t, s.ID(), token.SessionID())
+		assert.Nil(t, token.UsedAt())
+		_, _, err = repos.Sessions.FindByRefreshToken(ctx, session.HashToken("unknown"))
+		assert.ErrorIs(t, err, session.ErrInvalidRefreshToken)
+	})
+
+	t.Run("should let only one of two rotations of a refresh token win", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		s, plain, err := session.NewSession(p.ID(), time.Hour, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, repos.Sessions.Save(ctx, s))
+		first, firstToken, err := repos.Sessions.FindByRefreshToken(ctx, session.HashToken(plain))
+		require.NoError(t, err)
+		second, secondToken, err := repos.Sessions.FindByRefreshToken(ctx, session.HashToken(plain))
+		require.NoError(t, err)
+		_, err = first.Rotate(firstToken, time.Hour, time.Now())
+		require.NoError(t, err)
+		_, err = second.Rotate(secondToken, time.Hour, time.Now())
+		require.NoError(t, err)
+
+		// Act
+		firstErr := repos.Sessions.Save(ctx, first)
+		secondErr := repos.Sessions.Save(ctx, second)
+
+		// Assert
+		require.NoError(t, firstErr)
+		assert.ErrorIs(t, secondErr, session.ErrRefreshTokenReused)
+	})
+
+	t.Run("should let only one of two concurrent refreshes of a token win", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		s, plain, err := session.NewSession(p.ID(), time.Hour, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, repos.Sessions.Save(ctx, s))
+		refresh := func() error {
+			return repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+				found, token, err := repos.Sessions.FindByRefreshToken(ctx, session.HashToken(plain))
+				if err != nil {
+					return err
+				}
+				holdLock()
+				if _, err := found.Rotate(token, time.Hour, time.Now()); err != nil {
+					return err
+				}
+				return repos.Sessions.Save(ctx, found)
+			})
+		}
+
+		// Act
+		failed := concurrently(refresh, refresh)
+
+		// Assert
+		require.Len(t, failed, 1)
+		assert.ErrorIs(t, failed[0], session.ErrRefreshTokenReused)
+	})
+
+	t.Run("should not bring back a session revoked during a concurrent refresh", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		s, plain, err := session.NewSession(p.ID(), time.Hour, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, repos.Sessions.Save(ctx, s))
+
+		// Act
+		failed := concurrently(
+			func() error {
+				return repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+					found, token, err := repos.Sessions.FindByRefreshToken(ctx, session.HashToken(plain))
+					if err != nil {
+						return err
+					}
+					holdLock()
+					if _, err := found.Rotate(token, time.Hour, time.Now()); err != nil {
+						return err
+					}
+					return repos.Sessions.Save(ctx, found)
+				})
+			},
+			func() error {
+				_, err := repos.Sessions.RevokeAllByPersonID(ctx, p.ID(), "password changed", time.Now())
+				return err
+			},
+		)
+
+		// Assert
+		for _, err := range failed {
+			assert.ErrorIs(t, err, session.ErrSessionRevoked)
+		}
+		revoked, err := repos.Sessions.FindByID(ctx, s.ID())
+		require.NoError(t, err)
+		assert.False(t, revoked.IsActive())
+		assert.Equal(t, "password changed", revoked.RevokedReason())
+	})
+
+	t.Run("should not bring back a session revoked between its rotation and its save", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		s, plain, err := session.NewSession(p.ID(), time.Hour, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, repos.Sessions.Save(ctx, s))
+		found, token, err := repos.Sessions.FindByRefreshToken(ctx, session.HashToken(plain))
+		require.NoError(t, err)
+		next, err := found.Rotate(token, time.Hour, time.Now())
+		require.NoError(t, err)
+		_, err = repos.Sessions.RevokeAllByPersonID(ctx, p.ID(), "password changed", time.Now())
+		require.NoError(t, err)
+
+		// Act
+		err = repos.Sessions.Save(ctx, found)
+
+		// Assert
+		assert.ErrorIs(t, err, session.ErrSessionRevoked)
+		revoked, err := repos.Sessions.FindByID(ctx, s.ID())
+		require.NoError(t, err)
+		assert.False(t, revoked.IsActive())
+		assert.Equal(t, "password changed", revoked.RevokedReason())
+		_, _, err = repos.Sessions.FindByRefreshToken(ctx, session.HashToken(next))
+		assert.ErrorIs(t, err, session.ErrInvalidRefreshToken)
+	})
+
+	t.Run("should keep the first revocation of a session", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		s, _, err := session.NewSession(p.ID(), time.Hour, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, repos.Sessions.Save(ctx, s))
+		first, err := repos.Sessions.FindByID(ctx, s.ID())
+		require.NoError(t, err)
+		second, err := repos.Sessions.FindByID(ctx, s.ID())
+		require.NoError(t, err)
+		first.Revoke("logout", time.Now())
+		second.Revoke("refresh token reuse", time.Now())
+		require.NoError(t, repos.Sessions.Save(ctx, first))
+
+		// Act
+		err = repos.Sessions.Save(ctx, second)
+
+		// Assert
+		require.NoError(t, err)
+		found, err := repos.Sessions.FindByID(ctx, s.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "logout", found.RevokedReason())
+	})
+
+	t.Run("should revoke the active sessions of a person", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		for i := 0; i < 2; i++ {
+			s, _, err := session.NewSession(p.ID(), time.Hour, time.Now())
+			require.NoError(t, err)
+			require.NoError(t, repos.Sessions.Save(ctx, s))
+		}
+
+		// Act
+		revoked, err := repos.Sessions.RevokeAllByPersonID(ctx, p.ID(), "password changed", time.Now())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), revoked)
+		again, err := repos.Sessions.RevokeAllByPersonID(ctx, p.ID(), "password changed", time.Now())
+		require.NoError(t, err)
+		assert.Zero(t, again)
+	})
+}
+
+func testMFA(t *testing.T, open func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("should save a confirmed enrolment with its recovery codes", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		e := confirmedEnrollment(t, p.ID())
+
+		// Act
+		err := repos.MFA.SaveEnrollment(ctx, e)
+
+		// Assert
+		require.NoError(t, err)
+		found, err := repos.MFA.FindEnrollmentByPersonID(ctx, p.ID())
+		require.NoError(t, err)
+		assert.Equal(t, e.ID(), found.ID())
+		assert.True(t, found.IsConfirmed())
+		assert.Equal(t, e.RemainingRecoveryCodes(), found.RemainingRecoveryCodes())
+		assert.NotZero(t, found.RemainingRecoveryCodes())
+	})
+
+	t.Run("should accept a recovery code only once", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		e, codes := confirmedEnrollmentWithCodes(t, p.ID())
+		require.NoError(t, repos.MFA.SaveEnrollment(ctx, e))
+		first, err := repos.MFA.FindEnrollmentByPersonID(ctx, p.ID())
+		require.NoError(t, err)
+		second, err := repos.MFA.FindEnrollmentByPersonID(ctx, p.ID())
+		require.NoError(t, err)
+		require.NoError(t, first.Verify(codes[0], time.Now()))
+		require.NoError(t, second.Verify(codes[0], time.Now()))
+
+		// Act
+		firstErr := repos.MFA.SaveEnrollment(ctx, first)
+		secondErr := repos.MFA.SaveEnrollment(ctx, second)
+
+		// Assert
+		require.NoError(t, firstErr)
+		assert.ErrorIs(t, secondErr, mfa.ErrInvalidCode)
+		found, err := repos.MFA.FindEnrollmentByPersonID(ctx, p.ID())
+		require.NoError(t, err)
+		assert.Equal(t, len(codes)-1, found.RemainingRecoveryCodes())
+	})
+
+	t.Run("should delete an enrolment", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		require.NoError(t, repos.MFA.SaveEnrollment(ctx, confirmedEnrollment(t, p.ID())))
+
+		// Act
+		err := repos.MFA.DeleteEnrollment(ctx, p.ID())
+
+		// Assert
+		require.NoError(t, err)
+		_, err = repos.MFA.FindEnrollmentByPersonID(ctx, p.ID())
+		assert.ErrorIs(t, err, mfa.ErrEnrollmentNotFound)
+		assert.ErrorIs(t, repos.MFA.DeleteEnrollment(ctx, p.ID()), mfa.ErrEnrollmentNotFound)
+	})
+
+	t.Run("should save a login challenge and find it by hash", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		c, plain, err := mfa.NewChallenge(p.ID(), mfa.ChallengeTOTP, time.Minute, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, repos.MFA.SaveChallenge(ctx, c))
+		c.RecordFailure()
+
+		// Act
+		err = repos.MFA.SaveChallenge(ctx, c)
+
+		// Assert
+		require.NoError(t, err)
+		found, err := repos.MFA.FindChallenge(ctx, c.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, c.ID(), found.ID())
+		assert.Equal(t, 1, found.Attempts())
+		assert.NotEqual(t, plain, found.Hash())
+		_, err = repos.MFA.FindChallenge(ctx, "unknown")
+		assert.ErrorIs(t, err, mfa.ErrInvalidChallenge)
+	})
+}
+
+func testLockouts(t *testing.T, open func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("should update, find and delete the failures of a key", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		policy, err := lockout.NewPolicy(5, time.Minute, time.Hour, time.Hour)
+		require.NoError(t, err)
+		key := lockout.IPKey("203.0.113.7")
+
+		// Act
+		for i := 0; i < 2; i++ {
+			_, err = repos.Lockouts.Update(ctx, key, func(s lockout.Status) lockout.Status {
+				return s.RecordFailure(policy, time.Now())
+			})
+			require.NoError(t, err)
+		}
+
+		// Assert
+		status, err := repos.Lockouts.Find(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, 2, status.Failures())
+		require.NoError(t, repos.Lockouts.Delete(ctx, key))
+		status, err = repos.Lockouts.Find(ctx, key)
+		require.NoError(t, err)
+		assert.True(t, status.IsZero())
+	})
+}
+
+func testPasswordResets(t *testing.T, open func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("should save a token and redeem it only once", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		token, plain, err := passwordreset.NewToken(p.ID(), time.Hour, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, repos.PasswordResets.Save(ctx, token))
+		first, err := repos.PasswordResets.FindByHash(ctx, passwordreset.HashToken(plain))
+		require.NoError(t, err)
+		second, err := repos.PasswordResets.FindByHash(ctx, passwordreset.HashToken(plain))
+		require.NoError(t, err)
+		require.NoError(t, first.Redeem(time.Now()))
+		require.NoError(t, second.Redeem(time.Now()))
+
+		// Act
+		firstErr := repos.PasswordResets.Save(ctx, first)
+		secondErr := repos.PasswordResets.Save(ctx, second)
+
+		// Assert
+		require.NoError(t, firstErr)
+		assert.ErrorIs(t, secondErr, passwordreset.ErrResetTokenUsed)
+		_, err = repos.PasswordResets.FindByHash(ctx, passwordreset.HashToken("unknown"))
+		assert.ErrorIs(t, err, passwordreset.ErrInvalidResetToken)
+	})
+
+	t.Run("should invalidate the open tokens of a person", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		token, plain, err := passwordreset.NewToken(p.ID(), time.Hour, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, repos.PasswordResets.Save(ctx, token))
+
+		// Act
+		err = repos.PasswordResets.InvalidateAllByPersonID(ctx, p.ID(), time.Now())
+
+		// Assert
+		require.NoError(t, err)
+		found, err := repos.PasswordResets.FindByHash(ctx, passwordreset.HashToken(plain))
+		require.NoError(t, err)
+		assert.NotNil(t, found.UsedAt())
+	})
+}
+
+func testAPIKeys(t *testing.T, open func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("should save a key and find it by ID and prefix", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleManager.String())
+		k, plain, err := apikey.NewKey(p.ID(), apikey.Service, "sensor", person.RoleUser, []int64{3, 1}, nil, time.Now())
+		require.NoError(t, err)
+
+		// Act
+		err = repos.APIKeys.Save(ctx, k)
+
+		// Assert
+		require.NoError(t, err)
+		require.NotZero(t, k.ID())
+		found, err := repos.APIKeys.FindByID(ctx, k.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "sensor", found.Name())
+		assert.ElementsMatch(t, []int64{1, 3}, found.FarmIDs())
+		prefix, err := apikey.ParsePrefix(plain)
+		require.NoError(t, err)
+		byPrefix, err := repos.APIKeys.FindByPrefix(ctx, prefix)
+		require.NoError(t, err)
+		assert.NoError(t, byPrefix.Verify(plain, time.Now()))
+		_, err = repos.APIKeys.FindByID(ctx, 999)
+		assert.ErrorIs(t, err, apikey.ErrAPIKeyNotFound)
+	})
+
+	t.Run("should list the keys of an owner and record their use", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		owner := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		other := savePerson(t, repos, "jane_doe", person.RoleUser.String())
+		k := saveAPIKey(t, repos, owner.ID(), "laptop")
+		saveAPIKey(t, repos, other.ID(), "phone")
+
+		// Act
+		err := repos.APIKeys.Touch(ctx, k.ID(), time.Now())
+
+		// Assert
+		require.NoError(t, err)
+		keys, err := repos.APIKeys.List(ctx, apikey.Filter{OwnerID: owner.ID()})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, "laptop", keys[0].Name())
+		assert.NotNil(t, keys[0].LastUsedAt())
+		all, err := repos.APIKeys.List(ctx, apikey.Filter{})
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+	})
+}
+
+func testOIDC(t *testing.T, open func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("should consume a login request only once", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		request, state, err := oidc.NewLoginRequest(time.Minute, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, repos.OIDC.SaveLoginRequest(ctx, request))
+		first, err := repos.OIDC.FindLoginRequest(ctx, oidc.HashState(state))
+		require.NoError(t, err)
+		second, err := repos.OIDC.FindLoginRequest(ctx, oidc.HashState(state))
+		require.NoError(t, err)
+		require.NoError(t, first.Consume(time.Now()))
+		require.NoError(t, second.Consume(time.Now()))
+
+		// Act
+		firstErr := repos.OIDC.SaveLoginRequest(ctx, first)
+		secondErr := repos.OIDC.SaveLoginRequest(ctx, second)
+
+		// Assert
+		require.NoError(t, firstErr)
+		assert.ErrorIs(t, secondErr, oidc.ErrLoginRequestConsumed)
+		_, err = repos.OIDC.FindLoginRequest(ctx, oidc.HashState("unknown"))
+		assert.ErrorIs(t, err, oidc.ErrInvalidState)
+	})
+
+	t.Run("should save a link and find it by issuer and subject", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		link, err := oidc.NewLink(p.ID(), "https://idp.example.com", "subject-1", time.Now())
+		require.NoError(t, err)
+
+		// Act
+		err = repos.OIDC.SaveLink(ctx, link)
+
+		// Assert
+		require.NoError(t, err)
+		found, err := repos.OIDC.FindLink(ctx, "https://idp.example.com", "subject-1")
+		require.NoError(t, err)
+		assert.Equal(t, p.ID(), found.PersonID())
+		_, err = repos.OIDC.FindLink(ctx, "https://idp.example.com", "subject-2")
+		assert.ErrorIs(t, err, oidc.ErrLinkNotFound)
+	})
+}
+
+func confirmedEnrollment(t *testing.T, personID int64) *mfa.Enrollment {
+	t.Helper()
+	e, _ := confirmedEnrollmentWithCodes(t, personID)
+	return e
+}
+
+// confirmedEnrollmentWithCodes enrols a person and returns the plain recovery codes
+func confirmedEnrollmentWithCodes(t *testing.T, personID int64) (*mfa.Enrollment, []string) {
+	t.Helper()
+	now := time.Now()
+	e, err := mfa.NewEnrollment(personID, now)
+	require.NoError(t, err)
+	code, err := mfa.Code(e.Secret(), now.Unix()/30)
+	require.NoError(t, err)
+	codes, err := e.Confirm(code, now)
+	require.NoError(t, err)
+	return e, codes
+}
+
+func saveAPIKey(t *testing.T, repos Repositories, ownerID int64, name string) *apikey.Key {
+	t.Helper()
+	k, _, err := apikey.NewKey(ownerID, apikey.Personal, name, person.RoleUser, nil, nil, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, repos.APIKeys.Save(context.Background(), k))
+	return k
+}
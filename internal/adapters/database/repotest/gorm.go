@@ -0,0 +1,24 @@
+package repotest
+
+import (
+	"github.com/cropflow/api/internal/adapters/database/gormrepo"
+	"gorm.io/gorm"
+)
+
+// Gorm returns the GORM repositories over db, for the adapters sharing them
+func Gorm(db *gorm.DB) Repositories {
+	return Repositories{
+		Farms:          gormrepo.NewFarmRepository(db),
+		Crops:          gormrepo.NewCropRepository(db),
+		Fertilizers:    gormrepo.NewFertilizerRepository(db),
+		Persons:        gormrepo.NewPersonRepository(db),
+		Sessions:       gormrepo.NewSessionRepository(db),
+		MFA:            gormrepo.NewMFARepository(db),
+		Lockouts:       gormrepo.NewLockoutRepository(db),
+		PasswordResets: gormrepo.NewPasswordResetRepository(db),
+		APIKeys:        gormrepo.NewAPIKeyRepository(db),
+		OIDC:           gormrepo.NewOIDCRepository(db),
+		Audit:          gormrepo.NewAuditRepository(db),
+		Tx:             gormrepo.NewTransactionManager(db),
+	}
+}
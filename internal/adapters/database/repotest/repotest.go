@@ -0,0 +1,808 @@
+// Package repotest provides the behaviour every storage adapter must share,
+// as a test suite each adapter runs against its own database.
+package repotest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/apikey"
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/cropflow/api/internal/domain/farm"
+	"github.com/cropflow/api/internal/domain/fertilizer"
+	"github.com/cropflow/api/internal/domain/lockout"
+	"github.com/cropflow/api/internal/domain/mfa"
+	"github.com/cropflow/api/internal/domain/oidc"
+	"github.com/cropflow/api/internal/domain/passwordreset"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/domain/session"
+	"github.com/cropflow/api/internal/domain/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Repositories are the repositories of an adapter, all backed by one database
+type Repositories struct {
+	Farms          farm.Repository
+	Crops          crop.Repository
+	Fertilizers    fertilizer.Repository
+	Persons        person.Repository
+	Sessions       session.Repository
+	MFA            mfa.Repository
+	Lockouts       lockout.Repository
+	PasswordResets passwordreset.Repository
+	APIKeys        apikey.Repository
+	OIDC           oidc.Repository
+	Audit          audit.Repository
+	Tx             transaction.Manager
+}
+
+// Run runs the suite. open is called by every test and must return
+// repositories over an empty database.
+func Run(t *testing.T, open func(t *testing.T) Repositories) {
+	t.Run("farms", func(t *testing.T) { testFarms(t, open) })
+	t.Run("crops", func(t *testing.T) { testCrops(t, open) })
+	t.Run("fertilizers", func(t *testing.T) { testFertilizers(t, open) })
+	t.Run("persons", func(t *testing.T) { testPersons(t, open) })
+	t.Run("sessions", func(t *testing.T) { testSessions(t, open) })
+	t.Run("mfa", func(t *testing.T) { testMFA(t, open) })
+	t.Run("lockouts", func(t *testing.T) { testLockouts(t, open) })
+	t.Run("password resets", func(t *testing.T) { testPasswordResets(t, open) })
+	t.Run("api keys", func(t *testing.T) { testAPIKeys(t, open) })
+	t.Run("oidc", func(t *testing.T) { testOIDC(t, open) })
+	t.Run("audit", func(t *testing.T) { testAudit(t, open) })
+	t.Run("transactions", func(t *testing.T) { testTransactions(t, open) })
+}
+
+func testFarms(t *testing.T, open func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("should save a farm and find it by ID", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := newFarm(t, "Fazenda Boa Vista", 150.5)
+
+		// Act
+		err := repos.Farms.Save(ctx, f)
+
+		// Assert
+		require.NoError(t, err)
+		require.NotZero(t, f.ID())
+		found, err := repos.Farms.FindByID(ctx, f.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "Fazenda Boa Vista", found.Name())
+		assert.Equal(t, 150.5, found.Size().Value())
+		exists, err := repos.Farms.ExistsByID(ctx, f.ID())
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("should update a saved farm", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := newFarm(t, "Fazenda Boa Vista", 150.5)
+		require.NoError(t, repos.Farms.Save(ctx, f))
+		require.NoError(t, f.ChangeName("Fazenda Santa Rita"))
+
+		// Act
+		err := repos.Farms.Save(ctx, f)
+
+		// Assert
+		require.NoError(t, err)
+		found, err := repos.Farms.FindByID(ctx, f.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "Fazenda Santa Rita", found.Name())
+	})
+
+	t.Run("should return error for an unknown farm", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+
+		// Act
+		_, err := repos.Farms.FindByID(ctx, 999)
+
+		// Assert
+		assert.ErrorIs(t, err, farm.ErrFarmNotFound)
+		exists, err := repos.Farms.ExistsByID(ctx, 999)
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("should list farms matching the filter one page at a time", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		for _, name := range []string{"Fazenda C", "Fazenda A", "Sitio B", "Fazenda B"} {
+			require.NoError(t, repos.Farms.Save(ctx, newFarm(t, name, 10)))
+		}
+		page := newPage(t, 2, "", "name", farm.SortFields)
+
+		// Act
+		first, err := repos.Farms.List(ctx, farm.Filter{NamePrefix: "Fazenda"}, page)
+		require.NoError(t, err)
+		second, err := repos.Farms.List(ctx, farm.Filter{NamePrefix: "Fazenda"}, newPage(t, 2, first.NextCursor, "name", farm.SortFields))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Fazenda A", "Fazenda B"}, farmNames(first.Items))
+		assert.Equal(t, []string{"Fazenda C"}, farmNames(second.Items))
+		assert.Empty(t, second.NextCursor)
+		assert.NotEmpty(t, second.PrevCursor)
+	})
+
+	t.Run("should list only the farms of a member", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		owner := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		mine := newFarm(t, "Fazenda A", 10)
+		_, err := mine.AddMember(owner.ID(), farm.MemberOwner)
+		require.NoError(t, err)
+		require.NoError(t, repos.Farms.Save(ctx, mine))
+		require.NoError(t, repos.Farms.Save(ctx, newFarm(t, "Fazenda B", 10)))
+
+		// Act
+		result, err := repos.Farms.List(ctx, farm.Filter{MemberID: owner.ID()}, newPage(t, 0, "", "", farm.SortFields))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Fazenda A"}, farmNames(result.Items))
+	})
+
+	t.Run("should delete a farm", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := newFarm(t, "Fazenda A", 10)
+		require.NoError(t, repos.Farms.Save(ctx, f))
+
+		// Act
+		err := repos.Farms.Delete(ctx, f.ID())
+
+		// Assert
+		require.NoError(t, err)
+		_, err = repos.Farms.FindByID(ctx, f.ID())
+		assert.ErrorIs(t, err, farm.ErrFarmNotFound)
+		assert.ErrorIs(t, repos.Farms.Delete(ctx, f.ID()), farm.ErrFarmNotFound)
+	})
+
+	t.Run("should refuse to delete a farm with crops", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := newFarm(t, "Fazenda A", 10)
+		require.NoError(t, repos.Farms.Save(ctx, f))
+		require.NoError(t, repos.Crops.Save(ctx, newCrop(t, "Soja", 5, f.ID())))
+
+		// Act
+		err := repos.Farms.Delete(ctx, f.ID())
+
+		// Assert
+		assert.ErrorIs(t, err, farm.ErrFarmHasCrops)
+	})
+
+	t.Run("should save, change and remove members", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		owner := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		viewer := savePerson(t, repos, "jane_doe", person.RoleUser.String())
+		f := newFarm(t, "Fazenda A", 10)
+		_, err := f.AddMember(owner.ID(), farm.MemberOwner)
+		require.NoError(t, err)
+		_, err = f.AddMember(viewer.ID(), farm.MemberViewer)
+		require.NoError(t, err)
+		require.NoError(t, repos.Farms.Save(ctx, f))
+
+		// Act
+		member, err := repos.Farms.FindMember(ctx, f.ID(), viewer.ID())
+		require.NoError(t, err)
+		require.NoError(t, member.ChangeRole(farm.MemberManager))
+		require.NoError(t, repos.Farms.UpdateMember(ctx, member))
+		require.NoError(t, repos.Farms.DeleteMember(ctx, f.ID(), owner.ID()))
+
+		// Assert
+		members, err := repos.Farms.FindMembers(ctx, f.ID())
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		assert.Equal(t, viewer.ID(), members[0].PersonID())
+		assert.Equal(t, farm.MemberManager, members[0].Role())
+		_, err = repos.Farms.FindMember(ctx, f.ID(), owner.ID())
+		assert.ErrorIs(t, err, farm.ErrMemberNotFound)
+		assert.ErrorIs(t, repos.Farms.DeleteMember(ctx, f.ID(), owner.ID()), farm.ErrMemberNotFound)
+	})
+
+	t.Run("should lock and return the owners of a farm", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		first := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		second := savePerson(t, repos, "jane_doe", person.RoleUser.String())
+		viewer := savePerson(t, repos, "joe_doe", person.RoleUser.String())
+		f := newFarm(t, "Fazenda A", 10)
+		for _, m := range []struct {
+			personID int64
+			role     farm.MemberRole
+		}{{second.ID(), farm.MemberOwner}, {viewer.ID(), farm.MemberViewer}, {first.ID(), farm.MemberOwner}} {
+			_, err := f.AddMember(m.personID, m.role)
+			require.NoError(t, err)
+		}
+		require.NoError(t, repos.Farms.Save(ctx, f))
+		var owners []int64
+
+		// Act
+		err := repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			owners, err = repos.Farms.LockOwners(ctx, f.ID())
+			return err
+		})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []int64{first.ID(), second.ID()}, owners)
+	})
+
+	t.Run("should return error for a member added twice", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		owner := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		f := newFarm(t, "Fazenda A", 10)
+		_, err := f.AddMember(owner.ID(), farm.MemberOwner)
+		require.NoError(t, err)
+		require.NoError(t, repos.Farms.Save(ctx, f))
+		_, err = f.AddMember(owner.ID(), farm.MemberViewer)
+		require.NoError(t, err)
+
+		// Act
+		err = repos.Farms.Save(ctx, f)
+
+		// Assert
+		assert.ErrorIs(t, err, farm.ErrMemberAlreadyExists)
+	})
+}
+
+func testCrops(t *testing.T, open func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("should save a crop and find it by ID and farm", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := saveFarm(t, repos, "Fazenda A")
+		c := newCrop(t, "Soja", 50.5, f.ID())
+
+		// Act
+		err := repos.Crops.Save(ctx, c)
+
+		// Assert
+		require.NoError(t, err)
+		require.NotZero(t, c.ID())
+		found, err := repos.Crops.FindByID(ctx, c.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "Soja", found.Name())
+		assert.Equal(t, 50.5, found.PlantedArea())
+		assert.Equal(t, crop.StatusPlanned, found.Status())
+		byFarm, err := repos.Crops.FindByFarmID(ctx, f.ID())
+		require.NoError(t, err)
+		require.Len(t, byFarm, 1)
+		assert.Equal(t, c.ID(), byFarm[0].ID())
+	})
+
+	t.Run("should return error for an unknown crop", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+
+		// Act
+		_, err := repos.Crops.FindByID(ctx, 999)
+
+		// Assert
+		assert.ErrorIs(t, err, crop.ErrCropNotFound)
+		_, err = repos.Crops.FindByIDForUpdate(ctx, 999)
+		assert.ErrorIs(t, err, crop.ErrCropNotFound)
+		assert.ErrorIs(t, repos.Crops.Delete(ctx, 999), crop.ErrCropNotFound)
+	})
+
+	t.Run("should record status transitions in order", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := saveFarm(t, repos, "Fazenda A")
+		c := newCrop(t, "Soja", 10, f.ID())
+		require.NoError(t, repos.Crops.Save(ctx, c))
+		plantedAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, c.Plant(plantedAt))
+		require.NoError(t, c.StartGrowing(plantedAt.AddDate(0, 0, 10)))
+
+		// Act
+		err := repos.Crops.Save(ctx, c)
+
+		// Assert
+		require.NoError(t, err)
+		found, err := repos.Crops.FindByID(ctx, c.ID())
+		require.NoError(t, err)
+		assert.Equal(t, crop.StatusGrowing, found.Status())
+		transitions, err := repos.Crops.FindTransitions(ctx, c.ID())
+		require.NoError(t, err)
+		require.Len(t, transitions, 3)
+		assert.Equal(t, crop.StatusPlanned, transitions[0].To())
+		assert.Equal(t, crop.StatusPlanted, transitions[1].To())
+		assert.Equal(t, crop.StatusGrowing, transitions[2].To())
+		assert.True(t, plantedAt.Equal(transitions[1].OccurredAt()))
+	})
+
+	t.Run("should let only one of two concurrent transitions of a crop win", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := saveFarm(t, repos, "Fazenda A")
+		c := newCrop(t, "Soja", 10, f.ID())
+		require.NoError(t, c.Plant(time.Now().AddDate(0, 0, -10)))
+		require.NoError(t, repos.Crops.Save(ctx, c))
+		transition := func() error {
+			return repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+				found, err := repos.Crops.FindByIDForUpdate(ctx, c.ID())
+				if err != nil {
+					return err
+				}
+				if err := found.TransitionTo(crop.StatusGrowing, time.Now(), ""); err != nil {
+					return err
+				}
+				return repos.Crops.Save(ctx, found)
+			})
+		}
+
+		// Act
+		failed := concurrently(transition, transition)
+
+		// Assert
+		require.Len(t, failed, 1)
+		assert.ErrorIs(t, failed[0], crop.ErrInvalidTransition)
+		transitions, err := repos.Crops.FindTransitions(ctx, c.ID())
+		require.NoError(t, err)
+		require.Len(t, transitions, 3)
+		assert.Equal(t, crop.StatusGrowing, transitions[2].To())
+	})
+
+	t.Run("should record harvests and fertilizer applications", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := saveFarm(t, repos, "Fazenda A")
+		urea := saveFertilizer(t, repos, "Ureia", "45-00-00")
+		c := newCrop(t, "Soja", 10, f.ID())
+		require.NoError(t, repos.Crops.Save(ctx, c))
+		appliedAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+		_, err := c.RecordApplication(urea.ID(), appliedAt, 100, crop.DoseKilogramsPerHectare, 10, nil, "")
+		require.NoError(t, err)
+		require.NoError(t, c.Plant(appliedAt))
+		require.NoError(t, c.StartGrowing(appliedAt.AddDate(0, 1, 0)))
+		_, err = c.RecordHarvest(appliedAt.AddDate(0, 4, 0), 30, crop.UnitTonne, nil, "", true)
+		require.NoError(t, err)
+
+		// Act
+		err = repos.Crops.Save(ctx, c)
+
+		// Assert
+		require.NoError(t, err)
+		fertilizers, err := repos.Crops.FindFertilizersByCropID(ctx, c.ID())
+		require.NoError(t, err)
+		assert.Equal(t, []int64{urea.ID()}, fertilizers)
+		applications, err := repos.Crops.FindApplications(ctx, crop.ApplicationFilter{FarmID: f.ID()})
+		require.NoError(t, err)
+		require.Len(t, applications, 1)
+		assert.Equal(t, 100.0, applications[0].Dose())
+		harvests, err := repos.Crops.FindHarvests(ctx, c.ID())
+		require.NoError(t, err)
+		require.Len(t, harvests, 1)
+		assert.Equal(t, 30.0, harvests[0].Quantity())
+	})
+
+	t.Run("should return error for an application of an unknown fertilizer", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := saveFarm(t, repos, "Fazenda A")
+		c := newCrop(t, "Soja", 10, f.ID())
+		require.NoError(t, repos.Crops.Save(ctx, c))
+		_, err := c.RecordApplication(999, time.Now(), 100, crop.DoseKilogramsPerHectare, 10, nil, "")
+		require.NoError(t, err)
+
+		// Act
+		err = repos.Crops.Save(ctx, c)
+
+		// Assert
+		assert.ErrorIs(t, err, crop.ErrFertilizerNotFound)
+	})
+
+	t.Run("should return error for a crop of a deleted farm", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := saveFarm(t, repos, "Fazenda A")
+		require.NoError(t, repos.Farms.Delete(ctx, f.ID()))
+		c := newCrop(t, "Soja", 10, f.ID())
+
+		// Act
+		err := repos.Crops.Save(ctx, c)
+
+		// Assert
+		assert.ErrorIs(t, err, crop.ErrFarmNotFound)
+	})
+
+	t.Run("should delete a crop with its history", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := saveFarm(t, repos, "Fazenda A")
+		c := newCrop(t, "Soja", 10, f.ID())
+		require.NoError(t, repos.Crops.Save(ctx, c))
+		require.NoError(t, c.Plant(time.Now()))
+		require.NoError(t, repos.Crops.Save(ctx, c))
+
+		// Act
+		err := repos.Crops.Delete(ctx, c.ID())
+
+		// Assert
+		require.NoError(t, err)
+		_, err = repos.Crops.FindByID(ctx, c.ID())
+		assert.ErrorIs(t, err, crop.ErrCropNotFound)
+		require.NoError(t, repos.Farms.Delete(ctx, f.ID()))
+	})
+
+	t.Run("should list the crops of a farm by status", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := saveFarm(t, repos, "Fazenda A")
+		other := saveFarm(t, repos, "Fazenda B")
+		planted := newCrop(t, "Soja", 10, f.ID())
+		require.NoError(t, planted.Plant(time.Now()))
+		require.NoError(t, repos.Crops.Save(ctx, planted))
+		require.NoError(t, repos.Crops.Save(ctx, newCrop(t, "Milho", 10, f.ID())))
+		require.NoError(t, repos.Crops.Save(ctx, newCrop(t, "Trigo", 10, other.ID())))
+
+		// Act
+		result, err := repos.Crops.List(ctx, crop.Filter{FarmID: f.ID(), Status: crop.StatusPlanned}, newPage(t, 0, "", "", crop.SortFields))
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, result.Items, 1)
+		assert.Equal(t, "Milho", result.Items[0].Name())
+	})
+}
+
+func testFertilizers(t *testing.T, open func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("should save a fertilizer and find it by ID", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		composition, err := fertilizer.ParseComposition("10-10-10")
+		require.NoError(t, err)
+		f, err := fertilizer.NewFertilizer("NPK", "Yara", composition)
+		require.NoError(t, err)
+
+		// Act
+		err = repos.Fertilizers.Save(ctx, f)
+
+		// Assert
+		require.NoError(t, err)
+		require.NotZero(t, f.ID())
+		found, err := repos.Fertilizers.FindByID(ctx, f.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "NPK", found.Name())
+		assert.Equal(t, "Yara", found.Brand())
+		assert.True(t, composition.Equals(found.Composition()))
+	})
+
+	t.Run("should find fertilizers by IDs, skipping unknown ones", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		urea := saveFertilizer(t, repos, "Ureia", "45-00-00")
+		kcl := saveFertilizer(t, repos, "KCl", "00-00-60")
+
+		// Act
+		found, err := repos.Fertilizers.FindByIDs(ctx, []int64{urea.ID(), kcl.ID(), 999})
+
+		// Assert
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int64{urea.ID(), kcl.ID()}, []int64{found[0].ID(), found[1].ID()})
+		assert.Len(t, found, 2)
+	})
+
+	t.Run("should list fertilizers matching the filter", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		saveFertilizer(t, repos, "Ureia", "45-00-00")
+		saveFertilizer(t, repos, "KCl", "00-00-60")
+
+		// Act
+		result, err := repos.Fertilizers.List(ctx, fertilizer.Filter{NamePrefix: "Ure"}, newPage(t, 0, "", "", fertilizer.SortFields))
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, result.Items, 1)
+		assert.Equal(t, "Ureia", result.Items[0].Name())
+	})
+
+	t.Run("should delete a fertilizer", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		urea := saveFertilizer(t, repos, "Ureia", "45-00-00")
+
+		// Act
+		err := repos.Fertilizers.Delete(ctx, urea.ID())
+
+		// Assert
+		require.NoError(t, err)
+		_, err = repos.Fertilizers.FindByID(ctx, urea.ID())
+		assert.ErrorIs(t, err, fertilizer.ErrFertilizerNotFound)
+		exists, err := repos.Fertilizers.ExistsByID(ctx, urea.ID())
+		require.NoError(t, err)
+		assert.False(t, exists)
+		assert.ErrorIs(t, repos.Fertilizers.Delete(ctx, urea.ID()), fertilizer.ErrFertilizerNotFound)
+	})
+}
+
+func testPersons(t *testing.T, open func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("should save a person and find it by ID and username", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+
+		// Act
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+
+		// Assert
+		found, err := repos.Persons.FindByID(ctx, p.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "john_doe", found.Username())
+		assert.NoError(t, found.Authenticate("SecurePass123!"))
+		byUsername, err := repos.Persons.FindByUsername(ctx, "john_doe")
+		require.NoError(t, err)
+		assert.Equal(t, p.ID(), byUsername.ID())
+		exists, err := repos.Persons.UsernameExists(ctx, "john_doe")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("should return error for an unknown person", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+
+		// Act
+		_, err := repos.Persons.FindByID(ctx, 999)
+
+		// Assert
+		assert.ErrorIs(t, err, person.ErrPersonNotFound)
+		_, err = repos.Persons.FindByUsername(ctx, "nobody")
+		assert.ErrorIs(t, err, person.ErrPersonNotFound)
+		_, err = repos.Persons.Update(ctx, 999, func(*person.Person) error { return nil })
+		assert.ErrorIs(t, err, person.ErrPersonNotFound)
+	})
+
+	t.Run("should return error for a taken username", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		savePerson(t, repos, "john_doe", person.RoleUser.String())
+		p, err := person.NewPerson("john_doe", "SecurePass123!", person.RoleUser.String(), person.DefaultPasswordPolicy())
+		require.NoError(t, err)
+
+		// Act
+		err = repos.Persons.Save(ctx, p)
+
+		// Assert
+		assert.ErrorIs(t, err, person.ErrUsernameAlreadyExists)
+	})
+
+	t.Run("should update a person and keep the change on error", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		savePerson(t, repos, "jane_doe", person.RoleUser.String())
+
+		// Act
+		_, err := repos.Persons.Update(ctx, p.ID(), func(p *person.Person) error { return p.ChangeUsername("john_smith") })
+		require.NoError(t, err)
+		_, conflictErr := repos.Persons.Update(ctx, p.ID(), func(p *person.Person) error { return p.ChangeUsername("jane_doe") })
+
+		// Assert
+		assert.ErrorIs(t, conflictErr, person.ErrUsernameAlreadyExists)
+		found, err := repos.Persons.FindByID(ctx, p.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "john_smith", found.Username())
+	})
+
+	t.Run("should record role changes and count the admins", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		admin := savePerson(t, repos, "admin", person.RoleAdmin.String())
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+		var admins int
+
+		// Act
+		_, err := repos.Persons.UpdateRole(ctx, p.ID(), func(p *person.Person, count int) error {
+			admins = count
+			return p.PromoteToRole(person.RoleManager.String(), admin.ID())
+		})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, admins)
+		changes, err := repos.Persons.FindRoleChanges(ctx, p.ID())
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, person.RoleUser, changes[0].From())
+		assert.Equal(t, person.RoleManager, changes[0].To())
+		assert.Equal(t, admin.ID(), changes[0].ChangedBy())
+	})
+
+	t.Run("should keep both of two concurrent changes of a person", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		admin := savePerson(t, repos, "admin", person.RoleAdmin.String())
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+
+		// Act
+		failed := concurrently(
+			func() error {
+				_, err := repos.Persons.Update(ctx, p.ID(), func(p *person.Person) error {
+					holdLock()
+					return p.ChangeUsername("john_smith")
+				})
+				return err
+			},
+			func() error {
+				_, err := repos.Persons.UpdateRole(ctx, p.ID(), func(p *person.Person, _ int) error {
+					holdLock()
+					return p.PromoteToRole(person.RoleManager.String(), admin.ID())
+				})
+				return err
+			},
+		)
+
+		// Assert
+		require.Empty(t, failed)
+		found, err := repos.Persons.FindByID(ctx, p.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "john_smith", found.Username())
+		assert.Equal(t, person.RoleManager, found.Role())
+	})
+
+	t.Run("should not demote the last two admins at the same time", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		first := savePerson(t, repos, "admin", person.RoleAdmin.String())
+		second := savePerson(t, repos, "root", person.RoleAdmin.String())
+		demote := func(id, by int64) func() error {
+			return func() error {
+				_, err := repos.Persons.UpdateRole(ctx, id, func(p *person.Person, admins int) error {
+					holdLock()
+					if admins <= 1 {
+						return person.ErrLastAdmin
+					}
+					return p.PromoteToRole(person.RoleUser.String(), by)
+				})
+				return err
+			}
+		}
+
+		// Act
+		failed := concurrently(demote(first.ID(), second.ID()), demote(second.ID(), first.ID()))
+
+		// Assert
+		require.Len(t, failed, 1)
+		assert.ErrorIs(t, failed[0], person.ErrLastAdmin)
+		admins, err := repos.Persons.List(ctx, person.Filter{Role: person.RoleAdmin}, newPage(t, 0, "", "username", person.SortFields))
+		require.NoError(t, err)
+		assert.Len(t, admins.Items, 1)
+	})
+
+	t.Run("should list persons by role", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		savePerson(t, repos, "admin", person.RoleAdmin.String())
+		savePerson(t, repos, "john_doe", person.RoleUser.String())
+		savePerson(t, repos, "jane_doe", person.RoleUser.String())
+
+		// Act
+		result, err := repos.Persons.List(ctx, person.Filter{Role: person.RoleUser}, newPage(t, 0, "", "username", person.SortFields))
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, result.Items, 2)
+		assert.Equal(t, "jane_doe", result.Items[0].Username())
+		assert.Equal(t, "john_doe", result.Items[1].Username())
+	})
+
+	t.Run("should delete a person but not the last admin", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		admin := savePerson(t, repos, "admin", person.RoleAdmin.String())
+		p := savePerson(t, repos, "john_doe", person.RoleUser.String())
+
+		// Act
+		err := repos.Persons.Delete(ctx, p.ID())
+
+		// Assert
+		require.NoError(t, err)
+		_, err = repos.Persons.FindByID(ctx, p.ID())
+		assert.ErrorIs(t, err, person.ErrPersonNotFound)
+		assert.ErrorIs(t, repos.Persons.Delete(ctx, p.ID()), person.ErrPersonNotFound)
+		assert.ErrorIs(t, repos.Persons.Delete(ctx, admin.ID()), person.ErrLastAdmin)
+	})
+}
+
+// concurrently runs the functions at the same time and returns the errors of
+// those that failed
+func concurrently(fns ...func() error) []error {
+	errs := make([]error, len(fns))
+	var wg sync.WaitGroup
+	wg.Add(len(fns))
+	for i, fn := range fns {
+		go func(i int, fn func() error) {
+			defer wg.Done()
+			errs[i] = fn()
+		}(i, fn)
+	}
+	wg.Wait()
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	return failed
+}
+
+// holdLock keeps a transaction open for a moment after its read, so that two
+// concurrent read-modify-writes overlap unless the repository locks the rows
+func holdLock() {
+	time.Sleep(50 * time.Millisecond)
+}
+
+func newFarm(t *testing.T, name string, size float64) *farm.Farm {
+	t.Helper()
+	f, err := farm.NewFarm(name, size)
+	require.NoError(t, err)
+	return f
+}
+
+func saveFarm(t *testing.T, repos Repositories, name string) *farm.Farm {
+	t.Helper()
+	f := newFarm(t, name, 100)
+	require.NoError(t, repos.Farms.Save(context.Background(), f))
+	return f
+}
+
+func newCrop(t *testing.T, name string, area float64, farmID int64) *crop.Crop {
+	t.Helper()
+	c, err := crop.NewCrop(name, area, farmID, nil, nil)
+	require.NoError(t, err)
+	return c
+}
+
+func saveFertilizer(t *testing.T, repos Repositories, name, formula string) *fertilizer.Fertilizer {
+	t.Helper()
+	composition, err := fertilizer.ParseComposition(formula)
+	require.NoError(t, err)
+	f, err := fertilizer.NewFertilizer(name, "Yara", composition)
+	require.NoError(t, err)
+	require.NoError(t, repos.Fertilizers.Save(context.Background(), f))
+	return f
+}
+
+func savePerson(t *testing.T, repos Repositories, username, role string) *person.Person {
+	t.Helper()
+	p, err := person.NewPerson(username, "SecurePass123!", role, person.DefaultPasswordPolicy())
+	require.NoError(t, err)
+	require.NoError(t, repos.Persons.Save(context.Background(), p))
+	return p
+}
+
+func newPage(t *testing.T, limit int, cursor, sortField string, sortFields []string) query.Page {
+	t.Helper()
+	page, err := query.NewPage(limit, cursor, sortField, "asc", sortFields)
+	require.NoError(t, err)
+	return page
+}
+
+func farmNames(farms []*farm.Farm) []string {
+	names := make([]string, len(farms))
+	for i, f := range farms {
+		names[i] = f.Name()
+	}
+	return names
+}
@@ -0,0 +1,117 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errAbort = errors.New("abort")
+
+func testTransactions(t *testing.T, open func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("should commit the writes of a unit of work together", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := newFarm(t, "Fazenda Boa Vista", 150.5)
+		var c *crop.Crop
+
+		// Act
+		err := repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+			if err := repos.Farms.Save(ctx, f); err != nil {
+				return err
+			}
+			// The transaction sees its own writes
+			exists, err := repos.Farms.ExistsByID(ctx, f.ID())
+			if err != nil || !exists {
+				return errors.Join(err, errAbort)
+			}
+			c = newCrop(t, "Soja", 100, f.ID())
+			return repos.Crops.Save(ctx, c)
+		})
+
+		// Assert
+		require.NoError(t, err)
+		found, err := repos.Crops.FindByID(ctx, c.ID())
+		require.NoError(t, err)
+		assert.Equal(t, f.ID(), found.FarmID())
+	})
+
+	t.Run("should roll back every write when the unit of work fails", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := newFarm(t, "Fazenda Boa Vista", 150.5)
+
+		// Act
+		err := repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+			if err := repos.Farms.Save(ctx, f); err != nil {
+				return err
+			}
+			if err := repos.Crops.Save(ctx, newCrop(t, "Soja", 100, f.ID())); err != nil {
+				return err
+			}
+			return errAbort
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, errAbort)
+		exists, err := repos.Farms.ExistsByID(ctx, f.ID())
+		require.NoError(t, err)
+		assert.False(t, exists)
+		entries, err := repos.Audit.List(ctx, audit.Filter{}, newPage(t, 0, "", "", audit.SortFields))
+		require.NoError(t, err)
+		assert.Empty(t, entries.Items)
+	})
+
+	t.Run("should join the transaction already in the context", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		f := newFarm(t, "Fazenda Boa Vista", 150.5)
+
+		// Act
+		err := repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+			if err := repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+				return repos.Farms.Save(ctx, f)
+			}); err != nil {
+				return err
+			}
+			return errAbort
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, errAbort)
+		exists, err := repos.Farms.ExistsByID(ctx, f.ID())
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("should keep the transaction usable after a failed write", func(t *testing.T) {
+		// Arrange
+		repos := open(t)
+		savePerson(t, repos, "john_doe", person.RoleUser.String())
+		duplicate, err := person.NewPerson("john_doe", "SecurePass123!", person.RoleUser.String(), person.DefaultPasswordPolicy())
+		require.NoError(t, err)
+		f := newFarm(t, "Fazenda Boa Vista", 150.5)
+
+		// Act
+		err = repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+			if err := repos.Persons.Save(ctx, duplicate); !errors.Is(err, person.ErrUsernameAlreadyExists) {
+				return errors.Join(err, errAbort)
+			}
+			return repos.Farms.Save(ctx, f)
+		})
+
+		// Assert
+		require.NoError(t, err)
+		exists, err := repos.Farms.ExistsByID(ctx, f.ID())
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+}
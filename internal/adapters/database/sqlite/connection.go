@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cropflow/api/config"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NewSQLiteConnection opens the SQLite database file at cfg.DBPath, creating
+// it when missing. Transactions take the write lock when they begin, so
+// concurrent writers wait for each other instead of failing to upgrade a read
+// lock, and WAL keeps reads going meanwhile.
+func NewSQLiteConnection(cfg *config.Config) (*gorm.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate", cfg.DBPath)
+
+	db, err := gorm.Open(utcDialector{&sqlite.Dialector{DSN: dsn}}, &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	return db, nil
+}
+
+// utcDialector writes every time in UTC. SQLite stores times as text with
+// their offset and compares them as text, so times written in different
+// zones, e.g. a local timestamp and a UTC cursor, would not sort correctly.
+type utcDialector struct {
+	*sqlite.Dialector
+}
+
+// BindVarTo converts the variable just added to the statement, which gorm
+// appends to stmt.Vars before binding it
+func (d utcDialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement, v interface{}) {
+	if last := len(stmt.Vars) - 1; last >= 0 {
+		switch t := stmt.Vars[last].(type) {
+		case time.Time:
+			stmt.Vars[last] = t.UTC()
+		case *time.Time:
+			if t != nil {
+				utc := t.UTC()
+				stmt.Vars[last] = &utc
+			}
+		}
+	}
+	d.Dialector.BindVarTo(writer, stmt, v)
+}
@@ -0,0 +1,29 @@
+package sqlite
+
+import (
+	"github.com/cropflow/api/internal/infrastructure/migrations"
+	"gorm.io/gorm"
+)
+
+// NewMigrator creates a migrator for the embedded SQLite migration set
+func NewMigrator(db *gorm.DB) (*migrations.Migrator, error) {
+	set, err := migrations.Embedded(migrations.DialectSQLite)
+	if err != nil {
+		return nil, err
+	}
+	return migrations.NewMigrator(db, fileLock{}, set), nil
+}
+
+// fileLock leaves serializing migrations to SQLite, which allows a single
+// writer per database file: each migration runs in a transaction that takes
+// the write lock, and a process that loses the race fails recording the
+// version instead of applying it twice
+type fileLock struct{}
+
+func (fileLock) Lock(conn *gorm.DB) error {
+	return nil
+}
+
+func (fileLock) Unlock(conn *gorm.DB) error {
+	return nil
+}
@@ -0,0 +1,95 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/config"
+	"github.com/cropflow/api/internal/adapters/database/gormrepo"
+	"github.com/cropflow/api/internal/adapters/database/repotest"
+	"github.com/cropflow/api/internal/adapters/database/sqlite"
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// openDatabase opens a migrated database in a file removed with the test
+func openDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := sqlite.NewSQLiteConnection(&config.Config{DBPath: filepath.Join(t.TempDir(), "cropflow.db")})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	migrator, err := sqlite.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	return db
+}
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		db := openDatabase(t)
+		return repotest.Gorm(db)
+	})
+}
+
+func TestMigrator(t *testing.T) {
+	t.Run("should revert and reapply every migration", func(t *testing.T) {
+		// Arrange
+		db := openDatabase(t)
+		migrator, err := sqlite.NewMigrator(db)
+		require.NoError(t, err)
+		statuses, err := migrator.Status(context.Background())
+		require.NoError(t, err)
+
+		// Act
+		reverted, err := migrator.Down(context.Background(), len(statuses))
+		require.NoError(t, err)
+		applied, err := migrator.Up(context.Background())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Len(t, reverted, len(statuses))
+		assert.Len(t, applied, len(statuses))
+		for _, status := range statuses {
+			assert.NotNil(t, status.AppliedAt)
+		}
+	})
+	t.Run("should turn the crop-fertilizer links into applications of unknown dose", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		db := openDatabase(t)
+		migrator, err := sqlite.NewMigrator(db)
+		require.NoError(t, err)
+		_, err = migrator.Down(ctx, 1)
+		require.NoError(t, err)
+		plantedAt := time.Date(2024, 9, 15, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, db.Exec("INSERT INTO farms (name, size) VALUES ('Fazenda Boa Vista', 150.5)").Error)
+		require.NoError(t, db.Exec("INSERT INTO crops (name, planted_area, farm_id, planting_date) VALUES ('Soja', 100, 1, ?)", plantedAt).Error)
+		require.NoError(t, db.Exec("INSERT INTO fertilizer (name, brand, composition) VALUES ('NPK', 'Yara', '10-10-10')").Error)
+		require.NoError(t, db.Exec("INSERT INTO crop_fertilizer (fertilizer_id, crop_id) VALUES (1, 1)").Error)
+
+		// Act
+		_, err = migrator.Up(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		applications, err := gormrepo.NewCropRepository(db).FindApplications(ctx, crop.ApplicationFilter{CropID: 1})
+		require.NoError(t, err)
+		require.Len(t, applications, 1)
+		assert.Equal(t, int64(1), applications[0].FertilizerID())
+		assert.Equal(t, crop.DoseUnknown, applications[0].DoseUnit())
+		assert.Zero(t, applications[0].Dose())
+		assert.Equal(t, 100.0, applications[0].AppliedArea())
+		assert.True(t, plantedAt.Equal(applications[0].AppliedAt()))
+		assert.False(t, db.Migrator().HasTable("crop_fertilizer"))
+	})
+}
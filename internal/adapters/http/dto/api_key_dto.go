@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/cropflow/api/internal/domain/apikey"
+)
+
+// APIKeyBodyDTO represents the request body for creating an API key. ownerId
+// is only accepted for service keys; role and farmIds narrow what the key can do.
+type APIKeyBodyDTO struct {
+	Name      string     `json:"name" binding:"required"`
+	Kind      string     `json:"kind"`
+	OwnerID   int64      `json:"ownerId"`
+	Role      string     `json:"role"`
+	FarmIDs   []int64    `json:"farmIds"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// APIKeyQueryDTO represents the query parameters of the API key list
+type APIKeyQueryDTO struct {
+	OwnerID int64 `form:"ownerId"`
+}
+
+// APIKeyDTO represents the response for API key data. Key holds the plain key
+// and is only set in the response to its creation.
+type APIKeyDTO struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Kind       string     `json:"kind"`
+	Prefix     string     `json:"prefix"`
+	Key        string     `json:"key,omitempty"`
+	OwnerID    int64      `json:"ownerId"`
+	Role       string     `json:"role"`
+	FarmIDs    []int64    `json:"farmIds"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// NewAPIKeyDTO maps an API key to its response representation
+func NewAPIKeyDTO(k *apikey.Key) APIKeyDTO {
+	farmIDs := k.FarmIDs()
+	if farmIDs == nil {
+		farmIDs = []int64{}
+	}
+	return APIKeyDTO{
+		ID:         k.ID(),
+		Name:       k.Name(),
+		Kind:       k.Kind().String(),
+		Prefix:     k.Prefix(),
+		OwnerID:    k.OwnerID(),
+		Role:       k.Role().String(),
+		FarmIDs:    farmIDs,
+		ExpiresAt:  k.ExpiresAt(),
+		LastUsedAt: k.LastUsedAt(),
+		RevokedAt:  k.RevokedAt(),
+		CreatedAt:  k.CreatedAt(),
+	}
+}
+
+// NewAPIKeyDTOList maps a list of API keys to their response representation
+func NewAPIKeyDTOList(keys []*apikey.Key) []APIKeyDTO {
+	response := make([]APIKeyDTO, len(keys))
+	for i, k := range keys {
+		response[i] = NewAPIKeyDTO(k)
+	}
+	return response
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/cropflow/api/internal/domain/crop"
+)
+
+// ApplicationBodyDTO represents the request body for fertilizer application recording
+type ApplicationBodyDTO struct {
+	FertilizerID int64      `json:"fertilizerId" binding:"required"`
+	AppliedAt    *time.Time `json:"appliedAt,omitempty"`
+	Dose         float64    `json:"dose" binding:"required"`
+	DoseUnit     string     `json:"doseUnit" binding:"required"`
+	AppliedArea  *float64   `json:"appliedArea,omitempty"`
+	OperatorID   *int64     `json:"operatorId,omitempty"`
+	Notes        string     `json:"notes,omitempty"`
+}
+
+// ApplicationDTO represents the response for fertilizer application data
+type ApplicationDTO struct {
+	ID           int64     `json:"id"`
+	CropID       int64     `json:"cropId"`
+	FertilizerID int64     `json:"fertilizerId"`
+	AppliedAt    time.Time `json:"appliedAt"`
+	Dose         float64   `json:"dose"`
+	DoseUnit     string    `json:"doseUnit"`
+	AppliedArea  float64   `json:"appliedArea"`
+	TotalAmount  float64   `json:"totalAmount"`
+	OperatorID   *int64    `json:"operatorId,omitempty"`
+	Notes        string    `json:"notes,omitempty"`
+}
+
+// NewApplicationDTO maps a fertilizer application entity to its response representation
+func NewApplicationDTO(a *crop.Application) ApplicationDTO {
+	return ApplicationDTO{
+		ID:           a.ID(),
+		CropID:       a.CropID(),
+		FertilizerID: a.FertilizerID(),
+		AppliedAt:    a.AppliedAt(),
+		Dose:         a.Dose(),
+		DoseUnit:     a.DoseUnit().String(),
+		AppliedArea:  a.AppliedArea(),
+		TotalAmount:  a.TotalAmount(),
+		OperatorID:   a.OperatorID(),
+		Notes:        a.Notes(),
+	}
+}
+
+// NewApplicationDTOList maps a list of fertilizer application entities to their response representation
+func NewApplicationDTOList(applications []*crop.Application) []ApplicationDTO {
+	response := make([]ApplicationDTO, len(applications))
+	for i, a := range applications {
+		response[i] = NewApplicationDTO(a)
+	}
+	return response
+}
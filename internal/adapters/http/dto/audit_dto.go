@@ -0,0 +1,103 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/cropflow/api/internal/domain/audit"
+)
+
+// AuditListQueryDTO represents the query parameters of audit log listings
+type AuditListQueryDTO struct {
+	PageQueryDTO
+	ActorID      int64      `form:"actorId"`
+	Action       string     `form:"action"`
+	ResourceType string     `form:"resourceType"`
+	ResourceID   int64      `form:"resourceId"`
+	RequestID    string     `form:"requestId"`
+	From         *time.Time `form:"from"`
+	To           *time.Time `form:"to"`
+}
+
+// ToFilter maps the query parameters to an audit filter
+func (q AuditListQueryDTO) ToFilter() (audit.Filter, error) {
+	filter := audit.Filter{
+		ActorID:    q.ActorID,
+		ResourceID: q.ResourceID,
+		RequestID:  q.RequestID,
+		From:       q.From,
+		To:         q.To,
+	}
+	if q.From != nil && q.To != nil && q.From.After(*q.To) {
+		return audit.Filter{}, audit.ErrInvalidPeriod
+	}
+	if q.Action != "" {
+		action, err := audit.NewAction(q.Action)
+		if err != nil {
+			return audit.Filter{}, err
+		}
+		filter.Action = action
+	}
+	if q.ResourceType != "" {
+		resourceType, err := audit.NewResourceType(q.ResourceType)
+		if err != nil {
+			return audit.Filter{}, err
+		}
+		filter.ResourceType = resourceType
+	}
+	return filter, nil
+}
+
+// AuditChangeDTO represents the value of a field before and after a change
+type AuditChangeDTO struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditEntryDTO represents the response for an audit log entry
+type AuditEntryDTO struct {
+	ID            int64                     `json:"id"`
+	ActorID       *int64                    `json:"actorId"`
+	ActorUsername string                    `json:"actorUsername,omitempty"`
+	APIKeyID      *int64                    `json:"apiKeyId,omitempty"`
+	Action        string                    `json:"action"`
+	ResourceType  string                    `json:"resourceType"`
+	ResourceID    int64                     `json:"resourceId"`
+	Changes       map[string]AuditChangeDTO `json:"changes"`
+	RequestID     string                    `json:"requestId,omitempty"`
+	OccurredAt    time.Time                 `json:"occurredAt"`
+}
+
+// NewAuditEntryDTO maps an audit entry to its response representation
+func NewAuditEntryDTO(e *audit.Entry) AuditEntryDTO {
+	changes := make(map[string]AuditChangeDTO, len(e.Changes()))
+	for field, c := range e.Changes() {
+		changes[field] = AuditChangeDTO{Before: c.Before, After: c.After}
+	}
+
+	response := AuditEntryDTO{
+		ID:            e.ID(),
+		ActorUsername: e.ActorUsername(),
+		Action:        e.Action().String(),
+		ResourceType:  e.ResourceType().String(),
+		ResourceID:    e.ResourceID(),
+		Changes:       changes,
+		RequestID:     e.RequestID(),
+		OccurredAt:    e.OccurredAt(),
+	}
+	if actorID := e.ActorID(); actorID != 0 {
+		response.ActorID = &actorID
+	}
+	if apiKeyID := e.APIKeyID(); apiKeyID != 0 {
+		response.APIKeyID = &apiKeyID
+	}
+	return response
+}
+
+// NewAuditEntryDTOList maps a list of audit entries to their response representation
+func NewAuditEntryDTOList(entries []*audit.Entry) []AuditEntryDTO {
+	response := make([]AuditEntryDTO, len(entries))
+	for i, e := range entries {
+		response[i] = NewAuditEntryDTO(e)
+	}
+	return response
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/cropflow/api/internal/domain/crop"
+)
 
 // CropBodyDTO represents the request body for crop creation
 type CropBodyDTO struct {
@@ -11,12 +15,98 @@ type CropBodyDTO struct {
 	HarvestDate *time.Time `json:"harvestDate,omitempty"`
 }
 
+// CropListQueryDTO represents the query parameters of crop listings
+type CropListQueryDTO struct {
+	PageQueryDTO
+	Name        string     `form:"name"`
+	Status      string     `form:"status"`
+	PlantedFrom *time.Time `form:"plantedFrom"`
+	PlantedTo   *time.Time `form:"plantedTo"`
+	MinArea     *float64   `form:"minArea"`
+	MaxArea     *float64   `form:"maxArea"`
+}
+
+// ToFilter maps the query parameters to a crop filter
+func (q CropListQueryDTO) ToFilter() (crop.Filter, error) {
+	filter := crop.Filter{
+		NamePrefix:  q.Name,
+		PlantedFrom: q.PlantedFrom,
+		PlantedTo:   q.PlantedTo,
+		MinArea:     q.MinArea,
+		MaxArea:     q.MaxArea,
+	}
+	if q.Status != "" {
+		status, err := crop.NewStatus(q.Status)
+		if err != nil {
+			return crop.Filter{}, err
+		}
+		filter.Status = status
+	}
+	return filter, nil
+}
+
 // CropDTO represents the response for crop data
 type CropDTO struct {
-	ID          int64      `json:"id"`
-	Name        string     `json:"name"`
-	PlantedArea float64    `json:"plantedArea"`
-	FarmID      int64      `json:"farmId"`
-	PlantedDate *time.Time `json:"plantedDate,omitempty"`
-	HarvestDate *time.Time `json:"harvestDate,omitempty"`
-}
\ No newline at end of file
+	ID              int64      `json:"id"`
+	Name            string     `json:"name"`
+	PlantedArea     float64    `json:"plantedArea"`
+	FarmID          int64      `json:"farmId"`
+	PlantedDate     *time.Time `json:"plantedDate,omitempty"`
+	HarvestDate     *time.Time `json:"harvestDate,omitempty"`
+	Status          string     `json:"status"`
+	StatusChangedAt *time.Time `json:"statusChangedAt,omitempty"`
+}
+
+// NewCropDTO maps a crop entity to its response representation
+func NewCropDTO(c *crop.Crop) CropDTO {
+	return CropDTO{
+		ID:              c.ID(),
+		Name:            c.Name(),
+		PlantedArea:     c.PlantedArea(),
+		FarmID:          c.FarmID(),
+		PlantedDate:     c.PlantedDate(),
+		HarvestDate:     c.HarvestDate(),
+		Status:          c.Status().String(),
+		StatusChangedAt: c.StatusChangedAt(),
+	}
+}
+
+// NewCropDTOList maps a list of crop entities to their response representation
+func NewCropDTOList(crops []*crop.Crop) []CropDTO {
+	response := make([]CropDTO, len(crops))
+	for i, c := range crops {
+		response[i] = NewCropDTO(c)
+	}
+	return response
+}
+
+// TransitionBodyDTO represents the request body for a crop status transition
+type TransitionBodyDTO struct {
+	Status     string     `json:"status" binding:"required"`
+	OccurredAt *time.Time `json:"occurredAt,omitempty"`
+	Note       string     `json:"note,omitempty"`
+}
+
+// TransitionDTO represents the response for a crop status transition
+type TransitionDTO struct {
+	From       string    `json:"from,omitempty"`
+	To         string    `json:"to"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Note       string    `json:"note,omitempty"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// NewTransitionDTOList maps a crop status history to its response representation
+func NewTransitionDTOList(transitions []crop.Transition) []TransitionDTO {
+	response := make([]TransitionDTO, len(transitions))
+	for i, t := range transitions {
+		response[i] = TransitionDTO{
+			From:       t.From().String(),
+			To:         t.To().String(),
+			OccurredAt: t.OccurredAt(),
+			Note:       t.Note(),
+			RecordedAt: t.RecordedAt(),
+		}
+	}
+	return response
+}
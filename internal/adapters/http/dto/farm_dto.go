@@ -1,14 +1,51 @@
 package dto
 
+import "github.com/cropflow/api/internal/domain/farm"
+
 // FarmBodyDTO represents the request body for farm creation
 type FarmBodyDTO struct {
 	Name string  `json:"name" binding:"required"`
 	Size float64 `json:"size" binding:"required"`
 }
 
+// FarmListQueryDTO represents the query parameters of farm listings
+type FarmListQueryDTO struct {
+	PageQueryDTO
+	Name    string   `form:"name"`
+	MinSize *float64 `form:"minSize"`
+	MaxSize *float64 `form:"maxSize"`
+}
+
+// ToFilter maps the query parameters to a farm filter
+func (q FarmListQueryDTO) ToFilter() farm.Filter {
+	return farm.Filter{
+		NamePrefix: q.Name,
+		MinSize:    q.MinSize,
+		MaxSize:    q.MaxSize,
+	}
+}
+
 // FarmDTO represents the response for farm data
 type FarmDTO struct {
 	ID   int64   `json:"id"`
 	Name string  `json:"name"`
 	Size float64 `json:"size"`
 }
+
+// NewFarmDTO maps a farm aggregate to its response representation
+func NewFarmDTO(f *farm.Farm) FarmDTO {
+	return FarmDTO{
+		ID:   f.ID(),
+		Name: f.Name(),
+		Size: f.Size().Value(),
+	}
+}
+
+// NewFarmDTOList maps a list of farm aggregates to their response representation
+func NewFarmDTOList(farms []*farm.Farm) []FarmDTO {
+	response := make([]FarmDTO, len(farms))
+	for i, f := range farms {
+		response[i] = NewFarmDTO(f)
+	}
+	return response
+}
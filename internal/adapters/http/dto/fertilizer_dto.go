@@ -1,16 +1,132 @@
 package dto
 
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/cropflow/api/internal/domain/fertilizer"
+)
+
 // FertilizerBodyDTO represents the request body for fertilizer creation
 type FertilizerBodyDTO struct {
-	Name        string `json:"name" binding:"required"`
-	Brand       string `json:"brand" binding:"required"`
-	Composition string `json:"composition" binding:"required"`
+	Name        string               `json:"name" binding:"required"`
+	Brand       string               `json:"brand" binding:"required"`
+	Composition *CompositionInputDTO `json:"composition" binding:"required"`
+}
+
+// NewFertilizerBodyDTO maps a fertilizer entity to its request body representation,
+// used as the merge patch target
+func NewFertilizerBodyDTO(f *fertilizer.Fertilizer) FertilizerBodyDTO {
+	return FertilizerBodyDTO{
+		Name:        f.Name(),
+		Brand:       f.Brand(),
+		Composition: &CompositionInputDTO{Formula: f.Composition().String()},
+	}
+}
+
+// CompositionInputDTO represents a composition in a request body. It accepts
+// the legacy formulation string ("NPK 20-05-20 + 2% S") as well as an object
+// with either a formula or a nutrient percentage map.
+type CompositionInputDTO struct {
+	Formula   string             `json:"formula,omitempty"`
+	Nutrients map[string]float64 `json:"nutrients,omitempty"`
+}
+
+// UnmarshalJSON accepts both the string and the object forms
+func (c *CompositionInputDTO) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*c = CompositionInputDTO{}
+		return json.Unmarshal(data, &c.Formula)
+	}
+
+	type object CompositionInputDTO
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode((*object)(c))
+}
+
+// MarshalJSON writes formula-only compositions in the string form
+func (c CompositionInputDTO) MarshalJSON() ([]byte, error) {
+	if len(c.Nutrients) == 0 {
+		return json.Marshal(c.Formula)
+	}
+	type object CompositionInputDTO
+	return json.Marshal(object(c))
+}
+
+// ToComposition parses the composition, preferring the nutrient map when given
+func (c *CompositionInputDTO) ToComposition() (fertilizer.Composition, error) {
+	if len(c.Nutrients) == 0 {
+		return fertilizer.ParseComposition(c.Formula)
+	}
+
+	percentages := make(map[fertilizer.Nutrient]float64, len(c.Nutrients))
+	for symbol, pct := range c.Nutrients {
+		n, err := fertilizer.NewNutrient(symbol)
+		if err != nil {
+			return fertilizer.Composition{}, err
+		}
+		percentages[n] = pct
+	}
+	return fertilizer.NewComposition(percentages)
+}
+
+// FertilizerListQueryDTO represents the query parameters of fertilizer listings
+type FertilizerListQueryDTO struct {
+	PageQueryDTO
+	Name  string `form:"name"`
+	Brand string `form:"brand"`
+}
+
+// ToFilter maps the query parameters to a fertilizer filter
+func (q FertilizerListQueryDTO) ToFilter() fertilizer.Filter {
+	return fertilizer.Filter{
+		NamePrefix: q.Name,
+		Brand:      q.Brand,
+	}
+}
+
+// CompositionDTO represents the response for a fertilizer composition
+type CompositionDTO struct {
+	Formula   string             `json:"formula"`
+	Nutrients map[string]float64 `json:"nutrients"`
+}
+
+// NewCompositionDTO maps a composition to its response representation
+func NewCompositionDTO(c fertilizer.Composition) CompositionDTO {
+	nutrients := make(map[string]float64)
+	for n, pct := range c.Nutrients() {
+		nutrients[n.String()] = pct
+	}
+	return CompositionDTO{
+		Formula:   c.String(),
+		Nutrients: nutrients,
+	}
 }
 
 // FertilizerDTO represents the response for fertilizer data
 type FertilizerDTO struct {
-	ID          int64  `json:"id"`
-	Name        string `json:"name"`
-	Brand       string `json:"brand"`
-	Composition string `json:"composition"`
+	ID          int64          `json:"id"`
+	Name        string         `json:"name"`
+	Brand       string         `json:"brand"`
+	Composition CompositionDTO `json:"composition"`
+}
+
+// NewFertilizerDTO maps a fertilizer entity to its response representation
+func NewFertilizerDTO(f *fertilizer.Fertilizer) FertilizerDTO {
+	return FertilizerDTO{
+		ID:          f.ID(),
+		Name:        f.Name(),
+		Brand:       f.Brand(),
+		Composition: NewCompositionDTO(f.Composition()),
+	}
+}
+
+// NewFertilizerDTOList maps a list of fertilizer entities to their response representation
+func NewFertilizerDTOList(fertilizers []*fertilizer.Fertilizer) []FertilizerDTO {
+	response := make([]FertilizerDTO, len(fertilizers))
+	for i, f := range fertilizers {
+		response[i] = NewFertilizerDTO(f)
+	}
+	return response
 }
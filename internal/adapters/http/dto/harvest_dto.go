@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/cropflow/api/internal/domain/crop"
+)
+
+// HarvestBodyDTO represents the request body for harvest recording
+type HarvestBodyDTO struct {
+	HarvestedAt *time.Time `json:"harvestedAt,omitempty"`
+	Quantity    float64    `json:"quantity" binding:"required"`
+	Unit        string     `json:"unit" binding:"required"`
+	Moisture    *float64   `json:"moisture,omitempty"`
+	Grade       string     `json:"grade,omitempty"`
+	Partial     bool       `json:"partial"`
+}
+
+// HarvestDTO represents the response for harvest data
+type HarvestDTO struct {
+	ID          int64     `json:"id"`
+	CropID      int64     `json:"cropId"`
+	HarvestedAt time.Time `json:"harvestedAt"`
+	Quantity    float64   `json:"quantity"`
+	Unit        string    `json:"unit"`
+	Kilograms   float64   `json:"kilograms"`
+	Moisture    *float64  `json:"moisture,omitempty"`
+	Grade       string    `json:"grade,omitempty"`
+	Partial     bool      `json:"partial"`
+}
+
+// NewHarvestDTO maps a harvest entity to its response representation
+func NewHarvestDTO(h *crop.Harvest) HarvestDTO {
+	return HarvestDTO{
+		ID:          h.ID(),
+		CropID:      h.CropID(),
+		HarvestedAt: h.HarvestedAt(),
+		Quantity:    h.Quantity(),
+		Unit:        h.Unit().String(),
+		Kilograms:   h.Kilograms(),
+		Moisture:    h.Moisture(),
+		Grade:       h.Grade(),
+		Partial:     h.IsPartial(),
+	}
+}
+
+// NewHarvestDTOList maps a list of harvest entities to their response representation
+func NewHarvestDTOList(harvests []*crop.Harvest) []HarvestDTO {
+	response := make([]HarvestDTO, len(harvests))
+	for i, h := range harvests {
+		response[i] = NewHarvestDTO(h)
+	}
+	return response
+}
+
+// YieldQueryDTO represents the query parameters of the yield endpoint
+type YieldQueryDTO struct {
+	Unit string `form:"unit"`
+}
+
+// ToUnit returns the unit the yield is reported in, kilograms by default
+func (q YieldQueryDTO) ToUnit() (crop.Unit, error) {
+	if q.Unit == "" {
+		return crop.UnitKilogram, nil
+	}
+	return crop.NewUnit(q.Unit)
+}
+
+// YieldDTO represents the response for crop yield data
+type YieldDTO struct {
+	CropID          int64   `json:"cropId"`
+	PlantedArea     float64 `json:"plantedArea"`
+	HarvestCount    int     `json:"harvestCount"`
+	Complete        bool    `json:"complete"`
+	Unit            string  `json:"unit"`
+	Total           float64 `json:"total"`
+	YieldPerHectare float64 `json:"yieldPerHectare"`
+}
+
+// NewYieldDTO maps a crop yield to its response representation in the given unit
+func NewYieldDTO(cropID int64, y crop.Yield, unit crop.Unit) YieldDTO {
+	return YieldDTO{
+		CropID:          cropID,
+		PlantedArea:     y.PlantedArea(),
+		HarvestCount:    y.HarvestCount(),
+		Complete:        y.IsComplete(),
+		Unit:            unit.String(),
+		Total:           y.Total(unit),
+		YieldPerHectare: y.PerHectare(unit),
+	}
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/cropflow/api/internal/domain/farm"
+)
+
+// MemberBodyDTO represents the request body for adding a farm member
+type MemberBodyDTO struct {
+	PersonID int64  `json:"personId" binding:"required"`
+	Role     string `json:"role" binding:"required"`
+}
+
+// MemberRoleBodyDTO represents the request body for changing a member role
+type MemberRoleBodyDTO struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// MemberDTO represents the response for farm member data
+type MemberDTO struct {
+	FarmID    int64     `json:"farmId"`
+	PersonID  int64     `json:"personId"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// NewMemberDTO maps a farm member to its response representation
+func NewMemberDTO(m *farm.Member) MemberDTO {
+	return MemberDTO{
+		FarmID:    m.FarmID(),
+		PersonID:  m.PersonID(),
+		Role:      m.Role().String(),
+		CreatedAt: m.CreatedAt(),
+		UpdatedAt: m.UpdatedAt(),
+	}
+}
+
+// NewMemberDTOList maps a list of farm members to their response representation
+func NewMemberDTOList(members []*farm.Member) []MemberDTO {
+	response := make([]MemberDTO, len(members))
+	for i, m := range members {
+		response[i] = NewMemberDTO(m)
+	}
+	return response
+}
@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/cropflow/api/internal/domain/nutrition"
+)
+
+// NutrientBalanceQueryDTO represents the query parameters of the nutrient balance reports
+type NutrientBalanceQueryDTO struct {
+	From *time.Time `form:"from"`
+	To   *time.Time `form:"to"`
+}
+
+// NutrientDTO represents the inputs of a single nutrient in a balance
+type NutrientDTO struct {
+	Nutrient               string   `json:"nutrient"`
+	AppliedKg              float64  `json:"appliedKg"`
+	AppliedKgPerHectare    float64  `json:"appliedKgPerHectare"`
+	TargetKgPerHectare     *float64 `json:"targetKgPerHectare,omitempty"`
+	DifferenceKgPerHectare *float64 `json:"differenceKgPerHectare,omitempty"`
+}
+
+// NutrientBalanceDTO represents the response of a nutrient balance report
+type NutrientBalanceDTO struct {
+	CropID                  int64                `json:"cropId,omitempty"`
+	FarmID                  int64                `json:"farmId,omitempty"`
+	From                    *time.Time           `json:"from,omitempty"`
+	To                      *time.Time           `json:"to,omitempty"`
+	Area                    float64              `json:"area"`
+	Applications            int                  `json:"applications"`
+	UnconvertedApplications int                  `json:"unconvertedApplications"`
+	Nutrients               []NutrientDTO        `json:"nutrients"`
+	Crops                   []NutrientBalanceDTO `json:"crops,omitempty"`
+}
+
+// NewCropNutrientBalanceDTO maps a crop balance to its response representation
+func NewCropNutrientBalanceDTO(b *nutrition.Balance, from, to *time.Time) NutrientBalanceDTO {
+	return NutrientBalanceDTO{
+		CropID:                  b.CropID(),
+		From:                    from,
+		To:                      to,
+		Area:                    b.Area(),
+		Applications:            b.ApplicationCount(),
+		UnconvertedApplications: b.UnconvertedCount(),
+		Nutrients:               newNutrientDTOList(b),
+	}
+}
+
+// NewFarmNutrientBalanceDTO maps a farm balance and its crop breakdown to its response representation
+func NewFarmNutrientBalanceDTO(farmID int64, b *nutrition.Balance, from, to *time.Time) NutrientBalanceDTO {
+	response := NewCropNutrientBalanceDTO(b, from, to)
+	response.FarmID = farmID
+	response.Crops = make([]NutrientBalanceDTO, len(b.Parts()))
+	for i, part := range b.Parts() {
+		response.Crops[i] = NewCropNutrientBalanceDTO(part, nil, nil)
+	}
+	return response
+}
+
+func newNutrientDTOList(b *nutrition.Balance) []NutrientDTO {
+	nutrients := make([]NutrientDTO, 0, len(b.Nutrients()))
+	for _, n := range b.Nutrients() {
+		item := NutrientDTO{
+			Nutrient:            n.String(),
+			AppliedKg:           b.Applied(n),
+			AppliedKgPerHectare: b.AppliedPerHectare(n),
+		}
+		if target, ok := b.TargetPerHectare(n); ok {
+			difference := item.AppliedKgPerHectare - target
+			item.TargetKgPerHectare = &target
+			item.DifferenceKgPerHectare = &difference
+		}
+		nutrients = append(nutrients, item)
+	}
+	return nutrients
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "github.com/cropflow/api/internal/domain/query"
+
+// PageQueryDTO represents the pagination query parameters shared by list endpoints
+type PageQueryDTO struct {
+	Limit     int    `form:"limit"`
+	Cursor    string `form:"cursor"`
+	Sort      string `form:"sort"`
+	Direction string `form:"direction"`
+}
+
+// ToPage validates the parameters against the sort fields of a resource
+func (q PageQueryDTO) ToPage(allowedSorts []string) (query.Page, error) {
+	return query.NewPage(q.Limit, q.Cursor, q.Sort, q.Direction, allowedSorts)
+}
+
+// PaginationDTO represents the cursors returned along with a page
+type PaginationDTO struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+}
+
+// PageDTO represents the response envelope of list endpoints
+type PageDTO[T any] struct {
+	Data       []T           `json:"data"`
+	Pagination PaginationDTO `json:"pagination"`
+}
+
+// NewPageDTO wraps a page of items in the list response envelope
+func NewPageDTO[T any](data []T, page query.Page, nextCursor, prevCursor string) PageDTO[T] {
+	return PageDTO[T]{
+		Data: data,
+		Pagination: PaginationDTO{
+			Limit:      page.Limit,
+			NextCursor: nextCursor,
+			PrevCursor: prevCursor,
+		},
+	}
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+// PasswordChangeBodyDTO represents the request body to change the caller's password
+type PasswordChangeBodyDTO struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required"`
+}
+
+// PasswordResetBodyDTO represents the request body to set a password with a reset token
+type PasswordResetBodyDTO struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required"`
+}
+
+// PasswordResetDTO represents the response to an issued reset token; the
+// token itself is only delivered through the notifier
+type PasswordResetDTO struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+}
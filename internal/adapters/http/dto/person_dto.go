@@ -1,19 +1,102 @@
 package dto
 
-import "github.com/cropflow/api/internal/domain/entities"
+import (
+	"time"
+
+	"github.com/cropflow/api/internal/domain/person"
+)
 
 // PersonBodyDTO represents the request body for person creation
 type PersonBodyDTO struct {
-	Username string         `json:"username" binding:"required"`
-	Password string         `json:"password" binding:"required"`
-	Role     entities.Role  `json:"role" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// PersonUpdateBodyDTO represents the request body for person updates
+type PersonUpdateBodyDTO struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// RoleBodyDTO represents the request body for a role change
+type RoleBodyDTO struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// PersonListQueryDTO represents the query parameters of person listings
+type PersonListQueryDTO struct {
+	PageQueryDTO
+	Username string `form:"username"`
+	Role     string `form:"role"`
+}
+
+// ToFilter maps the query parameters to a person filter
+func (q PersonListQueryDTO) ToFilter() (person.Filter, error) {
+	filter := person.Filter{UsernamePrefix: q.Username}
+	if q.Role != "" {
+		role, err := person.NewRole(q.Role)
+		if err != nil {
+			return person.Filter{}, err
+		}
+		filter.Role = role
+	}
+	return filter, nil
 }
 
 // PersonDTO represents the response for person data
 type PersonDTO struct {
-	ID       int64         `json:"id"`
-	Username string        `json:"username"`
-	Role     entities.Role `json:"role"`
+	ID           int64      `json:"id"`
+	Username     string     `json:"username"`
+	Role         string     `json:"role"`
+	FailedLogins int        `json:"failedLogins"`
+	LockedUntil  *time.Time `json:"lockedUntil,omitempty"`
+}
+
+// NewPersonDTO maps a person aggregate to its response representation
+func NewPersonDTO(p *person.Person) PersonDTO {
+	status := p.LoginStatus()
+	response := PersonDTO{
+		ID:           p.ID(),
+		Username:     p.Username(),
+		Role:         p.Role().String(),
+		FailedLogins: status.Failures(),
+	}
+	if status.IsLocked(time.Now()) {
+		response.LockedUntil = status.LockedUntil()
+	}
+	return response
+}
+
+// NewPersonDTOList maps a list of person aggregates to their response representation
+func NewPersonDTOList(persons []*person.Person) []PersonDTO {
+	response := make([]PersonDTO, len(persons))
+	for i, p := range persons {
+		response[i] = NewPersonDTO(p)
+	}
+	return response
+}
+
+// RoleChangeDTO represents the response for a person role change
+type RoleChangeDTO struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	ChangedBy *int64    `json:"changedBy"`
+	ChangedAt time.Time `json:"changedAt"`
+}
+
+// NewRoleChangeDTOList maps a person role history to its response representation
+func NewRoleChangeDTOList(changes []person.RoleChange) []RoleChangeDTO {
+	response := make([]RoleChangeDTO, len(changes))
+	for i, c := range changes {
+		response[i] = RoleChangeDTO{
+			From:      c.From().String(),
+			To:        c.To().String(),
+			ChangedAt: c.ChangedAt(),
+		}
+		if changedBy := c.ChangedBy(); changedBy != 0 {
+			response[i].ChangedBy = &changedBy
+		}
+	}
+	return response
 }
 
 // LoginBodyDTO represents the login request body
@@ -22,9 +105,30 @@ type LoginBodyDTO struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// RefreshBodyDTO represents the request body for token refresh
+type RefreshBodyDTO struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
 // TokenDTO represents the token response
 type TokenDTO struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	TokenType    string `json:"tokenType"`
+	ExpiresIn    int64  `json:"expiresIn"`
+
+	// RecoveryCodes is only set when a login completed a two-factor enrolment
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+}
+
+// NewTokenDTO maps issued tokens to their response representation
+func NewTokenDTO(accessToken, refreshToken string, expiresIn time.Duration) TokenDTO {
+	return TokenDTO{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(expiresIn.Seconds()),
+	}
 }
 
 // ResponseDTO represents a generic response
@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+// LoginChallengeDTO represents the login response when a second factor is needed.
+// challengeType is TOTP when a code must be sent to /auth/login/totp, or ENROLL
+// when the role requires two-factor authentication and the person must enrol first.
+type LoginChallengeDTO struct {
+	ChallengeToken string `json:"challengeToken"`
+	ChallengeType  string `json:"challengeType"`
+	ExpiresIn      int64  `json:"expiresIn"`
+}
+
+// NewLoginChallengeDTO maps a login challenge to its response representation
+func NewLoginChallengeDTO(token, kind string, expiresIn time.Duration) LoginChallengeDTO {
+	return LoginChallengeDTO{
+		ChallengeToken: token,
+		ChallengeType:  kind,
+		ExpiresIn:      int64(expiresIn.Seconds()),
+	}
+}
+
+// ChallengeBodyDTO represents the request body to enrol during a login
+type ChallengeBodyDTO struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+}
+
+// LoginCodeBodyDTO represents the request body completing a login challenge
+type LoginCodeBodyDTO struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// CodeBodyDTO represents a request body carrying a TOTP or recovery code
+type CodeBodyDTO struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TOTPProvisioningDTO represents the secret to add to an authenticator app
+type TOTPProvisioningDTO struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// RecoveryCodesDTO represents freshly issued recovery codes, shown only once
+type RecoveryCodesDTO struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
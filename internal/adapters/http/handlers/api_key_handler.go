@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/cropflow/api/internal/adapters/http/dto"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler handles API key HTTP requests
+type APIKeyHandler struct {
+	apiKeyUseCase *usecases.APIKeyUseCase
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyUseCase *usecases.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+// CreateAPIKey handles POST /api-keys; the plain key is only returned here
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var body dto.APIKeyBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	key, plain, err := h.apiKeyUseCase.CreateKey(c.Request.Context(), usecases.APIKeyInput{
+		Name:      body.Name,
+		Kind:      body.Kind,
+		OwnerID:   body.OwnerID,
+		Role:      body.Role,
+		FarmIDs:   body.FarmIDs,
+		ExpiresAt: body.ExpiresAt,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := dto.NewAPIKeyDTO(key)
+	response.Key = plain
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetAPIKeys handles GET /api-keys
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	var params dto.APIKeyQueryDTO
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	keys, err := h.apiKeyUseCase.ListKeys(c.Request.Context(), params.OwnerID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIKeyDTOList(keys))
+}
+
+// GetAPIKeyByID handles GET /api-keys/:id
+func (h *APIKeyHandler) GetAPIKeyByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	key, err := h.apiKeyUseCase.GetKey(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIKeyDTO(key))
+}
+
+// RevokeAPIKey handles DELETE /api-keys/:id
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.apiKeyUseCase.RevokeKey(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/cropflow/api/internal/adapters/http/dto"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// ApplicationHandler handles fertilizer application HTTP requests
+type ApplicationHandler struct {
+	cropUseCase *usecases.CropUseCase
+}
+
+// NewApplicationHandler creates a new fertilizer application handler
+func NewApplicationHandler(cropUseCase *usecases.CropUseCase) *ApplicationHandler {
+	return &ApplicationHandler{
+		cropUseCase: cropUseCase,
+	}
+}
+
+// CreateApplication handles POST /crops/:id/applications
+func (h *ApplicationHandler) CreateApplication(c *gin.Context) {
+	cropID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crop id"})
+		return
+	}
+
+	var body dto.ApplicationBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	application, err := h.cropUseCase.RecordApplication(c.Request.Context(), cropID, body.FertilizerID, body.AppliedAt, body.Dose, body.DoseUnit, body.AppliedArea, body.OperatorID, body.Notes)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewApplicationDTO(application))
+}
+
+// GetApplications handles GET /crops/:id/applications
+func (h *ApplicationHandler) GetApplications(c *gin.Context) {
+	cropID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crop id"})
+		return
+	}
+
+	applications, err := h.cropUseCase.ListApplications(c.Request.Context(), cropID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewApplicationDTOList(applications))
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cropflow/api/internal/adapters/http/dto"
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler handles audit log HTTP requests
+type AuditHandler struct {
+	auditUseCase *usecases.AuditUseCase
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditUseCase *usecases.AuditUseCase) *AuditHandler {
+	return &AuditHandler{
+		auditUseCase: auditUseCase,
+	}
+}
+
+// GetAuditEntries handles GET /audit
+func (h *AuditHandler) GetAuditEntries(c *gin.Context) {
+	var params dto.AuditListQueryDTO
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := params.ToPage(audit.SortFields)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	filter, err := params.ToFilter()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	result, err := h.auditUseCase.ListEntries(c.Request.Context(), filter, page)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPageDTO(dto.NewAuditEntryDTOList(result.Items), page, result.NextCursor, result.PrevCursor))
+}
@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/cropflow/api/internal/adapters/http/dto"
+	"github.com/cropflow/api/internal/domain/lockout"
+	"github.com/cropflow/api/internal/domain/person"
 	"github.com/cropflow/api/internal/usecases"
 	"github.com/gin-gonic/gin"
 )
@@ -28,19 +33,115 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authUseCase.Login(body.Username, body.Password)
+	result, err := h.authUseCase.Login(c.Request.Context(), body.Username, body.Password, c.ClientIP())
 	if err != nil {
-		if err == usecases.ErrInvalidCredentials {
+		setRetryAfter(c, err)
+		if errors.Is(err, person.ErrInvalidCredentials) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "invalid credentials"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	response := dto.TokenDTO{
-		Token: token,
+	respondLogin(c, result)
+}
+
+// setRetryAfter tells a locked out client how many seconds to wait before logging in again
+func setRetryAfter(c *gin.Context, err error) {
+	var locked *lockout.LockedError
+	if errors.As(err, &locked) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+	}
+}
+
+// respondLogin writes the tokens of a login, or the challenge that has to be completed first
+func respondLogin(c *gin.Context, result usecases.LoginResult) {
+	if challenge := result.Challenge; challenge != nil {
+		c.JSON(http.StatusOK, dto.NewLoginChallengeDTO(challenge.Token, challenge.Kind.String(), challenge.ExpiresIn))
+		return
+	}
+
+	tokens := result.Tokens
+	c.JSON(http.StatusOK, dto.NewTokenDTO(tokens.AccessToken, tokens.RefreshToken, tokens.ExpiresIn))
+}
+
+// EnrollTOTP handles POST /auth/login/totp/enroll
+func (h *AuthHandler) EnrollTOTP(c *gin.Context) {
+	var body dto.ChallengeBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
+	provisioning, err := h.authUseCase.EnrollForLogin(c.Request.Context(), body.ChallengeToken)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TOTPProvisioningDTO{Secret: provisioning.Secret, ProvisioningURI: provisioning.URI})
+}
+
+// LoginTOTP handles POST /auth/login/totp
+func (h *AuthHandler) LoginTOTP(c *gin.Context) {
+	var body dto.LoginCodeBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, recoveryCodes, err := h.authUseCase.CompleteLogin(c.Request.Context(), body.ChallengeToken, body.Code, c.ClientIP())
+	if err != nil {
+		setRetryAfter(c, err)
+		respondError(c, err)
+		return
+	}
+
+	response := dto.NewTokenDTO(tokens.AccessToken, tokens.RefreshToken, tokens.ExpiresIn)
+	response.RecoveryCodes = recoveryCodes
 	c.JSON(http.StatusOK, response)
 }
+
+// Refresh handles POST /auth/refresh
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var body dto.RefreshBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.authUseCase.Refresh(c.Request.Context(), body.RefreshToken)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewTokenDTO(tokens.AccessToken, tokens.RefreshToken, tokens.ExpiresIn))
+}
+
+// Logout handles POST /auth/logout
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.authUseCase.Logout(c.Request.Context()); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeSessions handles DELETE /persons/:id/sessions
+func (h *AuthHandler) RevokeSessions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if _, err := h.authUseCase.RevokeAllSessions(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
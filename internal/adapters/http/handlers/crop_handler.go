@@ -5,7 +5,7 @@ import (
 	"strconv"
 
 	"github.com/cropflow/api/internal/adapters/http/dto"
-	"github.com/cropflow/api/internal/domain/entities"
+	"github.com/cropflow/api/internal/domain/crop"
 	"github.com/cropflow/api/internal/usecases"
 	"github.com/gin-gonic/gin"
 )
@@ -36,56 +36,42 @@ func (h *CropHandler) CreateCrop(c *gin.Context) {
 		return
 	}
 
-	crop := &entities.Crop{
-		Name:        body.Name,
-		PlantedArea: body.PlantedArea,
-		FarmID:      farmID,
-		PlantedDate: body.PlantedDate,
-		HarvestDate: body.HarvestDate,
-	}
-
-	if err := h.cropUseCase.CreateCrop(crop); err != nil {
-		if err == usecases.ErrFarmNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "farm not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	crop, err := h.cropUseCase.CreateCrop(c.Request.Context(), farmID, body.Name, body.PlantedArea, body.PlantedDate, body.HarvestDate)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	response := dto.CropDTO{
-		ID:          crop.ID,
-		Name:        crop.Name,
-		PlantedArea: crop.PlantedArea,
-		FarmID:      crop.FarmID,
-		PlantedDate: crop.PlantedDate,
-		HarvestDate: crop.HarvestDate,
-	}
-
-	c.JSON(http.StatusCreated, response)
+	c.JSON(http.StatusCreated, dto.NewCropDTO(crop))
 }
 
 // GetAllCrops handles GET /crops
 func (h *CropHandler) GetAllCrops(c *gin.Context) {
-	crops, err := h.cropUseCase.GetAllCrops()
+	var params dto.CropListQueryDTO
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := params.ToPage(crop.SortFields)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	response := make([]dto.CropDTO, len(crops))
-	for i, crop := range crops {
-		response[i] = dto.CropDTO{
-			ID:          crop.ID,
-			Name:        crop.Name,
-			PlantedArea: crop.PlantedArea,
-			FarmID:      crop.FarmID,
-			PlantedDate: crop.PlantedDate,
-			HarvestDate: crop.HarvestDate,
-		}
+	filter, err := params.ToFilter()
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	result, err := h.cropUseCase.ListCrops(c.Request.Context(), filter, page)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPageDTO(dto.NewCropDTOList(result.Items), page, result.NextCursor, result.PrevCursor))
 }
 
 // GetCropByID handles GET /crops/:id
@@ -96,26 +82,13 @@ func (h *CropHandler) GetCropByID(c *gin.Context) {
 		return
 	}
 
-	crop, err := h.cropUseCase.GetCropByID(id)
+	crop, err := h.cropUseCase.GetCropByID(c.Request.Context(), id)
 	if err != nil {
-		if err == usecases.ErrCropNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "crop not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	response := dto.CropDTO{
-		ID:          crop.ID,
-		Name:        crop.Name,
-		PlantedArea: crop.PlantedArea,
-		FarmID:      crop.FarmID,
-		PlantedDate: crop.PlantedDate,
-		HarvestDate: crop.HarvestDate,
-	}
-
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, dto.NewCropDTO(crop))
 }
 
 // GetCropsByFarmID handles GET /farms/:id/crops
@@ -126,58 +99,74 @@ func (h *CropHandler) GetCropsByFarmID(c *gin.Context) {
 		return
 	}
 
-	crops, err := h.cropUseCase.GetCropsByFarmID(farmID)
+	var params dto.CropListQueryDTO
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := params.ToPage(crop.SortFields)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	filter, err := params.ToFilter()
 	if err != nil {
-		if err == usecases.ErrFarmNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "farm not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	response := make([]dto.CropDTO, len(crops))
-	for i, crop := range crops {
-		response[i] = dto.CropDTO{
-			ID:          crop.ID,
-			Name:        crop.Name,
-			PlantedArea: crop.PlantedArea,
-			FarmID:      crop.FarmID,
-			PlantedDate: crop.PlantedDate,
-			HarvestDate: crop.HarvestDate,
-		}
+	result, err := h.cropUseCase.ListCropsByFarmID(c.Request.Context(), farmID, filter, page)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, dto.NewPageDTO(dto.NewCropDTOList(result.Items), page, result.NextCursor, result.PrevCursor))
 }
 
-// AddFertilizerToCrop handles POST /crops/:cropId/fertilizers/:fertilizerId
-func (h *CropHandler) AddFertilizerToCrop(c *gin.Context) {
-	cropID, err := strconv.ParseInt(c.Param("cropId"), 10, 64)
+// CreateTransition handles POST /crops/:id/transitions
+func (h *CropHandler) CreateTransition(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crop id"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 		return
 	}
 
-	fertilizerID, err := strconv.ParseInt(c.Param("fertilizerId"), 10, 64)
+	var body dto.TransitionBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	crop, err := h.cropUseCase.TransitionCrop(c.Request.Context(), id, body.Status, body.OccurredAt, body.Note)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid fertilizer id"})
+		respondError(c, err)
 		return
 	}
 
-	if err := h.cropUseCase.AddFertilizerToCrop(cropID, fertilizerID); err != nil {
-		if err == usecases.ErrCropNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "crop not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	c.JSON(http.StatusCreated, dto.NewCropDTO(crop))
+}
+
+// GetTransitions handles GET /crops/:id/transitions
+func (h *CropHandler) GetTransitions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Fertilizante associado à plantação com sucesso"})
+	transitions, err := h.cropUseCase.GetCropTransitions(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewTransitionDTOList(transitions))
 }
 
-// GetFertilizersByCropID handles GET /crops/:cropId/fertilizers
+// GetFertilizersByCropID handles GET /crop/:cropId/fertilizers
 func (h *CropHandler) GetFertilizersByCropID(c *gin.Context) {
 	cropID, err := strconv.ParseInt(c.Param("cropId"), 10, 64)
 	if err != nil {
@@ -185,25 +174,77 @@ func (h *CropHandler) GetFertilizersByCropID(c *gin.Context) {
 		return
 	}
 
-	fertilizers, err := h.cropUseCase.GetFertilizersByCropID(cropID)
+	fertilizers, err := h.cropUseCase.GetFertilizersByCropID(c.Request.Context(), cropID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewFertilizerDTOList(fertilizers))
+}
+
+// UpdateCrop handles PUT /crops/:id
+func (h *CropHandler) UpdateCrop(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var body dto.CropBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.update(c, id, body)
+}
+
+// PatchCrop handles PATCH /crops/:id
+func (h *CropHandler) PatchCrop(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	crop, err := h.cropUseCase.GetCropByID(c.Request.Context(), id)
 	if err != nil {
-		if err == usecases.ErrCropNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "crop not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	response := make([]dto.FertilizerDTO, len(fertilizers))
-	for i, fertilizer := range fertilizers {
-		response[i] = dto.FertilizerDTO{
-			ID:          fertilizer.ID,
-			Name:        fertilizer.Name,
-			Brand:       fertilizer.Brand,
-			Composition: fertilizer.Composition,
-		}
+	var body dto.CropBodyDTO
+	if err := bindMergePatch(c, dto.NewCropDTO(crop), &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+	h.update(c, id, body)
+}
+
+// DeleteCrop handles DELETE /crops/:id
+func (h *CropHandler) DeleteCrop(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.cropUseCase.DeleteCrop(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CropHandler) update(c *gin.Context, id int64, body dto.CropBodyDTO) {
+	crop, err := h.cropUseCase.UpdateCrop(c.Request.Context(), id, body.Name, body.PlantedArea, body.PlantedDate, body.HarvestDate)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewCropDTO(crop))
+}
@@ -0,0 +1,74 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cropflow/api/internal/adapters/database/memory"
+	"github.com/cropflow/api/internal/adapters/http/handlers"
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCropHandler_PatchCrop(t *testing.T) {
+	tests := []struct {
+		name      string
+		harvested bool
+		patch     string
+		expected  int
+		cropName  string
+	}{
+		{"should change only the fields sent", false, `{"name":"Milho"}`, http.StatusOK, "Milho"},
+		{"should keep the dates of a harvested crop", true, `{"name":"Milho","plantedArea":80}`, http.StatusOK, "Milho"},
+		{"should not give a harvest date to a planned crop", false, `{"harvestDate":"2024-09-01T00:00:00Z"}`, http.StatusUnprocessableEntity, "Soja"},
+		{"should not clear the harvest date of a harvested crop", true, `{"harvestDate":null}`, http.StatusUnprocessableEntity, "Soja"},
+		{"should not move the planted date of a harvested crop", true, `{"plantedDate":"2020-01-01T00:00:00Z"}`, http.StatusUnprocessableEntity, "Soja"},
+		{"should reject removing a required field", false, `{"name":null}`, http.StatusBadRequest, "Soja"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := memory.NewStore()
+			farmUC := newFarmUseCase(store)
+			uc := newCropUseCase(store)
+			ctx := callerContext(t, store, "john_doe", person.RoleManager)
+			f, err := farmUC.CreateFarm(ctx, "Fazenda Boa Vista", 150.5)
+			require.NoError(t, err)
+			c, err := uc.CreateCrop(ctx, f.ID(), "Soja", 100, nil, nil)
+			require.NoError(t, err)
+			if tt.harvested {
+				for _, status := range []crop.Status{crop.StatusPlanted, crop.StatusGrowing, crop.StatusHarvested} {
+					c, err = uc.TransitionCrop(ctx, c.ID(), status.String(), nil, "")
+					require.NoError(t, err)
+				}
+			}
+			h := handlers.NewCropHandler(uc)
+
+			// Act
+			w := serve(ctx, "PATCH", "/crops/:id", "/crops/1", tt.patch, h.PatchCrop)
+
+			// Assert
+			assert.Equal(t, tt.expected, w.Code)
+			found, err := uc.GetCropByID(ctx, c.ID())
+			require.NoError(t, err)
+			assert.Equal(t, tt.cropName, found.Name())
+			assert.Equal(t, c.Status(), found.Status())
+			assert.NoError(t, found.KeepDates(c.PlantedDate(), c.HarvestDate()))
+		})
+	}
+}
+
+// newCropUseCase wires a CropUseCase to the store
+func newCropUseCase(store *memory.Store) *usecases.CropUseCase {
+	return usecases.NewCropUseCase(
+		memory.NewCropRepository(store),
+		memory.NewFarmRepository(store),
+		memory.NewFertilizerRepository(store),
+		memory.NewPersonRepository(store),
+		memory.NewTransactionManager(store),
+	)
+}
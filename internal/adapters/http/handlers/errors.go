@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/cropflow/api/internal/domain/apikey"
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/cropflow/api/internal/domain/farm"
+	"github.com/cropflow/api/internal/domain/fertilizer"
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/lockout"
+	"github.com/cropflow/api/internal/domain/mfa"
+	"github.com/cropflow/api/internal/domain/nutrition"
+	"github.com/cropflow/api/internal/domain/oidc"
+	"github.com/cropflow/api/internal/domain/passwordreset"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/domain/session"
+	"github.com/gin-gonic/gin"
+)
+
+// statusByError maps domain errors to the HTTP status returned to clients
+var statusByError = map[error]int{
+	farm.ErrFarmNotFound:             http.StatusNotFound,
+	crop.ErrCropNotFound:             http.StatusNotFound,
+	crop.ErrFarmNotFound:             http.StatusNotFound,
+	crop.ErrFertilizerNotFound:       http.StatusNotFound,
+	crop.ErrHarvestNotFound:          http.StatusNotFound,
+	crop.ErrOperatorNotFound:         http.StatusNotFound,
+	fertilizer.ErrFertilizerNotFound: http.StatusNotFound,
+	person.ErrPersonNotFound:         http.StatusNotFound,
+	farm.ErrMemberNotFound:           http.StatusNotFound,
+	mfa.ErrEnrollmentNotFound:        http.StatusNotFound,
+	apikey.ErrAPIKeyNotFound:         http.StatusNotFound,
+	oidc.ErrNotConfigured:            http.StatusNotFound,
+
+	identity.ErrUnauthenticated:    http.StatusUnauthorized,
+	session.ErrSessionNotFound:     http.StatusUnauthorized,
+	session.ErrSessionRevoked:      http.StatusUnauthorized,
+	session.ErrInvalidRefreshToken: http.StatusUnauthorized,
+	session.ErrRefreshTokenExpired: http.StatusUnauthorized,
+	session.ErrRefreshTokenReused:  http.StatusUnauthorized,
+	mfa.ErrInvalidCode:             http.StatusUnauthorized,
+	mfa.ErrInvalidChallenge:        http.StatusUnauthorized,
+	mfa.ErrChallengeExpired:        http.StatusUnauthorized,
+	mfa.ErrTooManyAttempts:         http.StatusUnauthorized,
+	farm.ErrFarmAccessDenied:       http.StatusForbidden,
+	person.ErrInvalidCredentials:   http.StatusForbidden,
+	mfa.ErrTOTPRequired:            http.StatusForbidden,
+	apikey.ErrInvalidAPIKey:        http.StatusUnauthorized,
+	apikey.ErrAPIKeyExpired:        http.StatusUnauthorized,
+	apikey.ErrAPIKeyRevoked:        http.StatusUnauthorized,
+	apikey.ErrServiceKeyForAdmin:   http.StatusForbidden,
+	apikey.ErrKeyManagedByKey:      http.StatusForbidden,
+	oidc.ErrProviderDenied:         http.StatusUnauthorized,
+	oidc.ErrInvalidIDToken:         http.StatusUnauthorized,
+	oidc.ErrNoRoleMapped:           http.StatusForbidden,
+
+	farm.ErrFarmHasCrops:            http.StatusConflict,
+	farm.ErrMemberAlreadyExists:     http.StatusConflict,
+	farm.ErrLastOwner:               http.StatusConflict,
+	crop.ErrInvalidTransition:       http.StatusConflict,
+	crop.ErrCropNotHarvestable:      http.StatusConflict,
+	fertilizer.ErrFertilizerInUse:   http.StatusConflict,
+	person.ErrUsernameAlreadyExists: http.StatusConflict,
+	person.ErrLastAdmin:             http.StatusConflict,
+	mfa.ErrTOTPAlreadyEnabled:       http.StatusConflict,
+	mfa.ErrTOTPNotConfirmed:         http.StatusConflict,
+	oidc.ErrUsernameTaken:           http.StatusConflict,
+
+	lockout.ErrLocked: http.StatusTooManyRequests,
+
+	query.ErrInvalidLimit:      http.StatusBadRequest,
+	query.ErrInvalidCursor:     http.StatusBadRequest,
+	query.ErrInvalidSort:       http.StatusBadRequest,
+	query.ErrInvalidDirection:  http.StatusBadRequest,
+	nutrition.ErrInvalidPeriod: http.StatusBadRequest,
+	audit.ErrInvalidPeriod:     http.StatusBadRequest,
+
+	passwordreset.ErrInvalidResetToken: http.StatusBadRequest,
+	passwordreset.ErrResetTokenExpired: http.StatusBadRequest,
+	passwordreset.ErrResetTokenUsed:    http.StatusBadRequest,
+	oidc.ErrInvalidState:               http.StatusBadRequest,
+	oidc.ErrLoginRequestExpired:        http.StatusBadRequest,
+	oidc.ErrLoginRequestConsumed:       http.StatusBadRequest,
+
+	oidc.ErrInvalidDiscovery: http.StatusBadGateway,
+
+	farm.ErrInvalidFarmName:                 http.StatusUnprocessableEntity,
+	farm.ErrInvalidFarmSize:                 http.StatusUnprocessableEntity,
+	farm.ErrInvalidMemberRole:               http.StatusUnprocessableEntity,
+	farm.ErrInvalidMemberPerson:             http.StatusUnprocessableEntity,
+	crop.ErrInvalidCropName:                 http.StatusUnprocessableEntity,
+	crop.ErrInvalidPlantedArea:              http.StatusUnprocessableEntity,
+	crop.ErrInvalidFarmID:                   http.StatusUnprocessableEntity,
+	crop.ErrInvalidHarvestDate:              http.StatusUnprocessableEntity,
+	crop.ErrInvalidStatus:                   http.StatusUnprocessableEntity,
+	crop.ErrInvalidTransitionDate:           http.StatusUnprocessableEntity,
+	crop.ErrDatesSetByTransitions:           http.StatusUnprocessableEntity,
+	crop.ErrInvalidHarvestQuantity:          http.StatusUnprocessableEntity,
+	crop.ErrInvalidUnit:                     http.StatusUnprocessableEntity,
+	crop.ErrInvalidMoisture:                 http.StatusUnprocessableEntity,
+	crop.ErrInvalidDose:                     http.StatusUnprocessableEntity,
+	crop.ErrInvalidDoseUnit:                 http.StatusUnprocessableEntity,
+	crop.ErrInvalidAppliedArea:              http.StatusUnprocessableEntity,
+	fertilizer.ErrInvalidFertilizerName:     http.StatusUnprocessableEntity,
+	fertilizer.ErrInvalidBrand:              http.StatusUnprocessableEntity,
+	fertilizer.ErrInvalidComposition:        http.StatusUnprocessableEntity,
+	fertilizer.ErrUnknownNutrient:           http.StatusUnprocessableEntity,
+	fertilizer.ErrInvalidNutrientPercentage: http.StatusUnprocessableEntity,
+	fertilizer.ErrCompositionOverflow:       http.StatusUnprocessableEntity,
+	person.ErrInvalidUsername:               http.StatusUnprocessableEntity,
+	person.ErrInvalidPassword:               http.StatusUnprocessableEntity,
+	person.ErrPasswordTooLong:               http.StatusUnprocessableEntity,
+	person.ErrPasswordTooWeak:               http.StatusUnprocessableEntity,
+	person.ErrPasswordTooCommon:             http.StatusUnprocessableEntity,
+	person.ErrInvalidRole:                   http.StatusUnprocessableEntity,
+	person.ErrSamePassword:                  http.StatusUnprocessableEntity,
+	apikey.ErrInvalidName:                   http.StatusUnprocessableEntity,
+	apikey.ErrInvalidKind:                   http.StatusUnprocessableEntity,
+	apikey.ErrInvalidOwner:                  http.StatusUnprocessableEntity,
+	apikey.ErrInvalidExpiry:                 http.StatusUnprocessableEntity,
+	apikey.ErrRoleExceedsOwner:              http.StatusUnprocessableEntity,
+	oidc.ErrMissingUsername:                 http.StatusUnprocessableEntity,
+	audit.ErrInvalidAction:                  http.StatusUnprocessableEntity,
+	audit.ErrInvalidResourceType:            http.StatusUnprocessableEntity,
+}
+
+// respondError writes the error response matching a domain error
+func respondError(c *gin.Context, err error) {
+	for domainErr, status := range statusByError {
+		if errors.Is(err, domainErr) {
+			c.JSON(status, gin.H{"error": domainErr.Error()})
+			return
+		}
+	}
+	RespondInternalError(c, err)
+}
+
+// RespondInternalError logs an unexpected error and answers 500 without its
+// details, which may describe the database or the server
+func RespondInternalError(c *gin.Context, err error) {
+	log.Printf("%s %s (request %s): %v", c.Request.Method, c.Request.URL.Path, audit.RequestIDFromContext(c.Request.Context()), err)
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
@@ -5,7 +5,7 @@ import (
 	"strconv"
 
 	"github.com/cropflow/api/internal/adapters/http/dto"
-	"github.com/cropflow/api/internal/domain/entities"
+	"github.com/cropflow/api/internal/domain/farm"
 	"github.com/cropflow/api/internal/usecases"
 	"github.com/gin-gonic/gin"
 )
@@ -30,43 +30,36 @@ func (h *FarmHandler) CreateFarm(c *gin.Context) {
 		return
 	}
 
-	farm := &entities.Farm{
-		Name: body.Name,
-		Size: body.Size,
-	}
-
-	if err := h.farmUseCase.CreateFarm(farm); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	farm, err := h.farmUseCase.CreateFarm(c.Request.Context(), body.Name, body.Size)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	response := dto.FarmDTO{
-		ID:   farm.ID,
-		Name: farm.Name,
-		Size: farm.Size,
-	}
-
-	c.JSON(http.StatusCreated, response)
+	c.JSON(http.StatusCreated, dto.NewFarmDTO(farm))
 }
 
 // GetAllFarms handles GET /farms
 func (h *FarmHandler) GetAllFarms(c *gin.Context) {
-	farms, err := h.farmUseCase.GetAllFarms()
+	var params dto.FarmListQueryDTO
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := params.ToPage(farm.SortFields)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	response := make([]dto.FarmDTO, len(farms))
-	for i, farm := range farms {
-		response[i] = dto.FarmDTO{
-			ID:   farm.ID,
-			Name: farm.Name,
-			Size: farm.Size,
-		}
+	result, err := h.farmUseCase.ListFarms(c.Request.Context(), params.ToFilter(), page)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, dto.NewPageDTO(dto.NewFarmDTOList(result.Items), page, result.NextCursor, result.PrevCursor))
 }
 
 // GetFarmByID handles GET /farms/:id
@@ -77,21 +70,77 @@ func (h *FarmHandler) GetFarmByID(c *gin.Context) {
 		return
 	}
 
-	farm, err := h.farmUseCase.GetFarmByID(id)
+	farm, err := h.farmUseCase.GetFarmByID(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewFarmDTO(farm))
+}
+
+// UpdateFarm handles PUT /farms/:id
+func (h *FarmHandler) UpdateFarm(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var body dto.FarmBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.update(c, id, body)
+}
+
+// PatchFarm handles PATCH /farms/:id
+func (h *FarmHandler) PatchFarm(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	farm, err := h.farmUseCase.GetFarmByID(c.Request.Context(), id)
 	if err != nil {
-		if err == usecases.ErrFarmNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "farm not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	response := dto.FarmDTO{
-		ID:   farm.ID,
-		Name: farm.Name,
-		Size: farm.Size,
+	var body dto.FarmBodyDTO
+	if err := bindMergePatch(c, dto.NewFarmDTO(farm), &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+	h.update(c, id, body)
+}
+
+// DeleteFarm handles DELETE /farms/:id
+func (h *FarmHandler) DeleteFarm(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.farmUseCase.DeleteFarm(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *FarmHandler) update(c *gin.Context, id int64, body dto.FarmBodyDTO) {
+	farm, err := h.farmUseCase.UpdateFarm(c.Request.Context(), id, body.Name, body.Size)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewFarmDTO(farm))
+}
@@ -0,0 +1,108 @@
+package handlers_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/adapters/database/memory"
+	"github.com/cropflow/api/internal/adapters/http/handlers"
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/lockout"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFarmHandler_PatchFarm(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    string
+		expected int
+		body     string
+	}{
+		{"should change only the fields sent", `{"name":"Fazenda Nova"}`, http.StatusOK, `{"id":1,"name":"Fazenda Nova","size":150.5}`},
+		{"should reject removing a required field", `{"size":null}`, http.StatusBadRequest, ""},
+		{"should reject a body that is not an object", `[{"name":"Fazenda Nova"}]`, http.StatusBadRequest, ""},
+		{"should reject a value the farm refuses", `{"size":-1}`, http.StatusUnprocessableEntity, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := memory.NewStore()
+			uc := newFarmUseCase(store)
+			ctx := callerContext(t, store, "john_doe", person.RoleManager)
+			f, err := uc.CreateFarm(ctx, "Fazenda Boa Vista", 150.5)
+			require.NoError(t, err)
+			h := handlers.NewFarmHandler(uc)
+
+			// Act
+			w := serve(ctx, "PATCH", "/farms/:id", "/farms/1", tt.patch, h.PatchFarm)
+
+			// Assert
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.body != "" {
+				assert.JSONEq(t, tt.body, w.Body.String())
+			}
+			found, err := uc.GetFarmByID(ctx, f.ID())
+			require.NoError(t, err)
+			if tt.expected != http.StatusOK {
+				assert.Equal(t, "Fazenda Boa Vista", found.Name())
+			}
+			assert.Equal(t, 150.5, found.Size().Value())
+		})
+	}
+
+	t.Run("should report a farm the caller is not a member of as not found", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := newFarmUseCase(store)
+		_, err := uc.CreateFarm(callerContext(t, store, "john_doe", person.RoleManager), "Fazenda Boa Vista", 150.5)
+		require.NoError(t, err)
+		h := handlers.NewFarmHandler(uc)
+
+		// Act
+		w := serve(callerContext(t, store, "jane_doe", person.RoleManager), "PATCH", "/farms/:id", "/farms/1", `{"name":"Fazenda Nova"}`, h.PatchFarm)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+// newFarmUseCase wires a FarmUseCase to the store
+func newFarmUseCase(store *memory.Store) *usecases.FarmUseCase {
+	return usecases.NewFarmUseCase(memory.NewFarmRepository(store), memory.NewPersonRepository(store), memory.NewTransactionManager(store))
+}
+
+// callerContext saves a person with the role and returns a context authenticated as them
+func callerContext(t *testing.T, store *memory.Store, username string, role person.Role) context.Context {
+	t.Helper()
+	now := time.Now()
+	p := person.Restore(0, username, "$2a$04$hash", role, now, now, lockout.Status{})
+	require.NoError(t, memory.NewPersonRepository(store).Save(context.Background(), p))
+	return identity.NewContext(context.Background(), identity.New(p.ID(), 0, username, role))
+}
+
+// serve sends the JSON body to the handler registered on the route, as the
+// caller authenticated in ctx
+func serve(ctx context.Context, method, route, path, body string, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.RecoveryWithWriter(io.Discard))
+	router.Handle(method, route, func(c *gin.Context) {
+		c.Request = c.Request.WithContext(ctx)
+	}, handler)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
@@ -5,7 +5,7 @@ import (
 	"strconv"
 
 	"github.com/cropflow/api/internal/adapters/http/dto"
-	"github.com/cropflow/api/internal/domain/entities"
+	"github.com/cropflow/api/internal/domain/fertilizer"
 	"github.com/cropflow/api/internal/usecases"
 	"github.com/gin-gonic/gin"
 )
@@ -30,46 +30,42 @@ func (h *FertilizerHandler) CreateFertilizer(c *gin.Context) {
 		return
 	}
 
-	fertilizer := &entities.Fertilizer{
-		Name:        body.Name,
-		Brand:       body.Brand,
-		Composition: body.Composition,
-	}
-
-	if err := h.fertilizerUseCase.CreateFertilizer(fertilizer); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	composition, err := body.Composition.ToComposition()
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	response := dto.FertilizerDTO{
-		ID:          fertilizer.ID,
-		Name:        fertilizer.Name,
-		Brand:       fertilizer.Brand,
-		Composition: fertilizer.Composition,
+	fertilizer, err := h.fertilizerUseCase.CreateFertilizer(c.Request.Context(), body.Name, body.Brand, composition)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusCreated, response)
+	c.JSON(http.StatusCreated, dto.NewFertilizerDTO(fertilizer))
 }
 
 // GetAllFertilizers handles GET /fertilizers
 func (h *FertilizerHandler) GetAllFertilizers(c *gin.Context) {
-	fertilizers, err := h.fertilizerUseCase.GetAllFertilizers()
+	var params dto.FertilizerListQueryDTO
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := params.ToPage(fertilizer.SortFields)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	response := make([]dto.FertilizerDTO, len(fertilizers))
-	for i, fertilizer := range fertilizers {
-		response[i] = dto.FertilizerDTO{
-			ID:          fertilizer.ID,
-			Name:        fertilizer.Name,
-			Brand:       fertilizer.Brand,
-			Composition: fertilizer.Composition,
-		}
+	result, err := h.fertilizerUseCase.ListFertilizers(c.Request.Context(), params.ToFilter(), page)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, dto.NewPageDTO(dto.NewFertilizerDTOList(result.Items), page, result.NextCursor, result.PrevCursor))
 }
 
 // GetFertilizerByID handles GET /fertilizers/:id
@@ -80,22 +76,83 @@ func (h *FertilizerHandler) GetFertilizerByID(c *gin.Context) {
 		return
 	}
 
-	fertilizer, err := h.fertilizerUseCase.GetFertilizerByID(id)
+	fertilizer, err := h.fertilizerUseCase.GetFertilizerByID(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewFertilizerDTO(fertilizer))
+}
+
+// UpdateFertilizer handles PUT /fertilizers/:id
+func (h *FertilizerHandler) UpdateFertilizer(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var body dto.FertilizerBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.update(c, id, body)
+}
+
+// PatchFertilizer handles PATCH /fertilizers/:id
+func (h *FertilizerHandler) PatchFertilizer(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	fertilizer, err := h.fertilizerUseCase.GetFertilizerByID(c.Request.Context(), id)
 	if err != nil {
-		if err == usecases.ErrFertilizerNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "fertilizer not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	response := dto.FertilizerDTO{
-		ID:          fertilizer.ID,
-		Name:        fertilizer.Name,
-		Brand:       fertilizer.Brand,
-		Composition: fertilizer.Composition,
+	var body dto.FertilizerBodyDTO
+	if err := bindMergePatch(c, dto.NewFertilizerBodyDTO(fertilizer), &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+	h.update(c, id, body)
+}
+
+// DeleteFertilizer handles DELETE /fertilizers/:id
+func (h *FertilizerHandler) DeleteFertilizer(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.fertilizerUseCase.DeleteFertilizer(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *FertilizerHandler) update(c *gin.Context, id int64, body dto.FertilizerBodyDTO) {
+	composition, err := body.Composition.ToComposition()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	fertilizer, err := h.fertilizerUseCase.UpdateFertilizer(c.Request.Context(), id, body.Name, body.Brand, composition)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewFertilizerDTO(fertilizer))
+}
@@ -0,0 +1,56 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cropflow/api/internal/adapters/database/memory"
+	"github.com/cropflow/api/internal/adapters/http/handlers"
+	"github.com/cropflow/api/internal/domain/fertilizer"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFertilizerHandler_PatchFertilizer(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    string
+		expected int
+		brand    string
+		formula  string
+	}{
+		{"should change only the fields sent", `{"brand":"Mosaic"}`, http.StatusOK, "Mosaic", "10-10-10"},
+		{"should replace the formula of the composition", `{"composition":{"formula":"20-05-20"}}`, http.StatusOK, "Yara", "20-05-20"},
+		{"should reject removing a required field", `{"brand":null}`, http.StatusBadRequest, "Yara", "10-10-10"},
+		{"should reject a composition that is not a formulation", `{"composition":{"formula":"NPK"}}`, http.StatusUnprocessableEntity, "Yara", "10-10-10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := memory.NewStore()
+			uc := usecases.NewFertilizerUseCase(memory.NewFertilizerRepository(store), memory.NewTransactionManager(store))
+			ctx := callerContext(t, store, "root_admin", person.RoleAdmin)
+			composition, err := fertilizer.ParseComposition("10-10-10")
+			require.NoError(t, err)
+			f, err := uc.CreateFertilizer(ctx, "NPK", "Yara", composition)
+			require.NoError(t, err)
+			h := handlers.NewFertilizerHandler(uc)
+
+			// Act
+			w := serve(ctx, "PATCH", "/fertilizers/:id", "/fertilizers/1", tt.patch, h.PatchFertilizer)
+
+			// Assert
+			assert.Equal(t, tt.expected, w.Code)
+			found, err := uc.GetFertilizerByID(ctx, f.ID())
+			require.NoError(t, err)
+			assert.Equal(t, "NPK", found.Name())
+			assert.Equal(t, tt.brand, found.Brand())
+			expected, err := fertilizer.ParseComposition(tt.formula)
+			require.NoError(t, err)
+			assert.Equal(t, expected.String(), found.Composition().String())
+		})
+	}
+}
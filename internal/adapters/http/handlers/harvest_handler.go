@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/cropflow/api/internal/adapters/http/dto"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// HarvestHandler handles crop harvest HTTP requests
+type HarvestHandler struct {
+	cropUseCase *usecases.CropUseCase
+}
+
+// NewHarvestHandler creates a new harvest handler
+func NewHarvestHandler(cropUseCase *usecases.CropUseCase) *HarvestHandler {
+	return &HarvestHandler{
+		cropUseCase: cropUseCase,
+	}
+}
+
+// CreateHarvest handles POST /crops/:id/harvests
+func (h *HarvestHandler) CreateHarvest(c *gin.Context) {
+	cropID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crop id"})
+		return
+	}
+
+	var body dto.HarvestBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	harvest, err := h.cropUseCase.RecordHarvest(c.Request.Context(), cropID, body.HarvestedAt, body.Quantity, body.Unit, body.Moisture, body.Grade, body.Partial)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewHarvestDTO(harvest))
+}
+
+// GetHarvests handles GET /crops/:id/harvests
+func (h *HarvestHandler) GetHarvests(c *gin.Context) {
+	cropID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crop id"})
+		return
+	}
+
+	harvests, err := h.cropUseCase.ListHarvests(c.Request.Context(), cropID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewHarvestDTOList(harvests))
+}
+
+// GetHarvestByID handles GET /crops/:id/harvests/:harvestId
+func (h *HarvestHandler) GetHarvestByID(c *gin.Context) {
+	cropID, harvestID, ok := parseHarvestIDs(c)
+	if !ok {
+		return
+	}
+
+	harvest, err := h.cropUseCase.GetHarvestByID(c.Request.Context(), cropID, harvestID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewHarvestDTO(harvest))
+}
+
+// DeleteHarvest handles DELETE /crops/:id/harvests/:harvestId
+func (h *HarvestHandler) DeleteHarvest(c *gin.Context) {
+	cropID, harvestID, ok := parseHarvestIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.cropUseCase.DeleteHarvest(c.Request.Context(), cropID, harvestID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetYield handles GET /crops/:id/yield
+func (h *HarvestHandler) GetYield(c *gin.Context) {
+	cropID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crop id"})
+		return
+	}
+
+	var params dto.YieldQueryDTO
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	unit, err := params.ToUnit()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	yield, err := h.cropUseCase.GetCropYield(c.Request.Context(), cropID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewYieldDTO(cropID, yield, unit))
+}
+
+func parseHarvestIDs(c *gin.Context) (int64, int64, bool) {
+	cropID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crop id"})
+		return 0, 0, false
+	}
+
+	harvestID, err := strconv.ParseInt(c.Param("harvestId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid harvest id"})
+		return 0, 0, false
+	}
+	return cropID, harvestID, true
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cropflow/api/internal/infrastructure/security"
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler publishes the public keys used to sign access tokens
+type JWKSHandler struct {
+	jwtService *security.JWTService
+}
+
+// NewJWKSHandler creates a new JWKS handler
+func NewJWKSHandler(jwtService *security.JWTService) *JWKSHandler {
+	return &JWKSHandler{
+		jwtService: jwtService,
+	}
+}
+
+// GetJWKS handles GET /.well-known/jwks.json
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	// Verifiers may cache the set for a short while; a rotated key is
+	// published as soon as it is active and retired keys remain for the overlap
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtService.JWKS())
+}
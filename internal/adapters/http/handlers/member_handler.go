@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/cropflow/api/internal/adapters/http/dto"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// MemberHandler handles farm membership HTTP requests
+type MemberHandler struct {
+	farmUseCase *usecases.FarmUseCase
+}
+
+// NewMemberHandler creates a new farm member handler
+func NewMemberHandler(farmUseCase *usecases.FarmUseCase) *MemberHandler {
+	return &MemberHandler{
+		farmUseCase: farmUseCase,
+	}
+}
+
+// GetMembers handles GET /farms/:id/members
+func (h *MemberHandler) GetMembers(c *gin.Context) {
+	farmID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid farm id"})
+		return
+	}
+
+	members, err := h.farmUseCase.ListMembers(c.Request.Context(), farmID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewMemberDTOList(members))
+}
+
+// CreateMember handles POST /farms/:id/members
+func (h *MemberHandler) CreateMember(c *gin.Context) {
+	farmID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid farm id"})
+		return
+	}
+
+	var body dto.MemberBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := h.farmUseCase.AddMember(c.Request.Context(), farmID, body.PersonID, body.Role)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewMemberDTO(member))
+}
+
+// UpdateMember handles PUT /farms/:id/members/:personId
+func (h *MemberHandler) UpdateMember(c *gin.Context) {
+	farmID, personID, ok := parseMemberIDs(c)
+	if !ok {
+		return
+	}
+
+	var body dto.MemberRoleBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := h.farmUseCase.ChangeMemberRole(c.Request.Context(), farmID, personID, body.Role)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewMemberDTO(member))
+}
+
+// DeleteMember handles DELETE /farms/:id/members/:personId
+func (h *MemberHandler) DeleteMember(c *gin.Context) {
+	farmID, personID, ok := parseMemberIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.farmUseCase.RemoveMember(c.Request.Context(), farmID, personID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseMemberIDs(c *gin.Context) (int64, int64, bool) {
+	farmID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid farm id"})
+		return 0, 0, false
+	}
+	personID, err := strconv.ParseInt(c.Param("personId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid person id"})
+		return 0, 0, false
+	}
+	return farmID, personID, true
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+var errInvalidMergePatch = errors.New("invalid merge patch: body must be a JSON object")
+
+// bindMergePatch applies the request body as a JSON Merge Patch (RFC 7396) onto
+// the current representation and binds the result into dst
+func bindMergePatch(c *gin.Context, current, dst interface{}) error {
+	patchBytes, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+
+	var patch interface{}
+	if err := json.Unmarshal(patchBytes, &patch); err != nil {
+		return err
+	}
+	if _, ok := patch.(map[string]interface{}); !ok {
+		return errInvalidMergePatch
+	}
+
+	currentBytes, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	var target interface{}
+	if err := json.Unmarshal(currentBytes, &target); err != nil {
+		return err
+	}
+
+	merged, err := json.Marshal(mergePatch(target, patch))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(merged, dst); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(dst)
+}
+
+// mergePatch implements the MergePatch algorithm described in RFC 7396
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
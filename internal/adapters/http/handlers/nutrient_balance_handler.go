@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/cropflow/api/internal/adapters/http/dto"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// NutrientBalanceHandler handles nutrient balance report HTTP requests
+type NutrientBalanceHandler struct {
+	nutrientBalanceUseCase *usecases.NutrientBalanceUseCase
+}
+
+// NewNutrientBalanceHandler creates a new nutrient balance handler
+func NewNutrientBalanceHandler(nutrientBalanceUseCase *usecases.NutrientBalanceUseCase) *NutrientBalanceHandler {
+	return &NutrientBalanceHandler{
+		nutrientBalanceUseCase: nutrientBalanceUseCase,
+	}
+}
+
+// GetCropBalance handles GET /crops/:id/nutrient-balance
+func (h *NutrientBalanceHandler) GetCropBalance(c *gin.Context) {
+	cropID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid crop id"})
+		return
+	}
+
+	var params dto.NutrientBalanceQueryDTO
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	balance, err := h.nutrientBalanceUseCase.GetCropBalance(c.Request.Context(), cropID, params.From, params.To)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewCropNutrientBalanceDTO(balance, params.From, params.To))
+}
+
+// GetFarmBalance handles GET /farms/:id/nutrient-balance
+func (h *NutrientBalanceHandler) GetFarmBalance(c *gin.Context) {
+	farmID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid farm id"})
+		return
+	}
+
+	var params dto.NutrientBalanceQueryDTO
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	balance, err := h.nutrientBalanceUseCase.GetFarmBalance(c.Request.Context(), farmID, params.From, params.To)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewFarmNutrientBalanceDTO(farmID, balance, params.From, params.To))
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCHandler handles logins through the OpenID Connect identity provider
+type OIDCHandler struct {
+	oidcUseCase *usecases.OIDCUseCase
+}
+
+// NewOIDCHandler creates a new OIDC handler
+func NewOIDCHandler(oidcUseCase *usecases.OIDCUseCase) *OIDCHandler {
+	return &OIDCHandler{
+		oidcUseCase: oidcUseCase,
+	}
+}
+
+// StartLogin handles GET /auth/oidc/login by redirecting to the identity provider
+func (h *OIDCHandler) StartLogin(c *gin.Context) {
+	authURL, err := h.oidcUseCase.StartLogin(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback handles GET /auth/oidc/callback, where the identity provider sends the person back
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	state := c.Query("state")
+	if state == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing state"})
+		return
+	}
+
+	result, err := h.oidcUseCase.CompleteLogin(c.Request.Context(), state, c.Query("code"), c.Query("error"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondLogin(c, result)
+}
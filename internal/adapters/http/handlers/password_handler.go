@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/cropflow/api/internal/adapters/http/dto"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// PasswordHandler handles password change and reset HTTP requests
+type PasswordHandler struct {
+	passwordUseCase *usecases.PasswordUseCase
+}
+
+// NewPasswordHandler creates a new password handler
+func NewPasswordHandler(passwordUseCase *usecases.PasswordUseCase) *PasswordHandler {
+	return &PasswordHandler{
+		passwordUseCase: passwordUseCase,
+	}
+}
+
+// ChangePassword handles POST /persons/me/password
+func (h *PasswordHandler) ChangePassword(c *gin.Context) {
+	var body dto.PasswordChangeBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.passwordUseCase.ChangePassword(c.Request.Context(), body.CurrentPassword, body.NewPassword); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RequestReset handles POST /persons/:id/password-reset
+func (h *PasswordHandler) RequestReset(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	expiresAt, err := h.passwordUseCase.RequestReset(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.PasswordResetDTO{ExpiresAt: expiresAt})
+}
+
+// ResetPassword handles POST /auth/password-reset
+func (h *PasswordHandler) ResetPassword(c *gin.Context) {
+	var body dto.PasswordResetBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.passwordUseCase.ResetPassword(c.Request.Context(), body.Token, body.NewPassword); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -5,7 +5,7 @@ import (
 	"strconv"
 
 	"github.com/cropflow/api/internal/adapters/http/dto"
-	"github.com/cropflow/api/internal/domain/entities"
+	"github.com/cropflow/api/internal/domain/person"
 	"github.com/cropflow/api/internal/usecases"
 	"github.com/gin-gonic/gin"
 )
@@ -30,48 +30,42 @@ func (h *PersonHandler) CreatePerson(c *gin.Context) {
 		return
 	}
 
-	person := &entities.Person{
-		Username: body.Username,
-		Password: body.Password,
-		Role:     body.Role,
-	}
-
-	if err := h.personUseCase.CreatePerson(person); err != nil {
-		if err == usecases.ErrUsernameAlreadyUsed {
-			c.JSON(http.StatusConflict, gin.H{"error": "username already in use"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	person, err := h.personUseCase.CreatePerson(c.Request.Context(), body.Username, body.Password)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	response := dto.PersonDTO{
-		ID:       person.ID,
-		Username: person.Username,
-		Role:     person.Role,
-	}
-
-	c.JSON(http.StatusCreated, response)
+	c.JSON(http.StatusCreated, dto.NewPersonDTO(person))
 }
 
 // GetAllPersons handles GET /persons
 func (h *PersonHandler) GetAllPersons(c *gin.Context) {
-	persons, err := h.personUseCase.GetAllPersons()
+	var params dto.PersonListQueryDTO
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := params.ToPage(person.SortFields)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	response := make([]dto.PersonDTO, len(persons))
-	for i, person := range persons {
-		response[i] = dto.PersonDTO{
-			ID:       person.ID,
-			Username: person.Username,
-			Role:     person.Role,
-		}
+	filter, err := params.ToFilter()
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	result, err := h.personUseCase.ListPersons(c.Request.Context(), filter, page)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPageDTO(dto.NewPersonDTOList(result.Items), page, result.NextCursor, result.PrevCursor))
 }
 
 // GetPersonByID handles GET /persons/:id
@@ -82,21 +76,134 @@ func (h *PersonHandler) GetPersonByID(c *gin.Context) {
 		return
 	}
 
-	person, err := h.personUseCase.GetPersonByID(id)
+	person, err := h.personUseCase.GetPersonByID(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPersonDTO(person))
+}
+
+// UpdatePerson handles PUT /persons/:id
+func (h *PersonHandler) UpdatePerson(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var body dto.PersonUpdateBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.update(c, id, body)
+}
+
+// PatchPerson handles PATCH /persons/:id
+func (h *PersonHandler) PatchPerson(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	person, err := h.personUseCase.GetPersonByID(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var body dto.PersonUpdateBodyDTO
+	if err := bindMergePatch(c, dto.NewPersonDTO(person), &body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.update(c, id, body)
+}
+
+// DeletePerson handles DELETE /persons/:id
+func (h *PersonHandler) DeletePerson(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.personUseCase.DeletePerson(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UnlockPerson handles POST /persons/:id/unlock
+func (h *PersonHandler) UnlockPerson(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	person, err := h.personUseCase.UnlockPerson(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPersonDTO(person))
+}
+
+// ChangeRole handles PUT /persons/:id/role
+func (h *PersonHandler) ChangeRole(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var body dto.RoleBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	person, err := h.personUseCase.ChangeRole(c.Request.Context(), id, body.Role)
 	if err != nil {
-		if err == usecases.ErrPersonNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "person not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	response := dto.PersonDTO{
-		ID:       person.ID,
-		Username: person.Username,
-		Role:     person.Role,
+	c.JSON(http.StatusOK, dto.NewPersonDTO(person))
+}
+
+// GetRoleChanges handles GET /persons/:id/role-changes
+func (h *PersonHandler) GetRoleChanges(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	changes, err := h.personUseCase.GetRoleChanges(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewRoleChangeDTOList(changes))
+}
+
+func (h *PersonHandler) update(c *gin.Context, id int64, body dto.PersonUpdateBodyDTO) {
+	person, err := h.personUseCase.UpdatePerson(c.Request.Context(), id, body.Username)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, dto.NewPersonDTO(person))
 }
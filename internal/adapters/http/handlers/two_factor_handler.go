@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cropflow/api/internal/adapters/http/dto"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorHandler handles the two-factor enrolment of the caller
+type TwoFactorHandler struct {
+	twoFactorUseCase *usecases.TwoFactorUseCase
+}
+
+// NewTwoFactorHandler creates a new two-factor handler
+func NewTwoFactorHandler(twoFactorUseCase *usecases.TwoFactorUseCase) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorUseCase: twoFactorUseCase,
+	}
+}
+
+// Enroll handles POST /persons/me/totp
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	provisioning, err := h.twoFactorUseCase.Enroll(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.TOTPProvisioningDTO{Secret: provisioning.Secret, ProvisioningURI: provisioning.URI})
+}
+
+// Confirm handles POST /persons/me/totp/confirm
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	var body dto.CodeBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recoveryCodes, err := h.twoFactorUseCase.Confirm(c.Request.Context(), body.Code)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RecoveryCodesDTO{RecoveryCodes: recoveryCodes})
+}
+
+// RegenerateRecoveryCodes handles POST /persons/me/totp/recovery-codes
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var body dto.CodeBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recoveryCodes, err := h.twoFactorUseCase.RegenerateRecoveryCodes(c.Request.Context(), body.Code)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RecoveryCodesDTO{RecoveryCodes: recoveryCodes})
+}
+
+// Disable handles DELETE /persons/me/totp
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var body dto.CodeBodyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.twoFactorUseCase.Disable(c.Request.Context(), body.Code); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,9 +1,18 @@
 package routes
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"strings"
 
 	"github.com/cropflow/api/internal/adapters/http/handlers"
+	"github.com/cropflow/api/internal/domain/apikey"
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/policy"
+	"github.com/cropflow/api/internal/domain/session"
 	"github.com/cropflow/api/internal/infrastructure/security"
 	"github.com/gin-gonic/gin"
 )
@@ -12,85 +21,246 @@ import (
 func SetupRoutes(
 	router *gin.Engine,
 	farmHandler *handlers.FarmHandler,
+	memberHandler *handlers.MemberHandler,
 	cropHandler *handlers.CropHandler,
+	harvestHandler *handlers.HarvestHandler,
+	applicationHandler *handlers.ApplicationHandler,
+	nutrientBalanceHandler *handlers.NutrientBalanceHandler,
 	fertilizerHandler *handlers.FertilizerHandler,
 	personHandler *handlers.PersonHandler,
 	authHandler *handlers.AuthHandler,
-	jwtService *security.JWTService,
+	twoFactorHandler *handlers.TwoFactorHandler,
+	passwordHandler *handlers.PasswordHandler,
+	apiKeyHandler *handlers.APIKeyHandler,
+	oidcHandler *handlers.OIDCHandler,
+	jwksHandler *handlers.JWKSHandler,
+	auditHandler *handlers.AuditHandler,
+	authenticator Authenticator,
+	permissions *policy.Policy,
 ) {
+	allow := func(resource policy.Resource, action policy.Action) gin.HandlerFunc {
+		return AuthMiddleware(authenticator, permissions, resource, action)
+	}
+
+	router.Use(RequestID())
+
 	// Public routes
 	router.POST("/persons", personHandler.CreatePerson)
 	router.POST("/auth/login", authHandler.Login)
+	router.POST("/auth/login/totp", authHandler.LoginTOTP)
+	router.POST("/auth/login/totp/enroll", authHandler.EnrollTOTP)
+	router.POST("/auth/refresh", authHandler.Refresh)
+	router.POST("/auth/password-reset", passwordHandler.ResetPassword)
+	router.GET("/auth/oidc/login", oidcHandler.StartLogin)
+	router.GET("/auth/oidc/callback", oidcHandler.Callback)
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
+	// Session routes
+	router.POST("/auth/logout", Authenticate(authenticator), authHandler.Logout)
+	router.DELETE("/persons/:id/sessions", allow(policy.Sessions, policy.Delete), authHandler.RevokeSessions)
+
+	// Password of the caller
+	router.POST("/persons/me/password", Authenticate(authenticator), passwordHandler.ChangePassword)
+
+	// Two-factor enrolment of the caller
+	router.POST("/persons/me/totp", Authenticate(authenticator), twoFactorHandler.Enroll)
+	router.POST("/persons/me/totp/confirm", Authenticate(authenticator), twoFactorHandler.Confirm)
+	router.POST("/persons/me/totp/recovery-codes", Authenticate(authenticator), twoFactorHandler.RegenerateRecoveryCodes)
+	router.DELETE("/persons/me/totp", Authenticate(authenticator), twoFactorHandler.Disable)
+
+	// API keys; the policy lets every user manage their own keys
+	router.POST("/api-keys", allow(policy.APIKeys, policy.Create), apiKeyHandler.CreateAPIKey)
+	router.GET("/api-keys", allow(policy.APIKeys, policy.Read), apiKeyHandler.GetAPIKeys)
+	router.GET("/api-keys/:id", allow(policy.APIKeys, policy.Read), apiKeyHandler.GetAPIKeyByID)
+	router.DELETE("/api-keys/:id", allow(policy.APIKeys, policy.Delete), apiKeyHandler.RevokeAPIKey)
+
+	// Person management routes
+	router.GET("/persons", allow(policy.Persons, policy.Read), personHandler.GetAllPersons)
+	router.GET("/persons/:id", allow(policy.Persons, policy.Read), personHandler.GetPersonByID)
+	router.PUT("/persons/:id", allow(policy.Persons, policy.Update), personHandler.UpdatePerson)
+	router.PATCH("/persons/:id", allow(policy.Persons, policy.Update), personHandler.PatchPerson)
+	router.DELETE("/persons/:id", allow(policy.Persons, policy.Delete), personHandler.DeletePerson)
+	router.POST("/persons/:id/unlock", allow(policy.Persons, policy.Update), personHandler.UnlockPerson)
+	router.PUT("/persons/:id/role", allow(policy.Persons, policy.Update), personHandler.ChangeRole)
+	router.GET("/persons/:id/role-changes", allow(policy.Persons, policy.Read), personHandler.GetRoleChanges)
+	router.POST("/persons/:id/password-reset", allow(policy.Persons, policy.Update), passwordHandler.RequestReset)
+
+	// Farm routes; which farms and crops a user sees is decided by farm membership
+	router.POST("/farms", allow(policy.Farms, policy.Create), farmHandler.CreateFarm)
+	router.GET("/farms", allow(policy.Farms, policy.Read), farmHandler.GetAllFarms)
+	router.GET("/farms/:id", allow(policy.Farms, policy.Read), farmHandler.GetFarmByID)
+	router.PUT("/farms/:id", allow(policy.Farms, policy.Update), farmHandler.UpdateFarm)
+	router.PATCH("/farms/:id", allow(policy.Farms, policy.Update), farmHandler.PatchFarm)
+	router.DELETE("/farms/:id", allow(policy.Farms, policy.Delete), farmHandler.DeleteFarm)
+
+	// Farm membership routes
+	router.GET("/farms/:id/members", allow(policy.Members, policy.Read), memberHandler.GetMembers)
+	router.POST("/farms/:id/members", allow(policy.Members, policy.Create), memberHandler.CreateMember)
+	router.PUT("/farms/:id/members/:personId", allow(policy.Members, policy.Update), memberHandler.UpdateMember)
+	router.DELETE("/farms/:id/members/:personId", allow(policy.Members, policy.Delete), memberHandler.DeleteMember)
 
-	// Farm routes
-	router.POST("/farms", farmHandler.CreateFarm)
-	router.GET("/farms", AuthMiddleware(jwtService, "ROLE_USER", "ROLE_MANAGER", "ROLE_ADMIN"), farmHandler.GetAllFarms)
-	router.GET("/farms/:id", farmHandler.GetFarmByID)
-	
 	// Farm-Crop relationship routes
-	router.POST("/farms/:id/crops", cropHandler.CreateCrop)
-	router.GET("/farms/:id/crops", cropHandler.GetCropsByFarmID)
+	router.POST("/farms/:id/crops", allow(policy.Crops, policy.Create), cropHandler.CreateCrop)
+	router.GET("/farms/:id/crops", allow(policy.Crops, policy.Read), cropHandler.GetCropsByFarmID)
 
 	// Crop routes
-	router.GET("/crops", AuthMiddleware(jwtService, "ROLE_MANAGER", "ROLE_ADMIN"), cropHandler.GetAllCrops)
-	router.GET("/crops/:id", cropHandler.GetCropByID)
+	router.GET("/crops", allow(policy.Crops, policy.Read), cropHandler.GetAllCrops)
+	router.GET("/crops/:id", allow(policy.Crops, policy.Read), cropHandler.GetCropByID)
+	router.PUT("/crops/:id", allow(policy.Crops, policy.Update), cropHandler.UpdateCrop)
+	router.PATCH("/crops/:id", allow(policy.Crops, policy.Update), cropHandler.PatchCrop)
+	router.DELETE("/crops/:id", allow(policy.Crops, policy.Delete), cropHandler.DeleteCrop)
+	router.POST("/crops/:id/transitions", allow(policy.Crops, policy.Update), cropHandler.CreateTransition)
+	router.GET("/crops/:id/transitions", allow(policy.Crops, policy.Read), cropHandler.GetTransitions)
+
+	// Crop harvest routes
+	router.POST("/crops/:id/harvests", allow(policy.Harvests, policy.Create), harvestHandler.CreateHarvest)
+	router.GET("/crops/:id/harvests", allow(policy.Harvests, policy.Read), harvestHandler.GetHarvests)
+	router.GET("/crops/:id/harvests/:harvestId", allow(policy.Harvests, policy.Read), harvestHandler.GetHarvestByID)
+	router.DELETE("/crops/:id/harvests/:harvestId", allow(policy.Harvests, policy.Delete), harvestHandler.DeleteHarvest)
+	router.GET("/crops/:id/yield", allow(policy.Harvests, policy.Read), harvestHandler.GetYield)
+
+	// Fertilizer application routes
+	router.POST("/crops/:id/applications", allow(policy.Applications, policy.Create), applicationHandler.CreateApplication)
+	router.GET("/crops/:id/applications", allow(policy.Applications, policy.Read), applicationHandler.GetApplications)
+
+	// Nutrient balance reports
+	router.GET("/crops/:id/nutrient-balance", allow(policy.Applications, policy.Read), nutrientBalanceHandler.GetCropBalance)
+	router.GET("/farms/:id/nutrient-balance", allow(policy.Applications, policy.Read), nutrientBalanceHandler.GetFarmBalance)
 
 	// Fertilizer routes
-	router.POST("/fertilizers", fertilizerHandler.CreateFertilizer)
-	router.GET("/fertilizers", AuthMiddleware(jwtService, "ROLE_ADMIN"), fertilizerHandler.GetAllFertilizers)
-	router.GET("/fertilizers/:id", fertilizerHandler.GetFertilizerByID)
-	
+	router.POST("/fertilizers", allow(policy.Fertilizers, policy.Create), fertilizerHandler.CreateFertilizer)
+	router.GET("/fertilizers", allow(policy.Fertilizers, policy.Read), fertilizerHandler.GetAllFertilizers)
+	router.GET("/fertilizers/:id", allow(policy.Fertilizers, policy.Read), fertilizerHandler.GetFertilizerByID)
+	router.PUT("/fertilizers/:id", allow(policy.Fertilizers, policy.Update), fertilizerHandler.UpdateFertilizer)
+	router.PATCH("/fertilizers/:id", allow(policy.Fertilizers, policy.Update), fertilizerHandler.PatchFertilizer)
+	router.DELETE("/fertilizers/:id", allow(policy.Fertilizers, policy.Delete), fertilizerHandler.DeleteFertilizer)
+
 	// Crop-Fertilizer relationship routes (using different base path to avoid conflicts)
-	router.POST("/crop/:cropId/fertilizer/:fertilizerId", cropHandler.AddFertilizerToCrop)
-	router.GET("/crop/:cropId/fertilizers", cropHandler.GetFertilizersByCropID)
+	router.GET("/crop/:cropId/fertilizers", allow(policy.Applications, policy.Read), cropHandler.GetFertilizersByCropID)
+
+	// Audit log
+	router.GET("/audit", allow(policy.Audit, policy.Read), auditHandler.GetAuditEntries)
 }
 
-// AuthMiddleware validates JWT token and checks user roles
-func AuthMiddleware(jwtService *security.JWTService, allowedRoles ...string) gin.HandlerFunc {
+// RequestIDHeader carries the ID of a request, in the response and optionally
+// in the request when a proxy or client already assigned one
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID tags each request with an ID, stored in the request context so
+// the audit log can tell which changes were made together
+func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Extract token from Authorization header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(401, gin.H{"error": "missing authorization header"})
-			c.Abort()
-			return
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			buf := make([]byte, 16)
+			if _, err := rand.Read(buf); err != nil {
+				handlers.RespondInternalError(c, err)
+				return
+			}
+			id = hex.EncodeToString(buf)
 		}
 
-		// Remove "Bearer " prefix
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			c.JSON(401, gin.H{"error": "invalid authorization header format"})
-			c.Abort()
-			return
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(audit.NewRequestContext(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// validRequestID accepts IDs of up to 64 letters, digits, dashes, underscores,
+// dots and colons, so a client cannot write arbitrary text to the audit log
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune("-_.:", r):
+		default:
+			return false
 		}
+	}
+	return true
+}
 
-		// Validate token
-		claims, err := jwtService.ValidateToken(tokenString)
-		if err != nil {
-			c.JSON(401, gin.H{"error": "invalid token"})
-			c.Abort()
+// Authenticator resolves the identity behind a bearer token
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (identity.Identity, error)
+}
+
+// Authenticate validates the bearer token and stores the caller identity in
+// the request context without checking permissions
+func Authenticate(authenticator Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := authenticate(c, authenticator); !ok {
 			return
 		}
+		c.Next()
+	}
+}
 
-		// Check if user has required role
-		hasRole := false
-		for _, role := range allowedRoles {
-			if claims.Role == role {
-				hasRole = true
-				break
-			}
+// AuthMiddleware validates the bearer token and checks the user role against
+// the permission policy for the resource action
+func AuthMiddleware(authenticator Authenticator, permissions *policy.Policy, resource policy.Resource, action policy.Action) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := authenticate(c, authenticator)
+		if !ok {
+			return
 		}
 
-		if !hasRole {
+		// Check if user role is granted the action by the policy
+		if !permissions.Allows(id.Role(), resource, action) {
 			c.JSON(403, gin.H{"error": "insufficient permissions"})
 			c.Abort()
 			return
 		}
 
-		// Set user info in context
-		c.Set("username", claims.Username)
-		c.Set("role", claims.Role)
-
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+func authenticate(c *gin.Context, authenticator Authenticator) (identity.Identity, bool) {
+	// Extract token from Authorization header; API keys may also come in X-API-Key
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		if key := c.GetHeader("X-API-Key"); key != "" {
+			authHeader = "Bearer " + key
+		}
+	}
+	if authHeader == "" {
+		c.JSON(401, gin.H{"error": "missing authorization header"})
+		c.Abort()
+		return identity.Identity{}, false
+	}
+
+	// Remove "Bearer " prefix
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		c.JSON(401, gin.H{"error": "invalid authorization header format"})
+		c.Abort()
+		return identity.Identity{}, false
+	}
+
+	// Validate token and its session
+	id, err := authenticator.Authenticate(c.Request.Context(), tokenString)
+	if err != nil {
+		switch {
+		case errors.Is(err, security.ErrExpiredToken), errors.Is(err, session.ErrSessionRevoked),
+			errors.Is(err, apikey.ErrAPIKeyExpired), errors.Is(err, apikey.ErrAPIKeyRevoked):
+			c.JSON(401, gin.H{"error": err.Error()})
+		case errors.Is(err, apikey.ErrInvalidAPIKey):
+			c.JSON(401, gin.H{"error": "invalid api key"})
+		case errors.Is(err, security.ErrInvalidToken):
+			c.JSON(401, gin.H{"error": "invalid token"})
+		default:
+			handlers.RespondInternalError(c, err)
+		}
+		c.Abort()
+		return identity.Identity{}, false
+	}
+
+	// Set user info in context; use cases read the identity from the request context
+	c.Set("username", id.Username())
+	c.Set("role", id.Role().String())
+	c.Request = c.Request.WithContext(identity.NewContext(c.Request.Context(), id))
+	return id, true
+}
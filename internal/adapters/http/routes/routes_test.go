@@ -0,0 +1,386 @@
+package routes_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/adapters/database/memory"
+	"github.com/cropflow/api/internal/adapters/http/handlers"
+	"github.com/cropflow/api/internal/adapters/http/routes"
+	"github.com/cropflow/api/internal/domain/apikey"
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/lockout"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/policy"
+	"github.com/cropflow/api/internal/domain/session"
+	"github.com/cropflow/api/internal/infrastructure/security"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// permissionMatrix lists the minimum role of every route under the default policy.
+// Public routes have no minimum role.
+var permissionMatrix = []struct {
+	method string
+	path   string
+	role   string
+}{
+	{"POST", "/persons", ""},
+	{"POST", "/auth/login", ""},
+	{"POST", "/auth/login/totp", ""},
+	{"POST", "/auth/login/totp/enroll", ""},
+	{"POST", "/auth/refresh", ""},
+	{"POST", "/auth/password-reset", ""},
+	{"GET", "/auth/oidc/login", ""},
+	{"GET", "/auth/oidc/callback", ""},
+	{"GET", "/.well-known/jwks.json", ""},
+
+	{"POST", "/auth/logout", "ROLE_USER"},
+	{"DELETE", "/persons/:id/sessions", "ROLE_ADMIN"},
+
+	{"POST", "/persons/me/password", "ROLE_USER"},
+	{"POST", "/persons/me/totp", "ROLE_USER"},
+	{"POST", "/persons/me/totp/confirm", "ROLE_USER"},
+	{"POST", "/persons/me/totp/recovery-codes", "ROLE_USER"},
+	{"DELETE", "/persons/me/totp", "ROLE_USER"},
+
+	{"POST", "/api-keys", "ROLE_USER"},
+	{"GET", "/api-keys", "ROLE_USER"},
+	{"GET", "/api-keys/:id", "ROLE_USER"},
+	{"DELETE", "/api-keys/:id", "ROLE_USER"},
+
+	{"GET", "/persons", "ROLE_ADMIN"},
+	{"GET", "/persons/:id", "ROLE_ADMIN"},
+	{"PUT", "/persons/:id", "ROLE_ADMIN"},
+	{"PATCH", "/persons/:id", "ROLE_ADMIN"},
+	{"DELETE", "/persons/:id", "ROLE_ADMIN"},
+	{"POST", "/persons/:id/unlock", "ROLE_ADMIN"},
+	{"PUT", "/persons/:id/role", "ROLE_ADMIN"},
+	{"GET", "/persons/:id/role-changes", "ROLE_ADMIN"},
+	{"POST", "/persons/:id/password-reset", "ROLE_ADMIN"},
+
+	{"POST", "/farms", "ROLE_MANAGER"},
+	{"GET", "/farms", "ROLE_USER"},
+	{"GET", "/farms/:id", "ROLE_USER"},
+	{"PUT", "/farms/:id", "ROLE_MANAGER"},
+	{"PATCH", "/farms/:id", "ROLE_MANAGER"},
+	{"DELETE", "/farms/:id", "ROLE_MANAGER"},
+
+	{"GET", "/farms/:id/members", "ROLE_USER"},
+	{"POST", "/farms/:id/members", "ROLE_MANAGER"},
+	{"PUT", "/farms/:id/members/:personId", "ROLE_MANAGER"},
+	{"DELETE", "/farms/:id/members/:personId", "ROLE_USER"},
+
+	{"POST", "/farms/:id/crops", "ROLE_MANAGER"},
+	{"GET", "/farms/:id/crops", "ROLE_USER"},
+
+	{"GET", "/crops", "ROLE_USER"},
+	{"GET", "/crops/:id", "ROLE_USER"},
+	{"PUT", "/crops/:id", "ROLE_MANAGER"},
+	{"PATCH", "/crops/:id", "ROLE_MANAGER"},
+	{"DELETE", "/crops/:id", "ROLE_MANAGER"},
+	{"POST", "/crops/:id/transitions", "ROLE_MANAGER"},
+	{"GET", "/crops/:id/transitions", "ROLE_USER"},
+
+	{"POST", "/crops/:id/harvests", "ROLE_MANAGER"},
+	{"GET", "/crops/:id/harvests", "ROLE_USER"},
+	{"GET", "/crops/:id/harvests/:harvestId", "ROLE_USER"},
+	{"DELETE", "/crops/:id/harvests/:harvestId", "ROLE_MANAGER"},
+	{"GET", "/crops/:id/yield", "ROLE_USER"},
+
+	{"POST", "/crops/:id/applications", "ROLE_MANAGER"},
+	{"GET", "/crops/:id/applications", "ROLE_USER"},
+	{"GET", "/crops/:id/nutrient-balance", "ROLE_USER"},
+	{"GET", "/farms/:id/nutrient-balance", "ROLE_USER"},
+	{"GET", "/crop/:cropId/fertilizers", "ROLE_USER"},
+
+	{"POST", "/fertilizers", "ROLE_ADMIN"},
+	{"GET", "/fertilizers", "ROLE_USER"},
+	{"GET", "/fertilizers/:id", "ROLE_USER"},
+	{"PUT", "/fertilizers/:id", "ROLE_ADMIN"},
+	{"PATCH", "/fertilizers/:id", "ROLE_ADMIN"},
+	{"DELETE", "/fertilizers/:id", "ROLE_ADMIN"},
+
+	{"GET", "/audit", "ROLE_ADMIN"},
+}
+
+var roles = []string{"ROLE_USER", "ROLE_MANAGER", "ROLE_ADMIN"}
+
+var rank = map[string]int{"ROLE_USER": 1, "ROLE_MANAGER": 2, "ROLE_ADMIN": 3}
+
+// roleAuthenticator accepts any role name as a token
+type roleAuthenticator struct{}
+
+func (roleAuthenticator) Authenticate(_ context.Context, token string) (identity.Identity, error) {
+	switch token {
+	case "revoked":
+		return identity.Identity{}, session.ErrSessionRevoked
+	case "cfk_revoked":
+		return identity.Identity{}, apikey.ErrAPIKeyRevoked
+	case "cfk_unknown":
+		return identity.Identity{}, apikey.ErrInvalidAPIKey
+	case "unavailable":
+		return identity.Identity{}, errors.New("database is unavailable")
+	}
+	role, err := person.NewRole(token)
+	if err != nil {
+		return identity.Identity{}, security.ErrInvalidToken
+	}
+	return identity.New(1, 1, "user", role), nil
+}
+
+// newRouter wires every route to handlers without use cases: requests that get
+// past the middleware fail inside the handler, which is enough to tell them
+// apart from the 401 and 403 responses of the middleware.
+func newRouter(t *testing.T, overrides map[string]map[string]string) *gin.Engine {
+	t.Helper()
+	return newRouterWithFarms(t, overrides, &handlers.FarmHandler{})
+}
+
+// newRouterWithFarms is newRouter with the farm routes served by the given handler
+func newRouterWithFarms(t *testing.T, overrides map[string]map[string]string, farmHandler *handlers.FarmHandler) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	permissions, err := policy.New(overrides)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(gin.RecoveryWithWriter(io.Discard))
+	routes.SetupRoutes(router,
+		farmHandler,
+		&handlers.MemberHandler{},
+		&handlers.CropHandler{},
+		&handlers.HarvestHandler{},
+		&handlers.ApplicationHandler{},
+		&handlers.NutrientBalanceHandler{},
+		&handlers.FertilizerHandler{},
+		&handlers.PersonHandler{},
+		&handlers.AuthHandler{},
+		&handlers.TwoFactorHandler{},
+		&handlers.PasswordHandler{},
+		&handlers.APIKeyHandler{},
+		&handlers.OIDCHandler{},
+		&handlers.JWKSHandler{},
+		&handlers.AuditHandler{},
+		roleAuthenticator{},
+		permissions,
+	)
+	return router
+}
+
+func TestSetupRoutes_PermissionMatrix(t *testing.T) {
+	router := newRouter(t, nil)
+
+	t.Run("should cover every registered route", func(t *testing.T) {
+		// Arrange
+		covered := make(map[string]bool)
+		for _, route := range permissionMatrix {
+			covered[route.method+" "+route.path] = true
+		}
+
+		// Assert
+		for _, route := range router.Routes() {
+			assert.True(t, covered[route.Method+" "+route.Path], "route %s %s is missing from the permission matrix", route.Method, route.Path)
+		}
+	})
+
+	for _, route := range permissionMatrix {
+		if route.role == "" {
+			continue
+		}
+		path := samplePath(route.path)
+
+		t.Run("should require a token for "+route.method+" "+route.path, func(t *testing.T) {
+			// Act
+			w := serve(router, route.method, path, "")
+
+			// Assert
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		})
+
+		for _, role := range roles {
+			allowed := rank[role] >= rank[route.role]
+
+			t.Run(role+" on "+route.method+" "+route.path, func(t *testing.T) {
+				// Act
+				w := serve(router, route.method, path, role)
+
+				// Assert
+				if allowed {
+					assert.NotEqual(t, http.StatusUnauthorized, w.Code)
+					assert.NotEqual(t, http.StatusForbidden, w.Code)
+				} else {
+					assert.Equal(t, http.StatusForbidden, w.Code)
+				}
+			})
+		}
+	}
+}
+
+func TestSetupRoutes_PolicyOverrides(t *testing.T) {
+	t.Run("should apply the configured policy", func(t *testing.T) {
+		// Arrange
+		router := newRouter(t, map[string]map[string]string{
+			"fertilizers": {"create": "ROLE_MANAGER"},
+		})
+
+		// Act
+		managerResponse := serve(router, "POST", "/fertilizers", "ROLE_MANAGER")
+		userResponse := serve(router, "POST", "/fertilizers", "ROLE_USER")
+
+		// Assert
+		assert.NotEqual(t, http.StatusForbidden, managerResponse.Code)
+		assert.Equal(t, http.StatusForbidden, userResponse.Code)
+	})
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	router := newRouter(t, nil)
+
+	t.Run("should reject invalid tokens", func(t *testing.T) {
+		// Act
+		w := serve(router, "GET", "/farms", "not-a-token")
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.JSONEq(t, `{"error":"invalid token"}`, w.Body.String())
+	})
+
+	t.Run("should reject tokens of revoked sessions", func(t *testing.T) {
+		// Act
+		w := serve(router, "GET", "/farms", "revoked")
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.JSONEq(t, `{"error":"session has been revoked"}`, w.Body.String())
+	})
+
+	t.Run("should accept api keys in the X-API-Key header", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest("GET", "/persons", nil)
+		req.Header.Set("X-API-Key", "ROLE_USER")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("should reject unknown and revoked api keys", func(t *testing.T) {
+		// Act
+		unknown := serve(router, "GET", "/farms", "cfk_unknown")
+		revoked := serve(router, "GET", "/farms", "cfk_revoked")
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, unknown.Code)
+		assert.JSONEq(t, `{"error":"invalid api key"}`, unknown.Body.String())
+		assert.Equal(t, http.StatusUnauthorized, revoked.Code)
+	})
+
+	t.Run("should not disclose unexpected errors", func(t *testing.T) {
+		// Act
+		w := serve(router, "GET", "/farms", "unavailable")
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":"internal server error"}`, w.Body.String())
+	})
+}
+
+func TestFarmRoutes(t *testing.T) {
+	t.Run("should create a farm and serve it to its owner", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		persons := memory.NewPersonRepository(store)
+		now := time.Now()
+		// roleAuthenticator authenticates every token as person 1
+		require.NoError(t, persons.Save(context.Background(), person.Restore(0, "user", "$2a$04$hash", person.RoleManager, now, now, lockout.Status{})))
+		farmHandler := handlers.NewFarmHandler(usecases.NewFarmUseCase(memory.NewFarmRepository(store), persons, memory.NewTransactionManager(store)))
+		router := newRouterWithFarms(t, nil, farmHandler)
+		req := httptest.NewRequest("POST", "/farms", strings.NewReader(`{"name":"Fazenda Boa Vista","size":150.5}`))
+		req.Header.Set("Authorization", "Bearer ROLE_MANAGER")
+		req.Header.Set("Content-Type", "application/json")
+		created := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(created, req)
+		found := serve(router, "GET", "/farms/1", "ROLE_MANAGER")
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, created.Code)
+		assert.Equal(t, http.StatusOK, found.Code)
+		assert.JSONEq(t, `{"id":1,"name":"Fazenda Boa Vista","size":150.5}`, found.Body.String())
+	})
+}
+
+func TestRequestID(t *testing.T) {
+	router := newRouter(t, nil)
+
+	t.Run("should generate a request id", func(t *testing.T) {
+		// Act
+		first := serve(router, "GET", "/farms", "")
+		second := serve(router, "GET", "/farms", "")
+
+		// Assert
+		assert.Len(t, first.Header().Get(routes.RequestIDHeader), 32)
+		assert.NotEqual(t, first.Header().Get(routes.RequestIDHeader), second.Header().Get(routes.RequestIDHeader))
+	})
+
+	t.Run("should keep the request id sent by the client", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest("GET", "/farms", nil)
+		req.Header.Set(routes.RequestIDHeader, "lb-7f3a:42")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, "lb-7f3a:42", w.Header().Get(routes.RequestIDHeader))
+	})
+
+	t.Run("should replace malformed request ids", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest("GET", "/farms", nil)
+		req.Header.Set(routes.RequestIDHeader, "<script>alert(1)</script>")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Len(t, w.Header().Get(routes.RequestIDHeader), 32)
+	})
+}
+
+func serve(router *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// samplePath fills the path parameters of a route with ids
+func samplePath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			parts[i] = "1"
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,17 @@
+package apikey
+
+import "errors"
+
+var (
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+	ErrInvalidAPIKey      = errors.New("invalid api key")
+	ErrAPIKeyExpired      = errors.New("api key has expired")
+	ErrAPIKeyRevoked      = errors.New("api key has been revoked")
+	ErrInvalidName        = errors.New("invalid api key name: must be between 1 and 100 characters")
+	ErrInvalidKind        = errors.New("invalid api key kind: must be PERSONAL or SERVICE")
+	ErrInvalidOwner       = errors.New("invalid api key owner: service keys need an owner, personal keys belong to the caller")
+	ErrInvalidExpiry      = errors.New("invalid api key expiry: must be in the future")
+	ErrRoleExceedsOwner   = errors.New("invalid api key role: must not exceed the role of its owner")
+	ErrServiceKeyForAdmin = errors.New("only administrators can manage service api keys")
+	ErrKeyManagedByKey    = errors.New("api keys cannot be managed with an api key")
+)
@@ -0,0 +1,267 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/person"
+)
+
+// Prefix starts every API key, so keys can be told apart from access tokens
+// and found by secret scanners
+const Prefix = "cfk_"
+
+const (
+	// idBytes is the randomness of the public part identifying a key
+	idBytes = 6
+	// secretBytes is the randomness of the secret part of a key
+	secretBytes = 32
+	// maxNameLength bounds the name given to a key
+	maxNameLength = 100
+	// touchInterval is how often the last use of a key is recorded, so busy
+	// clients do not cause a write on every request
+	touchInterval = time.Minute
+)
+
+// Kind tells who manages a key
+type Kind string
+
+const (
+	// Personal keys are created by a person for their own scripts and tools
+	Personal Kind = "PERSONAL"
+	// Service keys are created by administrators for the person account of a
+	// machine client, e.g. an irrigation controller or an ERP integration
+	Service Kind = "SERVICE"
+)
+
+// NewKind creates a new Kind with validation
+func NewKind(value string) (Kind, error) {
+	kind := Kind(value)
+	switch kind {
+	case Personal, Service:
+		return kind, nil
+	default:
+		return "", ErrInvalidKind
+	}
+}
+
+// String returns the string representation of the kind
+func (k Kind) String() string {
+	return string(k)
+}
+
+// Key is a long-lived credential for machine clients. It acts as its owner,
+// never with more than the role it was scoped to and, when farm IDs are set,
+// only on those farms. Only a hash of the secret is stored; the prefix
+// identifies the key in listings and lookups.
+type Key struct {
+	id         int64
+	ownerID    int64
+	kind       Kind
+	name       string
+	prefix     string
+	hash       string
+	role       person.Role
+	farmIDs    []int64
+	expiresAt  *time.Time
+	lastUsedAt *time.Time
+	revokedAt  *time.Time
+	createdAt  time.Time
+}
+
+// NewKey issues a key for its owner and returns it in plain form, which is
+// only shown once (Factory Method)
+func NewKey(ownerID int64, kind Kind, name string, role person.Role, farmIDs []int64, expiresAt *time.Time, now time.Time) (*Key, string, error) {
+	if ownerID <= 0 {
+		return nil, "", ErrInvalidOwner
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLength {
+		return nil, "", ErrInvalidName
+	}
+	if _, err := NewKind(kind.String()); err != nil {
+		return nil, "", err
+	}
+	if _, err := person.NewRole(role.String()); err != nil {
+		return nil, "", err
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, "", ErrInvalidExpiry
+	}
+
+	id := make([]byte, idBytes)
+	secret := make([]byte, secretBytes)
+	if _, err := rand.Read(id); err != nil {
+		return nil, "", err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	// The hex identifier never contains the underscore separating the secret
+	prefix := Prefix + hex.EncodeToString(id)
+	plain := prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+
+	return &Key{
+		ownerID:   ownerID,
+		kind:      kind,
+		name:      name,
+		prefix:    prefix,
+		hash:      HashKey(plain),
+		role:      role,
+		farmIDs:   uniqueFarmIDs(farmIDs),
+		expiresAt: expiresAt,
+		createdAt: now,
+	}, plain, nil
+}
+
+// RestoreKey reconstructs a Key from persistence (used by repository)
+func RestoreKey(id, ownerID int64, kind Kind, name, prefix, hash string, role person.Role, farmIDs []int64, expiresAt, lastUsedAt, revokedAt *time.Time, createdAt time.Time) *Key {
+	return &Key{
+		id:         id,
+		ownerID:    ownerID,
+		kind:       kind,
+		name:       name,
+		prefix:     prefix,
+		hash:       hash,
+		role:       role,
+		farmIDs:    farmIDs,
+		expiresAt:  expiresAt,
+		lastUsedAt: lastUsedAt,
+		revokedAt:  revokedAt,
+		createdAt:  createdAt,
+	}
+}
+
+// Getters (encapsulation)
+func (k *Key) ID() int64 {
+	return k.id
+}
+
+func (k *Key) OwnerID() int64 {
+	return k.ownerID
+}
+
+func (k *Key) Kind() Kind {
+	return k.kind
+}
+
+func (k *Key) Name() string {
+	return k.name
+}
+
+// Prefix returns the public part identifying the key, e.g. cfk_1f2e3d4c5b6a
+func (k *Key) Prefix() string {
+	return k.prefix
+}
+
+func (k *Key) Hash() string {
+	return k.hash
+}
+
+func (k *Key) Role() person.Role {
+	return k.role
+}
+
+// FarmIDs returns the farms the key is limited to; empty means every farm its owner can access
+func (k *Key) FarmIDs() []int64 {
+	return k.farmIDs
+}
+
+func (k *Key) ExpiresAt() *time.Time {
+	return k.expiresAt
+}
+
+func (k *Key) LastUsedAt() *time.Time {
+	return k.lastUsedAt
+}
+
+func (k *Key) RevokedAt() *time.Time {
+	return k.revokedAt
+}
+
+func (k *Key) CreatedAt() time.Time {
+	return k.createdAt
+}
+
+// SetID is used by repository after insertion
+func (k *Key) SetID(id int64) {
+	k.id = id
+}
+
+// Business Methods
+
+// Verify checks a plain key against this one and that it can still be used
+func (k *Key) Verify(plain string, now time.Time) error {
+	if subtle.ConstantTimeCompare([]byte(HashKey(plain)), []byte(k.hash)) != 1 {
+		return ErrInvalidAPIKey
+	}
+	if k.revokedAt != nil {
+		return ErrAPIKeyRevoked
+	}
+	if k.expiresAt != nil && !now.Before(*k.expiresAt) {
+		return ErrAPIKeyExpired
+	}
+	return nil
+}
+
+// MarkUsed records a use of the key and tells whether it needs to be stored;
+// uses within a minute of the last recorded one are not
+func (k *Key) MarkUsed(now time.Time) bool {
+	if k.lastUsedAt != nil && now.Sub(*k.lastUsedAt) < touchInterval {
+		return false
+	}
+	k.lastUsedAt = &now
+	return true
+}
+
+// Revoke stops the key from being accepted
+func (k *Key) Revoke(now time.Time) {
+	if k.revokedAt == nil {
+		k.revokedAt = &now
+	}
+}
+
+// EffectiveRole returns the role the key acts with: its own role, lowered to
+// the current role of its owner if the owner was demoted since
+func (k *Key) EffectiveRole(ownerRole person.Role) person.Role {
+	if ownerRole.HasPermission(k.role) {
+		return k.role
+	}
+	return ownerRole
+}
+
+// HashKey returns the stored form of a plain API key
+func HashKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParsePrefix extracts the public part of a plain key, e.g. cfk_1f2e3d4c5b6a
+func ParsePrefix(plain string) (string, error) {
+	rest, ok := strings.CutPrefix(plain, Prefix)
+	if !ok {
+		return "", ErrInvalidAPIKey
+	}
+	id, _, ok := strings.Cut(rest, "_")
+	if !ok || id == "" {
+		return "", ErrInvalidAPIKey
+	}
+	return Prefix + id, nil
+}
+
+func uniqueFarmIDs(farmIDs []int64) []int64 {
+	seen := make(map[int64]bool, len(farmIDs))
+	unique := make([]int64, 0, len(farmIDs))
+	for _, id := range farmIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,119 @@
+package apikey_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/apikey"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKey(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should issue a prefixed key stored only as a hash", func(t *testing.T) {
+		// Act
+		key, plain, err := apikey.NewKey(7, apikey.Personal, " irrigation ", person.RoleManager, []int64{3, 3, 4}, nil, now)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(plain, key.Prefix()+"_"))
+		assert.True(t, strings.HasPrefix(key.Prefix(), apikey.Prefix))
+		assert.Equal(t, apikey.HashKey(plain), key.Hash())
+		assert.Equal(t, "irrigation", key.Name())
+		assert.Equal(t, []int64{3, 4}, key.FarmIDs())
+
+		prefix, err := apikey.ParsePrefix(plain)
+		require.NoError(t, err)
+		assert.Equal(t, key.Prefix(), prefix)
+	})
+
+	t.Run("should validate its fields", func(t *testing.T) {
+		// Arrange
+		past := now.Add(-time.Minute)
+
+		// Act & Assert
+		_, _, err := apikey.NewKey(0, apikey.Personal, "ci", person.RoleUser, nil, nil, now)
+		assert.Equal(t, apikey.ErrInvalidOwner, err)
+
+		_, _, err = apikey.NewKey(7, apikey.Personal, "  ", person.RoleUser, nil, nil, now)
+		assert.Equal(t, apikey.ErrInvalidName, err)
+
+		_, _, err = apikey.NewKey(7, apikey.Kind("ROBOT"), "ci", person.RoleUser, nil, nil, now)
+		assert.Equal(t, apikey.ErrInvalidKind, err)
+
+		_, _, err = apikey.NewKey(7, apikey.Personal, "ci", person.Role("ROLE_ROOT"), nil, nil, now)
+		assert.Equal(t, person.ErrInvalidRole, err)
+
+		_, _, err = apikey.NewKey(7, apikey.Personal, "ci", person.RoleUser, nil, &past, now)
+		assert.Equal(t, apikey.ErrInvalidExpiry, err)
+	})
+}
+
+func TestKey_Verify(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(time.Hour)
+
+	t.Run("should accept the key until it expires", func(t *testing.T) {
+		// Arrange
+		key, plain, err := apikey.NewKey(7, apikey.Service, "erp", person.RoleUser, nil, &expiresAt, now)
+		require.NoError(t, err)
+
+		// Act & Assert
+		assert.NoError(t, key.Verify(plain, now))
+		assert.Equal(t, apikey.ErrAPIKeyExpired, key.Verify(plain, expiresAt))
+		assert.Equal(t, apikey.ErrInvalidAPIKey, key.Verify(plain+"x", now))
+	})
+
+	t.Run("should reject a revoked key", func(t *testing.T) {
+		// Arrange
+		key, plain, err := apikey.NewKey(7, apikey.Personal, "ci", person.RoleUser, nil, nil, now)
+		require.NoError(t, err)
+
+		// Act
+		key.Revoke(now)
+
+		// Assert
+		assert.Equal(t, apikey.ErrAPIKeyRevoked, key.Verify(plain, now))
+	})
+}
+
+func TestKey_MarkUsed(t *testing.T) {
+	t.Run("should record a use at most once a minute", func(t *testing.T) {
+		// Arrange
+		now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+		key, _, err := apikey.NewKey(7, apikey.Personal, "ci", person.RoleUser, nil, nil, now)
+		require.NoError(t, err)
+
+		// Act & Assert
+		assert.True(t, key.MarkUsed(now))
+		assert.False(t, key.MarkUsed(now.Add(30*time.Second)))
+		assert.True(t, key.MarkUsed(now.Add(time.Minute)))
+		assert.Equal(t, now.Add(time.Minute), *key.LastUsedAt())
+	})
+}
+
+func TestKey_EffectiveRole(t *testing.T) {
+	t.Run("should never exceed the current role of the owner", func(t *testing.T) {
+		// Arrange
+		key, _, err := apikey.NewKey(7, apikey.Personal, "ci", person.RoleManager, nil, nil, time.Now())
+		require.NoError(t, err)
+
+		// Act & Assert
+		assert.Equal(t, person.RoleManager, key.EffectiveRole(person.RoleAdmin))
+		assert.Equal(t, person.RoleUser, key.EffectiveRole(person.RoleUser))
+	})
+}
+
+func TestParsePrefix(t *testing.T) {
+	t.Run("should reject values that are not api keys", func(t *testing.T) {
+		// Act & Assert
+		for _, value := range []string{"", "eyJhbGciOi", "cfk_", "cfk_abc"} {
+			_, err := apikey.ParsePrefix(value)
+			assert.Equal(t, apikey.ErrInvalidAPIKey, err, value)
+		}
+	})
+}
@@ -0,0 +1,21 @@
+package apikey
+
+import (
+	"context"
+	"time"
+)
+
+// Filter narrows down a key listing; zero values are ignored
+type Filter struct {
+	OwnerID int64
+}
+
+// Repository defines the interface for API key persistence (Port)
+type Repository interface {
+	Save(ctx context.Context, key *Key) error
+	FindByID(ctx context.Context, id int64) (*Key, error)
+	FindByPrefix(ctx context.Context, prefix string) (*Key, error)
+	List(ctx context.Context, filter Filter) ([]*Key, error)
+	// Touch records the last use of a key without loading it
+	Touch(ctx context.Context, id int64, at time.Time) error
+}
@@ -0,0 +1,16 @@
+package audit
+
+import "context"
+
+type requestIDKey struct{}
+
+// NewRequestContext returns a copy of ctx carrying the ID of the request being served
+func NewRequestContext(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the ID of the request being served, or "" outside of one
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
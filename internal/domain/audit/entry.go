@@ -0,0 +1,202 @@
+package audit
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/identity"
+)
+
+// Action represents what was done to an audited resource
+type Action string
+
+const (
+	ActionCreate Action = "CREATE"
+	ActionUpdate Action = "UPDATE"
+	ActionDelete Action = "DELETE"
+)
+
+// NewAction creates a new Action with validation
+func NewAction(value string) (Action, error) {
+	action := Action(value)
+	switch action {
+	case ActionCreate, ActionUpdate, ActionDelete:
+		return action, nil
+	default:
+		return "", ErrInvalidAction
+	}
+}
+
+// String returns the string representation of the action
+func (a Action) String() string {
+	return string(a)
+}
+
+// ResourceType represents the kind of resource an entry is about
+type ResourceType string
+
+const (
+	ResourceFarm       ResourceType = "farm"
+	ResourceCrop       ResourceType = "crop"
+	ResourceFertilizer ResourceType = "fertilizer"
+	ResourcePerson     ResourceType = "person"
+)
+
+// NewResourceType creates a new ResourceType with validation
+func NewResourceType(value string) (ResourceType, error) {
+	resourceType := ResourceType(value)
+	switch resourceType {
+	case ResourceFarm, ResourceCrop, ResourceFertilizer, ResourcePerson:
+		return resourceType, nil
+	default:
+		return "", ErrInvalidResourceType
+	}
+}
+
+// String returns the string representation of the resource type
+func (r ResourceType) String() string {
+	return string(r)
+}
+
+// Redacted replaces the values of secret fields, e.g. password hashes
+const Redacted = "[redacted]"
+
+// Change holds the value of a field before and after a change; nil stands for
+// a field of a resource that did not exist before or no longer exists after
+type Change struct {
+	Before any
+	After  any
+}
+
+// Diff returns the fields that differ between two snapshots of a resource,
+// nil standing for the resource not existing. Values of redacted fields are
+// replaced by Redacted, so only the fact they changed is recorded.
+func Diff(before, after map[string]any, redacted ...string) map[string]Change {
+	changes := make(map[string]Change)
+	for field, value := range before {
+		if next, ok := after[field]; !ok || !reflect.DeepEqual(value, next) {
+			changes[field] = Change{Before: value, After: after[field]}
+		}
+	}
+	for field, value := range after {
+		if _, ok := before[field]; !ok {
+			changes[field] = Change{After: value}
+		}
+	}
+
+	for _, field := range redacted {
+		change, ok := changes[field]
+		if !ok {
+			continue
+		}
+		if change.Before != nil {
+			change.Before = Redacted
+		}
+		if change.After != nil {
+			change.After = Redacted
+		}
+		changes[field] = change
+	}
+	return changes
+}
+
+// Entry represents an append-only record of a change to a resource
+type Entry struct {
+	id            int64
+	actorID       int64
+	apiKeyID      int64
+	actorUsername string
+	action        Action
+	resourceType  ResourceType
+	resourceID    int64
+	changes       map[string]Change
+	requestID     string
+	occurredAt    time.Time
+}
+
+// NewEntry records a change made by the actor, the zero Identity standing for
+// an anonymous caller or the system itself (Factory Method)
+func NewEntry(actor identity.Identity, requestID string, action Action, resourceType ResourceType, resourceID int64, changes map[string]Change, occurredAt time.Time) (*Entry, error) {
+	if _, err := NewAction(action.String()); err != nil {
+		return nil, err
+	}
+	if _, err := NewResourceType(resourceType.String()); err != nil {
+		return nil, err
+	}
+
+	return &Entry{
+		actorID:       actor.PersonID(),
+		apiKeyID:      actor.APIKeyID(),
+		actorUsername: actor.Username(),
+		action:        action,
+		resourceType:  resourceType,
+		resourceID:    resourceID,
+		changes:       changes,
+		requestID:     requestID,
+		occurredAt:    occurredAt,
+	}, nil
+}
+
+// RestoreEntry reconstructs an Entry from persistence (used by repository)
+func RestoreEntry(id, actorID, apiKeyID int64, actorUsername string, action Action, resourceType ResourceType, resourceID int64, changes map[string]Change, requestID string, occurredAt time.Time) *Entry {
+	return &Entry{
+		id:            id,
+		actorID:       actorID,
+		apiKeyID:      apiKeyID,
+		actorUsername: actorUsername,
+		action:        action,
+		resourceType:  resourceType,
+		resourceID:    resourceID,
+		changes:       changes,
+		requestID:     requestID,
+		occurredAt:    occurredAt,
+	}
+}
+
+// Getters (encapsulation)
+func (e *Entry) ID() int64 {
+	return e.id
+}
+
+// ActorID returns the person who made the change, 0 for an anonymous caller or the system
+func (e *Entry) ActorID() int64 {
+	return e.actorID
+}
+
+// APIKeyID returns the API key the actor used, 0 when none
+func (e *Entry) APIKeyID() int64 {
+	return e.apiKeyID
+}
+
+func (e *Entry) ActorUsername() string {
+	return e.actorUsername
+}
+
+func (e *Entry) Action() Action {
+	return e.action
+}
+
+func (e *Entry) ResourceType() ResourceType {
+	return e.resourceType
+}
+
+func (e *Entry) ResourceID() int64 {
+	return e.resourceID
+}
+
+func (e *Entry) Changes() map[string]Change {
+	return e.changes
+}
+
+func (e *Entry) RequestID() string {
+	return e.requestID
+}
+
+func (e *Entry) OccurredAt() time.Time {
+	return e.occurredAt
+}
+
+// SetID is used by repository after insertion
+func (e *Entry) SetID(id int64) {
+	e.id = id
+}
@@ -0,0 +1,158 @@
+package audit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("should keep only the fields that changed", func(t *testing.T) {
+		// Arrange
+		before := map[string]any{"name": "Fazenda A", "size": 10.0, "farmId": 1.0}
+		after := map[string]any{"name": "Fazenda B", "size": 10.0, "farmId": 1.0}
+
+		// Act
+		changes := audit.Diff(before, after)
+
+		// Assert
+		assert.Equal(t, map[string]audit.Change{"name": {Before: "Fazenda A", After: "Fazenda B"}}, changes)
+	})
+
+	t.Run("should record every field of a created resource", func(t *testing.T) {
+		// Arrange
+		after := map[string]any{"name": "Fazenda A", "size": 10.0}
+
+		// Act
+		changes := audit.Diff(nil, after)
+
+		// Assert
+		assert.Equal(t, map[string]audit.Change{
+			"name": {After: "Fazenda A"},
+			"size": {After: 10.0},
+		}, changes)
+	})
+
+	t.Run("should record every field of a deleted resource", func(t *testing.T) {
+		// Arrange
+		before := map[string]any{"name": "Fazenda A"}
+
+		// Act
+		changes := audit.Diff(before, nil)
+
+		// Assert
+		assert.Equal(t, map[string]audit.Change{"name": {Before: "Fazenda A"}}, changes)
+	})
+
+	t.Run("should return no changes for equal snapshots", func(t *testing.T) {
+		// Arrange
+		snapshot := map[string]any{"plantedDate": nil, "status": "PLANNED"}
+
+		// Act
+		changes := audit.Diff(snapshot, snapshot)
+
+		// Assert
+		assert.Empty(t, changes)
+	})
+
+	t.Run("should hide the values of redacted fields", func(t *testing.T) {
+		// Arrange
+		before := map[string]any{"username": "john_doe", "password": "$2a$old"}
+		after := map[string]any{"username": "john_doe", "password": "$2a$new"}
+
+		// Act
+		changes := audit.Diff(before, after, "password")
+
+		// Assert
+		assert.Equal(t, map[string]audit.Change{
+			"password": {Before: audit.Redacted, After: audit.Redacted},
+		}, changes)
+	})
+
+	t.Run("should leave an unchanged redacted field out", func(t *testing.T) {
+		// Arrange
+		snapshot := map[string]any{"password": "$2a$hash"}
+
+		// Act
+		changes := audit.Diff(snapshot, snapshot, "password")
+
+		// Assert
+		assert.Empty(t, changes)
+	})
+}
+
+func TestNewEntry(t *testing.T) {
+	changes := map[string]audit.Change{"name": {After: "Fazenda A"}}
+	now := time.Now()
+
+	t.Run("should record the actor and request", func(t *testing.T) {
+		// Arrange
+		actor := identity.NewForAPIKey(7, 3, "admin", person.RoleAdmin, nil)
+
+		// Act
+		entry, err := audit.NewEntry(actor, "req-1", audit.ActionCreate, audit.ResourceFarm, 42, changes, now)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), entry.ActorID())
+		assert.Equal(t, int64(3), entry.APIKeyID())
+		assert.Equal(t, "admin", entry.ActorUsername())
+		assert.Equal(t, "req-1", entry.RequestID())
+		assert.Equal(t, audit.ResourceFarm, entry.ResourceType())
+		assert.Equal(t, int64(42), entry.ResourceID())
+		assert.Equal(t, changes, entry.Changes())
+	})
+
+	t.Run("should leave the actor empty for anonymous callers", func(t *testing.T) {
+		// Act
+		entry, err := audit.NewEntry(identity.Identity{}, "", audit.ActionCreate, audit.ResourcePerson, 1, changes, now)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Zero(t, entry.ActorID())
+		assert.Empty(t, entry.ActorUsername())
+	})
+
+	t.Run("should return error for invalid action", func(t *testing.T) {
+		// Act
+		_, err := audit.NewEntry(identity.Identity{}, "", audit.Action("PURGE"), audit.ResourceFarm, 1, changes, now)
+
+		// Assert
+		assert.ErrorIs(t, err, audit.ErrInvalidAction)
+	})
+
+	t.Run("should return error for invalid resource type", func(t *testing.T) {
+		// Act
+		_, err := audit.NewEntry(identity.Identity{}, "", audit.ActionCreate, audit.ResourceType("session"), 1, changes, now)
+
+		// Assert
+		assert.ErrorIs(t, err, audit.ErrInvalidResourceType)
+	})
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	t.Run("should return the request ID stored in the context", func(t *testing.T) {
+		// Arrange
+		ctx := audit.NewRequestContext(context.Background(), "req-1")
+
+		// Act
+		requestID := audit.RequestIDFromContext(ctx)
+
+		// Assert
+		assert.Equal(t, "req-1", requestID)
+	})
+
+	t.Run("should return empty outside of a request", func(t *testing.T) {
+		// Act
+		requestID := audit.RequestIDFromContext(context.Background())
+
+		// Assert
+		assert.Empty(t, requestID)
+	})
+}
@@ -0,0 +1,9 @@
+package audit
+
+import "errors"
+
+var (
+	ErrInvalidAction       = errors.New("invalid audit action: must be CREATE, UPDATE or DELETE")
+	ErrInvalidResourceType = errors.New("invalid audit resource type: must be farm, crop, fertilizer or person")
+	ErrInvalidPeriod       = errors.New("invalid audit period: from must not be after to")
+)
@@ -0,0 +1,17 @@
+package audit
+
+import "time"
+
+// SortFields lists the fields audit entries can be ordered by; the first one is the default
+var SortFields = []string{"id", "occurredAt"}
+
+// Filter narrows down an audit listing; zero values are ignored
+type Filter struct {
+	ActorID      int64
+	Action       Action
+	ResourceType ResourceType
+	ResourceID   int64
+	RequestID    string
+	From         *time.Time
+	To           *time.Time
+}
@@ -0,0 +1,14 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/cropflow/api/internal/domain/query"
+)
+
+// Repository defines the interface for reading the audit log (Port). Entries
+// are appended by the repositories of the audited resources, in the same
+// transaction as the change, and are never updated or deleted.
+type Repository interface {
+	List(ctx context.Context, filter Filter, page query.Page) (query.Result[*Entry], error)
+}
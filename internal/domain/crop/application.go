@@ -0,0 +1,179 @@
+package crop
+
+import "time"
+
+// DoseUnit represents the unit a fertilizer dose per hectare is measured in
+type DoseUnit string
+
+const (
+	DoseKilogramsPerHectare   DoseUnit = "KG_HA"
+	DoseGramsPerHectare       DoseUnit = "G_HA"
+	DoseLitersPerHectare      DoseUnit = "L_HA"
+	DoseMillilitersPerHectare DoseUnit = "ML_HA"
+
+	// DoseUnknown marks applications migrated from the former crop-fertilizer
+	// links, which recorded neither dose nor date: their dose is zero and
+	// their date is the crop's planting date, or its creation date when it has
+	// none. It is never accepted for new applications.
+	DoseUnknown DoseUnit = "UNKNOWN"
+)
+
+// NewDoseUnit creates a new DoseUnit with validation
+func NewDoseUnit(value string) (DoseUnit, error) {
+	unit := DoseUnit(value)
+	switch unit {
+	case DoseKilogramsPerHectare, DoseGramsPerHectare, DoseLitersPerHectare, DoseMillilitersPerHectare:
+		return unit, nil
+	default:
+		return "", ErrInvalidDoseUnit
+	}
+}
+
+// String returns the string representation of the dose unit
+func (u DoseUnit) String() string {
+	return string(u)
+}
+
+// Application represents a fertilizer applied to a crop on a given date.
+// The same fertilizer may be applied to a crop several times per season.
+type Application struct {
+	id           int64
+	cropID       int64
+	fertilizerID int64
+	appliedAt    time.Time
+	dose         float64
+	doseUnit     DoseUnit
+	appliedArea  float64
+	operatorID   *int64
+	notes        string
+	createdAt    time.Time
+}
+
+// RestoreApplication reconstructs an Application from persistence (used by repository)
+func RestoreApplication(id, cropID, fertilizerID int64, appliedAt time.Time, dose float64, doseUnit DoseUnit, appliedArea float64, operatorID *int64, notes string, createdAt time.Time) *Application {
+	return &Application{
+		id:           id,
+		cropID:       cropID,
+		fertilizerID: fertilizerID,
+		appliedAt:    appliedAt,
+		dose:         dose,
+		doseUnit:     doseUnit,
+		appliedArea:  appliedArea,
+		operatorID:   operatorID,
+		notes:        notes,
+		createdAt:    createdAt,
+	}
+}
+
+// Getters (encapsulation)
+func (a *Application) ID() int64 {
+	return a.id
+}
+
+func (a *Application) CropID() int64 {
+	return a.cropID
+}
+
+func (a *Application) FertilizerID() int64 {
+	return a.fertilizerID
+}
+
+func (a *Application) AppliedAt() time.Time {
+	return a.appliedAt
+}
+
+// Dose returns the amount applied per hectare, in DoseUnit
+func (a *Application) Dose() float64 {
+	return a.dose
+}
+
+func (a *Application) DoseUnit() DoseUnit {
+	return a.doseUnit
+}
+
+// AppliedArea returns the hectares of the crop that received the application
+func (a *Application) AppliedArea() float64 {
+	return a.appliedArea
+}
+
+// OperatorID returns the person who carried out the application, if known
+func (a *Application) OperatorID() *int64 {
+	return a.operatorID
+}
+
+func (a *Application) Notes() string {
+	return a.notes
+}
+
+func (a *Application) CreatedAt() time.Time {
+	return a.createdAt
+}
+
+// SetID is used by repository after insertion
+func (a *Application) SetID(id int64) {
+	a.id = id
+}
+
+// TotalAmount returns the total amount applied (dose times applied area),
+// in the dose unit without the per-hectare part
+func (a *Application) TotalAmount() float64 {
+	return a.dose * a.appliedArea
+}
+
+// ProductKilograms returns the kilograms of product applied over the applied area.
+// Doses measured by volume cannot be converted without the product density,
+// and unknown doses not at all, in which case ok is false.
+func (a *Application) ProductKilograms() (kilograms float64, ok bool) {
+	switch a.doseUnit {
+	case DoseKilogramsPerHectare:
+		return a.TotalAmount(), true
+	case DoseGramsPerHectare:
+		return a.TotalAmount() / 1000, true
+	default:
+		return 0, false
+	}
+}
+
+// RecordApplication registers a fertilizer application on the crop.
+// The applied area may cover only part of the planted area.
+func (c *Crop) RecordApplication(fertilizerID int64, appliedAt time.Time, dose float64, doseUnit DoseUnit, appliedArea float64, operatorID *int64, notes string) (*Application, error) {
+	if fertilizerID <= 0 {
+		return nil, ErrFertilizerNotFound
+	}
+
+	if dose <= 0 {
+		return nil, ErrInvalidDose
+	}
+
+	if _, err := NewDoseUnit(doseUnit.String()); err != nil {
+		return nil, err
+	}
+
+	if appliedArea <= 0 || appliedArea > c.plantedArea {
+		return nil, ErrInvalidAppliedArea
+	}
+
+	a := &Application{
+		cropID:       c.id,
+		fertilizerID: fertilizerID,
+		appliedAt:    appliedAt,
+		dose:         dose,
+		doseUnit:     doseUnit,
+		appliedArea:  appliedArea,
+		operatorID:   operatorID,
+		notes:        notes,
+		createdAt:    time.Now(),
+	}
+	c.pendingApplications = append(c.pendingApplications, a)
+	return a, nil
+}
+
+// PendingApplications returns the applications recorded since the crop was loaded
+func (c *Crop) PendingApplications() []*Application {
+	return c.pendingApplications
+}
+
+// ClearPendingApplications is used by repository once the applications are persisted
+func (c *Crop) ClearPendingApplications() {
+	c.pendingApplications = nil
+}
@@ -0,0 +1,103 @@
+package crop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDoseUnit(t *testing.T) {
+	t.Run("should accept supported dose units", func(t *testing.T) {
+		for _, value := range []string{"KG_HA", "G_HA", "L_HA", "ML_HA"} {
+			// Act
+			unit, err := crop.NewDoseUnit(value)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, value, unit.String())
+		}
+	})
+
+	t.Run("should return error for unknown dose unit", func(t *testing.T) {
+		// Act
+		_, err := crop.NewDoseUnit("KG")
+
+		// Assert
+		assert.Equal(t, crop.ErrInvalidDoseUnit, err)
+	})
+
+	t.Run("should not accept the unit of migrated applications", func(t *testing.T) {
+		// Act
+		_, err := crop.NewDoseUnit(crop.DoseUnknown.String())
+
+		// Assert
+		assert.Equal(t, crop.ErrInvalidDoseUnit, err)
+	})
+}
+
+func TestCrop_RecordApplication(t *testing.T) {
+	appliedAt := time.Date(2024, 11, 5, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should allow the same fertilizer to be applied several times", func(t *testing.T) {
+		// Arrange
+		c, err := crop.NewCrop("Milho", 50.0, 1, nil, nil)
+		require.NoError(t, err)
+		operatorID := int64(7)
+
+		// Act
+		first, err := c.RecordApplication(3, appliedAt, 200, crop.DoseKilogramsPerHectare, 50, &operatorID, "base")
+		require.NoError(t, err)
+		second, err := c.RecordApplication(3, appliedAt.AddDate(0, 1, 0), 100, crop.DoseKilogramsPerHectare, 20, nil, "cobertura")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), first.FertilizerID())
+		assert.Equal(t, &operatorID, first.OperatorID())
+		assert.Equal(t, 10000.0, first.TotalAmount())
+		assert.Equal(t, 2000.0, second.TotalAmount())
+		assert.Len(t, c.PendingApplications(), 2)
+	})
+
+	t.Run("should convert mass doses to kilograms of product", func(t *testing.T) {
+		// Arrange
+		c, err := crop.NewCrop("Milho", 50.0, 1, nil, nil)
+		require.NoError(t, err)
+		grams, err := c.RecordApplication(3, appliedAt, 500, crop.DoseGramsPerHectare, 10, nil, "")
+		require.NoError(t, err)
+		liters, err := c.RecordApplication(3, appliedAt, 2, crop.DoseLitersPerHectare, 10, nil, "")
+		require.NoError(t, err)
+
+		// Act
+		kilograms, ok := grams.ProductKilograms()
+		_, convertible := liters.ProductKilograms()
+
+		// Assert
+		assert.True(t, ok)
+		assert.Equal(t, 5.0, kilograms)
+		assert.False(t, convertible)
+	})
+
+	t.Run("should validate dose, unit and applied area", func(t *testing.T) {
+		// Arrange
+		c, err := crop.NewCrop("Milho", 50.0, 1, nil, nil)
+		require.NoError(t, err)
+
+		// Act & Assert
+		_, err = c.RecordApplication(3, appliedAt, 0, crop.DoseKilogramsPerHectare, 10, nil, "")
+		assert.Equal(t, crop.ErrInvalidDose, err)
+
+		_, err = c.RecordApplication(3, appliedAt, 100, crop.DoseUnit("KG"), 10, nil, "")
+		assert.Equal(t, crop.ErrInvalidDoseUnit, err)
+
+		_, err = c.RecordApplication(3, appliedAt, 100, crop.DoseKilogramsPerHectare, 0, nil, "")
+		assert.Equal(t, crop.ErrInvalidAppliedArea, err)
+
+		_, err = c.RecordApplication(3, appliedAt, 100, crop.DoseKilogramsPerHectare, 50.5, nil, "")
+		assert.Equal(t, crop.ErrInvalidAppliedArea, err)
+
+		assert.Empty(t, c.PendingApplications())
+	})
+}
@@ -12,8 +12,14 @@ type Crop struct {
 	farmID      int64
 	plantedDate *time.Time
 	harvestDate *time.Time
+	status      Status
 	createdAt   time.Time
 	updatedAt   time.Time
+
+	statusChangedAt     *time.Time
+	pendingTransitions  []Transition
+	pendingHarvests     []*Harvest
+	pendingApplications []*Application
 }
 
 // NewCrop creates a new Crop with validation (Factory Method)
@@ -41,22 +47,28 @@ func NewCrop(name string, plantedArea float64, farmID int64, plantedDate, harves
 		farmID:      farmID,
 		plantedDate: plantedDate,
 		harvestDate: harvestDate,
+		status:      StatusPlanned,
 		createdAt:   now,
 		updatedAt:   now,
+		pendingTransitions: []Transition{
+			{to: StatusPlanned, occurredAt: now, recordedAt: now},
+		},
 	}, nil
 }
 
 // Restore reconstructs a Crop from persistence (used by repository)
-func Restore(id int64, name string, plantedArea float64, farmID int64, plantedDate, harvestDate time.Time, createdAt, updatedAt time.Time) *Crop {
+func Restore(id int64, name string, plantedArea float64, farmID int64, plantedDate, harvestDate *time.Time, status Status, statusChangedAt *time.Time, createdAt, updatedAt time.Time) *Crop {
 	return &Crop{
-		id:          id,
-		name:        name,
-		plantedArea: plantedArea,
-		farmID:      farmID,
-		plantedDate: &plantedDate,
-		harvestDate: &harvestDate,
-		createdAt:   createdAt,
-		updatedAt:   updatedAt,
+		id:              id,
+		name:            name,
+		plantedArea:     plantedArea,
+		farmID:          farmID,
+		plantedDate:     plantedDate,
+		harvestDate:     harvestDate,
+		status:          status,
+		statusChangedAt: statusChangedAt,
+		createdAt:       createdAt,
+		updatedAt:       updatedAt,
 	}
 }
 
@@ -85,6 +97,14 @@ func (c *Crop) HarvestDate() *time.Time {
 	return c.harvestDate
 }
 
+func (c *Crop) Status() Status {
+	return c.status
+}
+
+func (c *Crop) StatusChangedAt() *time.Time {
+	return c.statusChangedAt
+}
+
 func (c *Crop) CreatedAt() time.Time {
 	return c.createdAt
 }
@@ -120,20 +140,20 @@ func (c *Crop) ChangePlantedArea(newArea float64) error {
 	return nil
 }
 
-// SetPlantedDate sets the planting date
-func (c *Crop) SetPlantedDate(date *time.Time) {
-	c.plantedDate = date
-	c.updatedAt = time.Now()
+// KeepDates checks that the given planted and harvest dates are the ones the
+// crop already has. Only the transitions to PLANTED and HARVESTED set them.
+func (c *Crop) KeepDates(plantedDate, harvestDate *time.Time) error {
+	if !sameDate(c.plantedDate, plantedDate) || !sameDate(c.harvestDate, harvestDate) {
+		return ErrDatesSetByTransitions
+	}
+	return nil
 }
 
-// ChangeHarvestDate changes the harvest date with validation
-func (c *Crop) ChangeHarvestDate(newDate *time.Time) error {
-	if c.plantedDate != nil && newDate != nil && newDate.Before(*c.plantedDate) {
-		return ErrInvalidHarvestDate
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
 	}
-	c.harvestDate = newDate
-	c.updatedAt = time.Now()
-	return nil
+	return a.Equal(*b)
 }
 
 // IsValid checks if the crop is in a valid state
@@ -189,39 +189,51 @@ func TestCrop_ChangePlantedArea(t *testing.T) {
 	})
 }
 
-func TestCrop_ChangeHarvestDate(t *testing.T) {
-	t.Run("should change harvest date successfully", func(t *testing.T) {
+func TestCrop_KeepDates(t *testing.T) {
+	plantedDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	harvestDate := plantedDate.AddDate(0, 6, 0)
+	sameHarvest := harvestDate.In(time.FixedZone("BRT", -3*60*60))
+	otherHarvest := plantedDate.AddDate(0, 8, 0)
+
+	tests := []struct {
+		name        string
+		plantedDate *time.Time
+		harvestDate *time.Time
+		expected    error
+	}{
+		{"should accept the dates the crop has", &plantedDate, &harvestDate, nil},
+		{"should accept the same instant in another zone", &plantedDate, &sameHarvest, nil},
+		{"should reject another harvest date", &plantedDate, &otherHarvest, crop.ErrDatesSetByTransitions},
+		{"should reject clearing the harvest date", &plantedDate, nil, crop.ErrDatesSetByTransitions},
+		{"should reject clearing the planted date", nil, &harvestDate, crop.ErrDatesSetByTransitions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			c, err := crop.NewCrop("Milho", 50.0, 1, &plantedDate, &harvestDate)
+			require.NoError(t, err)
+
+			// Act
+			err = c.KeepDates(tt.plantedDate, tt.harvestDate)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.expected)
+			assert.Equal(t, &harvestDate, c.HarvestDate())
+		})
+	}
+
+	t.Run("should reject a harvest date on a crop that was not harvested", func(t *testing.T) {
 		// Arrange
-		plantedDate := time.Now()
-		harvestDate := plantedDate.AddDate(0, 6, 0)
-		c, _ := crop.NewCrop("Milho", 50.0, 1, &plantedDate, &harvestDate)
-		originalUpdatedAt := c.UpdatedAt()
-		time.Sleep(1 * time.Millisecond)
-		newHarvestDate := plantedDate.AddDate(0, 8, 0)
-
-		// Act
-		err := c.ChangeHarvestDate(&newHarvestDate)
-
-		// Assert
+		c, err := crop.NewCrop("Milho", 50.0, 1, nil, nil)
 		require.NoError(t, err)
-		assert.Equal(t, &newHarvestDate, c.HarvestDate())
-		assert.True(t, c.UpdatedAt().After(originalUpdatedAt))
-	})
-
-	t.Run("should return error when new harvest date is before planted date", func(t *testing.T) {
-		// Arrange
-		plantedDate := time.Now()
-		harvestDate := plantedDate.AddDate(0, 6, 0)
-		c, _ := crop.NewCrop("Milho", 50.0, 1, &plantedDate, &harvestDate)
-		newHarvestDate := plantedDate.AddDate(0, -1, 0)
 
 		// Act
-		err := c.ChangeHarvestDate(&newHarvestDate)
+		err = c.KeepDates(nil, &harvestDate)
 
 		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, crop.ErrInvalidHarvestDate, err)
-		assert.Equal(t, &harvestDate, c.HarvestDate())
+		assert.ErrorIs(t, err, crop.ErrDatesSetByTransitions)
+		assert.Nil(t, c.HarvestDate())
 	})
 }
 
@@ -253,7 +265,7 @@ func TestRestore(t *testing.T) {
 		updatedAt := time.Now()
 
 		// Act
-		c := crop.Restore(id, name, plantedArea, farmID, plantedDate, harvestDate, createdAt, updatedAt)
+		c := crop.Restore(id, name, plantedArea, farmID, &plantedDate, &harvestDate, crop.StatusPlanted, &plantedDate, createdAt, updatedAt)
 
 		// Assert
 		assert.NotNil(t, c)
@@ -263,6 +275,8 @@ func TestRestore(t *testing.T) {
 		assert.Equal(t, farmID, c.FarmID())
 		assert.Equal(t, &plantedDate, c.PlantedDate())
 		assert.Equal(t, &harvestDate, c.HarvestDate())
+		assert.Equal(t, crop.StatusPlanted, c.Status())
+		assert.Empty(t, c.PendingTransitions())
 		assert.Equal(t, createdAt, c.CreatedAt())
 		assert.Equal(t, updatedAt, c.UpdatedAt())
 	})
@@ -3,12 +3,24 @@ package crop
 import "errors"
 
 var (
-	ErrCropNotFound        = errors.New("crop not found")
-	ErrInvalidCropName     = errors.New("invalid crop name: cannot be empty")
-	ErrInvalidPlantedArea  = errors.New("invalid planted area: must be greater than zero")
-	ErrInvalidFarmID       = errors.New("invalid farm ID")
-	ErrInvalidHarvestDate  = errors.New("invalid harvest date: must be after planted date")
-	ErrFarmNotFound        = errors.New("farm not found")
-	ErrFertilizerNotFound  = errors.New("fertilizer not found")
-	ErrDuplicateFertilizer = errors.New("fertilizer already associated with this crop")
+	ErrCropNotFound           = errors.New("crop not found")
+	ErrInvalidCropName        = errors.New("invalid crop name: cannot be empty")
+	ErrInvalidPlantedArea     = errors.New("invalid planted area: must be greater than zero")
+	ErrInvalidFarmID          = errors.New("invalid farm ID")
+	ErrInvalidHarvestDate     = errors.New("invalid harvest date: must be after planted date")
+	ErrFarmNotFound           = errors.New("farm not found")
+	ErrFertilizerNotFound     = errors.New("fertilizer not found")
+	ErrInvalidStatus          = errors.New("invalid status: must be PLANNED, PLANTED, GROWING, HARVESTED, FAILED or ABANDONED")
+	ErrInvalidTransition      = errors.New("invalid status transition for the current crop status")
+	ErrInvalidTransitionDate  = errors.New("invalid transition date: must not be before the previous transition")
+	ErrDatesSetByTransitions  = errors.New("invalid crop dates: the planted and harvest dates are set by the PLANTED and HARVESTED transitions")
+	ErrHarvestNotFound        = errors.New("harvest not found")
+	ErrCropNotHarvestable     = errors.New("crop must be GROWING or HARVESTED to record a harvest")
+	ErrInvalidHarvestQuantity = errors.New("invalid harvest quantity: must be greater than zero")
+	ErrInvalidUnit            = errors.New("invalid unit: must be KG, TONNE or BAG_60KG")
+	ErrInvalidMoisture        = errors.New("invalid moisture: must be between 0 and 100")
+	ErrInvalidDose            = errors.New("invalid dose: must be greater than zero")
+	ErrInvalidDoseUnit        = errors.New("invalid dose unit: must be KG_HA, G_HA, L_HA or ML_HA")
+	ErrInvalidAppliedArea     = errors.New("invalid applied area: must be greater than zero and not exceed the planted area")
+	ErrOperatorNotFound       = errors.New("operator not found")
 )
@@ -0,0 +1,30 @@
+package crop
+
+import "time"
+
+// SortFields lists the fields crops can be ordered by; the first one is the default
+var SortFields = []string{"id", "name", "plantedArea", "createdAt"}
+
+// Filter narrows down a crop listing; zero values are ignored
+type Filter struct {
+	FarmID      int64
+	Status      Status
+	NamePrefix  string
+	PlantedFrom *time.Time
+	PlantedTo   *time.Time
+	MinArea     *float64
+	MaxArea     *float64
+
+	// MemberID restricts the listing to crops of farms the person is a member of
+	MemberID int64
+	// FarmIDs restricts the listing to crops of the given farms, e.g. the farms an API key is limited to
+	FarmIDs []int64
+}
+
+// ApplicationFilter narrows down fertilizer applications; zero values are ignored
+type ApplicationFilter struct {
+	CropID      int64
+	FarmID      int64
+	AppliedFrom *time.Time
+	AppliedTo   *time.Time
+}
@@ -0,0 +1,219 @@
+package crop
+
+import "time"
+
+// Unit represents the unit a harvested quantity is measured in
+type Unit string
+
+const (
+	UnitKilogram Unit = "KG"
+	UnitTonne    Unit = "TONNE"
+	UnitBag      Unit = "BAG_60KG"
+)
+
+// kilogramsPerUnit converts each unit to kilograms
+var kilogramsPerUnit = map[Unit]float64{
+	UnitKilogram: 1,
+	UnitTonne:    1000,
+	UnitBag:      60,
+}
+
+// NewUnit creates a new Unit with validation
+func NewUnit(value string) (Unit, error) {
+	unit := Unit(value)
+	if _, ok := kilogramsPerUnit[unit]; !ok {
+		return "", ErrInvalidUnit
+	}
+	return unit, nil
+}
+
+// String returns the string representation of the unit
+func (u Unit) String() string {
+	return string(u)
+}
+
+// ToKilograms converts a quantity in this unit to kilograms
+func (u Unit) ToKilograms(quantity float64) float64 {
+	return quantity * kilogramsPerUnit[u]
+}
+
+// FromKilograms converts a quantity in kilograms to this unit
+func (u Unit) FromKilograms(kilograms float64) float64 {
+	return kilograms / kilogramsPerUnit[u]
+}
+
+// Harvest represents a load harvested from a crop.
+// A crop may be harvested in several partial loads before the final one.
+type Harvest struct {
+	id          int64
+	cropID      int64
+	harvestedAt time.Time
+	quantity    float64
+	unit        Unit
+	moisture    *float64
+	grade       string
+	partial     bool
+	createdAt   time.Time
+}
+
+// RestoreHarvest reconstructs a Harvest from persistence (used by repository)
+func RestoreHarvest(id, cropID int64, harvestedAt time.Time, quantity float64, unit Unit, moisture *float64, grade string, partial bool, createdAt time.Time) *Harvest {
+	return &Harvest{
+		id:          id,
+		cropID:      cropID,
+		harvestedAt: harvestedAt,
+		quantity:    quantity,
+		unit:        unit,
+		moisture:    moisture,
+		grade:       grade,
+		partial:     partial,
+		createdAt:   createdAt,
+	}
+}
+
+// Getters (encapsulation)
+func (h *Harvest) ID() int64 {
+	return h.id
+}
+
+func (h *Harvest) CropID() int64 {
+	return h.cropID
+}
+
+func (h *Harvest) HarvestedAt() time.Time {
+	return h.harvestedAt
+}
+
+func (h *Harvest) Quantity() float64 {
+	return h.quantity
+}
+
+func (h *Harvest) Unit() Unit {
+	return h.unit
+}
+
+// Moisture returns the grain moisture percentage measured at harvest, if any
+func (h *Harvest) Moisture() *float64 {
+	return h.moisture
+}
+
+func (h *Harvest) Grade() string {
+	return h.grade
+}
+
+func (h *Harvest) IsPartial() bool {
+	return h.partial
+}
+
+func (h *Harvest) CreatedAt() time.Time {
+	return h.createdAt
+}
+
+// SetID is used by repository after insertion
+func (h *Harvest) SetID(id int64) {
+	h.id = id
+}
+
+// Kilograms returns the harvested quantity in kilograms
+func (h *Harvest) Kilograms() float64 {
+	return h.unit.ToKilograms(h.quantity)
+}
+
+// RecordHarvest registers a harvest of a growing or harvested crop.
+// A final (non-partial) harvest of a growing crop moves it to HARVESTED.
+func (c *Crop) RecordHarvest(harvestedAt time.Time, quantity float64, unit Unit, moisture *float64, grade string, partial bool) (*Harvest, error) {
+	if c.status != StatusGrowing && c.status != StatusHarvested {
+		return nil, ErrCropNotHarvestable
+	}
+
+	if quantity <= 0 {
+		return nil, ErrInvalidHarvestQuantity
+	}
+
+	if _, ok := kilogramsPerUnit[unit]; !ok {
+		return nil, ErrInvalidUnit
+	}
+
+	if moisture != nil && (*moisture < 0 || *moisture > 100) {
+		return nil, ErrInvalidMoisture
+	}
+
+	if c.plantedDate != nil && harvestedAt.Before(*c.plantedDate) {
+		return nil, ErrInvalidHarvestDate
+	}
+
+	if !partial && c.status == StatusGrowing {
+		if err := c.TransitionTo(StatusHarvested, harvestedAt, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	h := &Harvest{
+		cropID:      c.id,
+		harvestedAt: harvestedAt,
+		quantity:    quantity,
+		unit:        unit,
+		moisture:    moisture,
+		grade:       grade,
+		partial:     partial,
+		createdAt:   time.Now(),
+	}
+	c.pendingHarvests = append(c.pendingHarvests, h)
+	return h, nil
+}
+
+// PendingHarvests returns the harvests recorded since the crop was loaded
+func (c *Crop) PendingHarvests() []*Harvest {
+	return c.pendingHarvests
+}
+
+// ClearPendingHarvests is used by repository once the harvests are persisted
+func (c *Crop) ClearPendingHarvests() {
+	c.pendingHarvests = nil
+}
+
+// Yield summarizes the harvests of a crop against its planted area
+type Yield struct {
+	plantedArea  float64
+	kilograms    float64
+	harvestCount int
+	complete     bool
+}
+
+// Yield computes the crop yield from its harvests
+func (c *Crop) Yield(harvests []*Harvest) Yield {
+	y := Yield{plantedArea: c.plantedArea, harvestCount: len(harvests)}
+	for _, h := range harvests {
+		y.kilograms += h.Kilograms()
+		if !h.partial {
+			y.complete = true
+		}
+	}
+	return y
+}
+
+func (y Yield) PlantedArea() float64 {
+	return y.plantedArea
+}
+
+func (y Yield) HarvestCount() int {
+	return y.harvestCount
+}
+
+// IsComplete reports whether the final harvest has been recorded
+func (y Yield) IsComplete() bool {
+	return y.complete
+}
+
+// Total returns the total harvested quantity in the given unit
+func (y Yield) Total(unit Unit) float64 {
+	return unit.FromKilograms(y.kilograms)
+}
+
+// PerHectare returns the harvested quantity per planted hectare in the given unit
+func (y Yield) PerHectare(unit Unit) float64 {
+	if y.plantedArea <= 0 {
+		return 0
+	}
+	return unit.FromKilograms(y.kilograms) / y.plantedArea
+}
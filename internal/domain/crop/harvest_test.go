@@ -0,0 +1,150 @@
+package crop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func growingCrop(t *testing.T, plantedAt time.Time) *crop.Crop {
+	t.Helper()
+	c, err := crop.NewCrop("Soja", 40.0, 1, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, c.Plant(plantedAt))
+	require.NoError(t, c.StartGrowing(plantedAt.AddDate(0, 0, 15)))
+	c.ClearPendingTransitions()
+	return c
+}
+
+func TestNewUnit(t *testing.T) {
+	t.Run("should convert supported units to kilograms", func(t *testing.T) {
+		// Act
+		tonne, err := crop.NewUnit("TONNE")
+		require.NoError(t, err)
+		bag, err := crop.NewUnit("BAG_60KG")
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, 2500.0, tonne.ToKilograms(2.5))
+		assert.Equal(t, 600.0, bag.ToKilograms(10))
+		assert.Equal(t, 10.0, bag.FromKilograms(600))
+	})
+
+	t.Run("should return error for unknown unit", func(t *testing.T) {
+		// Act
+		_, err := crop.NewUnit("LB")
+
+		// Assert
+		assert.Equal(t, crop.ErrInvalidUnit, err)
+	})
+}
+
+func TestCrop_RecordHarvest(t *testing.T) {
+	plantedAt := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should keep crop growing on partial harvest", func(t *testing.T) {
+		// Arrange
+		c := growingCrop(t, plantedAt)
+		moisture := 13.5
+
+		// Act
+		h, err := c.RecordHarvest(plantedAt.AddDate(0, 4, 0), 120, crop.UnitBag, &moisture, "A", true)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, h.IsPartial())
+		assert.Equal(t, 7200.0, h.Kilograms())
+		assert.Equal(t, &moisture, h.Moisture())
+		assert.Equal(t, crop.StatusGrowing, c.Status())
+		assert.Len(t, c.PendingHarvests(), 1)
+		assert.Empty(t, c.PendingTransitions())
+	})
+
+	t.Run("should mark crop harvested on final harvest", func(t *testing.T) {
+		// Arrange
+		c := growingCrop(t, plantedAt)
+		harvestedAt := plantedAt.AddDate(0, 5, 0)
+
+		// Act
+		_, err := c.RecordHarvest(harvestedAt, 80, crop.UnitTonne, nil, "", false)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, crop.StatusHarvested, c.Status())
+		assert.Equal(t, &harvestedAt, c.HarvestDate())
+		assert.Len(t, c.PendingTransitions(), 1)
+	})
+
+	t.Run("should reject harvest of a crop that is not growing", func(t *testing.T) {
+		// Arrange
+		c, err := crop.NewCrop("Soja", 40.0, 1, nil, nil)
+		require.NoError(t, err)
+
+		// Act
+		_, err = c.RecordHarvest(plantedAt, 10, crop.UnitKilogram, nil, "", true)
+
+		// Assert
+		assert.Equal(t, crop.ErrCropNotHarvestable, err)
+	})
+
+	t.Run("should validate quantity, unit, moisture and date", func(t *testing.T) {
+		// Arrange
+		c := growingCrop(t, plantedAt)
+		harvestedAt := plantedAt.AddDate(0, 4, 0)
+		moisture := 120.0
+
+		// Act & Assert
+		_, err := c.RecordHarvest(harvestedAt, 0, crop.UnitKilogram, nil, "", true)
+		assert.Equal(t, crop.ErrInvalidHarvestQuantity, err)
+
+		_, err = c.RecordHarvest(harvestedAt, 10, crop.Unit("LB"), nil, "", true)
+		assert.Equal(t, crop.ErrInvalidUnit, err)
+
+		_, err = c.RecordHarvest(harvestedAt, 10, crop.UnitKilogram, &moisture, "", true)
+		assert.Equal(t, crop.ErrInvalidMoisture, err)
+
+		_, err = c.RecordHarvest(plantedAt.AddDate(0, 0, -1), 10, crop.UnitKilogram, nil, "", true)
+		assert.Equal(t, crop.ErrInvalidHarvestDate, err)
+
+		assert.Empty(t, c.PendingHarvests())
+	})
+}
+
+func TestCrop_Yield(t *testing.T) {
+	t.Run("should compute yield per hectare from all harvests", func(t *testing.T) {
+		// Arrange
+		plantedAt := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+		c := growingCrop(t, plantedAt)
+		first, err := c.RecordHarvest(plantedAt.AddDate(0, 4, 0), 1200, crop.UnitBag, nil, "", true)
+		require.NoError(t, err)
+		last, err := c.RecordHarvest(plantedAt.AddDate(0, 4, 10), 72, crop.UnitTonne, nil, "", false)
+		require.NoError(t, err)
+
+		// Act
+		yield := c.Yield([]*crop.Harvest{first, last})
+
+		// Assert
+		assert.Equal(t, 2, yield.HarvestCount())
+		assert.True(t, yield.IsComplete())
+		assert.Equal(t, 144000.0, yield.Total(crop.UnitKilogram))
+		assert.Equal(t, 3600.0, yield.PerHectare(crop.UnitKilogram))
+		assert.Equal(t, 60.0, yield.PerHectare(crop.UnitBag))
+	})
+
+	t.Run("should be empty without harvests", func(t *testing.T) {
+		// Arrange
+		c, err := crop.NewCrop("Soja", 40.0, 1, nil, nil)
+		require.NoError(t, err)
+
+		// Act
+		yield := c.Yield(nil)
+
+		// Assert
+		assert.Zero(t, yield.HarvestCount())
+		assert.False(t, yield.IsComplete())
+		assert.Zero(t, yield.PerHectare(crop.UnitKilogram))
+	})
+}
@@ -0,0 +1,172 @@
+package crop
+
+import "time"
+
+// Status represents the lifecycle stage of a crop
+type Status string
+
+const (
+	StatusPlanned   Status = "PLANNED"
+	StatusPlanted   Status = "PLANTED"
+	StatusGrowing   Status = "GROWING"
+	StatusHarvested Status = "HARVESTED"
+	StatusFailed    Status = "FAILED"
+	StatusAbandoned Status = "ABANDONED"
+)
+
+// allowedTransitions lists, for each status, the statuses it can move to.
+// HARVESTED, FAILED and ABANDONED are terminal.
+var allowedTransitions = map[Status][]Status{
+	StatusPlanned: {StatusPlanted, StatusAbandoned},
+	StatusPlanted: {StatusGrowing, StatusFailed, StatusAbandoned},
+	StatusGrowing: {StatusHarvested, StatusFailed, StatusAbandoned},
+}
+
+// NewStatus creates a new Status with validation
+func NewStatus(value string) (Status, error) {
+	status := Status(value)
+	switch status {
+	case StatusPlanned, StatusPlanted, StatusGrowing, StatusHarvested, StatusFailed, StatusAbandoned:
+		return status, nil
+	default:
+		return "", ErrInvalidStatus
+	}
+}
+
+// String returns the string representation of the status
+func (s Status) String() string {
+	return string(s)
+}
+
+// CanTransitionTo checks if a crop in this status may move to the next one
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range allowedTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal checks if no further transitions are possible from this status
+func (s Status) IsTerminal() bool {
+	return len(allowedTransitions[s]) == 0
+}
+
+// Transition represents a recorded change of a crop's status
+type Transition struct {
+	from       Status
+	to         Status
+	occurredAt time.Time
+	note       string
+	recordedAt time.Time
+}
+
+// RestoreTransition reconstructs a Transition from persistence (used by repository)
+func RestoreTransition(from, to Status, occurredAt time.Time, note string, recordedAt time.Time) Transition {
+	return Transition{
+		from:       from,
+		to:         to,
+		occurredAt: occurredAt,
+		note:       note,
+		recordedAt: recordedAt,
+	}
+}
+
+// From returns the status before the transition (empty for the initial one)
+func (t Transition) From() Status {
+	return t.from
+}
+
+// To returns the status after the transition
+func (t Transition) To() Status {
+	return t.to
+}
+
+// OccurredAt returns when the transition happened in the field
+func (t Transition) OccurredAt() time.Time {
+	return t.occurredAt
+}
+
+// Note returns the free-text remark attached to the transition
+func (t Transition) Note() string {
+	return t.note
+}
+
+// RecordedAt returns when the transition was registered in the system
+func (t Transition) RecordedAt() time.Time {
+	return t.recordedAt
+}
+
+// TransitionTo moves the crop to the given status, enforcing the lifecycle.
+// Planting and harvesting also set the corresponding crop dates.
+func (c *Crop) TransitionTo(to Status, at time.Time, note string) error {
+	if !c.status.CanTransitionTo(to) {
+		return ErrInvalidTransition
+	}
+
+	if c.statusChangedAt != nil && at.Before(*c.statusChangedAt) {
+		return ErrInvalidTransitionDate
+	}
+
+	switch to {
+	case StatusPlanted:
+		if c.harvestDate != nil && c.harvestDate.Before(at) {
+			return ErrInvalidHarvestDate
+		}
+		c.plantedDate = &at
+	case StatusHarvested:
+		if c.plantedDate != nil && at.Before(*c.plantedDate) {
+			return ErrInvalidHarvestDate
+		}
+		c.harvestDate = &at
+	}
+
+	now := time.Now()
+	c.pendingTransitions = append(c.pendingTransitions, Transition{
+		from:       c.status,
+		to:         to,
+		occurredAt: at,
+		note:       note,
+		recordedAt: now,
+	})
+	c.status = to
+	c.statusChangedAt = &at
+	c.updatedAt = now
+	return nil
+}
+
+// Plant marks a planned crop as planted
+func (c *Crop) Plant(at time.Time) error {
+	return c.TransitionTo(StatusPlanted, at, "")
+}
+
+// StartGrowing marks a planted crop as growing
+func (c *Crop) StartGrowing(at time.Time) error {
+	return c.TransitionTo(StatusGrowing, at, "")
+}
+
+// Harvest marks a growing crop as harvested
+func (c *Crop) Harvest(at time.Time) error {
+	return c.TransitionTo(StatusHarvested, at, "")
+}
+
+// Fail marks a planted or growing crop as lost
+func (c *Crop) Fail(at time.Time, reason string) error {
+	return c.TransitionTo(StatusFailed, at, reason)
+}
+
+// Abandon marks a crop that will not be carried on
+func (c *Crop) Abandon(at time.Time, reason string) error {
+	return c.TransitionTo(StatusAbandoned, at, reason)
+}
+
+// PendingTransitions returns the transitions recorded since the crop was loaded
+func (c *Crop) PendingTransitions() []Transition {
+	return c.pendingTransitions
+}
+
+// ClearPendingTransitions is used by repository once the transitions are persisted
+func (c *Crop) ClearPendingTransitions() {
+	c.pendingTransitions = nil
+}
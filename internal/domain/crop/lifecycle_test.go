@@ -0,0 +1,151 @@
+package crop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStatus(t *testing.T) {
+	t.Run("should accept every lifecycle status", func(t *testing.T) {
+		for _, value := range []string{"PLANNED", "PLANTED", "GROWING", "HARVESTED", "FAILED", "ABANDONED"} {
+			// Act
+			status, err := crop.NewStatus(value)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, value, status.String())
+		}
+	})
+
+	t.Run("should return error for unknown status", func(t *testing.T) {
+		// Act
+		_, err := crop.NewStatus("SPROUTED")
+
+		// Assert
+		assert.Equal(t, crop.ErrInvalidStatus, err)
+	})
+}
+
+func TestStatus_CanTransitionTo(t *testing.T) {
+	t.Run("should follow the crop lifecycle", func(t *testing.T) {
+		assert.True(t, crop.StatusPlanned.CanTransitionTo(crop.StatusPlanted))
+		assert.True(t, crop.StatusPlanted.CanTransitionTo(crop.StatusGrowing))
+		assert.True(t, crop.StatusGrowing.CanTransitionTo(crop.StatusHarvested))
+		assert.True(t, crop.StatusGrowing.CanTransitionTo(crop.StatusFailed))
+		assert.True(t, crop.StatusPlanned.CanTransitionTo(crop.StatusAbandoned))
+
+		assert.False(t, crop.StatusPlanned.CanTransitionTo(crop.StatusHarvested))
+		assert.False(t, crop.StatusPlanned.CanTransitionTo(crop.StatusFailed))
+		assert.False(t, crop.StatusGrowing.CanTransitionTo(crop.StatusPlanted))
+	})
+
+	t.Run("should not leave terminal statuses", func(t *testing.T) {
+		for _, status := range []crop.Status{crop.StatusHarvested, crop.StatusFailed, crop.StatusAbandoned} {
+			assert.True(t, status.IsTerminal())
+			assert.False(t, status.CanTransitionTo(crop.StatusPlanned))
+		}
+	})
+}
+
+func TestCrop_Lifecycle(t *testing.T) {
+	t.Run("should start planned with an initial transition", func(t *testing.T) {
+		// Act
+		c, err := crop.NewCrop("Milho", 50.0, 1, nil, nil)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, crop.StatusPlanned, c.Status())
+		require.Len(t, c.PendingTransitions(), 1)
+		assert.Equal(t, crop.Status(""), c.PendingTransitions()[0].From())
+		assert.Equal(t, crop.StatusPlanned, c.PendingTransitions()[0].To())
+	})
+
+	t.Run("should go through the lifecycle and set the crop dates", func(t *testing.T) {
+		// Arrange
+		c, _ := crop.NewCrop("Milho", 50.0, 1, nil, nil)
+		c.ClearPendingTransitions()
+		plantedAt := time.Date(2024, 9, 15, 0, 0, 0, 0, time.UTC)
+		growingAt := plantedAt.AddDate(0, 0, 10)
+		harvestedAt := plantedAt.AddDate(0, 5, 0)
+
+		// Act
+		require.NoError(t, c.Plant(plantedAt))
+		require.NoError(t, c.StartGrowing(growingAt))
+		require.NoError(t, c.Harvest(harvestedAt))
+
+		// Assert
+		assert.Equal(t, crop.StatusHarvested, c.Status())
+		assert.Equal(t, &plantedAt, c.PlantedDate())
+		assert.Equal(t, &harvestedAt, c.HarvestDate())
+		assert.Equal(t, &harvestedAt, c.StatusChangedAt())
+
+		transitions := c.PendingTransitions()
+		require.Len(t, transitions, 3)
+		assert.Equal(t, crop.StatusPlanned, transitions[0].From())
+		assert.Equal(t, crop.StatusPlanted, transitions[0].To())
+		assert.Equal(t, plantedAt, transitions[0].OccurredAt())
+		assert.Equal(t, crop.StatusGrowing, transitions[2].From())
+		assert.Equal(t, crop.StatusHarvested, transitions[2].To())
+	})
+
+	t.Run("should return error when skipping a stage", func(t *testing.T) {
+		// Arrange
+		c, _ := crop.NewCrop("Milho", 50.0, 1, nil, nil)
+
+		// Act
+		err := c.Harvest(time.Now())
+
+		// Assert
+		assert.Equal(t, crop.ErrInvalidTransition, err)
+		assert.Equal(t, crop.StatusPlanned, c.Status())
+		assert.Nil(t, c.HarvestDate())
+	})
+
+	t.Run("should return error when transition predates the previous one", func(t *testing.T) {
+		// Arrange
+		c, _ := crop.NewCrop("Milho", 50.0, 1, nil, nil)
+		plantedAt := time.Date(2024, 9, 15, 0, 0, 0, 0, time.UTC)
+		_ = c.Plant(plantedAt)
+
+		// Act
+		err := c.StartGrowing(plantedAt.AddDate(0, 0, -1))
+
+		// Assert
+		assert.Equal(t, crop.ErrInvalidTransitionDate, err)
+		assert.Equal(t, crop.StatusPlanted, c.Status())
+	})
+
+	t.Run("should return error when planting after the expected harvest", func(t *testing.T) {
+		// Arrange
+		harvestDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		c, _ := crop.NewCrop("Milho", 50.0, 1, nil, &harvestDate)
+
+		// Act
+		err := c.Plant(harvestDate.AddDate(0, 1, 0))
+
+		// Assert
+		assert.Equal(t, crop.ErrInvalidHarvestDate, err)
+		assert.Equal(t, crop.StatusPlanned, c.Status())
+	})
+
+	t.Run("should record the reason when a crop fails", func(t *testing.T) {
+		// Arrange
+		c, _ := crop.NewCrop("Milho", 50.0, 1, nil, nil)
+		plantedAt := time.Date(2024, 9, 15, 0, 0, 0, 0, time.UTC)
+		_ = c.Plant(plantedAt)
+
+		// Act
+		err := c.Fail(plantedAt.AddDate(0, 1, 0), "geada")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, crop.StatusFailed, c.Status())
+		last := c.PendingTransitions()[len(c.PendingTransitions())-1]
+		assert.Equal(t, "geada", last.Note())
+		assert.Equal(t, crop.ErrInvalidTransition, c.Abandon(time.Now(), ""))
+	})
+}
@@ -1,14 +1,27 @@
 package crop
 
-import "context"
+import (
+	"context"
+
+	"github.com/cropflow/api/internal/domain/query"
+)
 
 // Repository defines the interface for crop persistence (Port)
 type Repository interface {
+	// Save returns ErrFarmNotFound when the farm of the crop does not exist,
+	// even if it was deleted after the caller last checked for it
 	Save(ctx context.Context, crop *Crop) error
 	FindByID(ctx context.Context, id int64) (*Crop, error)
-	FindAll(ctx context.Context) ([]*Crop, error)
-	FindByFarmID(ctx context.Context, farmID int64) ([]*Crop, error)
+	// FindByIDForUpdate loads a crop locked against concurrent changes until
+	// the transaction in ctx ends
+	FindByIDForUpdate(ctx context.Context, id int64) (*Crop, error)
+	List(ctx context.Context, filter Filter, page query.Page) (query.Result[*Crop], error)
 	Delete(ctx context.Context, id int64) error
-	AddFertilizer(ctx context.Context, cropID, fertilizerID int64) error
+	FindByFarmID(ctx context.Context, farmID int64) ([]*Crop, error)
+	FindApplications(ctx context.Context, filter ApplicationFilter) ([]*Application, error)
 	FindFertilizersByCropID(ctx context.Context, cropID int64) ([]int64, error)
+	FindTransitions(ctx context.Context, cropID int64) ([]Transition, error)
+	FindHarvests(ctx context.Context, cropID int64) ([]*Harvest, error)
+	FindHarvestByID(ctx context.Context, cropID, harvestID int64) (*Harvest, error)
+	DeleteHarvest(ctx context.Context, cropID, harvestID int64) error
 }
@@ -9,4 +9,11 @@ var (
 	ErrFarmCapacityReached = errors.New("farm has reached maximum crop capacity (100)")
 	ErrCropNotFound        = errors.New("crop not found in farm")
 	ErrDuplicateCrop       = errors.New("crop already exists in farm")
+	ErrFarmHasCrops        = errors.New("farm still has crops")
+	ErrFarmAccessDenied    = errors.New("insufficient farm permissions")
+	ErrMemberNotFound      = errors.New("member not found")
+	ErrMemberAlreadyExists = errors.New("person is already a member of the farm")
+	ErrInvalidMemberRole   = errors.New("invalid member role: must be OWNER, MANAGER or VIEWER")
+	ErrInvalidMemberPerson = errors.New("invalid member: person is required")
+	ErrLastOwner           = errors.New("farm must keep at least one owner")
 )
@@ -11,6 +11,8 @@ type Farm struct {
 	size      Size
 	createdAt time.Time
 	updatedAt time.Time
+
+	pendingMembers []*Member
 }
 
 // NewFarm creates a new Farm with validation (Factory Method)
@@ -0,0 +1,16 @@
+package farm
+
+// SortFields lists the fields farms can be ordered by; the first one is the default
+var SortFields = []string{"id", "name", "size", "createdAt"}
+
+// Filter narrows down a farm listing; zero values are ignored
+type Filter struct {
+	NamePrefix string
+	MinSize    *float64
+	MaxSize    *float64
+
+	// MemberID restricts the listing to the farms the person is a member of
+	MemberID int64
+	// FarmIDs restricts the listing to the given farms, e.g. the farms an API key is limited to
+	FarmIDs []int64
+}
@@ -0,0 +1,140 @@
+package farm
+
+import "time"
+
+// MemberRole represents the role a person plays in a specific farm
+type MemberRole string
+
+const (
+	MemberViewer  MemberRole = "VIEWER"
+	MemberManager MemberRole = "MANAGER"
+	MemberOwner   MemberRole = "OWNER"
+)
+
+// memberRoleLevels orders the member roles from least to most privileged
+var memberRoleLevels = map[MemberRole]int{
+	MemberViewer:  1,
+	MemberManager: 2,
+	MemberOwner:   3,
+}
+
+// NewMemberRole creates a new MemberRole with validation
+func NewMemberRole(value string) (MemberRole, error) {
+	role := MemberRole(value)
+	if _, ok := memberRoleLevels[role]; !ok {
+		return "", ErrInvalidMemberRole
+	}
+	return role, nil
+}
+
+// String returns the string representation of the member role
+func (r MemberRole) String() string {
+	return string(r)
+}
+
+// HasPermission checks if this role has permission level equal or higher than required
+func (r MemberRole) HasPermission(required MemberRole) bool {
+	return memberRoleLevels[r] >= memberRoleLevels[required]
+}
+
+// Member represents the membership of a person in a farm.
+// Viewers can read the farm and its crops, managers can also change them,
+// and owners can additionally manage members and delete the farm.
+type Member struct {
+	farmID    int64
+	personID  int64
+	role      MemberRole
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// RestoreMember reconstructs a Member from persistence (used by repository)
+func RestoreMember(farmID, personID int64, role MemberRole, createdAt, updatedAt time.Time) *Member {
+	return &Member{
+		farmID:    farmID,
+		personID:  personID,
+		role:      role,
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+	}
+}
+
+// Getters (encapsulation)
+func (m *Member) FarmID() int64 {
+	return m.farmID
+}
+
+func (m *Member) PersonID() int64 {
+	return m.personID
+}
+
+func (m *Member) Role() MemberRole {
+	return m.role
+}
+
+func (m *Member) CreatedAt() time.Time {
+	return m.createdAt
+}
+
+func (m *Member) UpdatedAt() time.Time {
+	return m.updatedAt
+}
+
+// IsOwner checks if the member owns the farm
+func (m *Member) IsOwner() bool {
+	return m.role == MemberOwner
+}
+
+// ChangeRole changes the role of the member with validation
+func (m *Member) ChangeRole(role MemberRole) error {
+	if _, ok := memberRoleLevels[role]; !ok {
+		return ErrInvalidMemberRole
+	}
+	m.role = role
+	m.updatedAt = time.Now()
+	return nil
+}
+
+// AddMember grants a person a role in the farm. The membership is persisted
+// together with the farm.
+func (f *Farm) AddMember(personID int64, role MemberRole) (*Member, error) {
+	if personID <= 0 {
+		return nil, ErrInvalidMemberPerson
+	}
+
+	if _, ok := memberRoleLevels[role]; !ok {
+		return nil, ErrInvalidMemberRole
+	}
+
+	for _, m := range f.pendingMembers {
+		if m.personID == personID {
+			return nil, ErrMemberAlreadyExists
+		}
+	}
+
+	now := time.Now()
+	m := &Member{
+		farmID:    f.id,
+		personID:  personID,
+		role:      role,
+		createdAt: now,
+		updatedAt: now,
+	}
+	f.pendingMembers = append(f.pendingMembers, m)
+	return m, nil
+}
+
+// PendingMembers returns the members added since the farm was loaded
+func (f *Farm) PendingMembers() []*Member {
+	return f.pendingMembers
+}
+
+// ClearPendingMembers is used by repository once the members are persisted
+func (f *Farm) ClearPendingMembers() {
+	f.pendingMembers = nil
+}
+
+// SetFarmID is used by repository when the farm is inserted with its members
+func (m *Member) SetFarmID(farmID int64) {
+	m.farmID = farmID
+}
@@ -0,0 +1,92 @@
+package farm_test
+
+import (
+	"testing"
+
+	"github.com/cropflow/api/internal/domain/farm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemberRole_HasPermission(t *testing.T) {
+	t.Run("should rank owner above manager above viewer", func(t *testing.T) {
+		// Assert
+		assert.True(t, farm.MemberOwner.HasPermission(farm.MemberManager))
+		assert.True(t, farm.MemberManager.HasPermission(farm.MemberViewer))
+		assert.True(t, farm.MemberViewer.HasPermission(farm.MemberViewer))
+		assert.False(t, farm.MemberViewer.HasPermission(farm.MemberManager))
+		assert.False(t, farm.MemberManager.HasPermission(farm.MemberOwner))
+	})
+
+	t.Run("should return error for unknown member role", func(t *testing.T) {
+		// Act
+		_, err := farm.NewMemberRole("ROLE_ADMIN")
+
+		// Assert
+		assert.Equal(t, farm.ErrInvalidMemberRole, err)
+	})
+}
+
+func TestFarm_AddMember(t *testing.T) {
+	t.Run("should add pending member", func(t *testing.T) {
+		// Arrange
+		f, err := farm.NewFarm("Fazenda Boa Vista", 100)
+		require.NoError(t, err)
+
+		// Act
+		m, err := f.AddMember(7, farm.MemberOwner)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), m.PersonID())
+		assert.True(t, m.IsOwner())
+		assert.Len(t, f.PendingMembers(), 1)
+	})
+
+	t.Run("should reject the same person twice", func(t *testing.T) {
+		// Arrange
+		f, err := farm.NewFarm("Fazenda Boa Vista", 100)
+		require.NoError(t, err)
+		_, err = f.AddMember(7, farm.MemberOwner)
+		require.NoError(t, err)
+
+		// Act
+		_, err = f.AddMember(7, farm.MemberViewer)
+
+		// Assert
+		assert.Equal(t, farm.ErrMemberAlreadyExists, err)
+	})
+
+	t.Run("should validate person and role", func(t *testing.T) {
+		// Arrange
+		f, err := farm.NewFarm("Fazenda Boa Vista", 100)
+		require.NoError(t, err)
+
+		// Act & Assert
+		_, err = f.AddMember(0, farm.MemberViewer)
+		assert.Equal(t, farm.ErrInvalidMemberPerson, err)
+
+		_, err = f.AddMember(7, farm.MemberRole("ADMIN"))
+		assert.Equal(t, farm.ErrInvalidMemberRole, err)
+
+		assert.Empty(t, f.PendingMembers())
+	})
+}
+
+func TestMember_ChangeRole(t *testing.T) {
+	t.Run("should change the role", func(t *testing.T) {
+		// Arrange
+		f, err := farm.NewFarm("Fazenda Boa Vista", 100)
+		require.NoError(t, err)
+		m, err := f.AddMember(7, farm.MemberViewer)
+		require.NoError(t, err)
+
+		// Act
+		err = m.ChangeRole(farm.MemberManager)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, farm.MemberManager, m.Role())
+		assert.Equal(t, farm.ErrInvalidMemberRole, m.ChangeRole(farm.MemberRole("")))
+	})
+}
@@ -1,12 +1,23 @@
 package farm
 
-import "context"
+import (
+	"context"
+
+	"github.com/cropflow/api/internal/domain/query"
+)
 
 // Repository defines the interface for farm persistence (Port)
 type Repository interface {
 	Save(ctx context.Context, farm *Farm) error
 	FindByID(ctx context.Context, id int64) (*Farm, error)
-	FindAll(ctx context.Context) ([]*Farm, error)
+	List(ctx context.Context, filter Filter, page query.Page) (query.Result[*Farm], error)
 	Delete(ctx context.Context, id int64) error
 	ExistsByID(ctx context.Context, id int64) (bool, error)
+	FindMember(ctx context.Context, farmID, personID int64) (*Member, error)
+	FindMembers(ctx context.Context, farmID int64) ([]*Member, error)
+	// LockOwners locks the owner memberships of a farm until the transaction
+	// in ctx ends and returns the IDs of the owners
+	LockOwners(ctx context.Context, farmID int64) ([]int64, error)
+	UpdateMember(ctx context.Context, member *Member) error
+	DeleteMember(ctx context.Context, farmID, personID int64) error
 }
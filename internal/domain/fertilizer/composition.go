@@ -0,0 +1,232 @@
+package fertilizer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Nutrient identifies a plant nutrient in the form it is declared on fertilizer labels
+type Nutrient string
+
+const (
+	Nitrogen   Nutrient = "N"
+	Phosphate  Nutrient = "P2O5"
+	Potash     Nutrient = "K2O"
+	Sulfur     Nutrient = "S"
+	Calcium    Nutrient = "Ca"
+	Magnesium  Nutrient = "Mg"
+	Boron      Nutrient = "B"
+	Chlorine   Nutrient = "Cl"
+	Cobalt     Nutrient = "Co"
+	Copper     Nutrient = "Cu"
+	Iron       Nutrient = "Fe"
+	Manganese  Nutrient = "Mn"
+	Molybdenum Nutrient = "Mo"
+	Nickel     Nutrient = "Ni"
+	Zinc       Nutrient = "Zn"
+)
+
+// Nutrients lists every supported nutrient in label order
+var Nutrients = []Nutrient{
+	Nitrogen, Phosphate, Potash, Sulfur, Calcium, Magnesium,
+	Boron, Chlorine, Cobalt, Copper, Iron, Manganese, Molybdenum, Nickel, Zinc,
+}
+
+// NewNutrient creates a new Nutrient with validation (case-insensitive)
+func NewNutrient(value string) (Nutrient, error) {
+	for _, n := range Nutrients {
+		if strings.EqualFold(string(n), value) {
+			return n, nil
+		}
+	}
+	return "", ErrUnknownNutrient
+}
+
+// String returns the string representation of the nutrient
+func (n Nutrient) String() string {
+	return string(n)
+}
+
+// Composition represents the guaranteed nutrient content of a fertilizer,
+// as mass percentages
+type Composition struct {
+	percentages map[Nutrient]float64
+}
+
+// NewComposition creates a new Composition from nutrient percentages with validation
+func NewComposition(percentages map[Nutrient]float64) (Composition, error) {
+	c := Composition{percentages: make(map[Nutrient]float64, len(percentages))}
+	total := 0.0
+	for symbol, pct := range percentages {
+		n, err := NewNutrient(string(symbol))
+		if err != nil {
+			return Composition{}, err
+		}
+		if pct < 0 || pct > 100 {
+			return Composition{}, ErrInvalidNutrientPercentage
+		}
+		if pct > 0 {
+			c.percentages[n] = pct
+			total += pct
+		}
+	}
+
+	if len(c.percentages) == 0 {
+		return Composition{}, ErrInvalidComposition
+	}
+	if total > 100 {
+		return Composition{}, ErrCompositionOverflow
+	}
+	return c, nil
+}
+
+var (
+	gradePattern   = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)-(\d+(?:[.,]\d+)?)-(\d+(?:[.,]\d+)?)$`)
+	percentFirst   = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)\s*%?\s*([A-Za-z][A-Za-z0-9]*)$`)
+	nutrientFirst  = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]*)\s*:?\s*(\d+(?:[.,]\d+)?)\s*%?$`)
+	npkPrefix      = regexp.MustCompile(`(?i)^npk\s*:?\s*`)
+	gradeNutrients = []Nutrient{Nitrogen, Phosphate, Potash}
+)
+
+// ParseComposition parses a label formulation such as "10-10-10" or
+// "NPK 20-05-20 + 2% S". The N-P2O5-K2O grade comes first and further
+// nutrients are added with "+", written as "2% S" or "S 2%".
+func ParseComposition(formula string) (Composition, error) {
+	formula = npkPrefix.ReplaceAllString(strings.TrimSpace(formula), "")
+	if formula == "" {
+		return Composition{}, ErrInvalidComposition
+	}
+
+	percentages := make(map[Nutrient]float64)
+	for i, part := range strings.Split(formula, "+") {
+		part = strings.TrimSpace(part)
+
+		if m := gradePattern.FindStringSubmatch(part); m != nil && i == 0 {
+			for j, n := range gradeNutrients {
+				pct, err := parsePercentage(m[j+1])
+				if err != nil {
+					return Composition{}, err
+				}
+				percentages[n] = pct
+			}
+			continue
+		}
+
+		var symbol, value string
+		if m := percentFirst.FindStringSubmatch(part); m != nil {
+			value, symbol = m[1], m[2]
+		} else if m := nutrientFirst.FindStringSubmatch(part); m != nil {
+			symbol, value = m[1], m[2]
+		} else {
+			return Composition{}, ErrInvalidComposition
+		}
+
+		n, err := NewNutrient(symbol)
+		if err != nil {
+			return Composition{}, err
+		}
+		if _, duplicated := percentages[n]; duplicated {
+			return Composition{}, ErrInvalidComposition
+		}
+		pct, err := parsePercentage(value)
+		if err != nil {
+			return Composition{}, err
+		}
+		percentages[n] = pct
+	}
+
+	return NewComposition(percentages)
+}
+
+func parsePercentage(value string) (float64, error) {
+	pct, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil {
+		return 0, ErrInvalidComposition
+	}
+	return pct, nil
+}
+
+// RestoreComposition reconstructs a Composition from persistence (used by repository)
+func RestoreComposition(percentages map[Nutrient]float64) Composition {
+	c := Composition{percentages: make(map[Nutrient]float64, len(percentages))}
+	for n, pct := range percentages {
+		if pct > 0 {
+			c.percentages[n] = pct
+		}
+	}
+	return c
+}
+
+// Percentage returns the mass percentage of a nutrient (zero when absent)
+func (c Composition) Percentage(n Nutrient) float64 {
+	return c.percentages[n]
+}
+
+// Nutrients returns the declared nutrients and their percentages
+func (c Composition) Nutrients() map[Nutrient]float64 {
+	percentages := make(map[Nutrient]float64, len(c.percentages))
+	for n, pct := range c.percentages {
+		percentages[n] = pct
+	}
+	return percentages
+}
+
+// Total returns the sum of all nutrient percentages
+func (c Composition) Total() float64 {
+	total := 0.0
+	for _, pct := range c.percentages {
+		total += pct
+	}
+	return total
+}
+
+// IsEmpty checks if no nutrient is declared
+func (c Composition) IsEmpty() bool {
+	return len(c.percentages) == 0
+}
+
+// NutrientMass returns the kilograms of a nutrient contained in the given
+// kilograms of product
+func (c Composition) NutrientMass(n Nutrient, productKilograms float64) float64 {
+	return productKilograms * c.percentages[n] / 100
+}
+
+// Equals checks if two compositions declare the same nutrients
+func (c Composition) Equals(other Composition) bool {
+	if len(c.percentages) != len(other.percentages) {
+		return false
+	}
+	for n, pct := range c.percentages {
+		if other.percentages[n] != pct {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the label formulation, e.g. "20-05-20 + 2% S"
+func (c Composition) String() string {
+	var parts []string
+	if c.percentages[Nitrogen] > 0 || c.percentages[Phosphate] > 0 || c.percentages[Potash] > 0 {
+		grade := make([]string, len(gradeNutrients))
+		for i, n := range gradeNutrients {
+			grade[i] = formatGrade(c.percentages[n])
+		}
+		parts = append(parts, strings.Join(grade, "-"))
+	}
+	for _, n := range Nutrients[len(gradeNutrients):] {
+		if pct, ok := c.percentages[n]; ok {
+			parts = append(parts, fmt.Sprintf("%s%% %s", strconv.FormatFloat(pct, 'f', -1, 64), n))
+		}
+	}
+	return strings.Join(parts, " + ")
+}
+
+func formatGrade(pct float64) string {
+	if pct == float64(int(pct)) {
+		return fmt.Sprintf("%02d", int(pct))
+	}
+	return strconv.FormatFloat(pct, 'f', -1, 64)
+}
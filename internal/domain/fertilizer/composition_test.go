@@ -0,0 +1,120 @@
+package fertilizer_test
+
+import (
+	"testing"
+
+	"github.com/cropflow/api/internal/domain/fertilizer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseComposition(t *testing.T) {
+	t.Run("should parse a plain NPK grade", func(t *testing.T) {
+		// Act
+		c, err := fertilizer.ParseComposition("10-10-10")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, c.Percentage(fertilizer.Nitrogen))
+		assert.Equal(t, 10.0, c.Percentage(fertilizer.Phosphate))
+		assert.Equal(t, 10.0, c.Percentage(fertilizer.Potash))
+		assert.Equal(t, 30.0, c.Total())
+		assert.Equal(t, "10-10-10", c.String())
+	})
+
+	t.Run("should parse grade with prefix and secondary nutrients", func(t *testing.T) {
+		// Act
+		c, err := fertilizer.ParseComposition("NPK 20-05-20 + 2% S + Zn 0,5%")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, map[fertilizer.Nutrient]float64{
+			fertilizer.Nitrogen:  20,
+			fertilizer.Phosphate: 5,
+			fertilizer.Potash:    20,
+			fertilizer.Sulfur:    2,
+			fertilizer.Zinc:      0.5,
+		}, c.Nutrients())
+		assert.Equal(t, "20-05-20 + 2% S + 0.5% Zn", c.String())
+	})
+
+	t.Run("should drop zero grades", func(t *testing.T) {
+		// Act
+		c, err := fertilizer.ParseComposition("45-00-00")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, map[fertilizer.Nutrient]float64{fertilizer.Nitrogen: 45}, c.Nutrients())
+		assert.Equal(t, "45-00-00", c.String())
+	})
+
+	t.Run("should parse formulations without NPK grade", func(t *testing.T) {
+		// Act
+		c, err := fertilizer.ParseComposition("18% Ca + 16% S")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 18.0, c.Percentage(fertilizer.Calcium))
+		assert.Equal(t, "16% S + 18% Ca", c.String())
+	})
+
+	t.Run("should reject malformed formulations", func(t *testing.T) {
+		for _, formula := range []string{"", "NPK", "adubo completo", "10-10", "2% S + 10-10-10", "2% S + 3% S"} {
+			// Act
+			_, err := fertilizer.ParseComposition(formula)
+
+			// Assert
+			assert.Equal(t, fertilizer.ErrInvalidComposition, err, formula)
+		}
+	})
+
+	t.Run("should reject unknown nutrients", func(t *testing.T) {
+		// Act
+		_, err := fertilizer.ParseComposition("10-10-10 + 2% Xy")
+
+		// Assert
+		assert.Equal(t, fertilizer.ErrUnknownNutrient, err)
+	})
+
+	t.Run("should reject sums over 100%", func(t *testing.T) {
+		// Act
+		_, err := fertilizer.ParseComposition("60-30-20")
+
+		// Assert
+		assert.Equal(t, fertilizer.ErrCompositionOverflow, err)
+	})
+}
+
+func TestNewComposition(t *testing.T) {
+	t.Run("should accept nutrient maps case-insensitively", func(t *testing.T) {
+		// Act
+		c, err := fertilizer.NewComposition(map[fertilizer.Nutrient]float64{"n": 46, "mg": 1})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 46.0, c.Percentage(fertilizer.Nitrogen))
+		assert.Equal(t, 1.0, c.Percentage(fertilizer.Magnesium))
+	})
+
+	t.Run("should validate percentages", func(t *testing.T) {
+		// Act
+		_, negative := fertilizer.NewComposition(map[fertilizer.Nutrient]float64{fertilizer.Nitrogen: -1})
+		_, empty := fertilizer.NewComposition(map[fertilizer.Nutrient]float64{fertilizer.Nitrogen: 0})
+		_, overflow := fertilizer.NewComposition(map[fertilizer.Nutrient]float64{fertilizer.Nitrogen: 60, fertilizer.Potash: 60})
+
+		// Assert
+		assert.Equal(t, fertilizer.ErrInvalidNutrientPercentage, negative)
+		assert.Equal(t, fertilizer.ErrInvalidComposition, empty)
+		assert.Equal(t, fertilizer.ErrCompositionOverflow, overflow)
+	})
+
+	t.Run("should compute nutrient mass in a product amount", func(t *testing.T) {
+		// Arrange
+		c, err := fertilizer.ParseComposition("45-00-00")
+		require.NoError(t, err)
+
+		// Act & Assert
+		assert.Equal(t, 90.0, c.NutrientMass(fertilizer.Nitrogen, 200))
+		assert.Zero(t, c.NutrientMass(fertilizer.Potash, 200))
+	})
+}
@@ -3,8 +3,12 @@ package fertilizer
 import "errors"
 
 var (
-	ErrFertilizerNotFound     = errors.New("fertilizer not found")
-	ErrInvalidFertilizerName  = errors.New("invalid fertilizer name: cannot be empty")
-	ErrInvalidBrand           = errors.New("invalid brand: cannot be empty")
-	ErrInvalidComposition     = errors.New("invalid composition: cannot be empty")
+	ErrFertilizerNotFound        = errors.New("fertilizer not found")
+	ErrInvalidFertilizerName     = errors.New("invalid fertilizer name: cannot be empty")
+	ErrInvalidBrand              = errors.New("invalid brand: cannot be empty")
+	ErrInvalidComposition        = errors.New("invalid composition: expected a formulation like 10-10-10 or NPK 20-05-20 + 2% S")
+	ErrUnknownNutrient           = errors.New("unknown nutrient: must be one of N, P2O5, K2O, S, Ca, Mg, B, Cl, Co, Cu, Fe, Mn, Mo, Ni, Zn")
+	ErrInvalidNutrientPercentage = errors.New("invalid nutrient percentage: must be between 0 and 100")
+	ErrCompositionOverflow       = errors.New("invalid composition: nutrient percentages add up to more than 100%")
+	ErrFertilizerInUse           = errors.New("fertilizer has been applied to crops")
 )
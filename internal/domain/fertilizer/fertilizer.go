@@ -9,13 +9,13 @@ type Fertilizer struct {
 	id          int64
 	name        string
 	brand       string
-	composition string
+	composition Composition
 	createdAt   time.Time
 	updatedAt   time.Time
 }
 
 // NewFertilizer creates a new Fertilizer with validation (Factory Method)
-func NewFertilizer(name, brand, composition string) (*Fertilizer, error) {
+func NewFertilizer(name, brand string, composition Composition) (*Fertilizer, error) {
 	if name == "" {
 		return nil, ErrInvalidFertilizerName
 	}
@@ -24,7 +24,7 @@ func NewFertilizer(name, brand, composition string) (*Fertilizer, error) {
 		return nil, ErrInvalidBrand
 	}
 
-	if composition == "" {
+	if composition.IsEmpty() {
 		return nil, ErrInvalidComposition
 	}
 
@@ -39,7 +39,7 @@ func NewFertilizer(name, brand, composition string) (*Fertilizer, error) {
 }
 
 // Restore reconstructs a Fertilizer from persistence (used by repository)
-func Restore(id int64, name, brand, composition string, createdAt, updatedAt time.Time) *Fertilizer {
+func Restore(id int64, name, brand string, composition Composition, createdAt, updatedAt time.Time) *Fertilizer {
 	return &Fertilizer{
 		id:          id,
 		name:        name,
@@ -63,7 +63,7 @@ func (f *Fertilizer) Brand() string {
 	return f.brand
 }
 
-func (f *Fertilizer) Composition() string {
+func (f *Fertilizer) Composition() Composition {
 	return f.composition
 }
 
@@ -103,8 +103,8 @@ func (f *Fertilizer) ChangeBrand(newBrand string) error {
 }
 
 // ChangeComposition changes the fertilizer composition with validation
-func (f *Fertilizer) ChangeComposition(newComposition string) error {
-	if newComposition == "" {
+func (f *Fertilizer) ChangeComposition(newComposition Composition) error {
+	if newComposition.IsEmpty() {
 		return ErrInvalidComposition
 	}
 	f.composition = newComposition
@@ -114,5 +114,5 @@ func (f *Fertilizer) ChangeComposition(newComposition string) error {
 
 // IsValid checks if the fertilizer is in a valid state
 func (f *Fertilizer) IsValid() bool {
-	return f.name != "" && f.brand != "" && f.composition != ""
+	return f.name != "" && f.brand != "" && !f.composition.IsEmpty()
 }
@@ -0,0 +1,10 @@
+package fertilizer
+
+// SortFields lists the fields fertilizers can be ordered by; the first one is the default
+var SortFields = []string{"id", "name", "brand", "createdAt"}
+
+// Filter narrows down a fertilizer listing; zero values are ignored
+type Filter struct {
+	NamePrefix string
+	Brand      string
+}
@@ -0,0 +1,17 @@
+package fertilizer
+
+import (
+	"context"
+
+	"github.com/cropflow/api/internal/domain/query"
+)
+
+// Repository defines the interface for fertilizer persistence (Port)
+type Repository interface {
+	Save(ctx context.Context, fertilizer *Fertilizer) error
+	FindByID(ctx context.Context, id int64) (*Fertilizer, error)
+	FindByIDs(ctx context.Context, ids []int64) ([]*Fertilizer, error)
+	List(ctx context.Context, filter Filter, page query.Page) (query.Result[*Fertilizer], error)
+	Delete(ctx context.Context, id int64) error
+	ExistsByID(ctx context.Context, id int64) (bool, error)
+}
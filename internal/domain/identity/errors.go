@@ -0,0 +1,7 @@
+package identity
+
+import "errors"
+
+var (
+	ErrUnauthenticated = errors.New("authentication required")
+)
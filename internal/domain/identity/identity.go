@@ -0,0 +1,96 @@
+package identity
+
+import (
+	"context"
+
+	"github.com/cropflow/api/internal/domain/person"
+)
+
+// Identity represents the authenticated person performing a request, either
+// within a login session or through an API key
+type Identity struct {
+	personID  int64
+	sessionID int64
+	apiKeyID  int64
+	username  string
+	role      person.Role
+
+	// farmIDs limits the farms an API key can access; empty means no limit
+	farmIDs []int64
+}
+
+// New creates a new Identity for a person authenticated within a session
+func New(personID, sessionID int64, username string, role person.Role) Identity {
+	return Identity{
+		personID:  personID,
+		sessionID: sessionID,
+		username:  username,
+		role:      role,
+	}
+}
+
+// NewForAPIKey creates a new Identity for a person authenticated with an API
+// key, limited to the given farms when there are any
+func NewForAPIKey(personID, apiKeyID int64, username string, role person.Role, farmIDs []int64) Identity {
+	return Identity{
+		personID: personID,
+		apiKeyID: apiKeyID,
+		username: username,
+		role:     role,
+		farmIDs:  farmIDs,
+	}
+}
+
+// Getters (encapsulation)
+func (i Identity) PersonID() int64 {
+	return i.personID
+}
+
+func (i Identity) SessionID() int64 {
+	return i.sessionID
+}
+
+// APIKeyID returns the API key the request was authenticated with, or zero for a session
+func (i Identity) APIKeyID() int64 {
+	return i.apiKeyID
+}
+
+func (i Identity) Username() string {
+	return i.username
+}
+
+func (i Identity) Role() person.Role {
+	return i.role
+}
+
+// FarmIDs returns the farms the identity is limited to; empty means no limit
+func (i Identity) FarmIDs() []int64 {
+	return i.farmIDs
+}
+
+// CanAccessFarm checks the farm is within the limits of the identity. Farm
+// membership is checked separately.
+func (i Identity) CanAccessFarm(farmID int64) bool {
+	if len(i.farmIDs) == 0 {
+		return true
+	}
+	for _, id := range i.farmIDs {
+		if id == farmID {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the identity
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the identity carried by ctx, if any
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(contextKey{}).(Identity)
+	return id, ok
+}
@@ -0,0 +1,25 @@
+package lockout
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrLocked        = errors.New("too many failed login attempts: try again later")
+	ErrInvalidPolicy = errors.New("invalid lockout policy: failures and lockout durations must be greater than zero")
+)
+
+// LockedError reports a lockout together with how long it still lasts
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return ErrLocked.Error()
+}
+
+// Unwrap lets errors.Is match ErrLocked
+func (e *LockedError) Unwrap() error {
+	return ErrLocked
+}
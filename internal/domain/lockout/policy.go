@@ -0,0 +1,51 @@
+package lockout
+
+import "time"
+
+// Policy decides when repeated login failures lock a username or an IP address.
+// Once MaxFailures consecutive failures are reached every further failure
+// doubles the lockout, starting at BaseLockout and capped at MaxLockout.
+// Failures older than ResetAfter are forgotten.
+type Policy struct {
+	MaxFailures int
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+	ResetAfter  time.Duration
+}
+
+// NewPolicy creates a Policy with validation
+func NewPolicy(maxFailures int, baseLockout, maxLockout, resetAfter time.Duration) (Policy, error) {
+	if maxFailures <= 0 || baseLockout <= 0 || maxLockout < baseLockout || resetAfter <= 0 {
+		return Policy{}, ErrInvalidPolicy
+	}
+	return Policy{
+		MaxFailures: maxFailures,
+		BaseLockout: baseLockout,
+		MaxLockout:  maxLockout,
+		ResetAfter:  resetAfter,
+	}, nil
+}
+
+// LockoutFor returns how long an account is locked after the given number of
+// consecutive failures; zero means it is not locked
+func (p Policy) LockoutFor(failures int) time.Duration {
+	if failures < p.MaxFailures {
+		return 0
+	}
+
+	lockout := p.BaseLockout
+	for i := p.MaxFailures; i < failures; i++ {
+		lockout *= 2
+		if lockout >= p.MaxLockout {
+			return p.MaxLockout
+		}
+	}
+	return lockout
+}
+
+// Settings holds the policies applied to usernames and to client IP addresses.
+// IP addresses are usually allowed more failures since many users can share one.
+type Settings struct {
+	Username Policy
+	IP       Policy
+}
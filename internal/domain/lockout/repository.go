@@ -0,0 +1,23 @@
+package lockout
+
+import "context"
+
+// Repository defines the interface for the failed login records of client IP
+// addresses and of usernames that do not belong to any person (Port).
+// Persons keep their own status in the person record.
+type Repository interface {
+	Find(ctx context.Context, key string) (Status, error)
+	// Update loads the status of key locked against concurrent logins, applies fn and stores the result
+	Update(ctx context.Context, key string, fn func(Status) Status) (Status, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// IPKey returns the repository key of a client IP address
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// UsernameKey returns the repository key of a username without a person
+func UsernameKey(username string) string {
+	return "username:" + username
+}
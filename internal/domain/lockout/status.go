@@ -0,0 +1,68 @@
+package lockout
+
+import "time"
+
+// Status is the failed login record of a username or an IP address (Value Object)
+type Status struct {
+	failures     int
+	lastFailedAt *time.Time
+	lockedUntil  *time.Time
+}
+
+// RestoreStatus reconstructs a Status from persistence (used by repository)
+func RestoreStatus(failures int, lastFailedAt, lockedUntil *time.Time) Status {
+	return Status{
+		failures:     failures,
+		lastFailedAt: lastFailedAt,
+		lockedUntil:  lockedUntil,
+	}
+}
+
+// Getters (encapsulation)
+func (s Status) Failures() int {
+	return s.failures
+}
+
+// LastFailedAt returns when the last failure happened, if any
+func (s Status) LastFailedAt() *time.Time {
+	return s.lastFailedAt
+}
+
+// LockedUntil returns until when logins are refused, if they are
+func (s Status) LockedUntil() *time.Time {
+	return s.lockedUntil
+}
+
+// IsLocked checks if logins are refused at the given time
+func (s Status) IsLocked(now time.Time) bool {
+	return s.lockedUntil != nil && now.Before(*s.lockedUntil)
+}
+
+// Check returns a LockedError while logins are refused
+func (s Status) Check(now time.Time) error {
+	if !s.IsLocked(now) {
+		return nil
+	}
+	return &LockedError{RetryAfter: s.lockedUntil.Sub(now)}
+}
+
+// RecordFailure returns the status after one more failed login
+func (s Status) RecordFailure(policy Policy, now time.Time) Status {
+	failures := s.failures
+	if s.lastFailedAt != nil && now.Sub(*s.lastFailedAt) >= policy.ResetAfter {
+		failures = 0
+	}
+	failures++
+
+	next := Status{failures: failures, lastFailedAt: &now, lockedUntil: s.lockedUntil}
+	if lockout := policy.LockoutFor(failures); lockout > 0 {
+		until := now.Add(lockout)
+		next.lockedUntil = &until
+	}
+	return next
+}
+
+// IsZero checks if there is no failure on record
+func (s Status) IsZero() bool {
+	return s.failures == 0 && s.lockedUntil == nil
+}
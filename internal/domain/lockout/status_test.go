@@ -0,0 +1,100 @@
+package lockout_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/lockout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPolicy(t *testing.T) lockout.Policy {
+	t.Helper()
+	policy, err := lockout.NewPolicy(3, time.Minute, 10*time.Minute, 24*time.Hour)
+	require.NoError(t, err)
+	return policy
+}
+
+func TestNewPolicy(t *testing.T) {
+	t.Run("should validate failures and durations", func(t *testing.T) {
+		// Act & Assert
+		_, err := lockout.NewPolicy(0, time.Minute, time.Hour, time.Hour)
+		assert.Equal(t, lockout.ErrInvalidPolicy, err)
+
+		_, err = lockout.NewPolicy(3, time.Hour, time.Minute, time.Hour)
+		assert.Equal(t, lockout.ErrInvalidPolicy, err)
+	})
+}
+
+func TestPolicy_LockoutFor(t *testing.T) {
+	t.Run("should double the lockout up to the maximum", func(t *testing.T) {
+		// Arrange
+		policy := newPolicy(t)
+
+		// Act & Assert
+		assert.Equal(t, time.Duration(0), policy.LockoutFor(2))
+		assert.Equal(t, time.Minute, policy.LockoutFor(3))
+		assert.Equal(t, 2*time.Minute, policy.LockoutFor(4))
+		assert.Equal(t, 8*time.Minute, policy.LockoutFor(6))
+		assert.Equal(t, 10*time.Minute, policy.LockoutFor(7))
+		assert.Equal(t, 10*time.Minute, policy.LockoutFor(100))
+	})
+}
+
+func TestStatus_RecordFailure(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should lock once the maximum failures is reached", func(t *testing.T) {
+		// Arrange
+		policy := newPolicy(t)
+		var status lockout.Status
+
+		// Act
+		status = status.RecordFailure(policy, now)
+		status = status.RecordFailure(policy, now)
+		unlocked := status.IsLocked(now)
+		status = status.RecordFailure(policy, now)
+
+		// Assert
+		assert.False(t, unlocked)
+		assert.Equal(t, 3, status.Failures())
+		assert.True(t, status.IsLocked(now))
+		assert.False(t, status.IsLocked(now.Add(time.Minute)))
+	})
+
+	t.Run("should report how long the lockout lasts", func(t *testing.T) {
+		// Arrange
+		policy := newPolicy(t)
+		var status lockout.Status
+		for i := 0; i < 4; i++ {
+			status = status.RecordFailure(policy, now)
+		}
+
+		// Act
+		err := status.Check(now.Add(30 * time.Second))
+
+		// Assert
+		var locked *lockout.LockedError
+		require.True(t, errors.As(err, &locked))
+		assert.ErrorIs(t, err, lockout.ErrLocked)
+		assert.Equal(t, 90*time.Second, locked.RetryAfter)
+		assert.NoError(t, status.Check(now.Add(2*time.Minute)))
+	})
+
+	t.Run("should forget failures after the reset window", func(t *testing.T) {
+		// Arrange
+		policy := newPolicy(t)
+		var status lockout.Status
+		status = status.RecordFailure(policy, now)
+		status = status.RecordFailure(policy, now)
+
+		// Act
+		status = status.RecordFailure(policy, now.Add(24*time.Hour))
+
+		// Assert
+		assert.Equal(t, 1, status.Failures())
+		assert.False(t, status.IsLocked(now.Add(24*time.Hour)))
+	})
+}
@@ -0,0 +1,147 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+)
+
+// ChallengeKind tells the client what completes a login challenge
+type ChallengeKind string
+
+const (
+	// ChallengeTOTP is completed with a TOTP or recovery code
+	ChallengeTOTP ChallengeKind = "TOTP"
+	// ChallengeEnroll is issued when a role requires two-factor authentication
+	// and the person has not enrolled yet; it is completed by enrolling
+	ChallengeEnroll ChallengeKind = "ENROLL"
+)
+
+// MaxChallengeAttempts is how many invalid codes a challenge accepts before
+// the password has to be entered again
+const MaxChallengeAttempts = 5
+
+// challengeTokenBytes is the amount of randomness in a challenge token
+const challengeTokenBytes = 32
+
+// String returns the string representation of the challenge kind
+func (k ChallengeKind) String() string {
+	return string(k)
+}
+
+// Challenge represents the second step of a login, started once the password
+// was verified. Only the hash of its token is stored.
+type Challenge struct {
+	id          int64
+	personID    int64
+	kind        ChallengeKind
+	hash        string
+	attempts    int
+	expiresAt   time.Time
+	completedAt *time.Time
+	createdAt   time.Time
+}
+
+// NewChallenge starts a login challenge and returns its plain token so it can
+// be handed to the client (Factory Method)
+func NewChallenge(personID int64, kind ChallengeKind, lifetime time.Duration, now time.Time) (*Challenge, string, error) {
+	if personID <= 0 {
+		return nil, "", ErrInvalidChallengePerson
+	}
+	if lifetime <= 0 {
+		return nil, "", ErrInvalidChallengeLifetime
+	}
+
+	buf := make([]byte, challengeTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", err
+	}
+	plain := base64.RawURLEncoding.EncodeToString(buf)
+
+	return &Challenge{
+		personID:  personID,
+		kind:      kind,
+		hash:      HashCode(plain),
+		expiresAt: now.Add(lifetime),
+		createdAt: now,
+	}, plain, nil
+}
+
+// RestoreChallenge reconstructs a Challenge from persistence (used by repository)
+func RestoreChallenge(id, personID int64, kind ChallengeKind, hash string, attempts int, expiresAt time.Time, completedAt *time.Time, createdAt time.Time) *Challenge {
+	return &Challenge{
+		id:          id,
+		personID:    personID,
+		kind:        kind,
+		hash:        hash,
+		attempts:    attempts,
+		expiresAt:   expiresAt,
+		completedAt: completedAt,
+		createdAt:   createdAt,
+	}
+}
+
+// Getters (encapsulation)
+func (c *Challenge) ID() int64 {
+	return c.id
+}
+
+func (c *Challenge) PersonID() int64 {
+	return c.personID
+}
+
+func (c *Challenge) Kind() ChallengeKind {
+	return c.kind
+}
+
+func (c *Challenge) Hash() string {
+	return c.hash
+}
+
+func (c *Challenge) Attempts() int {
+	return c.attempts
+}
+
+func (c *Challenge) ExpiresAt() time.Time {
+	return c.expiresAt
+}
+
+// CompletedAt returns when the challenge was completed, if it was
+func (c *Challenge) CompletedAt() *time.Time {
+	return c.completedAt
+}
+
+func (c *Challenge) CreatedAt() time.Time {
+	return c.createdAt
+}
+
+// SetID is used by repository after insertion
+func (c *Challenge) SetID(id int64) {
+	c.id = id
+}
+
+// Business Methods
+
+// EnsureOpen checks the challenge can still be completed at the given time
+func (c *Challenge) EnsureOpen(now time.Time) error {
+	if c.completedAt != nil {
+		return ErrInvalidChallenge
+	}
+	if c.attempts >= MaxChallengeAttempts {
+		return ErrTooManyAttempts
+	}
+	if !now.Before(c.expiresAt) {
+		return ErrChallengeExpired
+	}
+	return nil
+}
+
+// RecordFailure counts an invalid code
+func (c *Challenge) RecordFailure() {
+	c.attempts++
+}
+
+// Complete marks the challenge as used so its token cannot be replayed
+func (c *Challenge) Complete(now time.Time) {
+	c.completedAt = &now
+}
@@ -0,0 +1,220 @@
+package mfa
+
+import "time"
+
+// Enrollment represents the TOTP second factor of a person. It only protects
+// logins once confirmed with a valid code, which also issues the recovery codes.
+type Enrollment struct {
+	id           int64
+	personID     int64
+	secret       string
+	confirmedAt  *time.Time
+	lastUsedStep int64
+	createdAt    time.Time
+	updatedAt    time.Time
+
+	recoveryCodes []*RecoveryCode
+	pendingCodes  []*RecoveryCode
+	usedCodes     []*RecoveryCode
+}
+
+// NewEnrollment starts an unconfirmed enrolment with a fresh secret (Factory Method)
+func NewEnrollment(personID int64, now time.Time) (*Enrollment, error) {
+	if personID <= 0 {
+		return nil, ErrInvalidEnrollmentPerson
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	return &Enrollment{
+		personID:  personID,
+		secret:    secret,
+		createdAt: now,
+		updatedAt: now,
+	}, nil
+}
+
+// RestoreEnrollment reconstructs an Enrollment from persistence with its unused recovery codes (used by repository)
+func RestoreEnrollment(id, personID int64, secret string, confirmedAt *time.Time, lastUsedStep int64, createdAt, updatedAt time.Time, recoveryCodes []*RecoveryCode) *Enrollment {
+	return &Enrollment{
+		id:            id,
+		personID:      personID,
+		secret:        secret,
+		confirmedAt:   confirmedAt,
+		lastUsedStep:  lastUsedStep,
+		createdAt:     createdAt,
+		updatedAt:     updatedAt,
+		recoveryCodes: recoveryCodes,
+	}
+}
+
+// Getters (encapsulation)
+func (e *Enrollment) ID() int64 {
+	return e.id
+}
+
+func (e *Enrollment) PersonID() int64 {
+	return e.personID
+}
+
+func (e *Enrollment) Secret() string {
+	return e.secret
+}
+
+// ConfirmedAt returns when the enrolment was confirmed, if it was
+func (e *Enrollment) ConfirmedAt() *time.Time {
+	return e.confirmedAt
+}
+
+func (e *Enrollment) LastUsedStep() int64 {
+	return e.lastUsedStep
+}
+
+func (e *Enrollment) CreatedAt() time.Time {
+	return e.createdAt
+}
+
+func (e *Enrollment) UpdatedAt() time.Time {
+	return e.updatedAt
+}
+
+// SetID is used by repository after insertion
+func (e *Enrollment) SetID(id int64) {
+	e.id = id
+}
+
+// IsConfirmed checks if the enrolment protects the logins of the person
+func (e *Enrollment) IsConfirmed() bool {
+	return e.confirmedAt != nil
+}
+
+// RemainingRecoveryCodes returns how many recovery codes can still be used
+func (e *Enrollment) RemainingRecoveryCodes() int {
+	remaining := 0
+	for _, c := range e.recoveryCodes {
+		if c.usedAt == nil {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// ProvisioningURI returns the otpauth:// URI to add the secret to an authenticator app
+func (e *Enrollment) ProvisioningURI(issuer, account string) string {
+	return provisioningURI(e.secret, issuer, account)
+}
+
+// Business Methods
+
+// ResetSecret replaces the secret of an enrolment that was never confirmed
+func (e *Enrollment) ResetSecret(now time.Time) error {
+	if e.IsConfirmed() {
+		return ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return err
+	}
+	e.secret = secret
+	e.updatedAt = now
+	return nil
+}
+
+// Confirm proves the person added the secret to an authenticator app and
+// returns the plain recovery codes, which are shown only this once
+func (e *Enrollment) Confirm(code string, now time.Time) ([]string, error) {
+	if e.IsConfirmed() {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	if err := e.verifyTOTP(code, now); err != nil {
+		return nil, err
+	}
+	e.confirmedAt = &now
+	return e.replaceRecoveryCodes(now)
+}
+
+// Verify checks a TOTP code or an unused recovery code. A TOTP code cannot be
+// used twice and a recovery code is spent once accepted.
+func (e *Enrollment) Verify(code string, now time.Time) error {
+	if !e.IsConfirmed() {
+		return ErrTOTPNotConfirmed
+	}
+
+	if err := e.verifyTOTP(code, now); err == nil {
+		return nil
+	}
+
+	hash := HashCode(normalizeRecoveryCode(code))
+	for _, c := range e.recoveryCodes {
+		if c.usedAt == nil && c.hash == hash {
+			used := now
+			c.usedAt = &used
+			e.usedCodes = append(e.usedCodes, c)
+			e.updatedAt = now
+			return nil
+		}
+	}
+	return ErrInvalidCode
+}
+
+// RegenerateRecoveryCodes invalidates the current recovery codes and returns new plain ones
+func (e *Enrollment) RegenerateRecoveryCodes(now time.Time) ([]string, error) {
+	if !e.IsConfirmed() {
+		return nil, ErrTOTPNotConfirmed
+	}
+	return e.replaceRecoveryCodes(now)
+}
+
+func (e *Enrollment) verifyTOTP(code string, now time.Time) error {
+	step, ok := matchStep(e.secret, code, now)
+	if !ok || step <= e.lastUsedStep {
+		return ErrInvalidCode
+	}
+	e.lastUsedStep = step
+	e.updatedAt = now
+	return nil
+}
+
+func (e *Enrollment) replaceRecoveryCodes(now time.Time) ([]string, error) {
+	plain := make([]string, 0, recoveryCodeCount)
+	codes := make([]*RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		plain = append(plain, code)
+		codes = append(codes, &RecoveryCode{
+			enrollmentID: e.id,
+			hash:         HashCode(normalizeRecoveryCode(code)),
+			createdAt:    now,
+		})
+	}
+
+	e.recoveryCodes = codes
+	e.pendingCodes = codes
+	e.usedCodes = nil
+	e.updatedAt = now
+	return plain, nil
+}
+
+// PendingRecoveryCodes returns the recovery codes issued since the enrolment
+// was loaded; when set they replace all stored codes
+func (e *Enrollment) PendingRecoveryCodes() []*RecoveryCode {
+	return e.pendingCodes
+}
+
+// UsedRecoveryCodes returns the recovery codes spent since the enrolment was loaded
+func (e *Enrollment) UsedRecoveryCodes() []*RecoveryCode {
+	return e.usedCodes
+}
+
+// ClearPendingRecoveryCodes is used by repository once the codes are persisted
+func (e *Enrollment) ClearPendingRecoveryCodes() {
+	e.pendingCodes = nil
+	e.usedCodes = nil
+}
@@ -0,0 +1,255 @@
+package mfa_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/mfa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// codeAt computes the TOTP code an authenticator app shows at the given time
+func codeAt(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	code, err := mfa.Code(secret, at.Unix()/30)
+	require.NoError(t, err)
+	return code
+}
+
+func TestCode(t *testing.T) {
+	t.Run("should match the RFC 6238 test vectors", func(t *testing.T) {
+		// Arrange
+		secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+		vectors := map[int64]string{59: "287082", 1111111109: "081804", 1234567890: "005924", 2000000000: "279037"}
+
+		for unix, expected := range vectors {
+			// Act
+			code, err := mfa.Code(secret, unix/30)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, expected, code, "time %d", unix)
+		}
+	})
+}
+
+func TestNewEnrollment(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should start an unconfirmed enrolment with a provisioning URI", func(t *testing.T) {
+		// Act
+		e, err := mfa.NewEnrollment(7, now)
+
+		// Assert
+		require.NoError(t, err)
+		assert.False(t, e.IsConfirmed())
+		assert.Len(t, e.Secret(), 32)
+		uri := e.ProvisioningURI("CropFlow", "ana")
+		assert.True(t, strings.HasPrefix(uri, "otpauth://totp/CropFlow:ana?"))
+		assert.Contains(t, uri, "secret="+e.Secret())
+		assert.Contains(t, uri, "issuer=CropFlow")
+	})
+
+	t.Run("should validate person", func(t *testing.T) {
+		// Act
+		_, err := mfa.NewEnrollment(0, now)
+
+		// Assert
+		assert.Equal(t, mfa.ErrInvalidEnrollmentPerson, err)
+	})
+}
+
+func TestEnrollment_Confirm(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should confirm with a valid code and issue hashed recovery codes", func(t *testing.T) {
+		// Arrange
+		e, err := mfa.NewEnrollment(7, now)
+		require.NoError(t, err)
+
+		// Act
+		codes, err := e.Confirm(codeAt(t, e.Secret(), now), now)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, e.IsConfirmed())
+		assert.Len(t, codes, 10)
+		require.Len(t, e.PendingRecoveryCodes(), 10)
+		assert.Equal(t, mfa.HashCode(strings.ReplaceAll(codes[0], "-", "")), e.PendingRecoveryCodes()[0].Hash())
+		assert.Equal(t, 10, e.RemainingRecoveryCodes())
+	})
+
+	t.Run("should tolerate one step of clock drift", func(t *testing.T) {
+		// Arrange
+		e, err := mfa.NewEnrollment(7, now)
+		require.NoError(t, err)
+
+		// Act
+		_, err = e.Confirm(codeAt(t, e.Secret(), now.Add(-30*time.Second)), now)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject invalid and stale codes", func(t *testing.T) {
+		// Arrange
+		e, err := mfa.NewEnrollment(7, now)
+		require.NoError(t, err)
+
+		// Act & Assert
+		_, err = e.Confirm("000000x", now)
+		assert.Equal(t, mfa.ErrInvalidCode, err)
+
+		_, err = e.Confirm(codeAt(t, e.Secret(), now.Add(-2*time.Minute)), now)
+		assert.Equal(t, mfa.ErrInvalidCode, err)
+		assert.False(t, e.IsConfirmed())
+	})
+
+	t.Run("should not confirm twice nor reset a confirmed secret", func(t *testing.T) {
+		// Arrange
+		e, err := mfa.NewEnrollment(7, now)
+		require.NoError(t, err)
+		_, err = e.Confirm(codeAt(t, e.Secret(), now), now)
+		require.NoError(t, err)
+
+		// Act & Assert
+		_, err = e.Confirm(codeAt(t, e.Secret(), now.Add(time.Minute)), now.Add(time.Minute))
+		assert.Equal(t, mfa.ErrTOTPAlreadyEnabled, err)
+		assert.Equal(t, mfa.ErrTOTPAlreadyEnabled, e.ResetSecret(now))
+	})
+}
+
+func TestEnrollment_Verify(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+
+	confirmed := func(t *testing.T) (*mfa.Enrollment, []string) {
+		e, err := mfa.NewEnrollment(7, now)
+		require.NoError(t, err)
+		codes, err := e.Confirm(codeAt(t, e.Secret(), now), now)
+		require.NoError(t, err)
+		e.ClearPendingRecoveryCodes()
+		return e, codes
+	}
+
+	t.Run("should accept a TOTP code only once", func(t *testing.T) {
+		// Arrange
+		e, _ := confirmed(t)
+		later := now.Add(time.Minute)
+		code := codeAt(t, e.Secret(), later)
+
+		// Act & Assert
+		assert.NoError(t, e.Verify(code, later))
+		assert.Equal(t, mfa.ErrInvalidCode, e.Verify(code, later))
+	})
+
+	t.Run("should not accept the code used to confirm", func(t *testing.T) {
+		// Arrange
+		e, _ := confirmed(t)
+
+		// Act
+		err := e.Verify(codeAt(t, e.Secret(), now), now)
+
+		// Assert
+		assert.Equal(t, mfa.ErrInvalidCode, err)
+	})
+
+	t.Run("should spend recovery codes", func(t *testing.T) {
+		// Arrange
+		e, codes := confirmed(t)
+
+		// Act & Assert
+		assert.NoError(t, e.Verify(strings.ToUpper(codes[3]), now))
+		require.Len(t, e.UsedRecoveryCodes(), 1)
+		assert.Equal(t, 9, e.RemainingRecoveryCodes())
+		assert.Equal(t, mfa.ErrInvalidCode, e.Verify(codes[3], now))
+	})
+
+	t.Run("should invalidate recovery codes when regenerated", func(t *testing.T) {
+		// Arrange
+		e, codes := confirmed(t)
+
+		// Act
+		fresh, err := e.RegenerateRecoveryCodes(now)
+
+		// Assert
+		require.NoError(t, err)
+		assert.NotEqual(t, codes, fresh)
+		assert.Equal(t, mfa.ErrInvalidCode, e.Verify(codes[0], now))
+		assert.NoError(t, e.Verify(fresh[0], now))
+	})
+
+	t.Run("should require a confirmed enrolment", func(t *testing.T) {
+		// Arrange
+		e, err := mfa.NewEnrollment(7, now)
+		require.NoError(t, err)
+
+		// Act
+		err = e.Verify(codeAt(t, e.Secret(), now), now)
+
+		// Assert
+		assert.Equal(t, mfa.ErrTOTPNotConfirmed, err)
+	})
+}
+
+func TestChallenge(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should expire and close after too many failures", func(t *testing.T) {
+		// Arrange
+		c, plain, err := mfa.NewChallenge(7, mfa.ChallengeTOTP, 5*time.Minute, now)
+		require.NoError(t, err)
+
+		// Act & Assert
+		assert.Equal(t, mfa.HashCode(plain), c.Hash())
+		assert.NoError(t, c.EnsureOpen(now))
+		assert.Equal(t, mfa.ErrChallengeExpired, c.EnsureOpen(now.Add(5*time.Minute)))
+
+		for i := 0; i < mfa.MaxChallengeAttempts; i++ {
+			c.RecordFailure()
+		}
+		assert.Equal(t, mfa.ErrTooManyAttempts, c.EnsureOpen(now))
+	})
+
+	t.Run("should not be completed twice", func(t *testing.T) {
+		// Arrange
+		c, _, err := mfa.NewChallenge(7, mfa.ChallengeTOTP, 5*time.Minute, now)
+		require.NoError(t, err)
+
+		// Act
+		c.Complete(now)
+
+		// Assert
+		assert.Equal(t, mfa.ErrInvalidChallenge, c.EnsureOpen(now))
+	})
+
+	t.Run("should validate person and lifetime", func(t *testing.T) {
+		// Act & Assert
+		_, _, err := mfa.NewChallenge(0, mfa.ChallengeTOTP, time.Minute, now)
+		assert.Equal(t, mfa.ErrInvalidChallengePerson, err)
+
+		_, _, err = mfa.NewChallenge(7, mfa.ChallengeTOTP, 0, now)
+		assert.Equal(t, mfa.ErrInvalidChallengeLifetime, err)
+	})
+}
+
+func TestNewSettings(t *testing.T) {
+	t.Run("should require two-factor for the configured roles", func(t *testing.T) {
+		// Act
+		settings, err := mfa.NewSettings("CropFlow", []string{"ROLE_ADMIN"})
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, settings.IsRequired("ROLE_ADMIN"))
+		assert.False(t, settings.IsRequired("ROLE_USER"))
+	})
+
+	t.Run("should reject unknown roles", func(t *testing.T) {
+		// Act
+		_, err := mfa.NewSettings("CropFlow", []string{"ROLE_ROOT"})
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,17 @@
+package mfa
+
+import "errors"
+
+var (
+	ErrEnrollmentNotFound       = errors.New("two-factor authentication is not enabled")
+	ErrTOTPAlreadyEnabled       = errors.New("two-factor authentication is already enabled")
+	ErrTOTPNotConfirmed         = errors.New("two-factor enrolment has not been confirmed")
+	ErrTOTPRequired             = errors.New("two-factor authentication is required for this role")
+	ErrInvalidCode              = errors.New("invalid two-factor code")
+	ErrInvalidChallenge         = errors.New("invalid login challenge")
+	ErrChallengeExpired         = errors.New("login challenge has expired")
+	ErrTooManyAttempts          = errors.New("too many invalid codes: log in again")
+	ErrInvalidEnrollmentPerson  = errors.New("invalid enrolment: person is required")
+	ErrInvalidChallengePerson   = errors.New("invalid login challenge: person is required")
+	ErrInvalidChallengeLifetime = errors.New("invalid login challenge lifetime: must be greater than zero")
+)
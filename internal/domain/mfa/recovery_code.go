@@ -0,0 +1,94 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Recovery code parameters; every code carries 50 bits of randomness
+const (
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 7
+	recoveryCodeChars = 10
+)
+
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// RecoveryCode is a single-use code that replaces the TOTP code when the
+// device is lost. Only its hash is stored.
+type RecoveryCode struct {
+	id           int64
+	enrollmentID int64
+	hash         string
+	usedAt       *time.Time
+	createdAt    time.Time
+}
+
+// RestoreRecoveryCode reconstructs a RecoveryCode from persistence (used by repository)
+func RestoreRecoveryCode(id, enrollmentID int64, hash string, usedAt *time.Time, createdAt time.Time) *RecoveryCode {
+	return &RecoveryCode{
+		id:           id,
+		enrollmentID: enrollmentID,
+		hash:         hash,
+		usedAt:       usedAt,
+		createdAt:    createdAt,
+	}
+}
+
+// Getters (encapsulation)
+func (c *RecoveryCode) ID() int64 {
+	return c.id
+}
+
+func (c *RecoveryCode) EnrollmentID() int64 {
+	return c.enrollmentID
+}
+
+func (c *RecoveryCode) Hash() string {
+	return c.hash
+}
+
+// UsedAt returns when the code was used, if it was
+func (c *RecoveryCode) UsedAt() *time.Time {
+	return c.usedAt
+}
+
+func (c *RecoveryCode) CreatedAt() time.Time {
+	return c.createdAt
+}
+
+// SetID is used by repository after insertion
+func (c *RecoveryCode) SetID(id int64) {
+	c.id = id
+}
+
+// SetEnrollmentID is used by repository when the enrolment is inserted with its codes
+func (c *RecoveryCode) SetEnrollmentID(enrollmentID int64) {
+	c.enrollmentID = enrollmentID
+}
+
+// HashCode returns the stored form of a plain recovery code or challenge token
+func HashCode(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeRecoveryCode lets users type codes with or without the separator and in any case
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// generateRecoveryCode creates a random plain recovery code formatted as xxxxx-xxxxx
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	code := recoveryCodeEncoding.EncodeToString(buf)[:recoveryCodeChars]
+	return code[:recoveryCodeChars/2] + "-" + code[recoveryCodeChars/2:], nil
+}
@@ -0,0 +1,13 @@
+package mfa
+
+import "context"
+
+// Repository defines the interface for two-factor persistence (Port)
+type Repository interface {
+	SaveEnrollment(ctx context.Context, enrollment *Enrollment) error
+	FindEnrollmentByPersonID(ctx context.Context, personID int64) (*Enrollment, error)
+	DeleteEnrollment(ctx context.Context, personID int64) error
+
+	SaveChallenge(ctx context.Context, challenge *Challenge) error
+	FindChallenge(ctx context.Context, hash string) (*Challenge, error)
+}
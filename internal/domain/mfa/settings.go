@@ -0,0 +1,34 @@
+package mfa
+
+import (
+	"fmt"
+
+	"github.com/cropflow/api/internal/domain/person"
+)
+
+// Settings configures two-factor authentication
+type Settings struct {
+	// Issuer names the service in authenticator apps
+	Issuer string
+
+	requiredRoles map[person.Role]bool
+}
+
+// NewSettings creates the settings; persons with one of the required roles
+// cannot log in without a second factor
+func NewSettings(issuer string, requiredRoles []string) (Settings, error) {
+	settings := Settings{Issuer: issuer, requiredRoles: make(map[person.Role]bool)}
+	for _, value := range requiredRoles {
+		role, err := person.NewRole(value)
+		if err != nil {
+			return Settings{}, fmt.Errorf("%w: %q", err, value)
+		}
+		settings.requiredRoles[role] = true
+	}
+	return settings, nil
+}
+
+// IsRequired checks if persons with the role must use two-factor authentication
+func (s Settings) IsRequired(role person.Role) bool {
+	return s.requiredRoles[role]
+}
@@ -0,0 +1,156 @@
+package nutrition
+
+import (
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/cropflow/api/internal/domain/fertilizer"
+)
+
+// Balance summarizes the nutrients applied to an area, optionally compared
+// with a target. A farm balance combines the balances of its crops.
+type Balance struct {
+	cropID       int64
+	area         float64
+	applied      map[fertilizer.Nutrient]float64
+	target       map[fertilizer.Nutrient]float64
+	applications int
+	unconverted  int
+	parts        []*Balance
+}
+
+// CropBalance computes the balance of a crop from its fertilizer applications.
+// Fertilizers are looked up by ID; applications whose dose cannot be converted
+// to mass are counted but left out of the totals.
+func CropBalance(c *crop.Crop, applications []*crop.Application, fertilizers map[int64]*fertilizer.Fertilizer, targets Targets) *Balance {
+	b := &Balance{
+		cropID:  c.ID(),
+		area:    c.PlantedArea(),
+		applied: make(map[fertilizer.Nutrient]float64),
+	}
+
+	for _, a := range applications {
+		b.applications++
+		kilograms, ok := a.ProductKilograms()
+		f, found := fertilizers[a.FertilizerID()]
+		if !ok || !found {
+			b.unconverted++
+			continue
+		}
+		for n := range f.Composition().Nutrients() {
+			b.applied[n] += f.Composition().NutrientMass(n, kilograms)
+		}
+	}
+
+	if perHectare, ok := targets.For(c.Name()); ok {
+		b.target = make(map[fertilizer.Nutrient]float64, len(perHectare))
+		for n, kg := range perHectare {
+			b.target[n] = kg * b.area
+		}
+	}
+	return b
+}
+
+// FarmBalance combines crop balances. The farm has a target only when every
+// crop has one.
+func FarmBalance(parts []*Balance) *Balance {
+	b := &Balance{
+		applied: make(map[fertilizer.Nutrient]float64),
+		parts:   parts,
+	}
+
+	hasTarget := len(parts) > 0
+	for _, p := range parts {
+		b.area += p.area
+		b.applications += p.applications
+		b.unconverted += p.unconverted
+		for n, kg := range p.applied {
+			b.applied[n] += kg
+		}
+		if p.target == nil {
+			hasTarget = false
+		}
+	}
+
+	if hasTarget {
+		b.target = make(map[fertilizer.Nutrient]float64)
+		for _, p := range parts {
+			for n, kg := range p.target {
+				b.target[n] += kg
+			}
+		}
+	}
+	return b
+}
+
+// CropID returns the crop the balance refers to (zero for a farm balance)
+func (b *Balance) CropID() int64 {
+	return b.cropID
+}
+
+// Area returns the planted hectares the balance refers to
+func (b *Balance) Area() float64 {
+	return b.area
+}
+
+// Applied returns the kilograms of a nutrient applied
+func (b *Balance) Applied(n fertilizer.Nutrient) float64 {
+	return b.applied[n]
+}
+
+// AppliedPerHectare returns the kilograms of a nutrient applied per hectare
+func (b *Balance) AppliedPerHectare(n fertilizer.Nutrient) float64 {
+	return perHectare(b.applied[n], b.area)
+}
+
+// HasTarget checks if the balance can be compared with a target
+func (b *Balance) HasTarget() bool {
+	return b.target != nil
+}
+
+// Target returns the kilograms of a nutrient recommended for the area;
+// ok is false when the target does not cover the nutrient
+func (b *Balance) Target(n fertilizer.Nutrient) (kg float64, ok bool) {
+	kg, ok = b.target[n]
+	return kg, ok
+}
+
+// TargetPerHectare returns the kilograms of a nutrient recommended per hectare;
+// ok is false when the target does not cover the nutrient
+func (b *Balance) TargetPerHectare(n fertilizer.Nutrient) (kg float64, ok bool) {
+	kg, ok = b.target[n]
+	return perHectare(kg, b.area), ok
+}
+
+// Nutrients returns the nutrients that were applied or targeted, in label order
+func (b *Balance) Nutrients() []fertilizer.Nutrient {
+	var nutrients []fertilizer.Nutrient
+	for _, n := range fertilizer.Nutrients {
+		_, applied := b.applied[n]
+		_, targeted := b.target[n]
+		if applied || targeted {
+			nutrients = append(nutrients, n)
+		}
+	}
+	return nutrients
+}
+
+// ApplicationCount returns how many applications were considered
+func (b *Balance) ApplicationCount() int {
+	return b.applications
+}
+
+// UnconvertedCount returns how many applications were left out of the totals
+func (b *Balance) UnconvertedCount() int {
+	return b.unconverted
+}
+
+// Parts returns the crop balances a farm balance was combined from
+func (b *Balance) Parts() []*Balance {
+	return b.parts
+}
+
+func perHectare(kg, area float64) float64 {
+	if area <= 0 {
+		return 0
+	}
+	return kg / area
+}
@@ -0,0 +1,133 @@
+package nutrition_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/cropflow/api/internal/domain/fertilizer"
+	"github.com/cropflow/api/internal/domain/nutrition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixtures(t *testing.T) (*crop.Crop, map[int64]*fertilizer.Fertilizer) {
+	t.Helper()
+	now := time.Now()
+	c := crop.Restore(1, "Milho", 10, 1, nil, nil, crop.StatusGrowing, nil, now, now)
+
+	urea, err := fertilizer.ParseComposition("45-00-00")
+	require.NoError(t, err)
+	npk, err := fertilizer.ParseComposition("NPK 20-05-20 + 2% S")
+	require.NoError(t, err)
+
+	return c, map[int64]*fertilizer.Fertilizer{
+		1: fertilizer.Restore(1, "Ureia", "X", urea, now, now),
+		2: fertilizer.Restore(2, "NPK", "Y", npk, now, now),
+	}
+}
+
+func TestCropBalance(t *testing.T) {
+	at := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should sum nutrients of every application", func(t *testing.T) {
+		// Arrange
+		c, fertilizers := fixtures(t)
+		applications := []*crop.Application{
+			crop.RestoreApplication(1, 1, 1, at, 200, crop.DoseKilogramsPerHectare, 10, nil, "", at),
+			crop.RestoreApplication(2, 1, 1, at, 100, crop.DoseKilogramsPerHectare, 5, nil, "", at),
+			crop.RestoreApplication(3, 1, 2, at, 300, crop.DoseKilogramsPerHectare, 10, nil, "", at),
+			crop.RestoreApplication(4, 1, 2, at, 2, crop.DoseLitersPerHectare, 10, nil, "", at),
+		}
+
+		// Act
+		b := nutrition.CropBalance(c, applications, fertilizers, nutrition.Targets{})
+
+		// Assert
+		assert.Equal(t, 4, b.ApplicationCount())
+		assert.Equal(t, 1, b.UnconvertedCount())
+		assert.InDelta(t, 900+225+600, b.Applied(fertilizer.Nitrogen), 1e-9)
+		assert.InDelta(t, 172.5, b.AppliedPerHectare(fertilizer.Nitrogen), 1e-9)
+		assert.InDelta(t, 150, b.Applied(fertilizer.Phosphate), 1e-9)
+		assert.InDelta(t, 60, b.Applied(fertilizer.Sulfur), 1e-9)
+		assert.Equal(t, []fertilizer.Nutrient{fertilizer.Nitrogen, fertilizer.Phosphate, fertilizer.Potash, fertilizer.Sulfur}, b.Nutrients())
+		assert.False(t, b.HasTarget())
+	})
+
+	t.Run("should compare with the target of the crop type", func(t *testing.T) {
+		// Arrange
+		c, fertilizers := fixtures(t)
+		targets, err := nutrition.NewTargets(map[string]map[string]float64{"milho": {"N": 150, "k2o": 80}})
+		require.NoError(t, err)
+
+		// Act
+		b := nutrition.CropBalance(c, nil, fertilizers, targets)
+
+		// Assert
+		assert.True(t, b.HasTarget())
+		nitrogen, ok := b.Target(fertilizer.Nitrogen)
+		assert.True(t, ok)
+		assert.Equal(t, 1500.0, nitrogen)
+		potash, ok := b.TargetPerHectare(fertilizer.Potash)
+		assert.True(t, ok)
+		assert.Equal(t, 80.0, potash)
+		_, ok = b.Target(fertilizer.Sulfur)
+		assert.False(t, ok)
+		assert.Zero(t, b.Applied(fertilizer.Nitrogen))
+		assert.Equal(t, []fertilizer.Nutrient{fertilizer.Nitrogen, fertilizer.Potash}, b.Nutrients())
+	})
+}
+
+func TestFarmBalance(t *testing.T) {
+	t.Run("should combine crop balances and areas", func(t *testing.T) {
+		// Arrange
+		at := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+		c, fertilizers := fixtures(t)
+		other := crop.Restore(2, "Soja", 30, 1, nil, nil, crop.StatusGrowing, nil, at, at)
+		targets, err := nutrition.NewTargets(map[string]map[string]float64{"Milho": {"N": 150}})
+		require.NoError(t, err)
+
+		first := nutrition.CropBalance(c, []*crop.Application{
+			crop.RestoreApplication(1, 1, 1, at, 200, crop.DoseKilogramsPerHectare, 10, nil, "", at),
+		}, fertilizers, targets)
+		second := nutrition.CropBalance(other, []*crop.Application{
+			crop.RestoreApplication(2, 2, 1, at, 100, crop.DoseKilogramsPerHectare, 30, nil, "", at),
+		}, fertilizers, targets)
+
+		// Act
+		b := nutrition.FarmBalance([]*nutrition.Balance{first, second})
+
+		// Assert
+		assert.Equal(t, 40.0, b.Area())
+		assert.InDelta(t, 900+1350, b.Applied(fertilizer.Nitrogen), 1e-9)
+		assert.InDelta(t, 56.25, b.AppliedPerHectare(fertilizer.Nitrogen), 1e-9)
+		assert.False(t, b.HasTarget(), "soja has no target")
+		assert.Len(t, b.Parts(), 2)
+	})
+}
+
+func TestNewPeriod(t *testing.T) {
+	t.Run("should reject from after to", func(t *testing.T) {
+		// Arrange
+		from := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+
+		// Act
+		_, err := nutrition.NewPeriod(&from, &to)
+
+		// Assert
+		assert.Equal(t, nutrition.ErrInvalidPeriod, err)
+	})
+}
+
+func TestNewTargets(t *testing.T) {
+	t.Run("should reject unknown nutrients and negative targets", func(t *testing.T) {
+		// Act
+		_, unknown := nutrition.NewTargets(map[string]map[string]float64{"milho": {"X": 1}})
+		_, negative := nutrition.NewTargets(map[string]map[string]float64{"milho": {"N": -1}})
+
+		// Assert
+		assert.Equal(t, fertilizer.ErrUnknownNutrient, unknown)
+		assert.Equal(t, nutrition.ErrInvalidTarget, negative)
+	})
+}
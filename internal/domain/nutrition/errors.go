@@ -0,0 +1,8 @@
+package nutrition
+
+import "errors"
+
+var (
+	ErrInvalidPeriod = errors.New("invalid period: from must not be after to")
+	ErrInvalidTarget = errors.New("invalid nutrient target: must not be negative")
+)
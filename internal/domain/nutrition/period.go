@@ -0,0 +1,25 @@
+package nutrition
+
+import "time"
+
+// Period is an optional date range; a nil bound leaves that side open
+type Period struct {
+	from *time.Time
+	to   *time.Time
+}
+
+// NewPeriod creates a new Period with validation
+func NewPeriod(from, to *time.Time) (Period, error) {
+	if from != nil && to != nil && from.After(*to) {
+		return Period{}, ErrInvalidPeriod
+	}
+	return Period{from: from, to: to}, nil
+}
+
+func (p Period) From() *time.Time {
+	return p.from
+}
+
+func (p Period) To() *time.Time {
+	return p.to
+}
@@ -0,0 +1,43 @@
+package nutrition
+
+import (
+	"strings"
+
+	"github.com/cropflow/api/internal/domain/fertilizer"
+)
+
+// Targets holds the recommended nutrient inputs, in kg per hectare, per crop type.
+// The crop type is matched against the crop name, ignoring case.
+type Targets struct {
+	byCropType map[string]map[fertilizer.Nutrient]float64
+}
+
+// NewTargets creates Targets from raw nutrient symbols with validation
+func NewTargets(raw map[string]map[string]float64) (Targets, error) {
+	t := Targets{byCropType: make(map[string]map[fertilizer.Nutrient]float64, len(raw))}
+	for cropType, nutrients := range raw {
+		perHectare := make(map[fertilizer.Nutrient]float64, len(nutrients))
+		for symbol, kg := range nutrients {
+			n, err := fertilizer.NewNutrient(symbol)
+			if err != nil {
+				return Targets{}, err
+			}
+			if kg < 0 {
+				return Targets{}, ErrInvalidTarget
+			}
+			perHectare[n] = kg
+		}
+		t.byCropType[normalizeCropType(cropType)] = perHectare
+	}
+	return t, nil
+}
+
+// For returns the per-hectare target of a crop type, if one is configured
+func (t Targets) For(cropType string) (map[fertilizer.Nutrient]float64, bool) {
+	perHectare, ok := t.byCropType[normalizeCropType(cropType)]
+	return perHectare, ok
+}
+
+func normalizeCropType(cropType string) string {
+	return strings.ToLower(strings.TrimSpace(cropType))
+}
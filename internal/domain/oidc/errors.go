@@ -0,0 +1,20 @@
+package oidc
+
+import "errors"
+
+var (
+	ErrNotConfigured        = errors.New("oidc login is not configured")
+	ErrInvalidState         = errors.New("invalid oidc login state")
+	ErrLoginRequestExpired  = errors.New("oidc login request has expired")
+	ErrInvalidLifetime      = errors.New("invalid oidc login request lifetime: must be greater than zero")
+	ErrProviderDenied       = errors.New("identity provider did not authorize the login")
+	ErrInvalidIDToken       = errors.New("invalid id token")
+	ErrInvalidDiscovery     = errors.New("invalid oidc discovery document")
+	ErrMissingUsername      = errors.New("id token has no usable username claim")
+	ErrUsernameTaken        = errors.New("username already belongs to a local account")
+	ErrNoRoleMapped         = errors.New("identity provider groups are not mapped to a role")
+	ErrInvalidRoleMapping   = errors.New("invalid oidc role mapping")
+	ErrInvalidLink          = errors.New("invalid oidc link: person, issuer and subject are required")
+	ErrLinkNotFound         = errors.New("oidc link not found")
+	ErrLoginRequestConsumed = errors.New("oidc login request was already used")
+)
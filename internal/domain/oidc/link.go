@@ -0,0 +1,63 @@
+package oidc
+
+import "time"
+
+// Link ties a person to the account that identifies them at an identity
+// provider, the pair of issuer and subject of its ID tokens
+type Link struct {
+	id        int64
+	personID  int64
+	issuer    string
+	subject   string
+	createdAt time.Time
+}
+
+// NewLink creates a new Link with validation (Factory Method)
+func NewLink(personID int64, issuer, subject string, now time.Time) (*Link, error) {
+	if personID <= 0 || issuer == "" || subject == "" {
+		return nil, ErrInvalidLink
+	}
+	return &Link{
+		personID:  personID,
+		issuer:    issuer,
+		subject:   subject,
+		createdAt: now,
+	}, nil
+}
+
+// RestoreLink reconstructs a Link from persistence (used by repository)
+func RestoreLink(id, personID int64, issuer, subject string, createdAt time.Time) *Link {
+	return &Link{
+		id:        id,
+		personID:  personID,
+		issuer:    issuer,
+		subject:   subject,
+		createdAt: createdAt,
+	}
+}
+
+// Getters (encapsulation)
+func (l *Link) ID() int64 {
+	return l.id
+}
+
+func (l *Link) PersonID() int64 {
+	return l.personID
+}
+
+func (l *Link) Issuer() string {
+	return l.issuer
+}
+
+func (l *Link) Subject() string {
+	return l.subject
+}
+
+func (l *Link) CreatedAt() time.Time {
+	return l.createdAt
+}
+
+// SetID is used by repository after insertion
+func (l *Link) SetID(id int64) {
+	l.id = id
+}
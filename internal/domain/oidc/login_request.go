@@ -0,0 +1,138 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+// secretBytes is the amount of randomness in the state, nonce and code verifier
+const secretBytes = 32
+
+// LoginRequest is a login started with the identity provider and not finished
+// yet. It binds the callback to the client that started it: the state comes
+// back in the redirect, the nonce inside the ID token and the PKCE code
+// verifier is needed to redeem the authorization code. Only the hash of the
+// state is stored.
+type LoginRequest struct {
+	id           int64
+	stateHash    string
+	nonce        string
+	codeVerifier string
+	expiresAt    time.Time
+	consumedAt   *time.Time
+	createdAt    time.Time
+}
+
+// NewLoginRequest starts a login and returns its plain state so it can be
+// sent to the identity provider (Factory Method)
+func NewLoginRequest(lifetime time.Duration, now time.Time) (*LoginRequest, string, error) {
+	if lifetime <= 0 {
+		return nil, "", ErrInvalidLifetime
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return nil, "", err
+	}
+	nonce, err := randomString()
+	if err != nil {
+		return nil, "", err
+	}
+	verifier, err := randomString()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &LoginRequest{
+		stateHash:    HashState(state),
+		nonce:        nonce,
+		codeVerifier: verifier,
+		expiresAt:    now.Add(lifetime),
+		createdAt:    now,
+	}, state, nil
+}
+
+// RestoreLoginRequest reconstructs a LoginRequest from persistence (used by repository)
+func RestoreLoginRequest(id int64, stateHash, nonce, codeVerifier string, expiresAt time.Time, consumedAt *time.Time, createdAt time.Time) *LoginRequest {
+	return &LoginRequest{
+		id:           id,
+		stateHash:    stateHash,
+		nonce:        nonce,
+		codeVerifier: codeVerifier,
+		expiresAt:    expiresAt,
+		consumedAt:   consumedAt,
+		createdAt:    createdAt,
+	}
+}
+
+// Getters (encapsulation)
+func (r *LoginRequest) ID() int64 {
+	return r.id
+}
+
+func (r *LoginRequest) StateHash() string {
+	return r.stateHash
+}
+
+func (r *LoginRequest) Nonce() string {
+	return r.nonce
+}
+
+func (r *LoginRequest) CodeVerifier() string {
+	return r.codeVerifier
+}
+
+func (r *LoginRequest) ExpiresAt() time.Time {
+	return r.expiresAt
+}
+
+// ConsumedAt returns when the callback of the login was handled, if it was
+func (r *LoginRequest) ConsumedAt() *time.Time {
+	return r.consumedAt
+}
+
+func (r *LoginRequest) CreatedAt() time.Time {
+	return r.createdAt
+}
+
+// SetID is used by repository after insertion
+func (r *LoginRequest) SetID(id int64) {
+	r.id = id
+}
+
+// Business Methods
+
+// CodeChallenge returns the S256 PKCE challenge of the code verifier (RFC 7636)
+func (r *LoginRequest) CodeChallenge() string {
+	sum := sha256.Sum256([]byte(r.codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Consume marks the request as used so its state cannot be replayed
+func (r *LoginRequest) Consume(now time.Time) error {
+	if r.consumedAt != nil {
+		return ErrLoginRequestConsumed
+	}
+	if !now.Before(r.expiresAt) {
+		return ErrLoginRequestExpired
+	}
+	r.consumedAt = &now
+	return nil
+}
+
+// HashState returns the stored form of a plain state
+func HashState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString() (string, error) {
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,79 @@
+package oidc_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/oidc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLoginRequest(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should store only the hash of the state", func(t *testing.T) {
+		// Act
+		request, state, err := oidc.NewLoginRequest(10*time.Minute, now)
+
+		// Assert
+		require.NoError(t, err)
+		assert.NotEmpty(t, state)
+		assert.Equal(t, oidc.HashState(state), request.StateHash())
+		assert.NotEmpty(t, request.Nonce())
+		assert.NotEqual(t, state, request.Nonce())
+		assert.Equal(t, now.Add(10*time.Minute), request.ExpiresAt())
+	})
+
+	t.Run("should derive the S256 code challenge from the verifier", func(t *testing.T) {
+		// Arrange
+		request, _, err := oidc.NewLoginRequest(time.Minute, now)
+		require.NoError(t, err)
+		sum := sha256.Sum256([]byte(request.CodeVerifier()))
+
+		// Act
+		challenge := request.CodeChallenge()
+
+		// Assert
+		assert.Equal(t, base64.RawURLEncoding.EncodeToString(sum[:]), challenge)
+		assert.GreaterOrEqual(t, len(request.CodeVerifier()), 43)
+	})
+
+	t.Run("should validate the lifetime", func(t *testing.T) {
+		// Act
+		_, _, err := oidc.NewLoginRequest(0, now)
+
+		// Assert
+		assert.Equal(t, oidc.ErrInvalidLifetime, err)
+	})
+}
+
+func TestLoginRequest_Consume(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should be consumed only once", func(t *testing.T) {
+		// Arrange
+		request, _, err := oidc.NewLoginRequest(time.Minute, now)
+		require.NoError(t, err)
+
+		// Act & Assert
+		assert.NoError(t, request.Consume(now))
+		assert.Equal(t, &now, request.ConsumedAt())
+		assert.Equal(t, oidc.ErrLoginRequestConsumed, request.Consume(now))
+	})
+
+	t.Run("should not be consumed after expiring", func(t *testing.T) {
+		// Arrange
+		request, _, err := oidc.NewLoginRequest(time.Minute, now)
+		require.NoError(t, err)
+
+		// Act
+		err = request.Consume(now.Add(time.Minute))
+
+		// Assert
+		assert.Equal(t, oidc.ErrLoginRequestExpired, err)
+		assert.Nil(t, request.ConsumedAt())
+	})
+}
@@ -0,0 +1,21 @@
+package oidc
+
+import "context"
+
+// Claims holds what a verified ID token says about the person
+type Claims struct {
+	Issuer   string
+	Subject  string
+	Username string
+	Email    string
+	Groups   []string
+}
+
+// Provider is the identity provider logins are delegated to (Port)
+type Provider interface {
+	// AuthorizationURL returns where to send the client to log in
+	AuthorizationURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems an authorization code and returns the claims of the
+	// verified ID token, which must carry the given nonce
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (Claims, error)
+}
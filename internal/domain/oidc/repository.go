@@ -0,0 +1,15 @@
+package oidc
+
+import "context"
+
+// Repository defines the interface for OIDC login persistence (Port)
+type Repository interface {
+	// SaveLoginRequest inserts a new request or records that an existing one
+	// was consumed; consuming a request that was consumed concurrently returns
+	// ErrLoginRequestConsumed
+	SaveLoginRequest(ctx context.Context, request *LoginRequest) error
+	FindLoginRequest(ctx context.Context, stateHash string) (*LoginRequest, error)
+
+	SaveLink(ctx context.Context, link *Link) error
+	FindLink(ctx context.Context, issuer, subject string) (*Link, error)
+}
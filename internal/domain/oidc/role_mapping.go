@@ -0,0 +1,56 @@
+package oidc
+
+import (
+	"fmt"
+
+	"github.com/cropflow/api/internal/domain/person"
+)
+
+// RoleMapping decides the role of a person from the groups the identity
+// provider puts in their ID token
+type RoleMapping struct {
+	roles       map[string]person.Role
+	defaultRole person.Role
+}
+
+// NewRoleMapping creates a mapping from group names to roles. Persons in none
+// of the groups get the default role, or cannot log in when it is empty.
+func NewRoleMapping(groups map[string]string, defaultRole string) (RoleMapping, error) {
+	mapping := RoleMapping{roles: make(map[string]person.Role, len(groups))}
+	for group, value := range groups {
+		if group == "" {
+			return RoleMapping{}, fmt.Errorf("%w: empty group name", ErrInvalidRoleMapping)
+		}
+		role, err := person.NewRole(value)
+		if err != nil {
+			return RoleMapping{}, fmt.Errorf("%w: %q: %v", ErrInvalidRoleMapping, value, err)
+		}
+		mapping.roles[group] = role
+	}
+	if defaultRole != "" {
+		role, err := person.NewRole(defaultRole)
+		if err != nil {
+			return RoleMapping{}, fmt.Errorf("%w: %q: %v", ErrInvalidRoleMapping, defaultRole, err)
+		}
+		mapping.defaultRole = role
+	}
+	return mapping, nil
+}
+
+// Role returns the highest role mapped from the groups
+func (m RoleMapping) Role(groups []string) (person.Role, error) {
+	var role person.Role
+	for _, group := range groups {
+		mapped, ok := m.roles[group]
+		if ok && (role == "" || !role.HasPermission(mapped)) {
+			role = mapped
+		}
+	}
+	if role != "" {
+		return role, nil
+	}
+	if m.defaultRole != "" {
+		return m.defaultRole, nil
+	}
+	return "", ErrNoRoleMapped
+}
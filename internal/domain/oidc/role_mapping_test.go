@@ -0,0 +1,63 @@
+package oidc_test
+
+import (
+	"testing"
+
+	"github.com/cropflow/api/internal/domain/oidc"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleMapping_Role(t *testing.T) {
+	groups := map[string]string{
+		"cropflow-admins":      "ROLE_ADMIN",
+		"cropflow-agronomists": "ROLE_MANAGER",
+	}
+
+	t.Run("should pick the highest mapped role", func(t *testing.T) {
+		// Arrange
+		mapping, err := oidc.NewRoleMapping(groups, "ROLE_USER")
+		require.NoError(t, err)
+
+		// Act
+		role, err := mapping.Role([]string{"everyone", "cropflow-agronomists", "cropflow-admins"})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, person.RoleAdmin, role)
+	})
+
+	t.Run("should fall back to the default role", func(t *testing.T) {
+		// Arrange
+		mapping, err := oidc.NewRoleMapping(groups, "ROLE_USER")
+		require.NoError(t, err)
+
+		// Act
+		role, err := mapping.Role([]string{"everyone"})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, person.RoleUser, role)
+	})
+
+	t.Run("should reject unmapped groups without a default role", func(t *testing.T) {
+		// Arrange
+		mapping, err := oidc.NewRoleMapping(groups, "")
+		require.NoError(t, err)
+
+		// Act
+		_, err = mapping.Role(nil)
+
+		// Assert
+		assert.Equal(t, oidc.ErrNoRoleMapped, err)
+	})
+
+	t.Run("should reject unknown roles", func(t *testing.T) {
+		// Act
+		_, err := oidc.NewRoleMapping(map[string]string{"admins": "ROLE_ROOT"}, "")
+
+		// Assert
+		assert.ErrorIs(t, err, oidc.ErrInvalidRoleMapping)
+	})
+}
@@ -0,0 +1,11 @@
+package passwordreset
+
+import "errors"
+
+var (
+	ErrInvalidResetToken  = errors.New("invalid password reset token")
+	ErrResetTokenExpired  = errors.New("password reset token has expired")
+	ErrResetTokenUsed     = errors.New("password reset token was already used")
+	ErrInvalidResetPerson = errors.New("invalid password reset: person is required")
+	ErrInvalidLifetime    = errors.New("invalid password reset lifetime: must be greater than zero")
+)
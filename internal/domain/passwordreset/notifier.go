@@ -0,0 +1,17 @@
+package passwordreset
+
+import (
+	"context"
+	"time"
+)
+
+// Recipient identifies the person a reset token is delivered to
+type Recipient struct {
+	PersonID int64
+	Username string
+}
+
+// Notifier delivers reset tokens to their person, e.g. by e-mail (Port)
+type Notifier interface {
+	SendResetToken(ctx context.Context, recipient Recipient, token string, expiresAt time.Time) error
+}
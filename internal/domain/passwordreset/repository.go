@@ -0,0 +1,16 @@
+package passwordreset
+
+import (
+	"context"
+	"time"
+)
+
+// Repository defines the interface for reset token persistence (Port)
+type Repository interface {
+	// Save inserts a new token or records that an existing one was redeemed;
+	// redeeming a token that was redeemed concurrently returns ErrResetTokenUsed
+	Save(ctx context.Context, token *Token) error
+	FindByHash(ctx context.Context, hash string) (*Token, error)
+	// InvalidateAllByPersonID spends the unused tokens of a person, e.g. when a new one is issued
+	InvalidateAllByPersonID(ctx context.Context, personID int64, at time.Time) error
+}
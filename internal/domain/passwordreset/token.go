@@ -0,0 +1,110 @@
+package passwordreset
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+// tokenBytes is the amount of randomness in a reset token
+const tokenBytes = 32
+
+// Token is a one-time token, issued by an administrator, that lets a person
+// choose a new password without knowing the current one. Only its hash is stored.
+type Token struct {
+	id        int64
+	personID  int64
+	hash      string
+	expiresAt time.Time
+	usedAt    *time.Time
+	createdAt time.Time
+}
+
+// NewToken issues a reset token for a person and returns it in plain form so
+// it can be delivered (Factory Method)
+func NewToken(personID int64, lifetime time.Duration, now time.Time) (*Token, string, error) {
+	if personID <= 0 {
+		return nil, "", ErrInvalidResetPerson
+	}
+	if lifetime <= 0 {
+		return nil, "", ErrInvalidLifetime
+	}
+
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", err
+	}
+	plain := base64.RawURLEncoding.EncodeToString(buf)
+
+	return &Token{
+		personID:  personID,
+		hash:      HashToken(plain),
+		expiresAt: now.Add(lifetime),
+		createdAt: now,
+	}, plain, nil
+}
+
+// RestoreToken reconstructs a Token from persistence (used by repository)
+func RestoreToken(id, personID int64, hash string, expiresAt time.Time, usedAt *time.Time, createdAt time.Time) *Token {
+	return &Token{
+		id:        id,
+		personID:  personID,
+		hash:      hash,
+		expiresAt: expiresAt,
+		usedAt:    usedAt,
+		createdAt: createdAt,
+	}
+}
+
+// Getters (encapsulation)
+func (t *Token) ID() int64 {
+	return t.id
+}
+
+func (t *Token) PersonID() int64 {
+	return t.personID
+}
+
+func (t *Token) Hash() string {
+	return t.hash
+}
+
+func (t *Token) ExpiresAt() time.Time {
+	return t.expiresAt
+}
+
+// UsedAt returns when the token was redeemed, if it was
+func (t *Token) UsedAt() *time.Time {
+	return t.usedAt
+}
+
+func (t *Token) CreatedAt() time.Time {
+	return t.createdAt
+}
+
+// SetID is used by repository after insertion
+func (t *Token) SetID(id int64) {
+	t.id = id
+}
+
+// Business Methods
+
+// Redeem spends the token; it can only be redeemed once and before it expires
+func (t *Token) Redeem(now time.Time) error {
+	if t.usedAt != nil {
+		return ErrResetTokenUsed
+	}
+	if !now.Before(t.expiresAt) {
+		return ErrResetTokenExpired
+	}
+	t.usedAt = &now
+	return nil
+}
+
+// HashToken returns the stored form of a plain reset token
+func HashToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
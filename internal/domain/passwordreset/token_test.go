@@ -0,0 +1,63 @@
+package passwordreset_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/passwordreset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewToken(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should issue a token stored only as a hash", func(t *testing.T) {
+		// Act
+		token, plain, err := passwordreset.NewToken(7, time.Hour, now)
+
+		// Assert
+		require.NoError(t, err)
+		assert.NotEmpty(t, plain)
+		assert.Equal(t, passwordreset.HashToken(plain), token.Hash())
+		assert.Equal(t, now.Add(time.Hour), token.ExpiresAt())
+		assert.Nil(t, token.UsedAt())
+	})
+
+	t.Run("should validate person and lifetime", func(t *testing.T) {
+		// Act & Assert
+		_, _, err := passwordreset.NewToken(0, time.Hour, now)
+		assert.Equal(t, passwordreset.ErrInvalidResetPerson, err)
+
+		_, _, err = passwordreset.NewToken(7, 0, now)
+		assert.Equal(t, passwordreset.ErrInvalidLifetime, err)
+	})
+}
+
+func TestToken_Redeem(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should be redeemed only once", func(t *testing.T) {
+		// Arrange
+		token, _, err := passwordreset.NewToken(7, time.Hour, now)
+		require.NoError(t, err)
+
+		// Act & Assert
+		assert.NoError(t, token.Redeem(now))
+		assert.Equal(t, &now, token.UsedAt())
+		assert.Equal(t, passwordreset.ErrResetTokenUsed, token.Redeem(now))
+	})
+
+	t.Run("should not be redeemed after it expires", func(t *testing.T) {
+		// Arrange
+		token, _, err := passwordreset.NewToken(7, time.Hour, now)
+		require.NoError(t, err)
+
+		// Act
+		err = token.Redeem(now.Add(time.Hour))
+
+		// Assert
+		assert.Equal(t, passwordreset.ErrResetTokenExpired, err)
+		assert.Nil(t, token.UsedAt())
+	})
+}
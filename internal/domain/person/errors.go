@@ -7,8 +7,13 @@ var (
 	ErrInvalidCredentials    = errors.New("invalid username or password")
 	ErrUsernameAlreadyExists = errors.New("username already exists")
 	ErrInvalidUsername       = errors.New("invalid username: must be at least 3 characters")
-	ErrInvalidPassword       = errors.New("invalid password: must be at least 8 characters")
-	ErrInvalidRole           = errors.New("invalid role: must be USER, MANAGER, or ADMIN")
+	ErrInvalidPassword       = errors.New("invalid password: shorter than the minimum length")
+	ErrPasswordTooLong       = errors.New("invalid password: longer than the maximum length")
+	ErrPasswordTooWeak       = errors.New("invalid password: missing a required character class")
+	ErrPasswordTooCommon     = errors.New("invalid password: too common")
+	ErrInvalidPasswordPolicy = errors.New("invalid password policy")
+	ErrInvalidRole           = errors.New("invalid role: must be ROLE_USER, ROLE_MANAGER, or ROLE_ADMIN")
 	ErrSamePassword          = errors.New("new password must be different from old password")
 	ErrSameRole              = errors.New("person already has this role")
+	ErrLastAdmin             = errors.New("the last admin cannot be demoted or removed")
 )
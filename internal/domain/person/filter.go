@@ -0,0 +1,10 @@
+package person
+
+// SortFields lists the fields persons can be ordered by; the first one is the default
+var SortFields = []string{"id", "username", "createdAt"}
+
+// Filter narrows down a person listing; zero values are ignored
+type Filter struct {
+	UsernamePrefix string
+	Role           Role
+}
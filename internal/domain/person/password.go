@@ -1,28 +1,28 @@
 package person
 
 import (
-	"errors"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
-// Password represents a password value object
+// dummyHash is a bcrypt hash with the default cost of a random password.
+// Comparing against it when a username does not exist makes the rejection take
+// as long as a wrong password, so response times do not reveal which usernames exist.
+const dummyHash = "$2a$10$qOe.ua.1mv1EfrxWD8j6FOsBLnK21uQkoaRgi24NkwceqVKh43ODi"
+
+// Password represents a password value object. The hash records its own
+// algorithm and parameters: bcrypt ($2a$...) or argon2id in PHC format ($argon2id$...).
 type Password struct {
 	hash string
 }
 
-// NewPassword creates a new Password from plain text with validation and hashing
-func NewPassword(plainText string) (Password, error) {
-	if len(plainText) < 8 {
-		return Password{}, errors.New("password must be at least 8 characters")
-	}
-
-	hash, err := bcrypt.GenerateFromPassword([]byte(plainText), bcrypt.DefaultCost)
-	if err != nil {
+// NewPassword creates a new Password from plain text, validated and hashed as the policy says
+func NewPassword(plainText string, policy PasswordPolicy) (Password, error) {
+	if err := policy.Validate(plainText); err != nil {
 		return Password{}, err
 	}
-
-	return Password{hash: string(hash)}, nil
+	return policy.Hashing.hash(plainText)
 }
 
 // NewPasswordFromHash creates a Password from an existing hash (for reconstruction from DB)
@@ -37,6 +37,9 @@ func (p Password) Hash() string {
 
 // Compare checks if the plain text password matches the hashed password
 func (p Password) Compare(plainText string) error {
+	if strings.HasPrefix(p.hash, argon2idPrefix) {
+		return compareArgon2id(p.hash, plainText)
+	}
 	return bcrypt.CompareHashAndPassword([]byte(p.hash), []byte(plainText))
 }
 
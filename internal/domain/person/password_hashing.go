@@ -0,0 +1,146 @@
+package person
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HashAlgorithm names the algorithm new passwords are hashed with
+type HashAlgorithm string
+
+const (
+	Bcrypt   HashAlgorithm = "bcrypt"
+	Argon2id HashAlgorithm = "argon2id"
+)
+
+// bcryptMaxLength is the number of bytes bcrypt hashes; anything after is ignored
+const bcryptMaxLength = 72
+
+const (
+	argon2idPrefix   = "$argon2id$"
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var errMalformedArgon2id = errors.New("malformed argon2id hash")
+
+// Argon2Params are the argon2id cost parameters; Memory is in KiB
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// PasswordHashing selects the algorithm and cost new passwords are hashed with.
+// Hashes made with another algorithm or cost keep working and are upgraded on login.
+type PasswordHashing struct {
+	Algorithm  HashAlgorithm
+	BcryptCost int
+	Argon2     Argon2Params
+}
+
+// NewPasswordHashing creates a PasswordHashing with validation
+func NewPasswordHashing(algorithm string, bcryptCost int, argon2 Argon2Params) (PasswordHashing, error) {
+	hashing := PasswordHashing{Algorithm: HashAlgorithm(algorithm), BcryptCost: bcryptCost, Argon2: argon2}
+	switch hashing.Algorithm {
+	case Bcrypt:
+		if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+			return PasswordHashing{}, fmt.Errorf("%w: bcrypt cost must be between %d and %d", ErrInvalidPasswordPolicy, bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case Argon2id:
+		if argon2.Iterations == 0 || argon2.Parallelism == 0 || argon2.Memory < 8*uint32(argon2.Parallelism) {
+			return PasswordHashing{}, fmt.Errorf("%w: argon2id needs iterations and parallelism of at least 1 and 8 KiB of memory per thread", ErrInvalidPasswordPolicy)
+		}
+	default:
+		return PasswordHashing{}, fmt.Errorf("%w: unknown hash algorithm %q", ErrInvalidPasswordPolicy, algorithm)
+	}
+	return hashing, nil
+}
+
+// DefaultPasswordHashing hashes with bcrypt at its default cost; the argon2id
+// parameters are the second recommended option of RFC 9106
+func DefaultPasswordHashing() PasswordHashing {
+	return PasswordHashing{
+		Algorithm:  Bcrypt,
+		BcryptCost: bcrypt.DefaultCost,
+		Argon2:     Argon2Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 4},
+	}
+}
+
+// NeedsRehash tells whether a stored hash was made with another algorithm or cost
+func (h PasswordHashing) NeedsRehash(p Password) bool {
+	if h.Algorithm == Argon2id {
+		params, _, _, err := decodeArgon2id(p.hash)
+		return err != nil || params != h.Argon2
+	}
+	cost, err := bcrypt.Cost([]byte(p.hash))
+	return err != nil || cost != h.BcryptCost
+}
+
+func (h PasswordHashing) hash(plainText string) (Password, error) {
+	if h.Algorithm == Argon2id {
+		salt := make([]byte, argon2SaltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return Password{}, err
+		}
+		key := argon2.IDKey([]byte(plainText), salt, h.Argon2.Iterations, h.Argon2.Memory, h.Argon2.Parallelism, argon2KeyLength)
+		return Password{hash: encodeArgon2id(h.Argon2, salt, key)}, nil
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(plainText), h.BcryptCost)
+	if err != nil {
+		return Password{}, err
+	}
+	return Password{hash: string(hash)}, nil
+}
+
+// encodeArgon2id formats a hash as $argon2id$v=19$m=65536,t=3,p=4$salt$key
+func encodeArgon2id(params Argon2Params, salt, key []byte) string {
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+func decodeArgon2id(hash string) (Argon2Params, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(hash, argon2idPrefix), "$")
+	if !strings.HasPrefix(hash, argon2idPrefix) || len(parts) != 4 {
+		return Argon2Params{}, nil, nil, errMalformedArgon2id
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2Params{}, nil, nil, errMalformedArgon2id
+	}
+	var params Argon2Params
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Argon2Params{}, nil, nil, errMalformedArgon2id
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Argon2Params{}, nil, nil, errMalformedArgon2id
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return Argon2Params{}, nil, nil, errMalformedArgon2id
+	}
+	return params, salt, key, nil
+}
+
+func compareArgon2id(hash, plainText string) error {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return err
+	}
+	candidate := argon2.IDKey([]byte(plainText), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, candidate) != 1 {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
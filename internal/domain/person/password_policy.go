@@ -0,0 +1,130 @@
+package person
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CharacterClass is a kind of character a password can be required to contain
+type CharacterClass string
+
+const (
+	Lowercase CharacterClass = "lower"
+	Uppercase CharacterClass = "upper"
+	Digit     CharacterClass = "digit"
+	Symbol    CharacterClass = "symbol"
+)
+
+// PasswordPolicy decides which passwords are accepted and how they are hashed.
+// MinLength counts characters; MaxLength counts bytes, since bcrypt ignores
+// everything after 72 bytes. Passwords on the denylist are rejected regardless of case.
+type PasswordPolicy struct {
+	MinLength       int
+	MaxLength       int
+	RequiredClasses []CharacterClass
+	Hashing         PasswordHashing
+
+	denylist map[string]struct{}
+	// dummy is hashed like new passwords so CompareDummy takes as long as a real comparison
+	dummy Password
+}
+
+// NewPasswordPolicy creates a PasswordPolicy with validation
+func NewPasswordPolicy(minLength, maxLength int, classes, denylist []string, hashing PasswordHashing) (PasswordPolicy, error) {
+	if minLength <= 0 || maxLength < minLength {
+		return PasswordPolicy{}, fmt.Errorf("%w: lengths must be positive and the maximum not below the minimum", ErrInvalidPasswordPolicy)
+	}
+	if hashing.Algorithm == Bcrypt && maxLength > bcryptMaxLength {
+		return PasswordPolicy{}, fmt.Errorf("%w: bcrypt ignores passwords beyond %d bytes", ErrInvalidPasswordPolicy, bcryptMaxLength)
+	}
+
+	required := make([]CharacterClass, 0, len(classes))
+	for _, class := range classes {
+		switch c := CharacterClass(strings.ToLower(class)); c {
+		case Lowercase, Uppercase, Digit, Symbol:
+			required = append(required, c)
+		default:
+			return PasswordPolicy{}, fmt.Errorf("%w: unknown character class %q", ErrInvalidPasswordPolicy, class)
+		}
+	}
+
+	words := make(map[string]struct{}, len(denylist))
+	for _, word := range denylist {
+		words[strings.ToLower(word)] = struct{}{}
+	}
+
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return PasswordPolicy{}, err
+	}
+	dummy, err := hashing.hash(base64.RawURLEncoding.EncodeToString(secret))
+	if err != nil {
+		return PasswordPolicy{}, err
+	}
+
+	return PasswordPolicy{
+		MinLength:       minLength,
+		MaxLength:       maxLength,
+		RequiredClasses: required,
+		Hashing:         hashing,
+		denylist:        words,
+		dummy:           dummy,
+	}, nil
+}
+
+// DefaultPasswordPolicy accepts passwords of 8 to 72 bytes hashed with bcrypt
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength: 8,
+		MaxLength: bcryptMaxLength,
+		Hashing:   DefaultPasswordHashing(),
+		dummy:     NewPasswordFromHash(dummyHash),
+	}
+}
+
+// Validate checks a plain text password against the policy without hashing it
+func (p PasswordPolicy) Validate(plainText string) error {
+	if utf8.RuneCountInString(plainText) < p.MinLength {
+		return ErrInvalidPassword
+	}
+	if len(plainText) > p.MaxLength {
+		return ErrPasswordTooLong
+	}
+	for _, class := range p.RequiredClasses {
+		if !strings.ContainsFunc(plainText, class.matches) {
+			return ErrPasswordTooWeak
+		}
+	}
+	if _, ok := p.denylist[strings.ToLower(plainText)]; ok {
+		return ErrPasswordTooCommon
+	}
+	return nil
+}
+
+// NeedsRehash tells whether a stored hash is outdated for the policy
+func (p PasswordPolicy) NeedsRehash(password Password) bool {
+	return p.Hashing.NeedsRehash(password)
+}
+
+// CompareDummy spends the same time as comparing a password hashed with the
+// policy, without any password to match
+func (p PasswordPolicy) CompareDummy(plainText string) {
+	_ = p.dummy.Compare(plainText)
+}
+
+func (c CharacterClass) matches(r rune) bool {
+	switch c {
+	case Lowercase:
+		return unicode.IsLower(r)
+	case Uppercase:
+		return unicode.IsUpper(r)
+	case Digit:
+		return unicode.IsDigit(r)
+	default:
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}
+}
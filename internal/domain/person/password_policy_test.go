@@ -0,0 +1,164 @@
+package person_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// cheapArgon2 keeps the tests fast; production parameters come from the configuration
+var cheapArgon2 = person.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	hashing, err := person.NewPasswordHashing("bcrypt", bcrypt.MinCost, cheapArgon2)
+	require.NoError(t, err)
+	policy, err := person.NewPasswordPolicy(10, 20, []string{"lower", "upper", "digit", "symbol"}, []string{"Password123!"}, hashing)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		password string
+		expected error
+	}{
+		{"should accept a password meeting every rule", "Plantio#2024", nil},
+		{"should reject a short password", "Pl#24", person.ErrInvalidPassword},
+		{"should reject a long password", "Plantio#2024-Plantio#2024", person.ErrPasswordTooLong},
+		{"should reject a password without a symbol", "Plantio2024", person.ErrPasswordTooWeak},
+		{"should reject a password without an uppercase letter", "plantio#2024", person.ErrPasswordTooWeak},
+		{"should reject a denylisted password regardless of case", "pASSWORD123!", person.ErrPasswordTooCommon},
+		{"should count characters, not bytes, for the minimum", "Colheitaç#1", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := policy.Validate(tt.password)
+
+			// Assert
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestNewPasswordPolicy(t *testing.T) {
+	t.Run("should reject a maximum length bcrypt would truncate", func(t *testing.T) {
+		// Act
+		_, err := person.NewPasswordPolicy(8, 100, nil, nil, person.DefaultPasswordHashing())
+
+		// Assert
+		assert.ErrorIs(t, err, person.ErrInvalidPasswordPolicy)
+	})
+
+	t.Run("should reject unknown character classes", func(t *testing.T) {
+		// Act
+		_, err := person.NewPasswordPolicy(8, 72, []string{"emoji"}, nil, person.DefaultPasswordHashing())
+
+		// Assert
+		assert.ErrorIs(t, err, person.ErrInvalidPasswordPolicy)
+	})
+
+	t.Run("should reject a maximum below the minimum", func(t *testing.T) {
+		// Act
+		_, err := person.NewPasswordPolicy(12, 10, nil, nil, person.DefaultPasswordHashing())
+
+		// Assert
+		assert.ErrorIs(t, err, person.ErrInvalidPasswordPolicy)
+	})
+}
+
+func TestNewPasswordHashing(t *testing.T) {
+	t.Run("should reject unknown algorithms and invalid costs", func(t *testing.T) {
+		// Act & Assert
+		_, err := person.NewPasswordHashing("md5", bcrypt.DefaultCost, cheapArgon2)
+		assert.ErrorIs(t, err, person.ErrInvalidPasswordPolicy)
+
+		_, err = person.NewPasswordHashing("bcrypt", 99, cheapArgon2)
+		assert.ErrorIs(t, err, person.ErrInvalidPasswordPolicy)
+
+		_, err = person.NewPasswordHashing("argon2id", bcrypt.DefaultCost, person.Argon2Params{Memory: 64})
+		assert.ErrorIs(t, err, person.ErrInvalidPasswordPolicy)
+	})
+}
+
+func TestPassword_Argon2id(t *testing.T) {
+	hashing, err := person.NewPasswordHashing("argon2id", bcrypt.MinCost, cheapArgon2)
+	require.NoError(t, err)
+	policy, err := person.NewPasswordPolicy(8, 128, nil, nil, hashing)
+	require.NoError(t, err)
+
+	t.Run("should hash in PHC format and compare", func(t *testing.T) {
+		// Act
+		password, err := person.NewPassword("Password123", policy)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(password.Hash(), "$argon2id$v=19$m=64,t=1,p=1$"))
+		assert.NoError(t, password.Compare("Password123"))
+		assert.Error(t, password.Compare("Password124"))
+	})
+
+	t.Run("should accept passwords longer than bcrypt allows", func(t *testing.T) {
+		// Arrange
+		long := strings.Repeat("a", 100)
+
+		// Act
+		password, err := person.NewPassword(long, policy)
+
+		// Assert
+		require.NoError(t, err)
+		assert.NoError(t, password.Compare(long))
+		assert.Error(t, password.Compare(long[:72]))
+	})
+
+	t.Run("should reject malformed hashes", func(t *testing.T) {
+		// Act
+		err := person.NewPasswordFromHash("$argon2id$v=19$m=64$bad").Compare("Password123")
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
+
+func TestPasswordPolicy_NeedsRehash(t *testing.T) {
+	bcryptMin, err := person.NewPasswordHashing("bcrypt", bcrypt.MinCost, cheapArgon2)
+	require.NoError(t, err)
+	bcryptPolicy, err := person.NewPasswordPolicy(8, 72, nil, nil, bcryptMin)
+	require.NoError(t, err)
+	argon2id, err := person.NewPasswordHashing("argon2id", bcrypt.MinCost, cheapArgon2)
+	require.NoError(t, err)
+	argon2Policy, err := person.NewPasswordPolicy(8, 72, nil, nil, argon2id)
+	require.NoError(t, err)
+
+	bcryptHash, err := person.NewPassword("Password123", bcryptPolicy)
+	require.NoError(t, err)
+	argon2Hash, err := person.NewPassword("Password123", argon2Policy)
+	require.NoError(t, err)
+
+	t.Run("should keep hashes matching the policy", func(t *testing.T) {
+		// Act & Assert
+		assert.False(t, bcryptPolicy.NeedsRehash(bcryptHash))
+		assert.False(t, argon2Policy.NeedsRehash(argon2Hash))
+	})
+
+	t.Run("should rehash on another algorithm", func(t *testing.T) {
+		// Act & Assert
+		assert.True(t, bcryptPolicy.NeedsRehash(argon2Hash))
+		assert.True(t, argon2Policy.NeedsRehash(bcryptHash))
+	})
+
+	t.Run("should rehash on another cost", func(t *testing.T) {
+		// Arrange
+		stronger := argon2id
+		stronger.Argon2.Iterations = 2
+		strongerPolicy, err := person.NewPasswordPolicy(8, 72, nil, nil, stronger)
+		require.NoError(t, err)
+
+		// Act & Assert
+		assert.True(t, person.DefaultPasswordPolicy().NeedsRehash(bcryptHash))
+		assert.True(t, strongerPolicy.NeedsRehash(argon2Hash))
+	})
+}
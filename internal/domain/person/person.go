@@ -1,7 +1,11 @@
 package person
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"time"
+
+	"github.com/cropflow/api/internal/domain/lockout"
 )
 
 // Person represents a person aggregate root
@@ -12,10 +16,15 @@ type Person struct {
 	role      Role
 	createdAt time.Time
 	updatedAt time.Time
+
+	// loginStatus tracks failed logins and the lockout they caused
+	loginStatus lockout.Status
+
+	pendingRoleChanges []RoleChange
 }
 
 // NewPerson creates a new Person with validation (Factory Method)
-func NewPerson(username, plainPassword, roleStr string) (*Person, error) {
+func NewPerson(username, plainPassword, roleStr string, policy PasswordPolicy) (*Person, error) {
 	if username == "" {
 		return nil, ErrInvalidUsername
 	}
@@ -24,7 +33,7 @@ func NewPerson(username, plainPassword, roleStr string) (*Person, error) {
 		return nil, ErrInvalidUsername
 	}
 
-	password, err := NewPassword(plainPassword)
+	password, err := NewPassword(plainPassword, policy)
 	if err != nil {
 		return nil, err
 	}
@@ -44,15 +53,48 @@ func NewPerson(username, plainPassword, roleStr string) (*Person, error) {
 	}, nil
 }
 
-// Restore reconstructs a Person from persistence (used by repository)
-func Restore(id int64, username, passwordHash string, role Role, createdAt, updatedAt time.Time) *Person {
+// NewExternalPerson creates a Person who logs in through an identity provider.
+// The password is random and never disclosed, so until an administrator
+// issues a reset the person cannot log in with a password.
+func NewExternalPerson(username, roleStr string, policy PasswordPolicy) (*Person, error) {
+	if len(username) < 3 {
+		return nil, ErrInvalidUsername
+	}
+
+	role, err := NewRole(roleStr)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	password, err := policy.Hashing.hash(base64.RawURLEncoding.EncodeToString(buf))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
 	return &Person{
-		id:        id,
 		username:  username,
-		password:  NewPasswordFromHash(passwordHash),
+		password:  password,
 		role:      role,
-		createdAt: createdAt,
-		updatedAt: updatedAt,
+		createdAt: now,
+		updatedAt: now,
+	}, nil
+}
+
+// Restore reconstructs a Person from persistence (used by repository)
+func Restore(id int64, username, passwordHash string, role Role, createdAt, updatedAt time.Time, loginStatus lockout.Status) *Person {
+	return &Person{
+		id:          id,
+		username:    username,
+		password:    NewPasswordFromHash(passwordHash),
+		role:        role,
+		createdAt:   createdAt,
+		updatedAt:   updatedAt,
+		loginStatus: loginStatus,
 	}
 }
 
@@ -81,6 +123,10 @@ func (p *Person) UpdatedAt() time.Time {
 	return p.updatedAt
 }
 
+func (p *Person) LoginStatus() lockout.Status {
+	return p.loginStatus
+}
+
 // SetID is used by repository after insertion
 func (p *Person) SetID(id int64) {
 	p.id = id
@@ -96,8 +142,28 @@ func (p *Person) Authenticate(plainPassword string) error {
 	return nil
 }
 
+// RecordFailedLogin counts a wrong password, locking the account once the policy says so
+func (p *Person) RecordFailedLogin(policy lockout.Policy, now time.Time) {
+	p.loginStatus = p.loginStatus.RecordFailure(policy, now)
+}
+
+// Unlock clears the failed logins and any lockout, after a successful login or by an administrator
+func (p *Person) Unlock() {
+	p.loginStatus = lockout.Status{}
+}
+
+// ChangeUsername changes the person's username with validation
+func (p *Person) ChangeUsername(newUsername string) error {
+	if len(newUsername) < 3 {
+		return ErrInvalidUsername
+	}
+	p.username = newUsername
+	p.updatedAt = time.Now()
+	return nil
+}
+
 // ChangePassword changes the person's password
-func (p *Person) ChangePassword(oldPassword, newPassword string) error {
+func (p *Person) ChangePassword(oldPassword, newPassword string, policy PasswordPolicy) error {
 	// Verify old password
 	if err := p.password.Compare(oldPassword); err != nil {
 		return ErrInvalidCredentials
@@ -109,18 +175,53 @@ func (p *Person) ChangePassword(oldPassword, newPassword string) error {
 	}
 
 	// Create new password
-	newPass, err := NewPassword(newPassword)
+	newPass, err := NewPassword(newPassword, policy)
+	if err != nil {
+		return err
+	}
+
+	p.password = newPass
+	p.updatedAt = time.Now()
+	return nil
+}
+
+// ResetPassword replaces the password without the old one, after the person
+// proved control of the account another way, e.g. with a reset token. It also
+// clears any login lockout.
+func (p *Person) ResetPassword(newPassword string, policy PasswordPolicy) error {
+	newPass, err := NewPassword(newPassword, policy)
 	if err != nil {
 		return err
 	}
 
 	p.password = newPass
+	p.loginStatus = lockout.Status{}
 	p.updatedAt = time.Now()
 	return nil
 }
 
-// PromoteToRole changes the person's role
-func (p *Person) PromoteToRole(newRoleStr string) error {
+// UpgradePassword rehashes the password with the policy's algorithm and cost
+// when the stored hash is outdated. It needs the plain password, so it is
+// called after a successful login; a password that no longer matches, e.g.
+// because it was changed meanwhile, is left alone.
+func (p *Person) UpgradePassword(plainPassword string, policy PasswordPolicy) error {
+	if !policy.NeedsRehash(p.password) || p.password.Compare(plainPassword) != nil {
+		return nil
+	}
+
+	upgraded, err := policy.Hashing.hash(plainPassword)
+	if err != nil {
+		return err
+	}
+	p.password = upgraded
+	p.updatedAt = time.Now()
+	return nil
+}
+
+// PromoteToRole changes the person's role, recording who changed it
+// (0 when the system did). Whether the last admin may lose the role is up to
+// the caller, which knows how many admins there are.
+func (p *Person) PromoteToRole(newRoleStr string, changedBy int64) error {
 	newRole, err := NewRole(newRoleStr)
 	if err != nil {
 		return err
@@ -130,14 +231,26 @@ func (p *Person) PromoteToRole(newRoleStr string) error {
 		return ErrSameRole
 	}
 
+	now := time.Now()
+	p.pendingRoleChanges = append(p.pendingRoleChanges, RoleChange{
+		from:      p.role,
+		to:        newRole,
+		changedBy: changedBy,
+		changedAt: now,
+	})
 	p.role = newRole
-	p.updatedAt = time.Now()
+	p.updatedAt = now
 	return nil
 }
 
-// CanAccessResource checks if this person can access a specific resource
-func (p *Person) CanAccessResource(resource string) bool {
-	return p.role.CanAccess(resource)
+// PendingRoleChanges returns the role changes made since the person was loaded
+func (p *Person) PendingRoleChanges() []RoleChange {
+	return p.pendingRoleChanges
+}
+
+// ClearPendingRoleChanges is used by repository once the role changes are persisted
+func (p *Person) ClearPendingRoleChanges() {
+	p.pendingRoleChanges = nil
 }
 
 // HasRole checks if person has a specific role
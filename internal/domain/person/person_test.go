@@ -1,9 +1,11 @@
 package person_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/cropflow/api/internal/domain/lockout"
 	"github.com/cropflow/api/internal/domain/person"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -17,7 +19,7 @@ func TestNewPerson(t *testing.T) {
 		role := "ROLE_USER"
 
 		// Act
-		p, err := person.NewPerson(username, plainPassword, role)
+		p, err := person.NewPerson(username, plainPassword, role, person.DefaultPasswordPolicy())
 
 		// Assert
 		require.NoError(t, err)
@@ -36,7 +38,7 @@ func TestNewPerson(t *testing.T) {
 		role := "ROLE_USER"
 
 		// Act
-		p, err := person.NewPerson(username, plainPassword, role)
+		p, err := person.NewPerson(username, plainPassword, role, person.DefaultPasswordPolicy())
 
 		// Assert
 		assert.Error(t, err)
@@ -51,7 +53,7 @@ func TestNewPerson(t *testing.T) {
 		role := "ROLE_USER"
 
 		// Act
-		p, err := person.NewPerson(username, plainPassword, role)
+		p, err := person.NewPerson(username, plainPassword, role, person.DefaultPasswordPolicy())
 
 		// Assert
 		assert.Error(t, err)
@@ -65,7 +67,7 @@ func TestNewPerson(t *testing.T) {
 		role := "INVALID_ROLE"
 
 		// Act
-		p, err := person.NewPerson(username, plainPassword, role)
+		p, err := person.NewPerson(username, plainPassword, role, person.DefaultPasswordPolicy())
 
 		// Assert
 		assert.Error(t, err)
@@ -79,7 +81,7 @@ func TestNewPerson(t *testing.T) {
 		role := "ROLE_MANAGER"
 
 		// Act
-		p, err := person.NewPerson(username, plainPassword, role)
+		p, err := person.NewPerson(username, plainPassword, role, person.DefaultPasswordPolicy())
 
 		// Assert
 		require.NoError(t, err)
@@ -93,7 +95,7 @@ func TestNewPerson(t *testing.T) {
 		role := "ROLE_ADMIN"
 
 		// Act
-		p, err := person.NewPerson(username, plainPassword, role)
+		p, err := person.NewPerson(username, plainPassword, role, person.DefaultPasswordPolicy())
 
 		// Assert
 		require.NoError(t, err)
@@ -101,11 +103,33 @@ func TestNewPerson(t *testing.T) {
 	})
 }
 
+func TestNewExternalPerson(t *testing.T) {
+	t.Run("should create a valid person without a usable password", func(t *testing.T) {
+		// Act
+		p, err := person.NewExternalPerson("jane.doe", "ROLE_MANAGER", person.DefaultPasswordPolicy())
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, p.IsValid())
+		assert.Equal(t, person.RoleManager, p.Role())
+		assert.Equal(t, person.ErrInvalidCredentials, p.Authenticate(""))
+	})
+
+	t.Run("should validate username and role", func(t *testing.T) {
+		// Act & Assert
+		_, err := person.NewExternalPerson("jd", "ROLE_USER", person.DefaultPasswordPolicy())
+		assert.Equal(t, person.ErrInvalidUsername, err)
+
+		_, err = person.NewExternalPerson("jane.doe", "ROLE_ROOT", person.DefaultPasswordPolicy())
+		assert.Equal(t, person.ErrInvalidRole, err)
+	})
+}
+
 func TestPerson_Authenticate(t *testing.T) {
 	t.Run("should authenticate with correct password", func(t *testing.T) {
 		// Arrange
 		plainPassword := "SecurePassword123"
-		p, _ := person.NewPerson("john_doe", plainPassword, "ROLE_USER")
+		p, _ := person.NewPerson("john_doe", plainPassword, "ROLE_USER", person.DefaultPasswordPolicy())
 
 		// Act
 		err := p.Authenticate(plainPassword)
@@ -117,7 +141,7 @@ func TestPerson_Authenticate(t *testing.T) {
 	t.Run("should fail authentication with incorrect password", func(t *testing.T) {
 		// Arrange
 		plainPassword := "SecurePassword123"
-		p, _ := person.NewPerson("john_doe", plainPassword, "ROLE_USER")
+		p, _ := person.NewPerson("john_doe", plainPassword, "ROLE_USER", person.DefaultPasswordPolicy())
 
 		// Act
 		err := p.Authenticate("WrongPassword")
@@ -133,12 +157,12 @@ func TestPerson_ChangePassword(t *testing.T) {
 		// Arrange
 		oldPassword := "OldPassword123"
 		newPassword := "NewPassword123"
-		p, _ := person.NewPerson("john_doe", oldPassword, "ROLE_USER")
+		p, _ := person.NewPerson("john_doe", oldPassword, "ROLE_USER", person.DefaultPasswordPolicy())
 		originalUpdatedAt := p.UpdatedAt()
 		time.Sleep(1 * time.Millisecond)
 
 		// Act
-		err := p.ChangePassword(oldPassword, newPassword)
+		err := p.ChangePassword(oldPassword, newPassword, person.DefaultPasswordPolicy())
 
 		// Assert
 		require.NoError(t, err)
@@ -150,10 +174,10 @@ func TestPerson_ChangePassword(t *testing.T) {
 	t.Run("should return error when new password is empty", func(t *testing.T) {
 		// Arrange
 		oldPassword := "OldPassword123"
-		p, _ := person.NewPerson("john_doe", oldPassword, "ROLE_USER")
+		p, _ := person.NewPerson("john_doe", oldPassword, "ROLE_USER", person.DefaultPasswordPolicy())
 
 		// Act
-		err := p.ChangePassword(oldPassword, "")
+		err := p.ChangePassword(oldPassword, "", person.DefaultPasswordPolicy())
 
 		// Assert
 		assert.Error(t, err)
@@ -162,10 +186,10 @@ func TestPerson_ChangePassword(t *testing.T) {
 	t.Run("should return error when old password is incorrect", func(t *testing.T) {
 		// Arrange
 		oldPassword := "OldPassword123"
-		p, _ := person.NewPerson("john_doe", oldPassword, "ROLE_USER")
+		p, _ := person.NewPerson("john_doe", oldPassword, "ROLE_USER", person.DefaultPasswordPolicy())
 
 		// Act
-		err := p.ChangePassword("WrongPassword", "NewPassword123")
+		err := p.ChangePassword("WrongPassword", "NewPassword123", person.DefaultPasswordPolicy())
 
 		// Assert
 		assert.Error(t, err)
@@ -173,15 +197,79 @@ func TestPerson_ChangePassword(t *testing.T) {
 	})
 }
 
+func TestPerson_ResetPassword(t *testing.T) {
+	t.Run("should replace the password and clear the lockout", func(t *testing.T) {
+		// Arrange
+		policy, err := lockout.NewPolicy(1, time.Minute, time.Hour, 24*time.Hour)
+		require.NoError(t, err)
+		p, _ := person.NewPerson("john_doe", "OldPassword123", "ROLE_USER", person.DefaultPasswordPolicy())
+		p.RecordFailedLogin(policy, time.Now())
+
+		// Act
+		err = p.ResetPassword("NewPassword123", person.DefaultPasswordPolicy())
+
+		// Assert
+		require.NoError(t, err)
+		assert.NoError(t, p.Authenticate("NewPassword123"))
+		assert.True(t, p.LoginStatus().IsZero())
+	})
+
+	t.Run("should validate the new password", func(t *testing.T) {
+		// Arrange
+		p, _ := person.NewPerson("john_doe", "OldPassword123", "ROLE_USER", person.DefaultPasswordPolicy())
+
+		// Act
+		err := p.ResetPassword("short", person.DefaultPasswordPolicy())
+
+		// Assert
+		assert.Equal(t, person.ErrInvalidPassword, err)
+		assert.NoError(t, p.Authenticate("OldPassword123"))
+	})
+}
+
+func TestPerson_UpgradePassword(t *testing.T) {
+	argon2id, err := person.NewPasswordHashing("argon2id", 0, person.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1})
+	require.NoError(t, err)
+	policy, err := person.NewPasswordPolicy(8, 128, nil, nil, argon2id)
+	require.NoError(t, err)
+
+	t.Run("should rehash an outdated hash with the policy algorithm", func(t *testing.T) {
+		// Arrange
+		p, _ := person.NewPerson("john_doe", "Password123", "ROLE_USER", person.DefaultPasswordPolicy())
+
+		// Act
+		err := p.UpgradePassword("Password123", policy)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(p.Password().Hash(), "$argon2id$"))
+		assert.False(t, policy.NeedsRehash(p.Password()))
+		assert.NoError(t, p.Authenticate("Password123"))
+	})
+
+	t.Run("should keep the hash when the password does not match", func(t *testing.T) {
+		// Arrange
+		p, _ := person.NewPerson("john_doe", "Password123", "ROLE_USER", person.DefaultPasswordPolicy())
+		hash := p.Password().Hash()
+
+		// Act
+		err := p.UpgradePassword("Changed12345", policy)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, hash, p.Password().Hash())
+	})
+}
+
 func TestPerson_PromoteToRole(t *testing.T) {
 	t.Run("should promote to role successfully", func(t *testing.T) {
 		// Arrange
-		p, _ := person.NewPerson("john_doe", "Password123", "ROLE_USER")
+		p, _ := person.NewPerson("john_doe", "Password123", "ROLE_USER", person.DefaultPasswordPolicy())
 		originalUpdatedAt := p.UpdatedAt()
 		time.Sleep(1 * time.Millisecond)
 
 		// Act
-		err := p.PromoteToRole("ROLE_MANAGER")
+		err := p.PromoteToRole("ROLE_MANAGER", 7)
 
 		// Assert
 		require.NoError(t, err)
@@ -189,12 +277,43 @@ func TestPerson_PromoteToRole(t *testing.T) {
 		assert.True(t, p.UpdatedAt().After(originalUpdatedAt))
 	})
 
+	t.Run("should record the change and who made it", func(t *testing.T) {
+		// Arrange
+		p, _ := person.NewPerson("john_doe", "Password123", "ROLE_USER", person.DefaultPasswordPolicy())
+
+		// Act
+		require.NoError(t, p.PromoteToRole("ROLE_ADMIN", 7))
+		require.NoError(t, p.PromoteToRole("ROLE_MANAGER", 0))
+
+		// Assert
+		changes := p.PendingRoleChanges()
+		require.Len(t, changes, 2)
+		assert.Equal(t, person.RoleUser, changes[0].From())
+		assert.Equal(t, person.RoleAdmin, changes[0].To())
+		assert.Equal(t, int64(7), changes[0].ChangedBy())
+		assert.Equal(t, person.RoleAdmin, changes[1].From())
+		assert.Equal(t, person.RoleManager, changes[1].To())
+		assert.Zero(t, changes[1].ChangedBy())
+	})
+
+	t.Run("should return ErrSameRole without recording a change", func(t *testing.T) {
+		// Arrange
+		p, _ := person.NewPerson("john_doe", "Password123", "ROLE_USER", person.DefaultPasswordPolicy())
+
+		// Act
+		err := p.PromoteToRole("ROLE_USER", 7)
+
+		// Assert
+		assert.ErrorIs(t, err, person.ErrSameRole)
+		assert.Empty(t, p.PendingRoleChanges())
+	})
+
 	t.Run("should return error for invalid role", func(t *testing.T) {
 		// Arrange
-		p, _ := person.NewPerson("john_doe", "Password123", "ROLE_USER")
+		p, _ := person.NewPerson("john_doe", "Password123", "ROLE_USER", person.DefaultPasswordPolicy())
 
 		// Act
-		err := p.PromoteToRole("INVALID_ROLE")
+		err := p.PromoteToRole("INVALID_ROLE", 7)
 
 		// Assert
 		assert.Error(t, err)
@@ -205,7 +324,7 @@ func TestPerson_PromoteToRole(t *testing.T) {
 func TestPerson_IsValid(t *testing.T) {
 	t.Run("should return true for valid person", func(t *testing.T) {
 		// Arrange
-		p, _ := person.NewPerson("john_doe", "Password123", "ROLE_USER")
+		p, _ := person.NewPerson("john_doe", "Password123", "ROLE_USER", person.DefaultPasswordPolicy())
 
 		// Act
 		isValid := p.IsValid()
@@ -227,7 +346,7 @@ func TestRestore(t *testing.T) {
 		updatedAt := time.Now()
 
 		// Act
-		p := person.Restore(id, username, passwordHash, role, createdAt, updatedAt)
+		p := person.Restore(id, username, passwordHash, role, createdAt, updatedAt, lockout.Status{})
 
 		// Assert
 		assert.NotNil(t, p)
@@ -243,7 +362,7 @@ func TestRestore(t *testing.T) {
 func TestPerson_SetID(t *testing.T) {
 	t.Run("should set person ID", func(t *testing.T) {
 		// Arrange
-		p, _ := person.NewPerson("john_doe", "Password123", "ROLE_USER")
+		p, _ := person.NewPerson("john_doe", "Password123", "ROLE_USER", person.DefaultPasswordPolicy())
 		assert.Zero(t, p.ID())
 
 		// Act
@@ -253,3 +372,25 @@ func TestPerson_SetID(t *testing.T) {
 		assert.Equal(t, int64(42), p.ID())
 	})
 }
+
+func TestPerson_RecordFailedLogin(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+	policy, err := lockout.NewPolicy(2, time.Minute, time.Hour, 24*time.Hour)
+	require.NoError(t, err)
+
+	t.Run("should lock the person and unlock it again", func(t *testing.T) {
+		// Arrange
+		p, _ := person.NewPerson("john_doe", "Password123", "ROLE_USER", person.DefaultPasswordPolicy())
+
+		// Act
+		p.RecordFailedLogin(policy, now)
+		p.RecordFailedLogin(policy, now)
+		locked := p.LoginStatus().IsLocked(now)
+		p.Unlock()
+
+		// Assert
+		assert.True(t, locked)
+		assert.True(t, p.LoginStatus().IsZero())
+		assert.False(t, p.LoginStatus().IsLocked(now))
+	})
+}
@@ -1,13 +1,24 @@
 package person
 
-import "context"
+import (
+	"context"
+
+	"github.com/cropflow/api/internal/domain/query"
+)
 
 // Repository defines the interface for person persistence (Port)
 type Repository interface {
 	Save(ctx context.Context, person *Person) error
 	FindByID(ctx context.Context, id int64) (*Person, error)
 	FindByUsername(ctx context.Context, username string) (*Person, error)
-	FindAll(ctx context.Context) ([]*Person, error)
+	// Update loads the person locked against concurrent changes, applies fn and saves it
+	Update(ctx context.Context, id int64, fn func(*Person) error) (*Person, error)
+	// UpdateRole is Update for role changes: the admins are locked too and fn is
+	// given how many there are, so concurrent changes cannot demote them all
+	UpdateRole(ctx context.Context, id int64, fn func(p *Person, admins int) error) (*Person, error)
+	List(ctx context.Context, filter Filter, page query.Page) (query.Result[*Person], error)
+	// Delete removes the person, failing with ErrLastAdmin for the last admin
 	Delete(ctx context.Context, id int64) error
 	UsernameExists(ctx context.Context, username string) (bool, error)
+	FindRoleChanges(ctx context.Context, personID int64) ([]RoleChange, error)
 }
@@ -1,14 +1,12 @@
 package person
 
-import "errors"
-
 // Role represents a user role value object
 type Role string
 
 const (
-	RoleUser    Role = "USER"
-	RoleManager Role = "MANAGER"
-	RoleAdmin   Role = "ADMIN"
+	RoleUser    Role = "ROLE_USER"
+	RoleManager Role = "ROLE_MANAGER"
+	RoleAdmin   Role = "ROLE_ADMIN"
 )
 
 // NewRole creates a new Role with validation
@@ -18,7 +16,7 @@ func NewRole(value string) (Role, error) {
 	case RoleUser, RoleManager, RoleAdmin:
 		return role, nil
 	default:
-		return "", errors.New("invalid role: must be USER, MANAGER, or ADMIN")
+		return "", ErrInvalidRole
 	}
 }
 
@@ -37,38 +35,17 @@ func (r Role) HasPermission(required Role) bool {
 	return hierarchy[r] >= hierarchy[required]
 }
 
-// CanAccess checks if this role can access a specific resource
-func (r Role) CanAccess(resource string) bool {
-	permissions := map[string][]Role{
-		"farms":       {RoleUser, RoleManager, RoleAdmin},
-		"crops":       {RoleManager, RoleAdmin},
-		"fertilizers": {RoleAdmin},
-	}
-
-	allowedRoles, exists := permissions[resource]
-	if !exists {
-		return false
-	}
-
-	for _, allowed := range allowedRoles {
-		if r == allowed {
-			return true
-		}
-	}
-	return false
-}
-
-// IsUser checks if role is USER
+// IsUser checks if role is ROLE_USER
 func (r Role) IsUser() bool {
 	return r == RoleUser
 }
 
-// IsManager checks if role is MANAGER
+// IsManager checks if role is ROLE_MANAGER
 func (r Role) IsManager() bool {
 	return r == RoleManager
 }
 
-// IsAdmin checks if role is ADMIN
+// IsAdmin checks if role is ROLE_ADMIN
 func (r Role) IsAdmin() bool {
 	return r == RoleAdmin
 }
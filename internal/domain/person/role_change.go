@@ -0,0 +1,42 @@
+package person
+
+import "time"
+
+// RoleChange represents a recorded change of a person's role
+type RoleChange struct {
+	from      Role
+	to        Role
+	changedBy int64
+	changedAt time.Time
+}
+
+// RestoreRoleChange reconstructs a RoleChange from persistence (used by repository)
+func RestoreRoleChange(from, to Role, changedBy int64, changedAt time.Time) RoleChange {
+	return RoleChange{
+		from:      from,
+		to:        to,
+		changedBy: changedBy,
+		changedAt: changedAt,
+	}
+}
+
+// From returns the role before the change
+func (c RoleChange) From() Role {
+	return c.from
+}
+
+// To returns the role after the change
+func (c RoleChange) To() Role {
+	return c.to
+}
+
+// ChangedBy returns the ID of the person who made the change, or 0 when the
+// system made it, e.g. on an identity provider login or the admin bootstrap
+func (c RoleChange) ChangedBy() int64 {
+	return c.changedBy
+}
+
+// ChangedAt returns when the change was made
+func (c RoleChange) ChangedAt() time.Time {
+	return c.changedAt
+}
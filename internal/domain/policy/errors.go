@@ -0,0 +1,8 @@
+package policy
+
+import "errors"
+
+var (
+	ErrUnknownResource = errors.New("invalid policy: unknown resource")
+	ErrUnknownAction   = errors.New("invalid policy: unknown action")
+)
@@ -0,0 +1,104 @@
+package policy
+
+import (
+	"fmt"
+
+	"github.com/cropflow/api/internal/domain/person"
+)
+
+// Resource identifies a group of endpoints protected by the policy
+type Resource string
+
+const (
+	Persons      Resource = "persons"
+	Farms        Resource = "farms"
+	Members      Resource = "members"
+	Crops        Resource = "crops"
+	Harvests     Resource = "harvests"
+	Applications Resource = "applications"
+	Fertilizers  Resource = "fertilizers"
+	Sessions     Resource = "sessions"
+	APIKeys      Resource = "apiKeys"
+	Audit        Resource = "audit"
+)
+
+// Action identifies what is done to a resource
+type Action string
+
+const (
+	Read   Action = "read"
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// Actions lists every action in the order they are documented
+var Actions = []Action{Read, Create, Update, Delete}
+
+// DefaultRules holds the minimum role required for each action on each resource.
+// Roles are compared with person.Role.HasPermission, so a rule granted to
+// ROLE_USER is also granted to ROLE_MANAGER and ROLE_ADMIN.
+var DefaultRules = map[Resource]map[Action]person.Role{
+	Persons:      {Read: person.RoleAdmin, Update: person.RoleAdmin, Delete: person.RoleAdmin},
+	Farms:        {Read: person.RoleUser, Create: person.RoleManager, Update: person.RoleManager, Delete: person.RoleManager},
+	Members:      {Read: person.RoleUser, Create: person.RoleManager, Update: person.RoleManager, Delete: person.RoleUser},
+	Crops:        {Read: person.RoleUser, Create: person.RoleManager, Update: person.RoleManager, Delete: person.RoleManager},
+	Harvests:     {Read: person.RoleUser, Create: person.RoleManager, Update: person.RoleManager, Delete: person.RoleManager},
+	Applications: {Read: person.RoleUser, Create: person.RoleManager, Update: person.RoleManager, Delete: person.RoleManager},
+	Fertilizers:  {Read: person.RoleUser, Create: person.RoleAdmin, Update: person.RoleAdmin, Delete: person.RoleAdmin},
+	Sessions:     {Delete: person.RoleAdmin},
+	APIKeys:      {Read: person.RoleUser, Create: person.RoleUser, Delete: person.RoleUser},
+	Audit:        {Read: person.RoleAdmin},
+}
+
+// Policy decides which roles may perform each action on each resource
+type Policy struct {
+	rules map[Resource]map[Action]person.Role
+}
+
+// New creates a Policy from the default rules overridden by the given ones,
+// e.g. {"fertilizers": {"create": "ROLE_MANAGER"}}
+func New(overrides map[string]map[string]string) (*Policy, error) {
+	rules := make(map[Resource]map[Action]person.Role, len(DefaultRules))
+	for resource, actions := range DefaultRules {
+		rules[resource] = make(map[Action]person.Role, len(actions))
+		for action, role := range actions {
+			rules[resource][action] = role
+		}
+	}
+
+	for resource, actions := range overrides {
+		r := Resource(resource)
+		if _, ok := rules[r]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownResource, resource)
+		}
+		for action, value := range actions {
+			a := Action(action)
+			if _, ok := rules[r][a]; !ok {
+				return nil, fmt.Errorf("%w: %s.%s", ErrUnknownAction, resource, action)
+			}
+			role, err := person.NewRole(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s.%s", err, resource, action)
+			}
+			rules[r][a] = role
+		}
+	}
+	return &Policy{rules: rules}, nil
+}
+
+// Allows checks if the role may perform the action on the resource.
+// Anything the policy does not mention is denied.
+func (p *Policy) Allows(role person.Role, resource Resource, action Action) bool {
+	required, ok := p.rules[resource][action]
+	if !ok {
+		return false
+	}
+	return role.HasPermission(required)
+}
+
+// RequiredRole returns the minimum role for the action on the resource
+func (p *Policy) RequiredRole(resource Resource, action Action) (person.Role, bool) {
+	role, ok := p.rules[resource][action]
+	return role, ok
+}
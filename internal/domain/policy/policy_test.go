@@ -0,0 +1,75 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_Allows(t *testing.T) {
+	t.Run("should grant an action to the minimum role and every role above it", func(t *testing.T) {
+		// Arrange
+		p, err := policy.New(nil)
+		require.NoError(t, err)
+
+		// Assert
+		assert.False(t, p.Allows(person.RoleUser, policy.Farms, policy.Create))
+		assert.True(t, p.Allows(person.RoleManager, policy.Farms, policy.Create))
+		assert.True(t, p.Allows(person.RoleAdmin, policy.Farms, policy.Create))
+	})
+
+	t.Run("should deny unknown roles, resources and actions", func(t *testing.T) {
+		// Arrange
+		p, err := policy.New(nil)
+		require.NoError(t, err)
+
+		// Assert
+		assert.False(t, p.Allows(person.Role("USER"), policy.Farms, policy.Read))
+		assert.False(t, p.Allows(person.RoleAdmin, policy.Resource("reports"), policy.Read))
+		assert.False(t, p.Allows(person.RoleAdmin, policy.Persons, policy.Create))
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should override the default rules", func(t *testing.T) {
+		// Act
+		p, err := policy.New(map[string]map[string]string{
+			"fertilizers": {"create": "ROLE_MANAGER"},
+		})
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, p.Allows(person.RoleManager, policy.Fertilizers, policy.Create))
+		assert.False(t, p.Allows(person.RoleManager, policy.Fertilizers, policy.Delete))
+	})
+
+	t.Run("should not change the default rules", func(t *testing.T) {
+		// Arrange
+		_, err := policy.New(map[string]map[string]string{
+			"farms": {"read": "ROLE_ADMIN"},
+		})
+		require.NoError(t, err)
+
+		// Act
+		p, err := policy.New(nil)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, p.Allows(person.RoleUser, policy.Farms, policy.Read))
+	})
+
+	t.Run("should reject unknown resources, actions and roles", func(t *testing.T) {
+		// Act & Assert
+		_, err := policy.New(map[string]map[string]string{"reports": {"read": "ROLE_USER"}})
+		assert.ErrorIs(t, err, policy.ErrUnknownResource)
+
+		_, err = policy.New(map[string]map[string]string{"farms": {"archive": "ROLE_USER"}})
+		assert.ErrorIs(t, err, policy.ErrUnknownAction)
+
+		_, err = policy.New(map[string]map[string]string{"farms": {"read": "USER"}})
+		assert.ErrorIs(t, err, person.ErrInvalidRole)
+	})
+}
@@ -0,0 +1,55 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// Cursor identifies the boundary row of a page for keyset pagination.
+// Clients only ever see its opaque encoded form.
+type Cursor struct {
+	Sort     Sort
+	Value    string
+	ID       int64
+	Backward bool
+}
+
+type cursorPayload struct {
+	Field     string    `json:"f"`
+	Direction Direction `json:"d"`
+	Value     string    `json:"v"`
+	ID        int64     `json:"i"`
+	Backward  bool      `json:"b,omitempty"`
+}
+
+// Encode returns the opaque representation of the cursor
+func (c Cursor) Encode() string {
+	payload, _ := json.Marshal(cursorPayload{
+		Field:     c.Sort.Field,
+		Direction: c.Sort.Direction,
+		Value:     c.Value,
+		ID:        c.ID,
+		Backward:  c.Backward,
+	})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// DecodeCursor parses an opaque cursor issued by Encode
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{
+		Sort:     Sort{Field: payload.Field, Direction: payload.Direction},
+		Value:    payload.Value,
+		ID:       payload.ID,
+		Backward: payload.Backward,
+	}, nil
+}
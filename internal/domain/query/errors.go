@@ -0,0 +1,10 @@
+package query
+
+import "errors"
+
+var (
+	ErrInvalidLimit     = errors.New("invalid limit: must be between 1 and 500")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidSort      = errors.New("invalid sort field")
+	ErrInvalidDirection = errors.New("invalid sort direction: must be asc or desc")
+)
@@ -0,0 +1,91 @@
+package query
+
+const (
+	// DefaultLimit is the page size used when the client does not ask for one
+	DefaultLimit = 50
+	// MaxLimit is the largest page size a client may request
+	MaxLimit = 500
+)
+
+// Direction represents a sort direction
+type Direction string
+
+const (
+	Asc  Direction = "asc"
+	Desc Direction = "desc"
+)
+
+// Sort represents the field and direction a list is ordered by
+type Sort struct {
+	Field     string
+	Direction Direction
+}
+
+// Page represents a request for one page of a keyset-paginated list
+type Page struct {
+	Limit  int
+	Cursor *Cursor
+	Sort   Sort
+}
+
+// NewPage builds a Page from raw client input, validating it against the sort
+// fields allowed for the resource. The first allowed field is the default sort.
+func NewPage(limit int, cursor, sortField, direction string, allowedSorts []string) (Page, error) {
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	if limit < 1 || limit > MaxLimit {
+		return Page{}, ErrInvalidLimit
+	}
+
+	if sortField == "" {
+		sortField = allowedSorts[0]
+	}
+	if !contains(allowedSorts, sortField) {
+		return Page{}, ErrInvalidSort
+	}
+
+	dir := Direction(direction)
+	switch dir {
+	case "":
+		dir = Asc
+	case Asc, Desc:
+	default:
+		return Page{}, ErrInvalidDirection
+	}
+
+	page := Page{
+		Limit: limit,
+		Sort:  Sort{Field: sortField, Direction: dir},
+	}
+
+	if cursor != "" {
+		decoded, err := DecodeCursor(cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		// A cursor is only meaningful for the ordering it was issued for
+		if decoded.Sort != page.Sort {
+			return Page{}, ErrInvalidCursor
+		}
+		page.Cursor = decoded
+	}
+
+	return page, nil
+}
+
+// Result represents one page of items with the cursors to its neighbours
+type Result[T any] struct {
+	Items      []T
+	NextCursor string
+	PrevCursor string
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var allowedSorts = []string{"id", "name"}
+
+func TestNewPage(t *testing.T) {
+	t.Run("should apply defaults when parameters are empty", func(t *testing.T) {
+		// Act
+		page, err := query.NewPage(0, "", "", "", allowedSorts)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, query.DefaultLimit, page.Limit)
+		assert.Equal(t, query.Sort{Field: "id", Direction: query.Asc}, page.Sort)
+		assert.Nil(t, page.Cursor)
+	})
+
+	t.Run("should return error when limit is out of range", func(t *testing.T) {
+		// Act
+		_, errNegative := query.NewPage(-1, "", "", "", allowedSorts)
+		_, errTooLarge := query.NewPage(query.MaxLimit+1, "", "", "", allowedSorts)
+
+		// Assert
+		assert.Equal(t, query.ErrInvalidLimit, errNegative)
+		assert.Equal(t, query.ErrInvalidLimit, errTooLarge)
+	})
+
+	t.Run("should return error when sort field is not allowed", func(t *testing.T) {
+		// Act
+		_, err := query.NewPage(10, "", "password", "", allowedSorts)
+
+		// Assert
+		assert.Equal(t, query.ErrInvalidSort, err)
+	})
+
+	t.Run("should return error when direction is invalid", func(t *testing.T) {
+		// Act
+		_, err := query.NewPage(10, "", "name", "sideways", allowedSorts)
+
+		// Assert
+		assert.Equal(t, query.ErrInvalidDirection, err)
+	})
+
+	t.Run("should decode cursor issued for the same ordering", func(t *testing.T) {
+		// Arrange
+		sort := query.Sort{Field: "name", Direction: query.Desc}
+		cursor := query.Cursor{Sort: sort, Value: "Milho", ID: 42, Backward: true}
+
+		// Act
+		page, err := query.NewPage(10, cursor.Encode(), "name", "desc", allowedSorts)
+
+		// Assert
+		require.NoError(t, err)
+		require.NotNil(t, page.Cursor)
+		assert.Equal(t, cursor, *page.Cursor)
+	})
+
+	t.Run("should reject cursor issued for another ordering", func(t *testing.T) {
+		// Arrange
+		cursor := query.Cursor{Sort: query.Sort{Field: "name", Direction: query.Asc}, Value: "Milho", ID: 42}
+
+		// Act
+		_, err := query.NewPage(10, cursor.Encode(), "id", "asc", allowedSorts)
+
+		// Assert
+		assert.Equal(t, query.ErrInvalidCursor, err)
+	})
+
+	t.Run("should reject malformed cursor", func(t *testing.T) {
+		// Act
+		_, err := query.NewPage(10, "not-a-cursor!", "", "", allowedSorts)
+
+		// Assert
+		assert.Equal(t, query.ErrInvalidCursor, err)
+	})
+}
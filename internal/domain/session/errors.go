@@ -0,0 +1,13 @@
+package session
+
+import "errors"
+
+var (
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrSessionRevoked       = errors.New("session has been revoked")
+	ErrInvalidRefreshToken  = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired  = errors.New("refresh token has expired")
+	ErrRefreshTokenReused   = errors.New("refresh token was already used: session revoked")
+	ErrInvalidSessionPerson = errors.New("invalid session: person is required")
+	ErrInvalidTokenLifetime = errors.New("invalid refresh token lifetime: must be greater than zero")
+)
@@ -0,0 +1,91 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+// refreshTokenBytes is the amount of randomness in a refresh token
+const refreshTokenBytes = 32
+
+// RefreshToken represents an opaque token that can be exchanged once for a new
+// access token. Only its hash is stored.
+type RefreshToken struct {
+	id        int64
+	sessionID int64
+	hash      string
+	expiresAt time.Time
+	usedAt    *time.Time
+	createdAt time.Time
+}
+
+// RestoreRefreshToken reconstructs a RefreshToken from persistence (used by repository)
+func RestoreRefreshToken(id, sessionID int64, hash string, expiresAt time.Time, usedAt *time.Time, createdAt time.Time) *RefreshToken {
+	return &RefreshToken{
+		id:        id,
+		sessionID: sessionID,
+		hash:      hash,
+		expiresAt: expiresAt,
+		usedAt:    usedAt,
+		createdAt: createdAt,
+	}
+}
+
+// Getters (encapsulation)
+func (t *RefreshToken) ID() int64 {
+	return t.id
+}
+
+func (t *RefreshToken) SessionID() int64 {
+	return t.sessionID
+}
+
+func (t *RefreshToken) Hash() string {
+	return t.hash
+}
+
+func (t *RefreshToken) ExpiresAt() time.Time {
+	return t.expiresAt
+}
+
+// UsedAt returns when the token was exchanged, if it was
+func (t *RefreshToken) UsedAt() *time.Time {
+	return t.usedAt
+}
+
+func (t *RefreshToken) CreatedAt() time.Time {
+	return t.createdAt
+}
+
+// SetID is used by repository after insertion
+func (t *RefreshToken) SetID(id int64) {
+	t.id = id
+}
+
+// SetSessionID is used by repository when the session is inserted with its tokens
+func (t *RefreshToken) SetSessionID(sessionID int64) {
+	t.sessionID = sessionID
+}
+
+// IsExpired checks if the token can no longer be used at the given time
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.expiresAt)
+}
+
+// HashToken returns the stored form of a plain refresh token
+func HashToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateToken creates a random plain refresh token
+func generateToken() (string, error) {
+	buf := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
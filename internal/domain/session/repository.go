@@ -0,0 +1,18 @@
+package session
+
+import (
+	"context"
+	"time"
+)
+
+// Repository defines the interface for session persistence (Port)
+type Repository interface {
+	// Save stores a session; saving a rotation of a session revoked in the
+	// meantime fails with ErrSessionRevoked
+	Save(ctx context.Context, session *Session) error
+	FindByID(ctx context.Context, id int64) (*Session, error)
+	// FindByRefreshToken loads the session of a refresh token, locked for the
+	// rest of the transaction
+	FindByRefreshToken(ctx context.Context, hash string) (*Session, *RefreshToken, error)
+	RevokeAllByPersonID(ctx context.Context, personID int64, reason string, at time.Time) (int64, error)
+}
@@ -0,0 +1,153 @@
+package session
+
+import "time"
+
+// Session represents a login of a person. Every refresh rotates its refresh
+// token; presenting a token that was already exchanged means it leaked, so the
+// whole session is revoked.
+type Session struct {
+	id            int64
+	personID      int64
+	createdAt     time.Time
+	revokedAt     *time.Time
+	revokedReason string
+
+	pendingTokens []*RefreshToken
+	usedTokens    []*RefreshToken
+}
+
+// NewSession starts a session for a person and issues its first refresh token,
+// returned in plain form so it can be handed to the client (Factory Method)
+func NewSession(personID int64, tokenLifetime time.Duration, now time.Time) (*Session, string, error) {
+	if personID <= 0 {
+		return nil, "", ErrInvalidSessionPerson
+	}
+
+	s := &Session{
+		personID:  personID,
+		createdAt: now,
+	}
+	plain, err := s.issue(tokenLifetime, now)
+	if err != nil {
+		return nil, "", err
+	}
+	return s, plain, nil
+}
+
+// Restore reconstructs a Session from persistence (used by repository)
+func Restore(id, personID int64, createdAt time.Time, revokedAt *time.Time, revokedReason string) *Session {
+	return &Session{
+		id:            id,
+		personID:      personID,
+		createdAt:     createdAt,
+		revokedAt:     revokedAt,
+		revokedReason: revokedReason,
+	}
+}
+
+// Getters (encapsulation)
+func (s *Session) ID() int64 {
+	return s.id
+}
+
+func (s *Session) PersonID() int64 {
+	return s.personID
+}
+
+func (s *Session) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+// RevokedAt returns when the session was revoked, if it was
+func (s *Session) RevokedAt() *time.Time {
+	return s.revokedAt
+}
+
+func (s *Session) RevokedReason() string {
+	return s.revokedReason
+}
+
+// SetID is used by repository after insertion
+func (s *Session) SetID(id int64) {
+	s.id = id
+}
+
+// IsActive checks if the session has not been revoked
+func (s *Session) IsActive() bool {
+	return s.revokedAt == nil
+}
+
+// Business Methods
+
+// Rotate exchanges a refresh token of this session for a new one.
+// Reusing an exchanged token revokes the session.
+func (s *Session) Rotate(presented *RefreshToken, tokenLifetime time.Duration, now time.Time) (string, error) {
+	if presented.sessionID != s.id {
+		return "", ErrInvalidRefreshToken
+	}
+
+	if !s.IsActive() {
+		return "", ErrSessionRevoked
+	}
+
+	if presented.usedAt != nil {
+		s.Revoke("refresh token reuse", now)
+		return "", ErrRefreshTokenReused
+	}
+
+	if presented.IsExpired(now) {
+		return "", ErrRefreshTokenExpired
+	}
+
+	plain, err := s.issue(tokenLifetime, now)
+	if err != nil {
+		return "", err
+	}
+	used := now
+	presented.usedAt = &used
+	s.usedTokens = append(s.usedTokens, presented)
+	return plain, nil
+}
+
+// Revoke ends the session; revoking an already revoked session keeps the first reason
+func (s *Session) Revoke(reason string, now time.Time) {
+	if s.revokedAt != nil {
+		return
+	}
+	s.revokedAt = &now
+	s.revokedReason = reason
+}
+
+func (s *Session) issue(tokenLifetime time.Duration, now time.Time) (string, error) {
+	if tokenLifetime <= 0 {
+		return "", ErrInvalidTokenLifetime
+	}
+
+	plain, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+	s.pendingTokens = append(s.pendingTokens, &RefreshToken{
+		sessionID: s.id,
+		hash:      HashToken(plain),
+		expiresAt: now.Add(tokenLifetime),
+		createdAt: now,
+	})
+	return plain, nil
+}
+
+// PendingTokens returns the refresh tokens issued since the session was loaded
+func (s *Session) PendingTokens() []*RefreshToken {
+	return s.pendingTokens
+}
+
+// UsedTokens returns the refresh tokens exchanged since the session was loaded
+func (s *Session) UsedTokens() []*RefreshToken {
+	return s.usedTokens
+}
+
+// ClearPendingTokens is used by repository once the tokens are persisted
+func (s *Session) ClearPendingTokens() {
+	s.pendingTokens = nil
+	s.usedTokens = nil
+}
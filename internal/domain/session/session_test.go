@@ -0,0 +1,106 @@
+package session_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSession(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should issue a refresh token stored only as a hash", func(t *testing.T) {
+		// Act
+		s, plain, err := session.NewSession(7, time.Hour, now)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, s.IsActive())
+		require.Len(t, s.PendingTokens(), 1)
+		token := s.PendingTokens()[0]
+		assert.NotEmpty(t, plain)
+		assert.NotEqual(t, plain, token.Hash())
+		assert.Equal(t, session.HashToken(plain), token.Hash())
+		assert.Equal(t, now.Add(time.Hour), token.ExpiresAt())
+	})
+
+	t.Run("should validate person and lifetime", func(t *testing.T) {
+		// Act & Assert
+		_, _, err := session.NewSession(0, time.Hour, now)
+		assert.Equal(t, session.ErrInvalidSessionPerson, err)
+
+		_, _, err = session.NewSession(7, 0, now)
+		assert.Equal(t, session.ErrInvalidTokenLifetime, err)
+	})
+}
+
+func TestSession_Rotate(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should exchange the token for a new one", func(t *testing.T) {
+		// Arrange
+		s := session.Restore(1, 7, now, nil, "")
+		presented := session.RestoreRefreshToken(10, 1, session.HashToken("old"), now.Add(time.Hour), nil, now)
+
+		// Act
+		plain, err := s.Rotate(presented, time.Hour, now.Add(time.Minute))
+
+		// Assert
+		require.NoError(t, err)
+		assert.NotEqual(t, "old", plain)
+		assert.NotNil(t, presented.UsedAt())
+		assert.Len(t, s.PendingTokens(), 1)
+		assert.Len(t, s.UsedTokens(), 1)
+	})
+
+	t.Run("should revoke the session when a used token is presented again", func(t *testing.T) {
+		// Arrange
+		s := session.Restore(1, 7, now, nil, "")
+		usedAt := now.Add(time.Minute)
+		presented := session.RestoreRefreshToken(10, 1, session.HashToken("old"), now.Add(time.Hour), &usedAt, now)
+
+		// Act
+		_, err := s.Rotate(presented, time.Hour, now.Add(2*time.Minute))
+
+		// Assert
+		assert.Equal(t, session.ErrRefreshTokenReused, err)
+		assert.False(t, s.IsActive())
+		assert.Empty(t, s.PendingTokens())
+	})
+
+	t.Run("should reject expired tokens and revoked sessions", func(t *testing.T) {
+		// Arrange
+		active := session.Restore(1, 7, now, nil, "")
+		revokedAt := now
+		revoked := session.Restore(2, 7, now, &revokedAt, "logout")
+
+		// Act
+		_, expiredErr := active.Rotate(session.RestoreRefreshToken(10, 1, "h", now.Add(time.Hour), nil, now), time.Hour, now.Add(time.Hour))
+		_, revokedErr := revoked.Rotate(session.RestoreRefreshToken(11, 2, "h", now.Add(time.Hour), nil, now), time.Hour, now)
+
+		// Assert
+		assert.Equal(t, session.ErrRefreshTokenExpired, expiredErr)
+		assert.Equal(t, session.ErrSessionRevoked, revokedErr)
+		assert.True(t, active.IsActive())
+	})
+}
+
+func TestSession_Revoke(t *testing.T) {
+	t.Run("should keep the first revocation", func(t *testing.T) {
+		// Arrange
+		now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+		s := session.Restore(1, 7, now, nil, "")
+
+		// Act
+		s.Revoke("logout", now)
+		s.Revoke("admin", now.Add(time.Hour))
+
+		// Assert
+		assert.False(t, s.IsActive())
+		assert.Equal(t, "logout", s.RevokedReason())
+		assert.Equal(t, now, *s.RevokedAt())
+	})
+}
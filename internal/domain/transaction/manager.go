@@ -0,0 +1,12 @@
+package transaction
+
+import "context"
+
+// Manager defines the interface for running a unit of work in a transaction (Port).
+// WithinTx calls fn with a context carrying the transaction; every repository
+// called with that context joins it, so the writes of fn are committed together
+// when fn returns nil and rolled back when it returns an error, which WithinTx
+// returns. A WithinTx inside fn joins the transaction already in the context.
+type Manager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// migrationName matches the name part of new migrations
+var migrationName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Create writes empty up and down files for a new migration in every dialect
+// directory under dir, numbered after the highest version found in any of
+// them so the dialects keep the same set. It returns the created paths.
+func Create(dir, name string) ([]string, error) {
+	if !migrationName.MatchString(name) {
+		return nil, fmt.Errorf("%w: name %q must be lowercase letters, digits and underscores", ErrInvalidMigration, name)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dialects []string
+	var latest int64
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dialects = append(dialects, entry.Name())
+
+		migrations, err := Load(os.DirFS(filepath.Join(dir, entry.Name())))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		if n := len(migrations); n > 0 && migrations[n-1].Version > latest {
+			latest = migrations[n-1].Version
+		}
+	}
+	if len(dialects) == 0 {
+		return nil, fmt.Errorf("%w: no dialect directories in %s", ErrUnknownDialect, dir)
+	}
+
+	version := fmt.Sprintf("%04d", latest+1)
+	var paths []string
+	for _, dialect := range dialects {
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, dialect, fmt.Sprintf("%s_%s.%s.sql", version, name, direction))
+			content := fmt.Sprintf("-- %s_%s (%s): write the %s statements, separated by ;\n", version, name, dialect, direction)
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				return paths, err
+			}
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,207 @@
+package migrations
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Dialects with an embedded migration set, one directory each. The dialects
+// share the same versions so every database goes through the same schema
+// changes, each written in its own SQL.
+const (
+	DialectMySQL    = "mysql"
+	DialectPostgres = "postgres"
+	DialectSQLite   = "sqlite"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+var (
+	ErrUnknownDialect   = errors.New("unknown migration dialect")
+	ErrInvalidMigration = errors.New("invalid migration")
+	ErrChecksumMismatch = errors.New("applied migration was modified")
+	ErrUnknownMigration = errors.New("applied migration is not part of this build")
+	ErrLocked           = errors.New("timed out waiting for the migration lock")
+)
+
+// fileName matches migration files such as 0002_add_crop_variety.up.sql
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// dollarQuote matches the opening tag of a PostgreSQL dollar-quoted string,
+// such as $$ or $body$, which may hold semicolons of a function body
+var dollarQuote = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+// Migration is a numbered schema change with the SQL to apply and revert it
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+
+	// Checksum is the SHA-256 of Up, recorded when the migration is applied
+	// so later edits of an applied file are detected
+	Checksum string
+}
+
+// Embedded returns the migration set compiled into the binary for a dialect
+func Embedded(dialect string) ([]Migration, error) {
+	if _, err := fs.Stat(files, dialect); err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDialect, dialect)
+	}
+	sub, err := fs.Sub(files, dialect)
+	if err != nil {
+		return nil, err
+	}
+	return Load(sub)
+}
+
+// Load reads the migrations in the root of fsys, sorted by version. Every
+// version needs both an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	// found holds the migration of each version and which of its files were read
+	type found struct {
+		migration Migration
+		up, down  bool
+	}
+	byVersion := make(map[int64]*found)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s does not match <version>_<name>.(up|down).sql", ErrInvalidMigration, entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%w: %s has an invalid version", ErrInvalidMigration, entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		f, ok := byVersion[version]
+		if !ok {
+			f = &found{migration: Migration{Version: version, Name: match[2]}}
+			byVersion[version] = f
+		}
+		if f.migration.Name != match[2] {
+			return nil, fmt.Errorf("%w: version %d is used by %s and %s", ErrInvalidMigration, version, f.migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			sum := sha256.Sum256(content)
+			f.migration.Up = string(content)
+			f.migration.Checksum = hex.EncodeToString(sum[:])
+			f.up = true
+		} else {
+			f.migration.Down = string(content)
+			f.down = true
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, f := range byVersion {
+		m := f.migration
+		if !f.up {
+			return nil, fmt.Errorf("%w: %04d_%s has no up file", ErrInvalidMigration, m.Version, m.Name)
+		}
+		if !f.down {
+			return nil, fmt.Errorf("%w: %04d_%s has no down file", ErrInvalidMigration, m.Version, m.Name)
+		}
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Statements splits a script into the statements it runs, one per ; outside
+// of quotes, dollar quotes and comments. Statements made only of comments are dropped.
+func Statements(script string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		hasCode    bool
+	)
+	flush := func() {
+		if hasCode {
+			statements = append(statements, strings.TrimSpace(current.String()))
+		}
+		current.Reset()
+		hasCode = false
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for end < len(script) {
+				if script[end] == c {
+					// A doubled quote escapes itself
+					if end+1 < len(script) && script[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				if script[end] == '\\' && c != '`' {
+					end++
+				}
+				end++
+			}
+			end = min(end, len(script)-1)
+			current.WriteString(script[i : end+1])
+			hasCode = true
+			i = end
+		case c == '$' && dollarQuote.MatchString(script[i:]):
+			tag := dollarQuote.FindString(script[i:])
+			end := strings.Index(script[i+len(tag):], tag)
+			if end < 0 {
+				end = len(script) - i - 2*len(tag)
+			}
+			current.WriteString(script[i : i+end+2*len(tag)])
+			hasCode = true
+			i += end + 2*len(tag) - 1
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				end = len(script) - i
+			}
+			current.WriteString(script[i : i+end])
+			i += end - 1
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				end = len(script) - i - 4
+			}
+			current.WriteString(script[i : i+end+4])
+			i += end + 3
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+			if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				hasCode = true
+			}
+		}
+	}
+	flush()
+	return statements
+}
@@ -0,0 +1,206 @@
+package migrations_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/cropflow/api/internal/infrastructure/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("should pair up and down files sorted by version", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{
+			"0002_add_variety.up.sql":   {Data: []byte("ALTER TABLE crops ADD variety TEXT;")},
+			"0002_add_variety.down.sql": {Data: []byte("ALTER TABLE crops DROP variety;")},
+			"0001_init.up.sql":          {Data: []byte("CREATE TABLE crops (id INTEGER);")},
+			"0001_init.down.sql":        {Data: []byte("DROP TABLE crops;")},
+			"README.md":                 {Data: []byte("ignored")},
+		}
+
+		// Act
+		set, err := migrations.Load(fsys)
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, set, 2)
+		assert.Equal(t, int64(1), set[0].Version)
+		assert.Equal(t, "init", set[0].Name)
+		assert.Equal(t, "DROP TABLE crops;", set[0].Down)
+		assert.Equal(t, "add_variety", set[1].Name)
+		assert.Len(t, set[1].Checksum, 64)
+		assert.NotEqual(t, set[0].Checksum, set[1].Checksum)
+	})
+
+	t.Run("should return error for a migration without down file", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{"0001_init.up.sql": {Data: []byte("CREATE TABLE crops (id INTEGER);")}}
+
+		// Act
+		_, err := migrations.Load(fsys)
+
+		// Assert
+		assert.ErrorIs(t, err, migrations.ErrInvalidMigration)
+	})
+
+	t.Run("should return error for two names with the same version", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{
+			"0001_init.up.sql":   {Data: []byte("")},
+			"0001_init.down.sql": {Data: []byte("")},
+			"0001_other.up.sql":  {Data: []byte("")},
+		}
+
+		// Act
+		_, err := migrations.Load(fsys)
+
+		// Assert
+		assert.ErrorIs(t, err, migrations.ErrInvalidMigration)
+	})
+
+	t.Run("should return error for a badly named file", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{"init.sql": {Data: []byte("")}}
+
+		// Act
+		_, err := migrations.Load(fsys)
+
+		// Assert
+		assert.ErrorIs(t, err, migrations.ErrInvalidMigration)
+	})
+}
+
+func TestEmbedded(t *testing.T) {
+	t.Run("should load the MySQL migration set", func(t *testing.T) {
+		// Act
+		set, err := migrations.Embedded(migrations.DialectMySQL)
+
+		// Assert
+		require.NoError(t, err)
+		require.NotEmpty(t, set)
+		for i, m := range set {
+			assert.Equal(t, int64(i+1), m.Version, "versions must have no gaps")
+		}
+	})
+
+	t.Run("should give every dialect the same versions", func(t *testing.T) {
+		// Arrange
+		mysqlSet, err := migrations.Embedded(migrations.DialectMySQL)
+		require.NoError(t, err)
+
+		for _, dialect := range []string{migrations.DialectPostgres, migrations.DialectSQLite} {
+			// Act
+			set, err := migrations.Embedded(dialect)
+
+			// Assert
+			require.NoError(t, err)
+			require.Len(t, set, len(mysqlSet), dialect)
+			for i := range mysqlSet {
+				assert.Equal(t, mysqlSet[i].Version, set[i].Version, dialect)
+				assert.Equal(t, mysqlSet[i].Name, set[i].Name, dialect)
+			}
+		}
+	})
+
+	t.Run("should return error for an unknown dialect", func(t *testing.T) {
+		// Act
+		_, err := migrations.Embedded("oracle")
+
+		// Assert
+		assert.ErrorIs(t, err, migrations.ErrUnknownDialect)
+	})
+}
+
+func TestStatements(t *testing.T) {
+	t.Run("should split on semicolons outside of quotes and comments", func(t *testing.T) {
+		// Arrange
+		script := `-- creates the table; then seeds it
+CREATE TABLE t (s TEXT DEFAULT 'a;b');
+/* a ; comment */
+INSERT INTO t (s) VALUES ('it''s; fine'), ("x;y");
+-- trailing comment;
+`
+
+		// Act
+		statements := migrations.Statements(script)
+
+		// Assert
+		require.Len(t, statements, 2)
+		assert.Contains(t, statements[0], "CREATE TABLE t (s TEXT DEFAULT 'a;b')")
+		assert.Contains(t, statements[1], `INSERT INTO t (s) VALUES ('it''s; fine'), ("x;y")`)
+	})
+
+	t.Run("should keep dollar-quoted bodies in one statement", func(t *testing.T) {
+		// Arrange
+		script := `DO $$
+BEGIN
+    CREATE EXTENSION IF NOT EXISTS postgis;
+EXCEPTION WHEN OTHERS THEN
+    RAISE NOTICE 'skipped: %', SQLERRM;
+END
+$$;
+CREATE FUNCTION one() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql;
+SELECT $1;`
+
+		// Act
+		statements := migrations.Statements(script)
+
+		// Assert
+		require.Len(t, statements, 3)
+		assert.Contains(t, statements[0], "RAISE NOTICE 'skipped: %', SQLERRM;\nEND\n$$")
+		assert.Contains(t, statements[1], "$body$ SELECT 1; $body$ LANGUAGE sql")
+		assert.Equal(t, "SELECT $1", statements[2])
+	})
+
+	t.Run("should return nothing for a script of comments", func(t *testing.T) {
+		// Act
+		statements := migrations.Statements("-- nothing to do yet\n")
+
+		// Assert
+		assert.Empty(t, statements)
+	})
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("should add the next version to every dialect", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		for _, dialect := range []string{"mysql", "postgres", "sqlite"} {
+			require.NoError(t, os.Mkdir(filepath.Join(dir, dialect), 0o755))
+		}
+		for _, name := range []string{"0001_init.up.sql", "0001_init.down.sql", "0002_audit.up.sql", "0002_audit.down.sql"} {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "mysql", name), nil, 0o644))
+		}
+
+		// Act
+		paths, err := migrations.Create(dir, "add_variety")
+
+		// Assert
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			filepath.Join(dir, "mysql", "0003_add_variety.up.sql"),
+			filepath.Join(dir, "mysql", "0003_add_variety.down.sql"),
+			filepath.Join(dir, "postgres", "0003_add_variety.up.sql"),
+			filepath.Join(dir, "postgres", "0003_add_variety.down.sql"),
+			filepath.Join(dir, "sqlite", "0003_add_variety.up.sql"),
+			filepath.Join(dir, "sqlite", "0003_add_variety.down.sql"),
+		}, paths)
+
+		set, err := migrations.Load(os.DirFS(filepath.Join(dir, "sqlite")))
+		require.NoError(t, err)
+		require.Len(t, set, 1)
+		assert.Empty(t, migrations.Statements(set[0].Up))
+	})
+
+	t.Run("should return error for an invalid name", func(t *testing.T) {
+		// Act
+		_, err := migrations.Create(t.TempDir(), "Add Variety")
+
+		// Assert
+		assert.ErrorIs(t, err, migrations.ErrInvalidMigration)
+	})
+}
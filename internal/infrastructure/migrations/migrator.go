@@ -0,0 +1,221 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// createTable keeps the applied versions; the types are understood by every
+// supported database
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    applied_at TIMESTAMP NOT NULL
+)`
+
+// Locker serializes migrations between processes sharing a database, such as
+// replicas starting at the same time. Both calls run on the same connection.
+type Locker interface {
+	Lock(conn *gorm.DB) error
+	Unlock(conn *gorm.DB) error
+}
+
+// Status reports whether a migration has been applied
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+
+	// Modified is set when the file of an applied migration changed after it ran
+	Modified bool
+	// Missing is set when an applied migration is not part of this build
+	Missing bool
+}
+
+// appliedMigration is a row of schema_migrations
+type appliedMigration struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator applies and reverts a migration set, recording each applied
+// version in schema_migrations
+type Migrator struct {
+	db         *gorm.DB
+	locker     Locker
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator for a migration set sorted by version
+func NewMigrator(db *gorm.DB, locker Locker, migrations []Migration) *Migrator {
+	return &Migrator{db: db, locker: locker, migrations: migrations}
+}
+
+// Up applies every pending migration in version order and returns them. It
+// refuses to run when an applied migration was modified.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(ctx, func(conn *gorm.DB, done map[int64]appliedMigration) error {
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(conn, migration); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the latest steps applied migrations, newest first, and returns
+// them. It stops at an applied migration that is not part of this build.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	byVersion := make(map[int64]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	var reverted []Migration
+	err := m.locked(ctx, func(conn *gorm.DB, done map[int64]appliedMigration) error {
+		versions := make([]int64, 0, len(done))
+		for version := range done {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for _, version := range versions[:min(steps, len(versions))] {
+			migration, ok := byVersion[version]
+			if !ok {
+				return fmt.Errorf("%w: %04d_%s", ErrUnknownMigration, version, done[version].Name)
+			}
+			if err := m.revert(conn, migration); err != nil {
+				return err
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists the migrations of the set and any applied version missing from it
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		done, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		known := make(map[int64]bool, len(m.migrations))
+		for _, migration := range m.migrations {
+			known[migration.Version] = true
+			status := Status{Version: migration.Version, Name: migration.Name}
+			if row, ok := done[migration.Version]; ok {
+				appliedAt := row.AppliedAt
+				status.AppliedAt = &appliedAt
+				status.Modified = row.Checksum != migration.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+		for _, row := range done {
+			if !known[row.Version] {
+				appliedAt := row.AppliedAt
+				statuses = append(statuses, Status{Version: row.Version, Name: row.Name, AppliedAt: &appliedAt, Missing: true})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// locked runs fn on a single connection holding the migration lock, after
+// checking that the applied migrations still match their files. Applied
+// versions unknown to this build are left alone, so an older replica can
+// still start after a newer one migrated, but they cannot be reverted.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *gorm.DB, done map[int64]appliedMigration) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := m.locker.Lock(conn); err != nil {
+			return err
+		}
+		defer m.locker.Unlock(conn)
+
+		done, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if row, ok := done[migration.Version]; ok && row.Checksum != migration.Checksum {
+				return fmt.Errorf("%w: %04d_%s", ErrChecksumMismatch, migration.Version, migration.Name)
+			}
+		}
+		return fn(conn, done)
+	})
+}
+
+// applied creates schema_migrations when needed and reads it by version
+func (m *Migrator) applied(conn *gorm.DB) (map[int64]appliedMigration, error) {
+	if err := conn.Exec(createTable).Error; err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var rows []appliedMigration
+	if err := conn.Raw("SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	done := make(map[int64]appliedMigration, len(rows))
+	for _, row := range rows {
+		done[row.Version] = row
+	}
+	return done, nil
+}
+
+// apply runs the up script and records the version in one transaction. On
+// databases where DDL commits implicitly, such as MySQL, a failing script
+// leaves its earlier statements applied and the version unrecorded.
+func (m *Migrator) apply(conn *gorm.DB, migration Migration) error {
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range Statements(migration.Up) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Exec("INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+			migration.Version, migration.Name, migration.Checksum, time.Now().UTC()).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply migration %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// revert runs the down script and forgets the version in one transaction
+func (m *Migrator) revert(conn *gorm.DB, migration Migration) error {
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range Statements(migration.Down) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revert migration %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
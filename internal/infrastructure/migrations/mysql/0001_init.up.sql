@@ -0,0 +1,54 @@
+-- Baseline schema, exactly as gorm AutoMigrate created it before versioned
+-- migrations. IF NOT EXISTS lets databases created that way adopt it
+-- unchanged; the later versions bring them up to date.
+
+CREATE TABLE IF NOT EXISTS `farms` (
+    `id` bigint AUTO_INCREMENT,
+    `name` longtext NOT NULL,
+    `size` double NOT NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `crops` (
+    `id` bigint AUTO_INCREMENT,
+    `name` longtext NOT NULL,
+    `planted_area` double NOT NULL,
+    `farm_id` bigint NOT NULL,
+    `planting_date` datetime(3) NULL,
+    `harvest_date` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_farms_crops` FOREIGN KEY (`farm_id`) REFERENCES `farms`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `fertilizer` (
+    `id` bigint AUTO_INCREMENT,
+    `name` longtext NOT NULL,
+    `brand` longtext NOT NULL,
+    `composition` longtext NOT NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `crop_fertilizer` (
+    `fertilizer_id` bigint,
+    `crop_id` bigint,
+    PRIMARY KEY (`fertilizer_id`,`crop_id`),
+    CONSTRAINT `fk_crop_fertilizer_fertilizer` FOREIGN KEY (`fertilizer_id`) REFERENCES `fertilizer`(`id`),
+    CONSTRAINT `fk_crop_fertilizer_crop` FOREIGN KEY (`crop_id`) REFERENCES `crops`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `person` (
+    `id` bigint AUTO_INCREMENT,
+    `username` varchar(191) NOT NULL,
+    `password` longtext NOT NULL,
+    `role` longtext NOT NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `uni_person_username` UNIQUE (`username`)
+);
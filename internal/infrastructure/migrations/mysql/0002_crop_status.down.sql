@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS `crop_status_transitions`;
+DROP INDEX `idx_crops_status` ON `crops`;
+ALTER TABLE `crops`
+    DROP COLUMN `status`,
+    DROP COLUMN `status_changed_at`;
@@ -0,0 +1,17 @@
+ALTER TABLE `crops`
+    ADD COLUMN `status` varchar(16) NOT NULL DEFAULT 'PLANNED',
+    ADD COLUMN `status_changed_at` datetime(3) NULL;
+CREATE INDEX `idx_crops_status` ON `crops` (`status`);
+
+CREATE TABLE `crop_status_transitions` (
+    `id` bigint AUTO_INCREMENT,
+    `crop_id` bigint NOT NULL,
+    `from_status` varchar(16),
+    `to_status` varchar(16) NOT NULL,
+    `occurred_at` datetime(3) NOT NULL,
+    `note` longtext,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_crop_status_transitions_crop_id` (`crop_id`),
+    CONSTRAINT `fk_crops_transitions` FOREIGN KEY (`crop_id`) REFERENCES `crops`(`id`)
+);
@@ -0,0 +1,14 @@
+CREATE TABLE `harvest` (
+    `id` bigint AUTO_INCREMENT,
+    `crop_id` bigint NOT NULL,
+    `harvested_at` datetime(3) NOT NULL,
+    `quantity` double NOT NULL,
+    `unit` varchar(16) NOT NULL,
+    `moisture` double,
+    `grade` varchar(32),
+    `partial` boolean NOT NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_harvest_crop_id` (`crop_id`),
+    CONSTRAINT `fk_crops_harvests` FOREIGN KEY (`crop_id`) REFERENCES `crops`(`id`)
+);
@@ -0,0 +1,18 @@
+CREATE TABLE `fertilizer_application` (
+    `id` bigint AUTO_INCREMENT,
+    `crop_id` bigint NOT NULL,
+    `fertilizer_id` bigint NOT NULL,
+    `applied_at` datetime(3) NOT NULL,
+    `dose` double NOT NULL,
+    `dose_unit` varchar(16) NOT NULL,
+    `applied_area` double NOT NULL,
+    `operator_id` bigint,
+    `notes` longtext,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_fertilizer_application_crop_id` (`crop_id`),
+    INDEX `idx_fertilizer_application_fertilizer_id` (`fertilizer_id`),
+    CONSTRAINT `fk_fertilizer_application_operator` FOREIGN KEY (`operator_id`) REFERENCES `person`(`id`) ON DELETE SET NULL,
+    CONSTRAINT `fk_crops_applications` FOREIGN KEY (`crop_id`) REFERENCES `crops`(`id`),
+    CONSTRAINT `fk_fertilizer_applications` FOREIGN KEY (`fertilizer_id`) REFERENCES `fertilizer`(`id`)
+);
@@ -0,0 +1,16 @@
+ALTER TABLE `fertilizer`
+    ADD COLUMN `pct_n` double NOT NULL DEFAULT 0,
+    ADD COLUMN `pct_p2o5` double NOT NULL DEFAULT 0,
+    ADD COLUMN `pct_k2o` double NOT NULL DEFAULT 0,
+    ADD COLUMN `pct_s` double NOT NULL DEFAULT 0,
+    ADD COLUMN `pct_ca` double NOT NULL DEFAULT 0,
+    ADD COLUMN `pct_mg` double NOT NULL DEFAULT 0,
+    ADD COLUMN `pct_b` double NOT NULL DEFAULT 0,
+    ADD COLUMN `pct_cl` double NOT NULL DEFAULT 0,
+    ADD COLUMN `pct_co` double NOT NULL DEFAULT 0,
+    ADD COLUMN `pct_cu` double NOT NULL DEFAULT 0,
+    ADD COLUMN `pct_fe` double NOT NULL DEFAULT 0,
+    ADD COLUMN `pct_mn` double NOT NULL DEFAULT 0,
+    ADD COLUMN `pct_mo` double NOT NULL DEFAULT 0,
+    ADD COLUMN `pct_ni` double NOT NULL DEFAULT 0,
+    ADD COLUMN `pct_zn` double NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+CREATE TABLE `farm_member` (
+    `farm_id` bigint,
+    `person_id` bigint,
+    `role` varchar(16) NOT NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`farm_id`,`person_id`),
+    INDEX `idx_farm_member_person_id` (`person_id`),
+    CONSTRAINT `fk_farm_member_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_farms_members` FOREIGN KEY (`farm_id`) REFERENCES `farms`(`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,23 @@
+CREATE TABLE `session` (
+    `id` bigint AUTO_INCREMENT,
+    `person_id` bigint NOT NULL,
+    `revoked_at` datetime(3) NULL,
+    `revoked_reason` varchar(64),
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_session_person_id` (`person_id`),
+    CONSTRAINT `fk_session_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `refresh_token` (
+    `id` bigint AUTO_INCREMENT,
+    `session_id` bigint NOT NULL,
+    `hash` varchar(64) NOT NULL,
+    `expires_at` datetime(3) NOT NULL,
+    `used_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_refresh_token_session_id` (`session_id`),
+    UNIQUE INDEX `idx_refresh_token_hash` (`hash`),
+    CONSTRAINT `fk_refresh_token_session` FOREIGN KEY (`session_id`) REFERENCES `session`(`id`) ON DELETE CASCADE
+);
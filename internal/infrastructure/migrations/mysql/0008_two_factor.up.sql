@@ -0,0 +1,38 @@
+CREATE TABLE `totp_enrollment` (
+    `id` bigint AUTO_INCREMENT,
+    `person_id` bigint NOT NULL,
+    `secret` varchar(64) NOT NULL,
+    `confirmed_at` datetime(3) NULL,
+    `last_used_step` bigint NOT NULL DEFAULT 0,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_totp_enrollment_person_id` (`person_id`),
+    CONSTRAINT `fk_totp_enrollment_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `recovery_code` (
+    `id` bigint AUTO_INCREMENT,
+    `enrollment_id` bigint NOT NULL,
+    `hash` varchar(64) NOT NULL,
+    `used_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_recovery_code_enrollment_id` (`enrollment_id`),
+    CONSTRAINT `fk_totp_enrollment_recovery_codes` FOREIGN KEY (`enrollment_id`) REFERENCES `totp_enrollment`(`id`)
+);
+
+CREATE TABLE `login_challenge` (
+    `id` bigint AUTO_INCREMENT,
+    `person_id` bigint NOT NULL,
+    `kind` varchar(16) NOT NULL,
+    `hash` varchar(64) NOT NULL,
+    `attempts` bigint NOT NULL DEFAULT 0,
+    `expires_at` datetime(3) NOT NULL,
+    `completed_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_login_challenge_person_id` (`person_id`),
+    UNIQUE INDEX `idx_login_challenge_hash` (`hash`),
+    CONSTRAINT `fk_login_challenge_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,12 @@
+ALTER TABLE `person`
+    ADD COLUMN `failed_logins` bigint NOT NULL DEFAULT 0,
+    ADD COLUMN `last_failed_login_at` datetime(3) NULL,
+    ADD COLUMN `locked_until` datetime(3) NULL;
+
+CREATE TABLE `login_attempt` (
+    `key` varchar(191),
+    `failures` bigint NOT NULL DEFAULT 0,
+    `last_failed_at` datetime(3) NULL,
+    `locked_until` datetime(3) NULL,
+    PRIMARY KEY (`key`)
+);
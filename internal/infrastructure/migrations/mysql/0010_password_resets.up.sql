@@ -0,0 +1,12 @@
+CREATE TABLE `password_reset_token` (
+    `id` bigint AUTO_INCREMENT,
+    `person_id` bigint NOT NULL,
+    `hash` varchar(64) NOT NULL,
+    `expires_at` datetime(3) NOT NULL,
+    `used_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_password_reset_token_person_id` (`person_id`),
+    UNIQUE INDEX `idx_password_reset_token_hash` (`hash`),
+    CONSTRAINT `fk_password_reset_token_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE
+);
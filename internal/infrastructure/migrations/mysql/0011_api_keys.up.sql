@@ -0,0 +1,18 @@
+CREATE TABLE `api_key` (
+    `id` bigint AUTO_INCREMENT,
+    `person_id` bigint NOT NULL,
+    `kind` varchar(16) NOT NULL,
+    `name` varchar(100) NOT NULL,
+    `prefix` varchar(32) NOT NULL,
+    `hash` varchar(64) NOT NULL,
+    `role` varchar(50) NOT NULL,
+    `farm_ids` longtext,
+    `expires_at` datetime(3) NULL,
+    `last_used_at` datetime(3) NULL,
+    `revoked_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_api_key_person_id` (`person_id`),
+    UNIQUE INDEX `idx_api_key_prefix` (`prefix`),
+    CONSTRAINT `fk_api_key_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,23 @@
+CREATE TABLE `oidc_login_request` (
+    `id` bigint AUTO_INCREMENT,
+    `state_hash` varchar(64) NOT NULL,
+    `nonce` varchar(64) NOT NULL,
+    `code_verifier` varchar(128) NOT NULL,
+    `expires_at` datetime(3) NOT NULL,
+    `consumed_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_oidc_login_request_state_hash` (`state_hash`)
+);
+
+CREATE TABLE `oidc_link` (
+    `id` bigint AUTO_INCREMENT,
+    `person_id` bigint NOT NULL,
+    `issuer` varchar(255) NOT NULL,
+    `subject` varchar(255) NOT NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_oidc_link_person_id` (`person_id`),
+    UNIQUE INDEX `idx_oidc_link_subject` (`issuer`,`subject`),
+    CONSTRAINT `fk_oidc_link_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `person_role_change`;
+ALTER TABLE `person`
+    DROP INDEX `idx_person_role`,
+    MODIFY `role` longtext NOT NULL;
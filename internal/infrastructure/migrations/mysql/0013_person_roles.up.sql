@@ -0,0 +1,16 @@
+-- The role was longtext, which MySQL cannot index
+ALTER TABLE `person`
+    MODIFY `role` varchar(191) NOT NULL,
+    ADD INDEX `idx_person_role` (`role`);
+
+CREATE TABLE `person_role_change` (
+    `id` bigint AUTO_INCREMENT,
+    `person_id` bigint NOT NULL,
+    `from_role` varchar(32) NOT NULL,
+    `to_role` varchar(32) NOT NULL,
+    `changed_by` bigint,
+    `changed_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_person_role_change_person_id` (`person_id`),
+    CONSTRAINT `fk_person_role_change_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,17 @@
+CREATE TABLE `audit_entry` (
+    `id` bigint AUTO_INCREMENT,
+    `actor_id` bigint,
+    `api_key_id` bigint,
+    `actor_username` varchar(255),
+    `action` varchar(16) NOT NULL,
+    `resource_type` varchar(32) NOT NULL,
+    `resource_id` bigint NOT NULL,
+    `changes` text NOT NULL,
+    `request_id` varchar(64),
+    `occurred_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_audit_entry_actor_id` (`actor_id`),
+    INDEX `idx_audit_resource` (`resource_type`,`resource_id`),
+    INDEX `idx_audit_entry_request_id` (`request_id`),
+    INDEX `idx_audit_entry_occurred_at` (`occurred_at`)
+);
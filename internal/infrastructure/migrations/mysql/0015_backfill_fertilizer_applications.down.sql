@@ -0,0 +1,12 @@
+CREATE TABLE `crop_fertilizer` (
+    `fertilizer_id` bigint,
+    `crop_id` bigint,
+    PRIMARY KEY (`fertilizer_id`,`crop_id`),
+    CONSTRAINT `fk_crop_fertilizer_fertilizer` FOREIGN KEY (`fertilizer_id`) REFERENCES `fertilizer`(`id`),
+    CONSTRAINT `fk_crop_fertilizer_crop` FOREIGN KEY (`crop_id`) REFERENCES `crops`(`id`)
+);
+
+INSERT INTO `crop_fertilizer` (`fertilizer_id`, `crop_id`)
+SELECT DISTINCT `fertilizer_id`, `crop_id` FROM `fertilizer_application` WHERE `dose_unit` = 'UNKNOWN';
+
+DELETE FROM `fertilizer_application` WHERE `dose_unit` = 'UNKNOWN';
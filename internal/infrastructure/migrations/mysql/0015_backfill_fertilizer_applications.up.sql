@@ -0,0 +1,10 @@
+-- Copies the crop-fertilizer links of the baseline schema into applications.
+-- The links recorded neither dose nor date, so the dose is zero with the
+-- UNKNOWN unit, the area is the whole crop and the date is the crop's
+-- planting date, or its creation date when it has none.
+INSERT INTO `fertilizer_application` (`crop_id`, `fertilizer_id`, `applied_at`, `dose`, `dose_unit`, `applied_area`, `created_at`)
+SELECT cf.`crop_id`, cf.`fertilizer_id`, COALESCE(c.`planting_date`, c.`created_at`, CURRENT_TIMESTAMP(3)), 0, 'UNKNOWN', c.`planted_area`, CURRENT_TIMESTAMP(3)
+FROM `crop_fertilizer` cf
+JOIN `crops` c ON c.`id` = cf.`crop_id`;
+
+DROP TABLE `crop_fertilizer`;
@@ -0,0 +1,64 @@
+-- Baseline schema, the same tables as the MySQL 0001_init.
+
+-- PostGIS is enabled when the server ships it and the user may create
+-- extensions, so later migrations can add geometry columns such as farm
+-- boundaries; otherwise the schema is created without it. Reverting leaves
+-- the extension in place, as other schemas of the database may use it.
+DO $$
+BEGIN
+    CREATE EXTENSION IF NOT EXISTS postgis;
+EXCEPTION WHEN OTHERS THEN
+    RAISE NOTICE 'PostGIS is not enabled: %', SQLERRM;
+END
+$$;
+
+CREATE TABLE "farms" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "size" double precision NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE "crops" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "planted_area" double precision NOT NULL,
+    "farm_id" bigint NOT NULL,
+    "planting_date" timestamptz,
+    "harvest_date" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_farms_crops" FOREIGN KEY ("farm_id") REFERENCES "farms"("id")
+);
+
+CREATE TABLE "fertilizer" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "brand" text NOT NULL,
+    "composition" text NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE "crop_fertilizer" (
+    "fertilizer_id" bigint,
+    "crop_id" bigint,
+    PRIMARY KEY ("fertilizer_id","crop_id"),
+    CONSTRAINT "fk_crop_fertilizer_fertilizer" FOREIGN KEY ("fertilizer_id") REFERENCES "fertilizer"("id"),
+    CONSTRAINT "fk_crop_fertilizer_crop" FOREIGN KEY ("crop_id") REFERENCES "crops"("id")
+);
+
+CREATE TABLE "person" (
+    "id" bigserial,
+    "username" text NOT NULL,
+    "password" text NOT NULL,
+    "role" text NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_person_username" UNIQUE ("username")
+);
@@ -0,0 +1,17 @@
+ALTER TABLE "crops"
+    ADD COLUMN "status" varchar(16) NOT NULL DEFAULT 'PLANNED',
+    ADD COLUMN "status_changed_at" timestamptz;
+CREATE INDEX "idx_crops_status" ON "crops" ("status");
+
+CREATE TABLE "crop_status_transitions" (
+    "id" bigserial,
+    "crop_id" bigint NOT NULL,
+    "from_status" varchar(16),
+    "to_status" varchar(16) NOT NULL,
+    "occurred_at" timestamptz NOT NULL,
+    "note" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_crops_transitions" FOREIGN KEY ("crop_id") REFERENCES "crops"("id")
+);
+CREATE INDEX "idx_crop_status_transitions_crop_id" ON "crop_status_transitions" ("crop_id");
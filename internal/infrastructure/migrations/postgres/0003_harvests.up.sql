@@ -0,0 +1,14 @@
+CREATE TABLE "harvest" (
+    "id" bigserial,
+    "crop_id" bigint NOT NULL,
+    "harvested_at" timestamptz NOT NULL,
+    "quantity" double precision NOT NULL,
+    "unit" varchar(16) NOT NULL,
+    "moisture" double precision,
+    "grade" varchar(32),
+    "partial" boolean NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_crops_harvests" FOREIGN KEY ("crop_id") REFERENCES "crops"("id")
+);
+CREATE INDEX "idx_harvest_crop_id" ON "harvest" ("crop_id");
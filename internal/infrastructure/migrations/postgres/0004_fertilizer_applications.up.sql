@@ -0,0 +1,18 @@
+CREATE TABLE "fertilizer_application" (
+    "id" bigserial,
+    "crop_id" bigint NOT NULL,
+    "fertilizer_id" bigint NOT NULL,
+    "applied_at" timestamptz NOT NULL,
+    "dose" double precision NOT NULL,
+    "dose_unit" varchar(16) NOT NULL,
+    "applied_area" double precision NOT NULL,
+    "operator_id" bigint,
+    "notes" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_fertilizer_application_operator" FOREIGN KEY ("operator_id") REFERENCES "person"("id") ON DELETE SET NULL,
+    CONSTRAINT "fk_crops_applications" FOREIGN KEY ("crop_id") REFERENCES "crops"("id"),
+    CONSTRAINT "fk_fertilizer_applications" FOREIGN KEY ("fertilizer_id") REFERENCES "fertilizer"("id")
+);
+CREATE INDEX "idx_fertilizer_application_fertilizer_id" ON "fertilizer_application" ("fertilizer_id");
+CREATE INDEX "idx_fertilizer_application_crop_id" ON "fertilizer_application" ("crop_id");
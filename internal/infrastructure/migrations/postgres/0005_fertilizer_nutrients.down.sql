@@ -0,0 +1,16 @@
+ALTER TABLE "fertilizer"
+    DROP COLUMN "pct_n",
+    DROP COLUMN "pct_p2o5",
+    DROP COLUMN "pct_k2o",
+    DROP COLUMN "pct_s",
+    DROP COLUMN "pct_ca",
+    DROP COLUMN "pct_mg",
+    DROP COLUMN "pct_b",
+    DROP COLUMN "pct_cl",
+    DROP COLUMN "pct_co",
+    DROP COLUMN "pct_cu",
+    DROP COLUMN "pct_fe",
+    DROP COLUMN "pct_mn",
+    DROP COLUMN "pct_mo",
+    DROP COLUMN "pct_ni",
+    DROP COLUMN "pct_zn";
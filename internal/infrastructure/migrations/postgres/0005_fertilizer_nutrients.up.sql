@@ -0,0 +1,16 @@
+ALTER TABLE "fertilizer"
+    ADD COLUMN "pct_n" double precision NOT NULL DEFAULT 0,
+    ADD COLUMN "pct_p2o5" double precision NOT NULL DEFAULT 0,
+    ADD COLUMN "pct_k2o" double precision NOT NULL DEFAULT 0,
+    ADD COLUMN "pct_s" double precision NOT NULL DEFAULT 0,
+    ADD COLUMN "pct_ca" double precision NOT NULL DEFAULT 0,
+    ADD COLUMN "pct_mg" double precision NOT NULL DEFAULT 0,
+    ADD COLUMN "pct_b" double precision NOT NULL DEFAULT 0,
+    ADD COLUMN "pct_cl" double precision NOT NULL DEFAULT 0,
+    ADD COLUMN "pct_co" double precision NOT NULL DEFAULT 0,
+    ADD COLUMN "pct_cu" double precision NOT NULL DEFAULT 0,
+    ADD COLUMN "pct_fe" double precision NOT NULL DEFAULT 0,
+    ADD COLUMN "pct_mn" double precision NOT NULL DEFAULT 0,
+    ADD COLUMN "pct_mo" double precision NOT NULL DEFAULT 0,
+    ADD COLUMN "pct_ni" double precision NOT NULL DEFAULT 0,
+    ADD COLUMN "pct_zn" double precision NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "refresh_token";
+DROP TABLE IF EXISTS "session";
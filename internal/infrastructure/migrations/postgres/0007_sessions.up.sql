@@ -0,0 +1,23 @@
+CREATE TABLE "session" (
+    "id" bigserial,
+    "person_id" bigint NOT NULL,
+    "revoked_at" timestamptz,
+    "revoked_reason" varchar(64),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_session_person" FOREIGN KEY ("person_id") REFERENCES "person"("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_session_person_id" ON "session" ("person_id");
+
+CREATE TABLE "refresh_token" (
+    "id" bigserial,
+    "session_id" bigint NOT NULL,
+    "hash" varchar(64) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_refresh_token_session" FOREIGN KEY ("session_id") REFERENCES "session"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_refresh_token_hash" ON "refresh_token" ("hash");
+CREATE INDEX "idx_refresh_token_session_id" ON "refresh_token" ("session_id");
@@ -0,0 +1,38 @@
+CREATE TABLE "totp_enrollment" (
+    "id" bigserial,
+    "person_id" bigint NOT NULL,
+    "secret" varchar(64) NOT NULL,
+    "confirmed_at" timestamptz,
+    "last_used_step" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_totp_enrollment_person" FOREIGN KEY ("person_id") REFERENCES "person"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_totp_enrollment_person_id" ON "totp_enrollment" ("person_id");
+
+CREATE TABLE "recovery_code" (
+    "id" bigserial,
+    "enrollment_id" bigint NOT NULL,
+    "hash" varchar(64) NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_totp_enrollment_recovery_codes" FOREIGN KEY ("enrollment_id") REFERENCES "totp_enrollment"("id")
+);
+CREATE INDEX "idx_recovery_code_enrollment_id" ON "recovery_code" ("enrollment_id");
+
+CREATE TABLE "login_challenge" (
+    "id" bigserial,
+    "person_id" bigint NOT NULL,
+    "kind" varchar(16) NOT NULL,
+    "hash" varchar(64) NOT NULL,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "expires_at" timestamptz NOT NULL,
+    "completed_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_login_challenge_person" FOREIGN KEY ("person_id") REFERENCES "person"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_login_challenge_hash" ON "login_challenge" ("hash");
+CREATE INDEX "idx_login_challenge_person_id" ON "login_challenge" ("person_id");
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "login_attempt";
+ALTER TABLE "person"
+    DROP COLUMN "failed_logins",
+    DROP COLUMN "last_failed_login_at",
+    DROP COLUMN "locked_until";
@@ -0,0 +1,12 @@
+ALTER TABLE "person"
+    ADD COLUMN "failed_logins" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN "last_failed_login_at" timestamptz,
+    ADD COLUMN "locked_until" timestamptz;
+
+CREATE TABLE "login_attempt" (
+    "key" varchar(191),
+    "failures" bigint NOT NULL DEFAULT 0,
+    "last_failed_at" timestamptz,
+    "locked_until" timestamptz,
+    PRIMARY KEY ("key")
+);
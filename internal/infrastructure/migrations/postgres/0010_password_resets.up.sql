@@ -0,0 +1,12 @@
+CREATE TABLE "password_reset_token" (
+    "id" bigserial,
+    "person_id" bigint NOT NULL,
+    "hash" varchar(64) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_password_reset_token_person" FOREIGN KEY ("person_id") REFERENCES "person"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_password_reset_token_hash" ON "password_reset_token" ("hash");
+CREATE INDEX "idx_password_reset_token_person_id" ON "password_reset_token" ("person_id");
@@ -0,0 +1,18 @@
+CREATE TABLE "api_key" (
+    "id" bigserial,
+    "person_id" bigint NOT NULL,
+    "kind" varchar(16) NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "prefix" varchar(32) NOT NULL,
+    "hash" varchar(64) NOT NULL,
+    "role" varchar(50) NOT NULL,
+    "farm_ids" text,
+    "expires_at" timestamptz,
+    "last_used_at" timestamptz,
+    "revoked_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_api_key_person" FOREIGN KEY ("person_id") REFERENCES "person"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_api_key_prefix" ON "api_key" ("prefix");
+CREATE INDEX "idx_api_key_person_id" ON "api_key" ("person_id");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "oidc_link";
+DROP TABLE IF EXISTS "oidc_login_request";
@@ -0,0 +1,23 @@
+CREATE TABLE "oidc_login_request" (
+    "id" bigserial,
+    "state_hash" varchar(64) NOT NULL,
+    "nonce" varchar(64) NOT NULL,
+    "code_verifier" varchar(128) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "consumed_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_oidc_login_request_state_hash" ON "oidc_login_request" ("state_hash");
+
+CREATE TABLE "oidc_link" (
+    "id" bigserial,
+    "person_id" bigint NOT NULL,
+    "issuer" varchar(255) NOT NULL,
+    "subject" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_oidc_link_person" FOREIGN KEY ("person_id") REFERENCES "person"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_oidc_link_subject" ON "oidc_link" ("issuer","subject");
+CREATE INDEX "idx_oidc_link_person_id" ON "oidc_link" ("person_id");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "person_role_change";
+DROP INDEX IF EXISTS "idx_person_role";
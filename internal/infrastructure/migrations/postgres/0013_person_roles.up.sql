@@ -0,0 +1,13 @@
+CREATE INDEX "idx_person_role" ON "person" ("role");
+
+CREATE TABLE "person_role_change" (
+    "id" bigserial,
+    "person_id" bigint NOT NULL,
+    "from_role" varchar(32) NOT NULL,
+    "to_role" varchar(32) NOT NULL,
+    "changed_by" bigint,
+    "changed_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_person_role_change_person" FOREIGN KEY ("person_id") REFERENCES "person"("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_person_role_change_person_id" ON "person_role_change" ("person_id");
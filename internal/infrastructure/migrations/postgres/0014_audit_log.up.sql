@@ -0,0 +1,17 @@
+CREATE TABLE "audit_entry" (
+    "id" bigserial,
+    "actor_id" bigint,
+    "api_key_id" bigint,
+    "actor_username" varchar(255),
+    "action" varchar(16) NOT NULL,
+    "resource_type" varchar(32) NOT NULL,
+    "resource_id" bigint NOT NULL,
+    "changes" text NOT NULL,
+    "request_id" varchar(64),
+    "occurred_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_audit_entry_occurred_at" ON "audit_entry" ("occurred_at");
+CREATE INDEX "idx_audit_entry_request_id" ON "audit_entry" ("request_id");
+CREATE INDEX "idx_audit_resource" ON "audit_entry" ("resource_type","resource_id");
+CREATE INDEX "idx_audit_entry_actor_id" ON "audit_entry" ("actor_id");
@@ -0,0 +1,8 @@
+-- Copies the crop-fertilizer links into applications, as the MySQL
+-- 0015_backfill_fertilizer_applications does.
+INSERT INTO "fertilizer_application" ("crop_id", "fertilizer_id", "applied_at", "dose", "dose_unit", "applied_area", "created_at")
+SELECT cf."crop_id", cf."fertilizer_id", COALESCE(c."planting_date", c."created_at", CURRENT_TIMESTAMP), 0, 'UNKNOWN', c."planted_area", CURRENT_TIMESTAMP
+FROM "crop_fertilizer" cf
+JOIN "crops" c ON c."id" = cf."crop_id";
+
+DROP TABLE "crop_fertilizer";
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS `person`;
+DROP TABLE IF EXISTS `crop_fertilizer`;
+DROP TABLE IF EXISTS `fertilizer`;
+DROP TABLE IF EXISTS `crops`;
+DROP TABLE IF EXISTS `farms`;
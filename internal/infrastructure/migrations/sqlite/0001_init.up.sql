@@ -0,0 +1,48 @@
+-- Baseline schema, the same tables as the MySQL 0001_init.
+
+CREATE TABLE `farms` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `name` text NOT NULL,
+    `size` real NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime
+);
+
+CREATE TABLE `crops` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `name` text NOT NULL,
+    `planted_area` real NOT NULL,
+    `farm_id` integer NOT NULL,
+    `planting_date` datetime,
+    `harvest_date` datetime,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_farms_crops` FOREIGN KEY (`farm_id`) REFERENCES `farms`(`id`)
+);
+
+CREATE TABLE `fertilizer` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `name` text NOT NULL,
+    `brand` text NOT NULL,
+    `composition` text NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime
+);
+
+CREATE TABLE `crop_fertilizer` (
+    `fertilizer_id` integer,
+    `crop_id` integer,
+    PRIMARY KEY (`fertilizer_id`,`crop_id`),
+    CONSTRAINT `fk_crop_fertilizer_fertilizer` FOREIGN KEY (`fertilizer_id`) REFERENCES `fertilizer`(`id`),
+    CONSTRAINT `fk_crop_fertilizer_crop` FOREIGN KEY (`crop_id`) REFERENCES `crops`(`id`)
+);
+
+CREATE TABLE `person` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `username` text NOT NULL,
+    `password` text NOT NULL,
+    `role` text NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `uni_person_username` UNIQUE (`username`)
+);
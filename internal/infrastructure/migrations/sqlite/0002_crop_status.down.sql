@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `crop_status_transitions`;
+DROP INDEX IF EXISTS `idx_crops_status`;
+ALTER TABLE `crops` DROP COLUMN `status`;
+ALTER TABLE `crops` DROP COLUMN `status_changed_at`;
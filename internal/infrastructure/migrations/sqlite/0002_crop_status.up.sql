@@ -0,0 +1,15 @@
+ALTER TABLE `crops` ADD COLUMN `status` text NOT NULL DEFAULT 'PLANNED';
+ALTER TABLE `crops` ADD COLUMN `status_changed_at` datetime;
+CREATE INDEX `idx_crops_status` ON `crops`(`status`);
+
+CREATE TABLE `crop_status_transitions` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `crop_id` integer NOT NULL,
+    `from_status` text,
+    `to_status` text NOT NULL,
+    `occurred_at` datetime NOT NULL,
+    `note` text,
+    `created_at` datetime,
+    CONSTRAINT `fk_crops_transitions` FOREIGN KEY (`crop_id`) REFERENCES `crops`(`id`)
+);
+CREATE INDEX `idx_crop_status_transitions_crop_id` ON `crop_status_transitions`(`crop_id`);
@@ -0,0 +1,13 @@
+CREATE TABLE `harvest` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `crop_id` integer NOT NULL,
+    `harvested_at` datetime NOT NULL,
+    `quantity` real NOT NULL,
+    `unit` text NOT NULL,
+    `moisture` real,
+    `grade` text,
+    `partial` numeric NOT NULL,
+    `created_at` datetime,
+    CONSTRAINT `fk_crops_harvests` FOREIGN KEY (`crop_id`) REFERENCES `crops`(`id`)
+);
+CREATE INDEX `idx_harvest_crop_id` ON `harvest`(`crop_id`);
@@ -0,0 +1,17 @@
+CREATE TABLE `fertilizer_application` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `crop_id` integer NOT NULL,
+    `fertilizer_id` integer NOT NULL,
+    `applied_at` datetime NOT NULL,
+    `dose` real NOT NULL,
+    `dose_unit` text NOT NULL,
+    `applied_area` real NOT NULL,
+    `operator_id` integer,
+    `notes` text,
+    `created_at` datetime,
+    CONSTRAINT `fk_crops_applications` FOREIGN KEY (`crop_id`) REFERENCES `crops`(`id`),
+    CONSTRAINT `fk_fertilizer_applications` FOREIGN KEY (`fertilizer_id`) REFERENCES `fertilizer`(`id`),
+    CONSTRAINT `fk_fertilizer_application_operator` FOREIGN KEY (`operator_id`) REFERENCES `person`(`id`) ON DELETE SET NULL
+);
+CREATE INDEX `idx_fertilizer_application_fertilizer_id` ON `fertilizer_application`(`fertilizer_id`);
+CREATE INDEX `idx_fertilizer_application_crop_id` ON `fertilizer_application`(`crop_id`);
@@ -0,0 +1,15 @@
+ALTER TABLE `fertilizer` DROP COLUMN `pct_n`;
+ALTER TABLE `fertilizer` DROP COLUMN `pct_p2o5`;
+ALTER TABLE `fertilizer` DROP COLUMN `pct_k2o`;
+ALTER TABLE `fertilizer` DROP COLUMN `pct_s`;
+ALTER TABLE `fertilizer` DROP COLUMN `pct_ca`;
+ALTER TABLE `fertilizer` DROP COLUMN `pct_mg`;
+ALTER TABLE `fertilizer` DROP COLUMN `pct_b`;
+ALTER TABLE `fertilizer` DROP COLUMN `pct_cl`;
+ALTER TABLE `fertilizer` DROP COLUMN `pct_co`;
+ALTER TABLE `fertilizer` DROP COLUMN `pct_cu`;
+ALTER TABLE `fertilizer` DROP COLUMN `pct_fe`;
+ALTER TABLE `fertilizer` DROP COLUMN `pct_mn`;
+ALTER TABLE `fertilizer` DROP COLUMN `pct_mo`;
+ALTER TABLE `fertilizer` DROP COLUMN `pct_ni`;
+ALTER TABLE `fertilizer` DROP COLUMN `pct_zn`;
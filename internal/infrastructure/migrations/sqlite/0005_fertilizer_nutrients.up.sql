@@ -0,0 +1,15 @@
+ALTER TABLE `fertilizer` ADD COLUMN `pct_n` real NOT NULL DEFAULT 0;
+ALTER TABLE `fertilizer` ADD COLUMN `pct_p2o5` real NOT NULL DEFAULT 0;
+ALTER TABLE `fertilizer` ADD COLUMN `pct_k2o` real NOT NULL DEFAULT 0;
+ALTER TABLE `fertilizer` ADD COLUMN `pct_s` real NOT NULL DEFAULT 0;
+ALTER TABLE `fertilizer` ADD COLUMN `pct_ca` real NOT NULL DEFAULT 0;
+ALTER TABLE `fertilizer` ADD COLUMN `pct_mg` real NOT NULL DEFAULT 0;
+ALTER TABLE `fertilizer` ADD COLUMN `pct_b` real NOT NULL DEFAULT 0;
+ALTER TABLE `fertilizer` ADD COLUMN `pct_cl` real NOT NULL DEFAULT 0;
+ALTER TABLE `fertilizer` ADD COLUMN `pct_co` real NOT NULL DEFAULT 0;
+ALTER TABLE `fertilizer` ADD COLUMN `pct_cu` real NOT NULL DEFAULT 0;
+ALTER TABLE `fertilizer` ADD COLUMN `pct_fe` real NOT NULL DEFAULT 0;
+ALTER TABLE `fertilizer` ADD COLUMN `pct_mn` real NOT NULL DEFAULT 0;
+ALTER TABLE `fertilizer` ADD COLUMN `pct_mo` real NOT NULL DEFAULT 0;
+ALTER TABLE `fertilizer` ADD COLUMN `pct_ni` real NOT NULL DEFAULT 0;
+ALTER TABLE `fertilizer` ADD COLUMN `pct_zn` real NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+CREATE TABLE `farm_member` (
+    `farm_id` integer,
+    `person_id` integer,
+    `role` text NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime,
+    PRIMARY KEY (`farm_id`,`person_id`),
+    CONSTRAINT `fk_farm_member_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_farms_members` FOREIGN KEY (`farm_id`) REFERENCES `farms`(`id`) ON DELETE CASCADE
+);
+CREATE INDEX `idx_farm_member_person_id` ON `farm_member`(`person_id`);
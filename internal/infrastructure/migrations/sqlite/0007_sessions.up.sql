@@ -0,0 +1,21 @@
+CREATE TABLE `session` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `person_id` integer NOT NULL,
+    `revoked_at` datetime,
+    `revoked_reason` text,
+    `created_at` datetime,
+    CONSTRAINT `fk_session_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE
+);
+CREATE INDEX `idx_session_person_id` ON `session`(`person_id`);
+
+CREATE TABLE `refresh_token` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `session_id` integer NOT NULL,
+    `hash` text NOT NULL,
+    `expires_at` datetime NOT NULL,
+    `used_at` datetime,
+    `created_at` datetime,
+    CONSTRAINT `fk_refresh_token_session` FOREIGN KEY (`session_id`) REFERENCES `session`(`id`) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX `idx_refresh_token_hash` ON `refresh_token`(`hash`);
+CREATE INDEX `idx_refresh_token_session_id` ON `refresh_token`(`session_id`);
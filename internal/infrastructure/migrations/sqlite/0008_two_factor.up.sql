@@ -0,0 +1,35 @@
+CREATE TABLE `totp_enrollment` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `person_id` integer NOT NULL,
+    `secret` text NOT NULL,
+    `confirmed_at` datetime,
+    `last_used_step` integer NOT NULL DEFAULT 0,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_totp_enrollment_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX `idx_totp_enrollment_person_id` ON `totp_enrollment`(`person_id`);
+
+CREATE TABLE `recovery_code` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `enrollment_id` integer NOT NULL,
+    `hash` text NOT NULL,
+    `used_at` datetime,
+    `created_at` datetime,
+    CONSTRAINT `fk_totp_enrollment_recovery_codes` FOREIGN KEY (`enrollment_id`) REFERENCES `totp_enrollment`(`id`)
+);
+CREATE INDEX `idx_recovery_code_enrollment_id` ON `recovery_code`(`enrollment_id`);
+
+CREATE TABLE `login_challenge` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `person_id` integer NOT NULL,
+    `kind` text NOT NULL,
+    `hash` text NOT NULL,
+    `attempts` integer NOT NULL DEFAULT 0,
+    `expires_at` datetime NOT NULL,
+    `completed_at` datetime,
+    `created_at` datetime,
+    CONSTRAINT `fk_login_challenge_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX `idx_login_challenge_hash` ON `login_challenge`(`hash`);
+CREATE INDEX `idx_login_challenge_person_id` ON `login_challenge`(`person_id`);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `login_attempt`;
+ALTER TABLE `person` DROP COLUMN `failed_logins`;
+ALTER TABLE `person` DROP COLUMN `last_failed_login_at`;
+ALTER TABLE `person` DROP COLUMN `locked_until`;
@@ -0,0 +1,11 @@
+ALTER TABLE `person` ADD COLUMN `failed_logins` integer NOT NULL DEFAULT 0;
+ALTER TABLE `person` ADD COLUMN `last_failed_login_at` datetime;
+ALTER TABLE `person` ADD COLUMN `locked_until` datetime;
+
+CREATE TABLE `login_attempt` (
+    `key` text,
+    `failures` integer NOT NULL DEFAULT 0,
+    `last_failed_at` datetime,
+    `locked_until` datetime,
+    PRIMARY KEY (`key`)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE `password_reset_token` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `person_id` integer NOT NULL,
+    `hash` text NOT NULL,
+    `expires_at` datetime NOT NULL,
+    `used_at` datetime,
+    `created_at` datetime,
+    CONSTRAINT `fk_password_reset_token_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX `idx_password_reset_token_hash` ON `password_reset_token`(`hash`);
+CREATE INDEX `idx_password_reset_token_person_id` ON `password_reset_token`(`person_id`);
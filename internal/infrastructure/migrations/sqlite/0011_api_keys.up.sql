@@ -0,0 +1,17 @@
+CREATE TABLE `api_key` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `person_id` integer NOT NULL,
+    `kind` text NOT NULL,
+    `name` text NOT NULL,
+    `prefix` text NOT NULL,
+    `hash` text NOT NULL,
+    `role` text NOT NULL,
+    `farm_ids` text,
+    `expires_at` datetime,
+    `last_used_at` datetime,
+    `revoked_at` datetime,
+    `created_at` datetime,
+    CONSTRAINT `fk_api_key_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX `idx_api_key_prefix` ON `api_key`(`prefix`);
+CREATE INDEX `idx_api_key_person_id` ON `api_key`(`person_id`);
@@ -0,0 +1,21 @@
+CREATE TABLE `oidc_login_request` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `state_hash` text NOT NULL,
+    `nonce` text NOT NULL,
+    `code_verifier` text NOT NULL,
+    `expires_at` datetime NOT NULL,
+    `consumed_at` datetime,
+    `created_at` datetime
+);
+CREATE UNIQUE INDEX `idx_oidc_login_request_state_hash` ON `oidc_login_request`(`state_hash`);
+
+CREATE TABLE `oidc_link` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `person_id` integer NOT NULL,
+    `issuer` text NOT NULL,
+    `subject` text NOT NULL,
+    `created_at` datetime,
+    CONSTRAINT `fk_oidc_link_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX `idx_oidc_link_subject` ON `oidc_link`(`issuer`,`subject`);
+CREATE INDEX `idx_oidc_link_person_id` ON `oidc_link`(`person_id`);
@@ -0,0 +1,12 @@
+CREATE INDEX `idx_person_role` ON `person`(`role`);
+
+CREATE TABLE `person_role_change` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `person_id` integer NOT NULL,
+    `from_role` text NOT NULL,
+    `to_role` text NOT NULL,
+    `changed_by` integer,
+    `changed_at` datetime NOT NULL,
+    CONSTRAINT `fk_person_role_change_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`) ON DELETE CASCADE
+);
+CREATE INDEX `idx_person_role_change_person_id` ON `person_role_change`(`person_id`);
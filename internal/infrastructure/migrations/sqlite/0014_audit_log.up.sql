@@ -0,0 +1,16 @@
+CREATE TABLE `audit_entry` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `actor_id` integer,
+    `api_key_id` integer,
+    `actor_username` text,
+    `action` text NOT NULL,
+    `resource_type` text NOT NULL,
+    `resource_id` integer NOT NULL,
+    `changes` text NOT NULL,
+    `request_id` text,
+    `occurred_at` datetime NOT NULL
+);
+CREATE INDEX `idx_audit_entry_occurred_at` ON `audit_entry`(`occurred_at`);
+CREATE INDEX `idx_audit_entry_request_id` ON `audit_entry`(`request_id`);
+CREATE INDEX `idx_audit_resource` ON `audit_entry`(`resource_type`,`resource_id`);
+CREATE INDEX `idx_audit_entry_actor_id` ON `audit_entry`(`actor_id`);
@@ -0,0 +1,57 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/passwordreset"
+)
+
+// FileNotifier appends reset tokens to a file as JSON lines, e.g. for local
+// development or for tests that need to read the delivered token
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+// fileMessage is one line written by FileNotifier
+type fileMessage struct {
+	PersonID  int64     `json:"personId"`
+	Username  string    `json:"username"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// NewFileNotifier creates a notifier that appends reset tokens to path
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+// SendResetToken appends the token for the recipient to the file
+func (n *FileNotifier) SendResetToken(_ context.Context, recipient passwordreset.Recipient, token string, expiresAt time.Time) error {
+	line, err := json.Marshal(fileMessage{
+		PersonID:  recipient.PersonID,
+		Username:  recipient.Username,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open notification file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
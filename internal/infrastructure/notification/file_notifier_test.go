@@ -0,0 +1,52 @@
+package notification_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/passwordreset"
+	"github.com/cropflow/api/internal/infrastructure/notification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileNotifier_SendResetToken(t *testing.T) {
+	t.Run("should append one JSON line per token", func(t *testing.T) {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "reset-tokens.jsonl")
+		notifier := notification.NewFileNotifier(path)
+		expiresAt := time.Date(2024, 11, 5, 13, 0, 0, 0, time.UTC)
+		recipient := passwordreset.Recipient{PersonID: 7, Username: "alice"}
+
+		// Act
+		require.NoError(t, notifier.SendResetToken(context.Background(), recipient, "first", expiresAt))
+		require.NoError(t, notifier.SendResetToken(context.Background(), recipient, "second", expiresAt))
+
+		// Assert
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+
+		var tokens []string
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var message struct {
+				PersonID  int64     `json:"personId"`
+				Username  string    `json:"username"`
+				Token     string    `json:"token"`
+				ExpiresAt time.Time `json:"expiresAt"`
+			}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &message))
+			assert.Equal(t, int64(7), message.PersonID)
+			assert.Equal(t, "alice", message.Username)
+			assert.True(t, expiresAt.Equal(message.ExpiresAt))
+			tokens = append(tokens, message.Token)
+		}
+		assert.Equal(t, []string{"first", "second"}, tokens)
+	})
+}
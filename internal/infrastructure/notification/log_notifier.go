@@ -0,0 +1,25 @@
+package notification
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/passwordreset"
+)
+
+// LogNotifier writes reset tokens to the application log. It is meant for
+// local development only, as anyone reading the log can reset the password.
+type LogNotifier struct{}
+
+// NewLogNotifier creates a notifier that logs reset tokens
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// SendResetToken logs the token for the recipient
+func (n *LogNotifier) SendResetToken(_ context.Context, recipient passwordreset.Recipient, token string, expiresAt time.Time) error {
+	log.Printf("Password reset token for %s (person %d), valid until %s: %s",
+		recipient.Username, recipient.PersonID, expiresAt.Format(time.RFC3339), token)
+	return nil
+}
@@ -0,0 +1,117 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/identity"
+	"gorm.io/gorm/schema"
+)
+
+// auditOmittedColumns are bookkeeping columns left out of audit snapshots
+var auditOmittedColumns = map[string]bool{
+	"id":                   true,
+	"created_at":           true,
+	"updated_at":           true,
+	"failed_logins":        true,
+	"last_failed_login_at": true,
+}
+
+// auditRedactedFields are recorded as changed without their values
+var auditRedactedFields = []string{"password"}
+
+// snapshotSchemas caches the parsed schemas of the models Snapshot is given
+var snapshotSchemas sync.Map
+
+// NewAuditEntry builds the audit entry for a change to a resource on behalf
+// of the caller in ctx. before and after are snapshots of the resource, nil
+// when it does not exist; the entry is nil when they are equal.
+func NewAuditEntry(ctx context.Context, resourceType audit.ResourceType, resourceID int64, before, after map[string]any) (*audit.Entry, error) {
+	changes := audit.Diff(before, after, auditRedactedFields...)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	action := audit.ActionUpdate
+	switch {
+	case before == nil:
+		action = audit.ActionCreate
+	case after == nil:
+		action = audit.ActionDelete
+	}
+
+	actor, _ := identity.FromContext(ctx)
+	return audit.NewEntry(actor, audit.RequestIDFromContext(ctx), action, resourceType, resourceID, changes, time.Now())
+}
+
+// Snapshot captures the columns of a model as JSON values keyed by their API
+// field name, e.g. planted_area as plantedArea. Times are taken in UTC so a
+// value read back from the database compares equal to the one written.
+func Snapshot(model any) (map[string]any, error) {
+	s, err := schema.Parse(model, &snapshotSchemas, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(model))
+	values := make(map[string]any, len(s.Fields))
+	for _, field := range s.Fields {
+		if field.DBName == "" || auditOmittedColumns[field.DBName] {
+			continue
+		}
+		v, _ := field.ValueOf(context.Background(), value)
+		switch t := v.(type) {
+		case time.Time:
+			v = t.UTC()
+		case *time.Time:
+			if t != nil {
+				utc := t.UTC()
+				v = &utc
+			}
+		}
+		values[camelCase(field.DBName)] = v
+	}
+
+	// Round trip through JSON so snapshots hold the values as they are stored in the log
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	var snap map[string]any
+	if err := json.Unmarshal(encoded, &snap); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// MemberField names the audited field holding the role of a farm member
+func MemberField(personID int64) string {
+	return "members." + strconv.FormatInt(personID, 10)
+}
+
+// HarvestField and ApplicationField name the audited fields holding a
+// harvest or fertilizer application recorded on a crop
+func HarvestField(id int64) string {
+	return "harvests." + strconv.FormatInt(id, 10)
+}
+
+func ApplicationField(id int64) string {
+	return "applications." + strconv.FormatInt(id, 10)
+}
+
+// camelCase turns a column name such as planted_area into plantedArea
+func camelCase(column string) string {
+	parts := strings.Split(column, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,484 @@
+package persistence
+
+import (
+	"encoding/json"
+
+	"github.com/cropflow/api/internal/domain/apikey"
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/cropflow/api/internal/domain/farm"
+	"github.com/cropflow/api/internal/domain/fertilizer"
+	"github.com/cropflow/api/internal/domain/lockout"
+	"github.com/cropflow/api/internal/domain/mfa"
+	"github.com/cropflow/api/internal/domain/oidc"
+	"github.com/cropflow/api/internal/domain/passwordreset"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/session"
+)
+
+// ToFarmModel maps a farm aggregate to its database model
+func ToFarmModel(f *farm.Farm) *FarmModel {
+	return &FarmModel{
+		ID:        f.ID(),
+		Name:      f.Name(),
+		Size:      f.Size().Value(),
+		CreatedAt: f.CreatedAt(),
+		UpdatedAt: f.UpdatedAt(),
+	}
+}
+
+// ToFarmDomain maps a farm database model back to the aggregate
+func ToFarmDomain(m *FarmModel) (*farm.Farm, error) {
+	size, err := farm.NewSize(m.Size)
+	if err != nil {
+		return nil, err
+	}
+	return farm.Restore(m.ID, m.Name, size, m.CreatedAt, m.UpdatedAt), nil
+}
+
+// ToFarmMemberModel maps a farm membership to its database model
+func ToFarmMemberModel(m *farm.Member) *FarmMemberModel {
+	return &FarmMemberModel{
+		FarmID:    m.FarmID(),
+		PersonID:  m.PersonID(),
+		Role:      m.Role().String(),
+		CreatedAt: m.CreatedAt(),
+		UpdatedAt: m.UpdatedAt(),
+	}
+}
+
+// ToFarmMemberDomain maps a farm membership database model back to the entity
+func ToFarmMemberDomain(m *FarmMemberModel) *farm.Member {
+	return farm.RestoreMember(m.FarmID, m.PersonID, farm.MemberRole(m.Role), m.CreatedAt, m.UpdatedAt)
+}
+
+// ToCropModel maps a crop entity to its database model
+func ToCropModel(c *crop.Crop) *CropModel {
+	return &CropModel{
+		ID:              c.ID(),
+		Name:            c.Name(),
+		PlantedArea:     c.PlantedArea(),
+		FarmID:          c.FarmID(),
+		PlantedDate:     c.PlantedDate(),
+		HarvestDate:     c.HarvestDate(),
+		Status:          c.Status().String(),
+		StatusChangedAt: c.StatusChangedAt(),
+		CreatedAt:       c.CreatedAt(),
+		UpdatedAt:       c.UpdatedAt(),
+	}
+}
+
+// ToCropDomain maps a crop database model back to the entity
+func ToCropDomain(m *CropModel) *crop.Crop {
+	return crop.Restore(m.ID, m.Name, m.PlantedArea, m.FarmID, m.PlantedDate, m.HarvestDate, crop.Status(m.Status), m.StatusChangedAt, m.CreatedAt, m.UpdatedAt)
+}
+
+// ToCropTransitionModel maps a crop status transition to its database model
+func ToCropTransitionModel(cropID int64, t crop.Transition) *CropTransitionModel {
+	return &CropTransitionModel{
+		CropID:     cropID,
+		FromStatus: t.From().String(),
+		ToStatus:   t.To().String(),
+		OccurredAt: t.OccurredAt(),
+		Note:       t.Note(),
+		CreatedAt:  t.RecordedAt(),
+	}
+}
+
+// ToCropTransitionDomain maps a crop status history row back to the value object
+func ToCropTransitionDomain(m *CropTransitionModel) crop.Transition {
+	return crop.RestoreTransition(crop.Status(m.FromStatus), crop.Status(m.ToStatus), m.OccurredAt, m.Note, m.CreatedAt)
+}
+
+// ToHarvestModel maps a harvest entity to its database model
+func ToHarvestModel(h *crop.Harvest) *HarvestModel {
+	return &HarvestModel{
+		ID:          h.ID(),
+		CropID:      h.CropID(),
+		HarvestedAt: h.HarvestedAt(),
+		Quantity:    h.Quantity(),
+		Unit:        h.Unit().String(),
+		Moisture:    h.Moisture(),
+		Grade:       h.Grade(),
+		Partial:     h.IsPartial(),
+		CreatedAt:   h.CreatedAt(),
+	}
+}
+
+// ToHarvestDomain maps a harvest database model back to the entity
+func ToHarvestDomain(m *HarvestModel) *crop.Harvest {
+	return crop.RestoreHarvest(m.ID, m.CropID, m.HarvestedAt, m.Quantity, crop.Unit(m.Unit), m.Moisture, m.Grade, m.Partial, m.CreatedAt)
+}
+
+// ToApplicationModel maps a fertilizer application entity to its database model
+func ToApplicationModel(a *crop.Application) *ApplicationModel {
+	return &ApplicationModel{
+		ID:           a.ID(),
+		CropID:       a.CropID(),
+		FertilizerID: a.FertilizerID(),
+		AppliedAt:    a.AppliedAt(),
+		Dose:         a.Dose(),
+		DoseUnit:     a.DoseUnit().String(),
+		AppliedArea:  a.AppliedArea(),
+		OperatorID:   a.OperatorID(),
+		Notes:        a.Notes(),
+		CreatedAt:    a.CreatedAt(),
+	}
+}
+
+// ToApplicationDomain maps a fertilizer application database model back to the entity
+func ToApplicationDomain(m *ApplicationModel) *crop.Application {
+	return crop.RestoreApplication(m.ID, m.CropID, m.FertilizerID, m.AppliedAt, m.Dose, crop.DoseUnit(m.DoseUnit), m.AppliedArea, m.OperatorID, m.Notes, m.CreatedAt)
+}
+
+// ToFertilizerModel maps a fertilizer entity to its database model
+func ToFertilizerModel(f *fertilizer.Fertilizer) *FertilizerModel {
+	return &FertilizerModel{
+		ID:          f.ID(),
+		Name:        f.Name(),
+		Brand:       f.Brand(),
+		Composition: f.Composition().String(),
+		Nutrients:   toNutrientsModel(f.Composition()),
+		CreatedAt:   f.CreatedAt(),
+		UpdatedAt:   f.UpdatedAt(),
+	}
+}
+
+// ToFertilizerDomain maps a fertilizer database model back to the entity.
+// Rows written before the structured columns existed only carry the label,
+// which is parsed on the fly; labels that cannot be parsed yield an empty composition.
+func ToFertilizerDomain(m *FertilizerModel) *fertilizer.Fertilizer {
+	composition := toComposition(m.Nutrients)
+	if composition.IsEmpty() {
+		if legacy, err := fertilizer.ParseComposition(m.Composition); err == nil {
+			composition = legacy
+		}
+	}
+	return fertilizer.Restore(m.ID, m.Name, m.Brand, composition, m.CreatedAt, m.UpdatedAt)
+}
+
+func toNutrientsModel(c fertilizer.Composition) NutrientsModel {
+	return NutrientsModel{
+		N:    c.Percentage(fertilizer.Nitrogen),
+		P2O5: c.Percentage(fertilizer.Phosphate),
+		K2O:  c.Percentage(fertilizer.Potash),
+		S:    c.Percentage(fertilizer.Sulfur),
+		Ca:   c.Percentage(fertilizer.Calcium),
+		Mg:   c.Percentage(fertilizer.Magnesium),
+		B:    c.Percentage(fertilizer.Boron),
+		Cl:   c.Percentage(fertilizer.Chlorine),
+		Co:   c.Percentage(fertilizer.Cobalt),
+		Cu:   c.Percentage(fertilizer.Copper),
+		Fe:   c.Percentage(fertilizer.Iron),
+		Mn:   c.Percentage(fertilizer.Manganese),
+		Mo:   c.Percentage(fertilizer.Molybdenum),
+		Ni:   c.Percentage(fertilizer.Nickel),
+		Zn:   c.Percentage(fertilizer.Zinc),
+	}
+}
+
+func toComposition(m NutrientsModel) fertilizer.Composition {
+	return fertilizer.RestoreComposition(map[fertilizer.Nutrient]float64{
+		fertilizer.Nitrogen:   m.N,
+		fertilizer.Phosphate:  m.P2O5,
+		fertilizer.Potash:     m.K2O,
+		fertilizer.Sulfur:     m.S,
+		fertilizer.Calcium:    m.Ca,
+		fertilizer.Magnesium:  m.Mg,
+		fertilizer.Boron:      m.B,
+		fertilizer.Chlorine:   m.Cl,
+		fertilizer.Cobalt:     m.Co,
+		fertilizer.Copper:     m.Cu,
+		fertilizer.Iron:       m.Fe,
+		fertilizer.Manganese:  m.Mn,
+		fertilizer.Molybdenum: m.Mo,
+		fertilizer.Nickel:     m.Ni,
+		fertilizer.Zinc:       m.Zn,
+	})
+}
+
+// ToPersonModel maps a person aggregate to its database model
+func ToPersonModel(p *person.Person) *PersonModel {
+	status := p.LoginStatus()
+	return &PersonModel{
+		ID:                p.ID(),
+		Username:          p.Username(),
+		Password:          p.Password().Hash(),
+		Role:              p.Role().String(),
+		FailedLogins:      status.Failures(),
+		LastFailedLoginAt: status.LastFailedAt(),
+		LockedUntil:       status.LockedUntil(),
+		CreatedAt:         p.CreatedAt(),
+		UpdatedAt:         p.UpdatedAt(),
+	}
+}
+
+// ToPersonDomain maps a person database model back to the aggregate
+func ToPersonDomain(m *PersonModel) (*person.Person, error) {
+	role, err := person.NewRole(m.Role)
+	if err != nil {
+		return nil, err
+	}
+	status := lockout.RestoreStatus(m.FailedLogins, m.LastFailedLoginAt, m.LockedUntil)
+	return person.Restore(m.ID, m.Username, m.Password, role, m.CreatedAt, m.UpdatedAt, status), nil
+}
+
+// ToRoleChangeModel maps a person role change to its database model
+func ToRoleChangeModel(personID int64, c person.RoleChange) *RoleChangeModel {
+	model := &RoleChangeModel{
+		PersonID:  personID,
+		FromRole:  c.From().String(),
+		ToRole:    c.To().String(),
+		ChangedAt: c.ChangedAt(),
+	}
+	if changedBy := c.ChangedBy(); changedBy != 0 {
+		model.ChangedBy = &changedBy
+	}
+	return model
+}
+
+// ToRoleChangeDomain maps a person role history row back to the value object
+func ToRoleChangeDomain(m *RoleChangeModel) person.RoleChange {
+	var changedBy int64
+	if m.ChangedBy != nil {
+		changedBy = *m.ChangedBy
+	}
+	return person.RestoreRoleChange(person.Role(m.FromRole), person.Role(m.ToRole), changedBy, m.ChangedAt)
+}
+
+// ToSessionModel maps a session aggregate to its database model
+func ToSessionModel(s *session.Session) *SessionModel {
+	return &SessionModel{
+		ID:            s.ID(),
+		PersonID:      s.PersonID(),
+		RevokedAt:     s.RevokedAt(),
+		RevokedReason: s.RevokedReason(),
+		CreatedAt:     s.CreatedAt(),
+	}
+}
+
+// ToSessionDomain maps a session database model back to the aggregate
+func ToSessionDomain(m *SessionModel) *session.Session {
+	return session.Restore(m.ID, m.PersonID, m.CreatedAt, m.RevokedAt, m.RevokedReason)
+}
+
+// ToRefreshTokenModel maps a refresh token to its database model
+func ToRefreshTokenModel(t *session.RefreshToken) *RefreshTokenModel {
+	return &RefreshTokenModel{
+		ID:        t.ID(),
+		SessionID: t.SessionID(),
+		Hash:      t.Hash(),
+		ExpiresAt: t.ExpiresAt(),
+		UsedAt:    t.UsedAt(),
+		CreatedAt: t.CreatedAt(),
+	}
+}
+
+// ToRefreshTokenDomain maps a refresh token database model back to the entity
+func ToRefreshTokenDomain(m *RefreshTokenModel) *session.RefreshToken {
+	return session.RestoreRefreshToken(m.ID, m.SessionID, m.Hash, m.ExpiresAt, m.UsedAt, m.CreatedAt)
+}
+
+// ToTOTPEnrollmentModel maps a TOTP enrolment to its database model
+func ToTOTPEnrollmentModel(e *mfa.Enrollment) *TOTPEnrollmentModel {
+	return &TOTPEnrollmentModel{
+		ID:           e.ID(),
+		PersonID:     e.PersonID(),
+		Secret:       e.Secret(),
+		ConfirmedAt:  e.ConfirmedAt(),
+		LastUsedStep: e.LastUsedStep(),
+		CreatedAt:    e.CreatedAt(),
+		UpdatedAt:    e.UpdatedAt(),
+	}
+}
+
+// ToTOTPEnrollmentDomain maps a TOTP enrolment database model back to the aggregate
+func ToTOTPEnrollmentDomain(m *TOTPEnrollmentModel) *mfa.Enrollment {
+	codes := make([]*mfa.RecoveryCode, len(m.RecoveryCodes))
+	for i := range m.RecoveryCodes {
+		codes[i] = ToRecoveryCodeDomain(&m.RecoveryCodes[i])
+	}
+	return mfa.RestoreEnrollment(m.ID, m.PersonID, m.Secret, m.ConfirmedAt, m.LastUsedStep, m.CreatedAt, m.UpdatedAt, codes)
+}
+
+// ToRecoveryCodeModel maps a recovery code to its database model
+func ToRecoveryCodeModel(c *mfa.RecoveryCode) *RecoveryCodeModel {
+	return &RecoveryCodeModel{
+		ID:           c.ID(),
+		EnrollmentID: c.EnrollmentID(),
+		Hash:         c.Hash(),
+		UsedAt:       c.UsedAt(),
+		CreatedAt:    c.CreatedAt(),
+	}
+}
+
+// ToRecoveryCodeDomain maps a recovery code database model back to the entity
+func ToRecoveryCodeDomain(m *RecoveryCodeModel) *mfa.RecoveryCode {
+	return mfa.RestoreRecoveryCode(m.ID, m.EnrollmentID, m.Hash, m.UsedAt, m.CreatedAt)
+}
+
+// ToLoginChallengeModel maps a login challenge to its database model
+func ToLoginChallengeModel(c *mfa.Challenge) *LoginChallengeModel {
+	return &LoginChallengeModel{
+		ID:          c.ID(),
+		PersonID:    c.PersonID(),
+		Kind:        c.Kind().String(),
+		Hash:        c.Hash(),
+		Attempts:    c.Attempts(),
+		ExpiresAt:   c.ExpiresAt(),
+		CompletedAt: c.CompletedAt(),
+		CreatedAt:   c.CreatedAt(),
+	}
+}
+
+// ToLoginChallengeDomain maps a login challenge database model back to the entity
+func ToLoginChallengeDomain(m *LoginChallengeModel) *mfa.Challenge {
+	return mfa.RestoreChallenge(m.ID, m.PersonID, mfa.ChallengeKind(m.Kind), m.Hash, m.Attempts, m.ExpiresAt, m.CompletedAt, m.CreatedAt)
+}
+
+// ToLoginAttemptModel maps the failed login status of a key to its database model
+func ToLoginAttemptModel(key string, s lockout.Status) *LoginAttemptModel {
+	return &LoginAttemptModel{
+		Key:          key,
+		Failures:     s.Failures(),
+		LastFailedAt: s.LastFailedAt(),
+		LockedUntil:  s.LockedUntil(),
+	}
+}
+
+// ToLoginAttemptDomain maps a login attempt database model back to the status
+func ToLoginAttemptDomain(m *LoginAttemptModel) lockout.Status {
+	return lockout.RestoreStatus(m.Failures, m.LastFailedAt, m.LockedUntil)
+}
+
+// ToPasswordResetTokenModel maps a password reset token to its database model
+func ToPasswordResetTokenModel(t *passwordreset.Token) *PasswordResetTokenModel {
+	return &PasswordResetTokenModel{
+		ID:        t.ID(),
+		PersonID:  t.PersonID(),
+		Hash:      t.Hash(),
+		ExpiresAt: t.ExpiresAt(),
+		UsedAt:    t.UsedAt(),
+		CreatedAt: t.CreatedAt(),
+	}
+}
+
+// ToPasswordResetTokenDomain maps a password reset token database model back to the entity
+func ToPasswordResetTokenDomain(m *PasswordResetTokenModel) *passwordreset.Token {
+	return passwordreset.RestoreToken(m.ID, m.PersonID, m.Hash, m.ExpiresAt, m.UsedAt, m.CreatedAt)
+}
+
+// ToAPIKeyModel maps an API key to its database model
+func ToAPIKeyModel(k *apikey.Key) *APIKeyModel {
+	return &APIKeyModel{
+		ID:         k.ID(),
+		PersonID:   k.OwnerID(),
+		Kind:       k.Kind().String(),
+		Name:       k.Name(),
+		Prefix:     k.Prefix(),
+		Hash:       k.Hash(),
+		Role:       k.Role().String(),
+		FarmIDs:    k.FarmIDs(),
+		ExpiresAt:  k.ExpiresAt(),
+		LastUsedAt: k.LastUsedAt(),
+		RevokedAt:  k.RevokedAt(),
+		CreatedAt:  k.CreatedAt(),
+	}
+}
+
+// ToAPIKeyDomain maps an API key database model back to the entity
+func ToAPIKeyDomain(m *APIKeyModel) *apikey.Key {
+	return apikey.RestoreKey(m.ID, m.PersonID, apikey.Kind(m.Kind), m.Name, m.Prefix, m.Hash, person.Role(m.Role),
+		m.FarmIDs, m.ExpiresAt, m.LastUsedAt, m.RevokedAt, m.CreatedAt)
+}
+
+// ToOIDCLoginRequestModel maps a pending OIDC login to its database model
+func ToOIDCLoginRequestModel(r *oidc.LoginRequest) *OIDCLoginRequestModel {
+	return &OIDCLoginRequestModel{
+		ID:           r.ID(),
+		StateHash:    r.StateHash(),
+		Nonce:        r.Nonce(),
+		CodeVerifier: r.CodeVerifier(),
+		ExpiresAt:    r.ExpiresAt(),
+		ConsumedAt:   r.ConsumedAt(),
+		CreatedAt:    r.CreatedAt(),
+	}
+}
+
+// ToOIDCLoginRequestDomain maps a pending OIDC login database model back to the entity
+func ToOIDCLoginRequestDomain(m *OIDCLoginRequestModel) *oidc.LoginRequest {
+	return oidc.RestoreLoginRequest(m.ID, m.StateHash, m.Nonce, m.CodeVerifier, m.ExpiresAt, m.ConsumedAt, m.CreatedAt)
+}
+
+// ToOIDCLinkModel maps an OIDC link to its database model
+func ToOIDCLinkModel(l *oidc.Link) *OIDCLinkModel {
+	return &OIDCLinkModel{
+		ID:        l.ID(),
+		PersonID:  l.PersonID(),
+		Issuer:    l.Issuer(),
+		Subject:   l.Subject(),
+		CreatedAt: l.CreatedAt(),
+	}
+}
+
+// ToOIDCLinkDomain maps an OIDC link database model back to the entity
+func ToOIDCLinkDomain(m *OIDCLinkModel) *oidc.Link {
+	return oidc.RestoreLink(m.ID, m.PersonID, m.Issuer, m.Subject, m.CreatedAt)
+}
+
+// auditChange is the stored form of an audit.Change
+type auditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// ToAuditEntryModel maps an audit entry to its database model
+func ToAuditEntryModel(e *audit.Entry) (*AuditEntryModel, error) {
+	changes := make(map[string]auditChange, len(e.Changes()))
+	for field, c := range e.Changes() {
+		changes[field] = auditChange{Before: c.Before, After: c.After}
+	}
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &AuditEntryModel{
+		ID:            e.ID(),
+		ActorUsername: e.ActorUsername(),
+		Action:        e.Action().String(),
+		ResourceType:  e.ResourceType().String(),
+		ResourceID:    e.ResourceID(),
+		Changes:       string(encoded),
+		RequestID:     e.RequestID(),
+		OccurredAt:    e.OccurredAt(),
+	}
+	if actorID := e.ActorID(); actorID != 0 {
+		model.ActorID = &actorID
+	}
+	if apiKeyID := e.APIKeyID(); apiKeyID != 0 {
+		model.APIKeyID = &apiKeyID
+	}
+	return model, nil
+}
+
+// ToAuditEntryDomain maps an audit log row back to the entry
+func ToAuditEntryDomain(m *AuditEntryModel) (*audit.Entry, error) {
+	var stored map[string]auditChange
+	if err := json.Unmarshal([]byte(m.Changes), &stored); err != nil {
+		return nil, err
+	}
+	changes := make(map[string]audit.Change, len(stored))
+	for field, c := range stored {
+		changes[field] = audit.Change{Before: c.Before, After: c.After}
+	}
+
+	var actorID, apiKeyID int64
+	if m.ActorID != nil {
+		actorID = *m.ActorID
+	}
+	if m.APIKeyID != nil {
+		apiKeyID = *m.APIKeyID
+	}
+	return audit.RestoreEntry(m.ID, actorID, apiKeyID, m.ActorUsername, audit.Action(m.Action), audit.ResourceType(m.ResourceType), m.ResourceID, changes, m.RequestID, m.OccurredAt), nil
+}
@@ -4,11 +4,13 @@ import "time"
 
 // FarmModel represents the farm database model
 type FarmModel struct {
-	ID        int64     `gorm:"primaryKey;autoIncrement"`
-	Name      string    `gorm:"not null"`
-	Size      float64   `gorm:"not null"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	ID        int64             `gorm:"primaryKey;autoIncrement"`
+	Name      string            `gorm:"not null"`
+	Size      float64           `gorm:"not null"`
+	Crops     []CropModel       `gorm:"foreignKey:FarmID"`
+	Members   []FarmMemberModel `gorm:"foreignKey:FarmID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time         `gorm:"autoCreateTime"`
+	UpdatedAt time.Time         `gorm:"autoUpdateTime"`
 }
 
 // TableName overrides the default table name
@@ -16,16 +18,36 @@ func (FarmModel) TableName() string {
 	return "farms"
 }
 
+// FarmMemberModel represents the membership of a person in a farm in the database
+type FarmMemberModel struct {
+	FarmID    int64        `gorm:"column:farm_id;primaryKey;autoIncrement:false"`
+	PersonID  int64        `gorm:"column:person_id;primaryKey;autoIncrement:false;index"`
+	Person    *PersonModel `gorm:"foreignKey:PersonID;constraint:OnDelete:CASCADE"`
+	Role      string       `gorm:"size:16;not null"`
+	CreatedAt time.Time    `gorm:"autoCreateTime"`
+	UpdatedAt time.Time    `gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the default table name
+func (FarmMemberModel) TableName() string {
+	return "farm_member"
+}
+
 // CropModel represents the crop database model
 type CropModel struct {
-	ID          int64      `gorm:"primaryKey;autoIncrement"`
-	Name        string     `gorm:"not null"`
-	PlantedArea float64    `gorm:"column:planted_area;not null"`
-	FarmID      int64      `gorm:"column:farm_id;not null"`
-	PlantedDate *time.Time `gorm:"column:planting_date"`
-	HarvestDate *time.Time `gorm:"column:harvest_date"`
-	CreatedAt   time.Time  `gorm:"autoCreateTime"`
-	UpdatedAt   time.Time  `gorm:"autoUpdateTime"`
+	ID              int64                 `gorm:"primaryKey;autoIncrement"`
+	Name            string                `gorm:"not null"`
+	PlantedArea     float64               `gorm:"column:planted_area;not null"`
+	FarmID          int64                 `gorm:"column:farm_id;not null"`
+	PlantedDate     *time.Time            `gorm:"column:planting_date"`
+	HarvestDate     *time.Time            `gorm:"column:harvest_date"`
+	Status          string                `gorm:"size:16;not null;default:PLANNED;index"`
+	StatusChangedAt *time.Time            `gorm:"column:status_changed_at"`
+	Applications    []ApplicationModel    `gorm:"foreignKey:CropID"`
+	Transitions     []CropTransitionModel `gorm:"foreignKey:CropID"`
+	Harvests        []HarvestModel        `gorm:"foreignKey:CropID"`
+	CreatedAt       time.Time             `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time             `gorm:"autoUpdateTime"`
 }
 
 // TableName overrides the default table name
@@ -33,14 +55,51 @@ func (CropModel) TableName() string {
 	return "crops"
 }
 
+// CropTransitionModel represents a row of the crop status history
+type CropTransitionModel struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	CropID     int64     `gorm:"column:crop_id;not null;index"`
+	FromStatus string    `gorm:"size:16"`
+	ToStatus   string    `gorm:"size:16;not null"`
+	OccurredAt time.Time `gorm:"not null"`
+	Note       string
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName overrides the default table name
+func (CropTransitionModel) TableName() string {
+	return "crop_status_transitions"
+}
+
+// HarvestModel represents a harvest of a crop in the database
+type HarvestModel struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	CropID      int64     `gorm:"column:crop_id;not null;index"`
+	HarvestedAt time.Time `gorm:"not null"`
+	Quantity    float64   `gorm:"not null"`
+	Unit        string    `gorm:"size:16;not null"`
+	Moisture    *float64
+	Grade       string    `gorm:"size:32"`
+	Partial     bool      `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName overrides the default table name
+func (HarvestModel) TableName() string {
+	return "harvest"
+}
+
 // PersonModel represents the person database model
 type PersonModel struct {
-	ID        int64     `gorm:"primaryKey;autoIncrement"`
-	Username  string    `gorm:"unique;not null"`
-	Password  string    `gorm:"not null"`
-	Role      string    `gorm:"not null"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	ID                int64      `gorm:"primaryKey;autoIncrement"`
+	Username          string     `gorm:"unique;not null"`
+	Password          string     `gorm:"not null"`
+	Role              string     `gorm:"not null;index"`
+	FailedLogins      int        `gorm:"column:failed_logins;not null;default:0"`
+	LastFailedLoginAt *time.Time `gorm:"column:last_failed_login_at"`
+	LockedUntil       *time.Time `gorm:"column:locked_until"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime"`
 }
 
 // TableName overrides the default table name
@@ -48,17 +107,261 @@ func (PersonModel) TableName() string {
 	return "person"
 }
 
+// RoleChangeModel represents a row of the person role history. ChangedBy has
+// no foreign key so the history outlives the admin who made the change.
+type RoleChangeModel struct {
+	ID        int64        `gorm:"primaryKey;autoIncrement"`
+	PersonID  int64        `gorm:"column:person_id;not null;index"`
+	Person    *PersonModel `gorm:"foreignKey:PersonID;constraint:OnDelete:CASCADE"`
+	FromRole  string       `gorm:"size:32;not null"`
+	ToRole    string       `gorm:"size:32;not null"`
+	ChangedBy *int64       `gorm:"column:changed_by"`
+	ChangedAt time.Time    `gorm:"not null"`
+}
+
+// TableName overrides the default table name
+func (RoleChangeModel) TableName() string {
+	return "person_role_change"
+}
+
 // FertilizerModel represents the fertilizer database model
 type FertilizerModel struct {
-	ID          int64     `gorm:"primaryKey;autoIncrement"`
-	Name        string    `gorm:"not null"`
-	Brand       string    `gorm:"not null"`
-	Composition string    `gorm:"not null"`
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+	ID           int64              `gorm:"primaryKey;autoIncrement"`
+	Name         string             `gorm:"not null"`
+	Brand        string             `gorm:"not null"`
+	Composition  string             `gorm:"not null"`
+	Nutrients    NutrientsModel     `gorm:"embedded;embeddedPrefix:pct_"`
+	Applications []ApplicationModel `gorm:"foreignKey:FertilizerID"`
+	CreatedAt    time.Time          `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time          `gorm:"autoUpdateTime"`
 }
 
 // TableName overrides the default table name
 func (FertilizerModel) TableName() string {
 	return "fertilizer"
 }
+
+// NutrientsModel holds the nutrient percentages of a fertilizer composition,
+// one column per nutrient
+type NutrientsModel struct {
+	N    float64 `gorm:"column:n;not null;default:0"`
+	P2O5 float64 `gorm:"column:p2o5;not null;default:0"`
+	K2O  float64 `gorm:"column:k2o;not null;default:0"`
+	S    float64 `gorm:"column:s;not null;default:0"`
+	Ca   float64 `gorm:"column:ca;not null;default:0"`
+	Mg   float64 `gorm:"column:mg;not null;default:0"`
+	B    float64 `gorm:"column:b;not null;default:0"`
+	Cl   float64 `gorm:"column:cl;not null;default:0"`
+	Co   float64 `gorm:"column:co;not null;default:0"`
+	Cu   float64 `gorm:"column:cu;not null;default:0"`
+	Fe   float64 `gorm:"column:fe;not null;default:0"`
+	Mn   float64 `gorm:"column:mn;not null;default:0"`
+	Mo   float64 `gorm:"column:mo;not null;default:0"`
+	Ni   float64 `gorm:"column:ni;not null;default:0"`
+	Zn   float64 `gorm:"column:zn;not null;default:0"`
+}
+
+// ApplicationModel represents a fertilizer application on a crop in the database
+type ApplicationModel struct {
+	ID           int64        `gorm:"primaryKey;autoIncrement"`
+	CropID       int64        `gorm:"column:crop_id;not null;index"`
+	FertilizerID int64        `gorm:"column:fertilizer_id;not null;index"`
+	AppliedAt    time.Time    `gorm:"not null"`
+	Dose         float64      `gorm:"not null"`
+	DoseUnit     string       `gorm:"size:16;not null"`
+	AppliedArea  float64      `gorm:"not null"`
+	OperatorID   *int64       `gorm:"column:operator_id"`
+	Operator     *PersonModel `gorm:"foreignKey:OperatorID;constraint:OnDelete:SET NULL"`
+	Notes        string
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName overrides the default table name
+func (ApplicationModel) TableName() string {
+	return "fertilizer_application"
+}
+
+// SessionModel represents a login session in the database
+type SessionModel struct {
+	ID            int64        `gorm:"primaryKey;autoIncrement"`
+	PersonID      int64        `gorm:"column:person_id;not null;index"`
+	Person        *PersonModel `gorm:"foreignKey:PersonID;constraint:OnDelete:CASCADE"`
+	RevokedAt     *time.Time   `gorm:"column:revoked_at"`
+	RevokedReason string       `gorm:"column:revoked_reason;size:64"`
+	CreatedAt     time.Time    `gorm:"autoCreateTime"`
+}
+
+// TableName overrides the default table name
+func (SessionModel) TableName() string {
+	return "session"
+}
+
+// RefreshTokenModel represents a hashed refresh token of a session in the database
+type RefreshTokenModel struct {
+	ID        int64         `gorm:"primaryKey;autoIncrement"`
+	SessionID int64         `gorm:"column:session_id;not null;index"`
+	Session   *SessionModel `gorm:"foreignKey:SessionID;constraint:OnDelete:CASCADE"`
+	Hash      string        `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time     `gorm:"not null"`
+	UsedAt    *time.Time    `gorm:"column:used_at"`
+	CreatedAt time.Time     `gorm:"autoCreateTime"`
+}
+
+// TableName overrides the default table name
+func (RefreshTokenModel) TableName() string {
+	return "refresh_token"
+}
+
+// TOTPEnrollmentModel represents the TOTP second factor of a person in the database
+type TOTPEnrollmentModel struct {
+	ID            int64               `gorm:"primaryKey;autoIncrement"`
+	PersonID      int64               `gorm:"column:person_id;not null;uniqueIndex"`
+	Person        *PersonModel        `gorm:"foreignKey:PersonID;constraint:OnDelete:CASCADE"`
+	Secret        string              `gorm:"size:64;not null"`
+	ConfirmedAt   *time.Time          `gorm:"column:confirmed_at"`
+	LastUsedStep  int64               `gorm:"column:last_used_step;not null;default:0"`
+	RecoveryCodes []RecoveryCodeModel `gorm:"foreignKey:EnrollmentID"`
+	CreatedAt     time.Time           `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time           `gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the default table name
+func (TOTPEnrollmentModel) TableName() string {
+	return "totp_enrollment"
+}
+
+// RecoveryCodeModel represents a hashed recovery code of a TOTP enrolment in the database
+type RecoveryCodeModel struct {
+	ID           int64                `gorm:"primaryKey;autoIncrement"`
+	EnrollmentID int64                `gorm:"column:enrollment_id;not null;index"`
+	Enrollment   *TOTPEnrollmentModel `gorm:"foreignKey:EnrollmentID;constraint:OnDelete:CASCADE"`
+	Hash         string               `gorm:"size:64;not null"`
+	UsedAt       *time.Time           `gorm:"column:used_at"`
+	CreatedAt    time.Time            `gorm:"autoCreateTime"`
+}
+
+// TableName overrides the default table name
+func (RecoveryCodeModel) TableName() string {
+	return "recovery_code"
+}
+
+// LoginChallengeModel represents the pending second step of a login in the database
+type LoginChallengeModel struct {
+	ID          int64        `gorm:"primaryKey;autoIncrement"`
+	PersonID    int64        `gorm:"column:person_id;not null;index"`
+	Person      *PersonModel `gorm:"foreignKey:PersonID;constraint:OnDelete:CASCADE"`
+	Kind        string       `gorm:"size:16;not null"`
+	Hash        string       `gorm:"size:64;not null;uniqueIndex"`
+	Attempts    int          `gorm:"not null;default:0"`
+	ExpiresAt   time.Time    `gorm:"not null"`
+	CompletedAt *time.Time   `gorm:"column:completed_at"`
+	CreatedAt   time.Time    `gorm:"autoCreateTime"`
+}
+
+// TableName overrides the default table name
+func (LoginChallengeModel) TableName() string {
+	return "login_challenge"
+}
+
+// LoginAttemptModel represents the failed logins of a client IP address or of
+// a username without a person in the database
+type LoginAttemptModel struct {
+	Key          string     `gorm:"primaryKey;size:191"`
+	Failures     int        `gorm:"not null;default:0"`
+	LastFailedAt *time.Time `gorm:"column:last_failed_at"`
+	LockedUntil  *time.Time `gorm:"column:locked_until"`
+}
+
+// TableName overrides the default table name
+func (LoginAttemptModel) TableName() string {
+	return "login_attempt"
+}
+
+// PasswordResetTokenModel represents a one-time password reset token in the database
+type PasswordResetTokenModel struct {
+	ID        int64        `gorm:"primaryKey;autoIncrement"`
+	PersonID  int64        `gorm:"column:person_id;not null;index"`
+	Person    *PersonModel `gorm:"foreignKey:PersonID;constraint:OnDelete:CASCADE"`
+	Hash      string       `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time    `gorm:"not null"`
+	UsedAt    *time.Time   `gorm:"column:used_at"`
+	CreatedAt time.Time    `gorm:"autoCreateTime"`
+}
+
+// TableName overrides the default table name
+func (PasswordResetTokenModel) TableName() string {
+	return "password_reset_token"
+}
+
+// APIKeyModel represents an API key in the database
+type APIKeyModel struct {
+	ID         int64        `gorm:"primaryKey;autoIncrement"`
+	PersonID   int64        `gorm:"column:person_id;not null;index"`
+	Person     *PersonModel `gorm:"foreignKey:PersonID;constraint:OnDelete:CASCADE"`
+	Kind       string       `gorm:"size:16;not null"`
+	Name       string       `gorm:"size:100;not null"`
+	Prefix     string       `gorm:"size:32;not null;uniqueIndex"`
+	Hash       string       `gorm:"size:64;not null"`
+	Role       string       `gorm:"size:50;not null"`
+	FarmIDs    []int64      `gorm:"column:farm_ids;serializer:json"`
+	ExpiresAt  *time.Time   `gorm:"column:expires_at"`
+	LastUsedAt *time.Time   `gorm:"column:last_used_at"`
+	RevokedAt  *time.Time   `gorm:"column:revoked_at"`
+	CreatedAt  time.Time    `gorm:"autoCreateTime"`
+}
+
+// TableName overrides the default table name
+func (APIKeyModel) TableName() string {
+	return "api_key"
+}
+
+// OIDCLoginRequestModel represents a pending OpenID Connect login in the database
+type OIDCLoginRequestModel struct {
+	ID           int64      `gorm:"primaryKey;autoIncrement"`
+	StateHash    string     `gorm:"column:state_hash;size:64;not null;uniqueIndex"`
+	Nonce        string     `gorm:"size:64;not null"`
+	CodeVerifier string     `gorm:"column:code_verifier;size:128;not null"`
+	ExpiresAt    time.Time  `gorm:"not null"`
+	ConsumedAt   *time.Time `gorm:"column:consumed_at"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+}
+
+// TableName overrides the default table name
+func (OIDCLoginRequestModel) TableName() string {
+	return "oidc_login_request"
+}
+
+// OIDCLinkModel ties a person to their account at an identity provider
+type OIDCLinkModel struct {
+	ID        int64        `gorm:"primaryKey;autoIncrement"`
+	PersonID  int64        `gorm:"column:person_id;not null;index"`
+	Person    *PersonModel `gorm:"foreignKey:PersonID;constraint:OnDelete:CASCADE"`
+	Issuer    string       `gorm:"size:255;not null;uniqueIndex:idx_oidc_link_subject"`
+	Subject   string       `gorm:"size:255;not null;uniqueIndex:idx_oidc_link_subject"`
+	CreatedAt time.Time    `gorm:"autoCreateTime"`
+}
+
+// TableName overrides the default table name
+func (OIDCLinkModel) TableName() string {
+	return "oidc_link"
+}
+
+// AuditEntryModel represents a row of the append-only audit log. Changes holds
+// the JSON object of the changed fields, each with its before and after value.
+type AuditEntryModel struct {
+	ID            int64     `gorm:"primaryKey;autoIncrement"`
+	ActorID       *int64    `gorm:"column:actor_id;index"`
+	APIKeyID      *int64    `gorm:"column:api_key_id"`
+	ActorUsername string    `gorm:"column:actor_username;size:255"`
+	Action        string    `gorm:"size:16;not null"`
+	ResourceType  string    `gorm:"column:resource_type;size:32;not null;index:idx_audit_resource"`
+	ResourceID    int64     `gorm:"column:resource_id;not null;index:idx_audit_resource"`
+	Changes       string    `gorm:"type:text;not null"`
+	RequestID     string    `gorm:"column:request_id;size:64;index"`
+	OccurredAt    time.Time `gorm:"not null;index"`
+}
+
+// TableName overrides the default table name
+func (AuditEntryModel) TableName() string {
+	return "audit_entry"
+}
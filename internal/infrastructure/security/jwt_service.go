@@ -14,32 +14,44 @@ var (
 
 // JWTService handles JWT token generation and validation
 type JWTService struct {
-	secretKey []byte
-	issuer    string
+	keys   *KeySet
+	issuer string
+	ttl    time.Duration
 }
 
-// NewJWTService creates a new JWT service
-func NewJWTService(secretKey, issuer string) *JWTService {
+// NewJWTService creates a new JWT service signing access tokens valid for ttl
+// with the active key of keys
+func NewJWTService(keys *KeySet, issuer string, ttl time.Duration) *JWTService {
 	return &JWTService{
-		secretKey: []byte(secretKey),
-		issuer:    issuer,
+		keys:   keys,
+		issuer: issuer,
+		ttl:    ttl,
 	}
 }
 
+// TTL returns how long issued access tokens are valid
+func (s *JWTService) TTL() time.Duration {
+	return s.ttl
+}
+
 // Claims represents the JWT claims
 type Claims struct {
-	Username string `json:"sub"`
-	Role     string `json:"role"`
+	PersonID  int64  `json:"pid"`
+	SessionID int64  `json:"sid"`
+	Username  string `json:"sub"`
+	Role      string `json:"role"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken generates a new JWT token for a user
-func (s *JWTService) GenerateToken(username, role string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
-	
+// GenerateToken generates a new JWT access token for a user session
+func (s *JWTService) GenerateToken(personID, sessionID int64, username, role string) (string, error) {
+	expirationTime := time.Now().Add(s.ttl)
+
 	claims := &Claims{
-		Username: username,
-		Role:     role,
+		PersonID:  personID,
+		SessionID: sessionID,
+		Username:  username,
+		Role:      role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -48,13 +60,12 @@ func (s *JWTService) GenerateToken(username, role string) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(s.secretKey)
-	if err != nil {
-		return "", err
+	key := s.keys.Active()
+	if key == nil {
+		return "", ErrNoSigningKey
 	}
 
-	return tokenString, nil
+	return key.Sign(claims)
 }
 
 // ValidateToken validates a JWT token and returns the claims
@@ -62,11 +73,13 @@ func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := s.keys.Lookup(kid, time.Now())
+		if !ok || token.Method.Alg() != key.Algorithm() {
 			return nil, ErrInvalidToken
 		}
-		return s.secretKey, nil
-	})
+		return key.PublicKey(), nil
+	}, jwt.WithValidMethods([]string{AlgorithmRS256, AlgorithmEdDSA}), jwt.WithIssuer(s.issuer))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -75,27 +88,16 @@ func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 		return nil, ErrInvalidToken
 	}
 
-	if !token.Valid {
+	// Tokens issued before person and session ids were embedded cannot be
+	// scoped to farms nor revoked
+	if !token.Valid || claims.PersonID == 0 || claims.SessionID == 0 {
 		return nil, ErrInvalidToken
 	}
 
 	return claims, nil
 }
 
-// ExtractUsername extracts the username from a token
-func (s *JWTService) ExtractUsername(tokenString string) (string, error) {
-	claims, err := s.ValidateToken(tokenString)
-	if err != nil {
-		return "", err
-	}
-	return claims.Username, nil
-}
-
-// ExtractRole extracts the role from a token
-func (s *JWTService) ExtractRole(tokenString string) (string, error) {
-	claims, err := s.ValidateToken(tokenString)
-	if err != nil {
-		return "", err
-	}
-	return claims.Role, nil
+// JWKS returns the public keys other services use to verify access tokens
+func (s *JWTService) JWKS() JWKS {
+	return s.keys.JWKS(time.Now())
 }
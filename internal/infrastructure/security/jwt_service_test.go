@@ -0,0 +1,250 @@
+package security_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/infrastructure/security"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newKey(t *testing.T, algorithm string) *security.SigningKey {
+	t.Helper()
+	key, err := security.GenerateSigningKey(algorithm)
+	require.NoError(t, err)
+	return key
+}
+
+func TestJWTService_GenerateToken(t *testing.T) {
+	for _, algorithm := range []string{security.AlgorithmRS256, security.AlgorithmEdDSA} {
+		t.Run("should sign and verify tokens with "+algorithm, func(t *testing.T) {
+			// Arrange
+			key := newKey(t, algorithm)
+			service := security.NewJWTService(security.NewKeySet(key), "cropflow", time.Minute)
+
+			// Act
+			token, err := service.GenerateToken(1, 2, "alice", "ROLE_USER")
+			require.NoError(t, err)
+			claims, err := service.ValidateToken(token)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), claims.PersonID)
+			assert.Equal(t, int64(2), claims.SessionID)
+			assert.Equal(t, "alice", claims.Username)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &security.Claims{})
+			require.NoError(t, err)
+			assert.Equal(t, key.ID(), parsed.Header["kid"])
+			assert.Equal(t, algorithm, parsed.Header["alg"])
+		})
+	}
+}
+
+func TestJWTService_ValidateToken(t *testing.T) {
+	t.Run("should reject tokens signed by an unknown key", func(t *testing.T) {
+		// Arrange
+		signer := security.NewJWTService(security.NewKeySet(newKey(t, security.AlgorithmEdDSA)), "cropflow", time.Minute)
+		verifier := security.NewJWTService(security.NewKeySet(newKey(t, security.AlgorithmEdDSA)), "cropflow", time.Minute)
+		token, err := signer.GenerateToken(1, 2, "alice", "ROLE_USER")
+		require.NoError(t, err)
+
+		// Act
+		_, err = verifier.ValidateToken(token)
+
+		// Assert
+		assert.Equal(t, security.ErrInvalidToken, err)
+	})
+
+	t.Run("should reject HMAC tokens", func(t *testing.T) {
+		// Arrange
+		key := newKey(t, security.AlgorithmRS256)
+		service := security.NewJWTService(security.NewKeySet(key), "cropflow", time.Minute)
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &security.Claims{PersonID: 1, SessionID: 2})
+		token.Header["kid"] = key.ID()
+		signed, err := token.SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		// Act
+		_, err = service.ValidateToken(signed)
+
+		// Assert
+		assert.Equal(t, security.ErrInvalidToken, err)
+	})
+
+	t.Run("should reject tokens from another issuer", func(t *testing.T) {
+		// Arrange
+		keys := security.NewKeySet(newKey(t, security.AlgorithmEdDSA))
+		token, err := security.NewJWTService(keys, "other", time.Minute).GenerateToken(1, 2, "alice", "ROLE_USER")
+		require.NoError(t, err)
+
+		// Act
+		_, err = security.NewJWTService(keys, "cropflow", time.Minute).ValidateToken(token)
+
+		// Assert
+		assert.Equal(t, security.ErrInvalidToken, err)
+	})
+}
+
+func TestKeySet_Rotate(t *testing.T) {
+	now := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should keep the previous key for the overlap window", func(t *testing.T) {
+		// Arrange
+		previous := newKey(t, security.AlgorithmEdDSA)
+		next := newKey(t, security.AlgorithmEdDSA)
+		keys := security.NewKeySet(previous)
+
+		// Act
+		keys.Rotate(next, now, time.Hour)
+
+		// Assert
+		assert.Equal(t, next, keys.Active())
+		_, ok := keys.Lookup(previous.ID(), now.Add(59*time.Minute))
+		assert.True(t, ok)
+		_, ok = keys.Lookup(previous.ID(), now.Add(time.Hour))
+		assert.False(t, ok)
+		_, ok = keys.Lookup(next.ID(), now.Add(24*time.Hour))
+		assert.True(t, ok)
+	})
+
+	t.Run("should verify tokens signed before the rotation", func(t *testing.T) {
+		// Arrange
+		keys := security.NewKeySet(newKey(t, security.AlgorithmRS256))
+		service := security.NewJWTService(keys, "cropflow", time.Minute)
+		token, err := service.GenerateToken(1, 2, "alice", "ROLE_USER")
+		require.NoError(t, err)
+
+		// Act
+		keys.Rotate(newKey(t, security.AlgorithmRS256), time.Now(), time.Hour)
+		_, err = service.ValidateToken(token)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should drop retired keys once the overlap has passed", func(t *testing.T) {
+		// Arrange
+		first := newKey(t, security.AlgorithmEdDSA)
+		keys := security.NewKeySet(first)
+		keys.Rotate(newKey(t, security.AlgorithmEdDSA), now, time.Hour)
+
+		// Act
+		keys.Rotate(newKey(t, security.AlgorithmEdDSA), now.Add(2*time.Hour), time.Hour)
+
+		// Assert
+		assert.Len(t, keys.JWKS(now.Add(2*time.Hour)).Keys, 2)
+		_, ok := keys.Lookup(first.ID(), now)
+		assert.False(t, ok)
+	})
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	t.Run("should publish RSA keys", func(t *testing.T) {
+		// Arrange
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		key, err := security.NewSigningKey(privateKey)
+		require.NoError(t, err)
+
+		// Act
+		jwks := security.NewKeySet(key).JWKS(time.Now())
+
+		// Assert
+		require.Len(t, jwks.Keys, 1)
+		jwk := jwks.Keys[0]
+		assert.Equal(t, "RSA", jwk.KeyType)
+		assert.Equal(t, "RS256", jwk.Algorithm)
+		assert.Equal(t, "sig", jwk.Use)
+		assert.Equal(t, key.ID(), jwk.KeyID)
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		require.NoError(t, err)
+		assert.Equal(t, 0, new(big.Int).SetBytes(n).Cmp(privateKey.N))
+		assert.Equal(t, "AQAB", jwk.E)
+	})
+
+	t.Run("should publish Ed25519 keys", func(t *testing.T) {
+		// Arrange
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		key, err := security.NewSigningKey(privateKey)
+		require.NoError(t, err)
+
+		// Act
+		jwks := security.NewKeySet(key).JWKS(time.Now())
+
+		// Assert
+		require.Len(t, jwks.Keys, 1)
+		assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+		assert.Equal(t, "Ed25519", jwks.Keys[0].Curve)
+		assert.Equal(t, "EdDSA", jwks.Keys[0].Algorithm)
+		assert.Equal(t, base64.RawURLEncoding.EncodeToString(publicKey), jwks.Keys[0].X)
+	})
+}
+
+func TestParseSigningKey(t *testing.T) {
+	t.Run("should parse PKCS#1 and PKCS#8 keys with a stable kid", func(t *testing.T) {
+		// Arrange
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		pkcs8, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		require.NoError(t, err)
+		pkcs1 := x509.MarshalPKCS1PrivateKey(privateKey)
+
+		// Act
+		fromPKCS8, err := security.ParseSigningKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))
+		require.NoError(t, err)
+		fromPKCS1, err := security.ParseSigningKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: pkcs1}))
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, security.AlgorithmRS256, fromPKCS8.Algorithm())
+		assert.Equal(t, fromPKCS8.ID(), fromPKCS1.ID())
+	})
+
+	t.Run("should reject data without a PEM block", func(t *testing.T) {
+		// Act
+		_, err := security.ParseSigningKey([]byte("not a key"))
+
+		// Assert
+		assert.ErrorIs(t, err, security.ErrUnsupportedKey)
+	})
+}
+
+func TestNewKeyRotator(t *testing.T) {
+	t.Run("should require the overlap to cover the token lifetime", func(t *testing.T) {
+		// Arrange
+		keys := security.NewKeySet(newKey(t, security.AlgorithmEdDSA))
+
+		// Act
+		_, err := security.NewKeyRotator(keys, security.AlgorithmEdDSA, time.Hour, time.Minute, 15*time.Minute)
+
+		// Assert
+		assert.Error(t, err)
+	})
+
+	t.Run("should replace the signing key", func(t *testing.T) {
+		// Arrange
+		previous := newKey(t, security.AlgorithmEdDSA)
+		keys := security.NewKeySet(previous)
+		rotator, err := security.NewKeyRotator(keys, security.AlgorithmEdDSA, time.Hour, 30*time.Minute, 15*time.Minute)
+		require.NoError(t, err)
+
+		// Act
+		err = rotator.Rotate(time.Now())
+
+		// Assert
+		require.NoError(t, err)
+		assert.NotEqual(t, previous.ID(), keys.Active().ID())
+		assert.Len(t, keys.JWKS(time.Now()).Keys, 2)
+	})
+}
@@ -0,0 +1,60 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// KeyRotator periodically replaces the signing key of a key set with a
+// freshly generated one
+type KeyRotator struct {
+	keys      *KeySet
+	algorithm string
+	interval  time.Duration
+	overlap   time.Duration
+}
+
+// NewKeyRotator creates a rotator. The overlap must cover the access token
+// lifetime, otherwise tokens signed just before a rotation would be rejected.
+func NewKeyRotator(keys *KeySet, algorithm string, interval, overlap, tokenTTL time.Duration) (*KeyRotator, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("key rotation interval must be positive, got %s", interval)
+	}
+	if overlap < tokenTTL {
+		return nil, fmt.Errorf("key overlap %s is shorter than the access token lifetime %s", overlap, tokenTTL)
+	}
+	return &KeyRotator{keys: keys, algorithm: algorithm, interval: interval, overlap: overlap}, nil
+}
+
+// Rotate generates a new signing key and retires the current one
+func (r *KeyRotator) Rotate(now time.Time) error {
+	next, err := GenerateSigningKey(r.algorithm)
+	if err != nil {
+		return err
+	}
+	r.keys.Rotate(next, now, r.overlap)
+	return nil
+}
+
+// Start rotates the key every interval until ctx is cancelled
+func (r *KeyRotator) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := r.Rotate(now); err != nil {
+					log.Printf("Failed to rotate signing key: %v", err)
+					continue
+				}
+				log.Printf("Rotated signing key, now signing with kid %s", r.keys.Active().ID())
+			}
+		}
+	}()
+}
@@ -0,0 +1,342 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// rsaKeyBits is the size of generated RSA keys
+const rsaKeyBits = 2048
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrUnsupportedKey       = errors.New("unsupported private key, expected RSA or Ed25519")
+	ErrNoSigningKey         = errors.New("no signing key available")
+)
+
+// SigningKey is a private key used to sign access tokens, identified by kid
+type SigningKey struct {
+	id         string
+	algorithm  string
+	privateKey crypto.Signer
+}
+
+// NewSigningKey wraps an RSA or Ed25519 private key; the kid is its RFC 7638 thumbprint
+func NewSigningKey(privateKey crypto.Signer) (*SigningKey, error) {
+	var algorithm string
+	switch privateKey.(type) {
+	case *rsa.PrivateKey:
+		algorithm = AlgorithmRS256
+	case ed25519.PrivateKey:
+		algorithm = AlgorithmEdDSA
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	key := &SigningKey{algorithm: algorithm, privateKey: privateKey}
+	key.id = key.thumbprint()
+	return key, nil
+}
+
+// GenerateSigningKey creates a fresh key for the given algorithm
+func GenerateSigningKey(algorithm string) (*SigningKey, error) {
+	switch algorithm {
+	case AlgorithmRS256:
+		privateKey, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate RSA key: %w", err)
+		}
+		return NewSigningKey(privateKey)
+	case AlgorithmEdDSA:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate Ed25519 key: %w", err)
+		}
+		return NewSigningKey(privateKey)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, algorithm)
+	}
+}
+
+// LoadSigningKey reads a PEM encoded private key (PKCS#8, or PKCS#1 for RSA)
+func LoadSigningKey(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	return ParseSigningKey(data)
+}
+
+// ParseSigningKey decodes a PEM encoded private key
+func ParseSigningKey(data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found", ErrUnsupportedKey)
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RSA key: %w", err)
+		}
+		return NewSigningKey(privateKey)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+	return NewSigningKey(signer)
+}
+
+// ID returns the key identifier placed in the kid header
+func (k *SigningKey) ID() string {
+	return k.id
+}
+
+// Algorithm returns the JWS algorithm of the key
+func (k *SigningKey) Algorithm() string {
+	return k.algorithm
+}
+
+// PublicKey returns the public half used to verify tokens
+func (k *SigningKey) PublicKey() crypto.PublicKey {
+	return k.privateKey.Public()
+}
+
+// Sign returns the claims as a JWT signed with the key, with its kid in the header
+func (k *SigningKey) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method(), claims)
+	token.Header["kid"] = k.id
+	return token.SignedString(k.privateKey)
+}
+
+func (k *SigningKey) method() jwt.SigningMethod {
+	if k.algorithm == AlgorithmRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+// JWK returns the public key in RFC 7517 format
+func (k *SigningKey) JWK() JWK {
+	jwk := JWK{KeyID: k.id, Use: "sig", Algorithm: k.algorithm}
+	switch pub := k.PublicKey().(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+	return jwk
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint, which keeps the kid stable
+// for a key across restarts and instances
+func (k *SigningKey) thumbprint() string {
+	jwk := k.JWK()
+	var canonical string
+	if jwk.KeyType == "RSA" {
+		canonical = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, jwk.E, jwk.N)
+	} else {
+		canonical = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":%q}`, jwk.X)
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// JWK is a public JSON Web Key
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// PublicKey decodes the key: RSA, Ed25519 (OKP) or P-256 (EC)
+func (j JWK) PublicKey() (crypto.PublicKey, error) {
+	switch {
+	case j.KeyType == "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid RSA modulus", ErrUnsupportedKey)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("%w: invalid RSA exponent", ErrUnsupportedKey)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case j.KeyType == "OKP" && j.Curve == "Ed25519":
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: invalid Ed25519 key", ErrUnsupportedKey)
+		}
+		return ed25519.PublicKey(x), nil
+	case j.KeyType == "EC" && j.Curve == "P-256":
+		x, errX := base64.RawURLEncoding.DecodeString(j.X)
+		y, errY := base64.RawURLEncoding.DecodeString(j.Y)
+		if errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+			return nil, fmt.Errorf("%w: invalid P-256 key", ErrUnsupportedKey)
+		}
+		// Parsing the uncompressed point checks that it lies on the curve
+		point := append([]byte{4}, append(x, y...)...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("%w: invalid P-256 key", ErrUnsupportedKey)
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s %s", ErrUnsupportedKey, j.KeyType, j.Curve)
+	}
+}
+
+// JWKS is a JSON Web Key Set as served by /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// verificationKey is a key still accepted for verification; a zero
+// expiresAt means it does not expire
+type verificationKey struct {
+	key       *SigningKey
+	expiresAt time.Time
+}
+
+// KeySet holds the active signing key and the keys still accepted for
+// verification. It is safe for concurrent use.
+type KeySet struct {
+	mu     sync.RWMutex
+	active *SigningKey
+	keys   map[string]verificationKey
+}
+
+// NewKeySet creates a key set signing with active; additional keys are only
+// accepted for verification, e.g. the previous key during a manual rotation
+func NewKeySet(active *SigningKey, additional ...*SigningKey) *KeySet {
+	set := &KeySet{active: active, keys: map[string]verificationKey{}}
+	for _, key := range append([]*SigningKey{active}, additional...) {
+		set.keys[key.id] = verificationKey{key: key}
+	}
+	return set
+}
+
+// LoadKeySet builds the key set from PEM files, the first one signing, or
+// generates a key for algorithm when no files are given
+func LoadKeySet(files []string, algorithm string) (*KeySet, error) {
+	if len(files) == 0 {
+		key, err := GenerateSigningKey(algorithm)
+		if err != nil {
+			return nil, err
+		}
+		return NewKeySet(key), nil
+	}
+
+	keys := make([]*SigningKey, 0, len(files))
+	for _, file := range files {
+		key, err := LoadSigningKey(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		keys = append(keys, key)
+	}
+	return NewKeySet(keys[0], keys[1:]...), nil
+}
+
+// Active returns the key new tokens are signed with
+func (s *KeySet) Active() *SigningKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.active
+}
+
+// Lookup finds a key by kid that is still valid for verification at now
+func (s *KeySet) Lookup(kid string, now time.Time) (*SigningKey, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.keys[kid]
+	if !ok || entry.expired(now) {
+		return nil, false
+	}
+	return entry.key, true
+}
+
+// Rotate makes next the signing key. The previous key stays valid for
+// verification for the overlap window, so tokens it signed keep working
+// until they expire.
+func (s *KeySet) Rotate(next *SigningKey, now time.Time, overlap time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.active != nil {
+		s.keys[s.active.id] = verificationKey{key: s.active, expiresAt: now.Add(overlap)}
+	}
+	s.active = next
+	s.keys[next.id] = verificationKey{key: next}
+
+	for kid, entry := range s.keys {
+		if entry.expired(now) {
+			delete(s.keys, kid)
+		}
+	}
+}
+
+// JWKS returns the public keys valid for verification at now, active key first
+func (s *KeySet) JWKS(now time.Time) JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var retiring []JWK
+	for kid, entry := range s.keys {
+		if s.active != nil && kid == s.active.id || entry.expired(now) {
+			continue
+		}
+		retiring = append(retiring, entry.key.JWK())
+	}
+	sort.Slice(retiring, func(i, j int) bool { return retiring[i].KeyID < retiring[j].KeyID })
+
+	set := JWKS{Keys: []JWK{}}
+	if s.active != nil {
+		set.Keys = append(set.Keys, s.active.JWK())
+	}
+	set.Keys = append(set.Keys, retiring...)
+	return set
+}
+
+func (k verificationKey) expired(now time.Time) bool {
+	return !k.expiresAt.IsZero() && !now.Before(k.expiresAt)
+}
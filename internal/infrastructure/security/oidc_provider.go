@@ -0,0 +1,323 @@
+package security
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/oidc"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// oidcKeyRefreshInterval is how long after a JWKS fetch that did not have a
+// kid unknown kids are rejected without fetching again, so forged tokens
+// cannot flood the identity provider
+const oidcKeyRefreshInterval = time.Minute
+
+// oidcClockSkew is the clock difference tolerated on ID token timestamps
+const oidcClockSkew = time.Minute
+
+// oidcResponseLimit caps the size of the documents read from the identity provider
+const oidcResponseLimit = 1 << 20
+
+// OIDCConfig configures the client of an OpenID Connect identity provider
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// UsernameClaim names the claim the username is taken from; email is used when it is missing
+	UsernameClaim string
+	// GroupsClaim names the claim holding the groups mapped to roles
+	GroupsClaim string
+}
+
+// oidcDiscovery is the part of the discovery document the provider uses
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDCProvider logs persons in with an OpenID Connect identity provider using
+// the authorization code flow with PKCE. The discovery document is fetched on
+// first use and the provider keys are cached by kid. It is safe for concurrent use.
+type OIDCProvider struct {
+	config OIDCConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]crypto.PublicKey
+	// keyMissAt is when a fresh JWKS last lacked the kid of a token
+	keyMissAt time.Time
+}
+
+// NewOIDCProvider creates the client of the identity provider at config.IssuerURL
+func NewOIDCProvider(config OIDCConfig, client *http.Client) *OIDCProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid"}
+	}
+	config.IssuerURL = strings.TrimSuffix(config.IssuerURL, "/")
+	return &OIDCProvider{config: config, client: client}
+}
+
+// AuthorizationURL returns the authorization endpoint URL the client is redirected to
+func (p *OIDCProvider) AuthorizationURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(p.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange redeems the authorization code at the token endpoint and verifies the returned ID token
+func (p *OIDCProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (oidc.Claims, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return oidc.Claims{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	if p.config.ClientSecret == "" {
+		form.Set("client_id", p.config.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oidc.Claims{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := p.fetchJSON(req, &body)
+	if err != nil {
+		return oidc.Claims{}, err
+	}
+	if status != http.StatusOK {
+		// Rejected codes, e.g. expired, replayed or with a wrong verifier
+		if body.Error != "" {
+			return oidc.Claims{}, fmt.Errorf("%w: %s", oidc.ErrProviderDenied, body.Error)
+		}
+		return oidc.Claims{}, fmt.Errorf("token endpoint returned status %d", status)
+	}
+	if body.IDToken == "" {
+		return oidc.Claims{}, fmt.Errorf("%w: token response has no id_token", oidc.ErrInvalidIDToken)
+	}
+
+	return p.verify(ctx, discovery.Issuer, body.IDToken, nonce)
+}
+
+// verify checks the signature, issuer, audience, lifetime and nonce of an ID token
+func (p *OIDCProvider) verify(ctx context.Context, issuer, rawIDToken, nonce string) (oidc.Claims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{AlgorithmRS256, AlgorithmEdDSA, "ES256"}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(oidcClockSkew),
+	)
+	if err != nil {
+		return oidc.Claims{}, fmt.Errorf("%w: %v", oidc.ErrInvalidIDToken, err)
+	}
+
+	if value, _ := claims["nonce"].(string); value == "" || value != nonce {
+		return oidc.Claims{}, fmt.Errorf("%w: nonce does not match", oidc.ErrInvalidIDToken)
+	}
+	// With several audiences the token must name this client as the authorized party
+	if audience, _ := claims.GetAudience(); len(audience) > 1 {
+		if azp, _ := claims["azp"].(string); azp != p.config.ClientID {
+			return oidc.Claims{}, fmt.Errorf("%w: unexpected authorized party", oidc.ErrInvalidIDToken)
+		}
+	}
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return oidc.Claims{}, fmt.Errorf("%w: missing subject", oidc.ErrInvalidIDToken)
+	}
+
+	result := oidc.Claims{
+		Issuer:  issuer,
+		Subject: subject,
+		Groups:  stringList(claims[p.config.GroupsClaim]),
+	}
+	result.Email, _ = claims["email"].(string)
+	result.Username, _ = claims[p.config.UsernameClaim].(string)
+	if result.Username == "" {
+		result.Username = result.Email
+	}
+	return result, nil
+}
+
+// key returns the provider key with the kid, fetching the JWKS again when it is unknown
+func (p *OIDCProvider) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookup(kid); ok {
+		return key, nil
+	}
+	if time.Since(p.keyMissAt) < oidcKeyRefreshInterval {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if err := p.fetchKeys(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := p.lookup(kid); ok {
+		return key, nil
+	}
+	p.keyMissAt = time.Now()
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// lookup finds a cached key; tokens without kid are accepted when the provider has a single key
+func (p *OIDCProvider) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *OIDCProvider) fetchKeys(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.discovery.JWKSURI, nil)
+	if err != nil {
+		return err
+	}
+	var set JWKS
+	status, err := p.fetchJSON(req, &set)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("jwks endpoint returned status %d", status)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// Keys of unsupported types are skipped rather than failing the whole set
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+	p.keys = keys
+	return nil
+}
+
+// discover fetches the discovery document once; failures are retried on the next login
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.IssuerURL+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var discovery oidcDiscovery
+	status, err := p.fetchJSON(req, &discovery)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: discovery endpoint returned status %d", oidc.ErrInvalidDiscovery, status)
+	}
+	// The issuer must match exactly, or tokens of another tenant could be accepted
+	if strings.TrimSuffix(discovery.Issuer, "/") != p.config.IssuerURL {
+		return nil, fmt.Errorf("%w: issuer %q does not match %q", oidc.ErrInvalidDiscovery, discovery.Issuer, p.config.IssuerURL)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: missing endpoints", oidc.ErrInvalidDiscovery)
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// fetchJSON sends the request and decodes the JSON response body whatever its status
+func (p *OIDCProvider) fetchJSON(req *http.Request, target interface{}) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("identity provider request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, oidcResponseLimit))
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(data, target); err != nil && resp.StatusCode == http.StatusOK {
+		return 0, fmt.Errorf("invalid response from %s: %w", req.URL.Path, err)
+	}
+	return resp.StatusCode, nil
+}
+
+// stringList reads a claim holding either a list of strings or a single string
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,220 @@
+package security_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/oidc"
+	"github.com/cropflow/api/internal/infrastructure/security"
+	"github.com/cropflow/api/internal/infrastructure/security/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURL = "http://cropflow.test/auth/oidc/callback"
+
+func newProvider(idp *oidctest.Server) *security.OIDCProvider {
+	return security.NewOIDCProvider(security.OIDCConfig{
+		IssuerURL:     idp.Issuer(),
+		ClientID:      idp.ClientID,
+		ClientSecret:  idp.ClientSecret,
+		RedirectURL:   redirectURL,
+		Scopes:        []string{"openid", "profile", "email"},
+		UsernameClaim: "preferred_username",
+		GroupsClaim:   "groups",
+	}, nil)
+}
+
+// authorize runs a login up to the callback and returns the code and the request it belongs to
+func authorize(t *testing.T, provider *security.OIDCProvider) (string, *oidc.LoginRequest) {
+	t.Helper()
+	request, state, err := oidc.NewLoginRequest(time.Minute, time.Now())
+	require.NoError(t, err)
+
+	authURL, err := provider.AuthorizationURL(context.Background(), state, request.Nonce(), request.CodeChallenge())
+	require.NoError(t, err)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, state, location.Query().Get("state"))
+	require.NotEmpty(t, location.Query().Get("code"), location.Query().Get("error"))
+	return location.Query().Get("code"), request
+}
+
+func TestOIDCProvider_Exchange(t *testing.T) {
+	user := oidctest.User{Subject: "u-123", Username: "jane", Email: "jane@example.com", Groups: []string{"agronomists"}}
+
+	for name, secret := range map[string]string{"confidential": "s3cr3t", "public": ""} {
+		t.Run("should return the verified claims for a "+name+" client", func(t *testing.T) {
+			// Arrange
+			idp := oidctest.NewServer("cropflow", secret)
+			defer idp.Close()
+			idp.SetUser(user)
+			provider := newProvider(idp)
+			code, request := authorize(t, provider)
+
+			// Act
+			claims, err := provider.Exchange(context.Background(), code, request.CodeVerifier(), request.Nonce())
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, oidc.Claims{
+				Issuer:   idp.Issuer(),
+				Subject:  "u-123",
+				Username: "jane",
+				Email:    "jane@example.com",
+				Groups:   []string{"agronomists"},
+			}, claims)
+		})
+	}
+
+	t.Run("should fall back to the email as username", func(t *testing.T) {
+		// Arrange
+		idp := oidctest.NewServer("cropflow", "s3cr3t")
+		defer idp.Close()
+		idp.SetUser(oidctest.User{Subject: "u-123", Email: "jane@example.com"})
+		provider := newProvider(idp)
+		code, request := authorize(t, provider)
+
+		// Act
+		claims, err := provider.Exchange(context.Background(), code, request.CodeVerifier(), request.Nonce())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "jane@example.com", claims.Username)
+	})
+
+	t.Run("should reject a code redeemed with another verifier or twice", func(t *testing.T) {
+		// Arrange
+		idp := oidctest.NewServer("cropflow", "s3cr3t")
+		defer idp.Close()
+		idp.SetUser(user)
+		provider := newProvider(idp)
+		code, request := authorize(t, provider)
+		other, _, err := oidc.NewLoginRequest(time.Minute, time.Now())
+		require.NoError(t, err)
+
+		// Act
+		_, wrongVerifier := provider.Exchange(context.Background(), code, other.CodeVerifier(), request.Nonce())
+		_, replayed := provider.Exchange(context.Background(), code, request.CodeVerifier(), request.Nonce())
+
+		// Assert
+		assert.ErrorIs(t, wrongVerifier, oidc.ErrProviderDenied)
+		assert.ErrorIs(t, replayed, oidc.ErrProviderDenied)
+	})
+
+	t.Run("should reject an ID token with another nonce", func(t *testing.T) {
+		// Arrange
+		idp := oidctest.NewServer("cropflow", "s3cr3t")
+		defer idp.Close()
+		idp.SetUser(user)
+		provider := newProvider(idp)
+		code, request := authorize(t, provider)
+
+		// Act
+		_, err := provider.Exchange(context.Background(), code, request.CodeVerifier(), "another-nonce")
+
+		// Assert
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	tampered := map[string]func(jwt.MapClaims){
+		"another audience": func(c jwt.MapClaims) { c["aud"] = "someone-else" },
+		"another issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"no subject":       func(c jwt.MapClaims) { delete(c, "sub") },
+		"expired":          func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"no expiry":        func(c jwt.MapClaims) { delete(c, "exp") },
+		"several audiences without azp": func(c jwt.MapClaims) {
+			c["aud"] = []string{"cropflow", "someone-else"}
+		},
+	}
+	for name, tamper := range tampered {
+		t.Run("should reject an ID token with "+name, func(t *testing.T) {
+			// Arrange
+			idp := oidctest.NewServer("cropflow", "s3cr3t")
+			defer idp.Close()
+			idp.SetUser(user)
+			idp.Tamper(tamper)
+			provider := newProvider(idp)
+			code, request := authorize(t, provider)
+
+			// Act
+			_, err := provider.Exchange(context.Background(), code, request.CodeVerifier(), request.Nonce())
+
+			// Assert
+			assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+		})
+	}
+
+	t.Run("should fetch the keys again after the provider rotates them", func(t *testing.T) {
+		// Arrange
+		idp := oidctest.NewServer("cropflow", "s3cr3t")
+		defer idp.Close()
+		idp.SetUser(user)
+		provider := newProvider(idp)
+		code, request := authorize(t, provider)
+		_, err := provider.Exchange(context.Background(), code, request.CodeVerifier(), request.Nonce())
+		require.NoError(t, err)
+		idp.RotateKey()
+
+		// Act
+		code, request = authorize(t, provider)
+		_, err = provider.Exchange(context.Background(), code, request.CodeVerifier(), request.Nonce())
+
+		// Assert
+		assert.NoError(t, err)
+	})
+}
+
+func TestOIDCProvider_AuthorizationURL(t *testing.T) {
+	t.Run("should send the PKCE challenge and the client parameters", func(t *testing.T) {
+		// Arrange
+		idp := oidctest.NewServer("cropflow", "s3cr3t")
+		defer idp.Close()
+		provider := newProvider(idp)
+
+		// Act
+		authURL, err := provider.AuthorizationURL(context.Background(), "state", "nonce", "challenge")
+
+		// Assert
+		require.NoError(t, err)
+		parsed, err := url.Parse(authURL)
+		require.NoError(t, err)
+		query := parsed.Query()
+		assert.Equal(t, idp.Issuer()+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+		assert.Equal(t, "code", query.Get("response_type"))
+		assert.Equal(t, "cropflow", query.Get("client_id"))
+		assert.Equal(t, redirectURL, query.Get("redirect_uri"))
+		assert.Equal(t, "openid profile email", query.Get("scope"))
+		assert.Equal(t, "challenge", query.Get("code_challenge"))
+		assert.Equal(t, "S256", query.Get("code_challenge_method"))
+		assert.Equal(t, "nonce", query.Get("nonce"))
+	})
+
+	t.Run("should reject a discovery document of another issuer", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"issuer":"https://evil.example.com","authorization_endpoint":"a","token_endpoint":"t","jwks_uri":"j"}`))
+		}))
+		defer server.Close()
+		provider := security.NewOIDCProvider(security.OIDCConfig{IssuerURL: server.URL, ClientID: "cropflow"}, nil)
+
+		// Act
+		_, err := provider.AuthorizationURL(context.Background(), "state", "nonce", "challenge")
+
+		// Assert
+		assert.ErrorIs(t, err, oidc.ErrInvalidDiscovery)
+	})
+}
@@ -1,26 +0,0 @@
-package security
-
-import "golang.org/x/crypto/bcrypt"
-
-// PasswordService handles password hashing and verification
-type PasswordService struct{}
-
-// NewPasswordService creates a new password service
-func NewPasswordService() *PasswordService {
-	return &PasswordService{}
-}
-
-// HashPassword hashes a plain text password using bcrypt
-func (s *PasswordService) HashPassword(password string) (string, error) {
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", err
-	}
-	return string(hashedBytes), nil
-}
-
-// CheckPassword compares a plain text password with a hashed password
-func (s *PasswordService) CheckPassword(hashedPassword, password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	return err == nil
-}
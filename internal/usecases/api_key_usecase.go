@@ -0,0 +1,195 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/apikey"
+	"github.com/cropflow/api/internal/domain/farm"
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/transaction"
+)
+
+// APIKeyInput holds what a client asks for when creating an API key. OwnerID
+// is only used for service keys; zero values take the owner's role and every
+// farm the owner can access.
+type APIKeyInput struct {
+	Name      string
+	Kind      string
+	OwnerID   int64
+	Role      string
+	FarmIDs   []int64
+	ExpiresAt *time.Time
+}
+
+// APIKeyUseCase manages API keys and authenticates the requests made with them.
+// Everybody manages their personal keys; service keys, issued to the person
+// account of a machine client, are managed by administrators.
+type APIKeyUseCase struct {
+	apiKeyRepo apikey.Repository
+	personRepo person.Repository
+	farmRepo   farm.Repository
+	tx         transaction.Manager
+}
+
+// NewAPIKeyUseCase creates a new API key use case
+func NewAPIKeyUseCase(apiKeyRepo apikey.Repository, personRepo person.Repository, farmRepo farm.Repository, tx transaction.Manager) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+		personRepo: personRepo,
+		farmRepo:   farmRepo,
+		tx:         tx,
+	}
+}
+
+// CreateKey issues a key and returns it with its plain form, which is not stored
+func (uc *APIKeyUseCase) CreateKey(ctx context.Context, input APIKeyInput) (*apikey.Key, string, error) {
+	caller, ok := identity.FromContext(ctx)
+	if !ok {
+		return nil, "", identity.ErrUnauthenticated
+	}
+	// A leaked key must not be able to mint more keys
+	if caller.APIKeyID() != 0 {
+		return nil, "", apikey.ErrKeyManagedByKey
+	}
+
+	kind := apikey.Personal
+	if input.Kind != "" {
+		var err error
+		if kind, err = apikey.NewKind(input.Kind); err != nil {
+			return nil, "", err
+		}
+	}
+
+	ownerID := caller.PersonID()
+	if kind == apikey.Service {
+		if !caller.Role().IsAdmin() {
+			return nil, "", apikey.ErrServiceKeyForAdmin
+		}
+		ownerID = input.OwnerID
+	} else if input.OwnerID != 0 && input.OwnerID != caller.PersonID() {
+		return nil, "", apikey.ErrInvalidOwner
+	}
+	if ownerID <= 0 {
+		return nil, "", apikey.ErrInvalidOwner
+	}
+
+	var key *apikey.Key
+	var plain string
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		owner, err := uc.personRepo.FindByID(ctx, ownerID)
+		if err != nil {
+			return err
+		}
+		role := owner.Role()
+		if input.Role != "" {
+			if role, err = person.NewRole(input.Role); err != nil {
+				return err
+			}
+			if !owner.Role().HasPermission(role) {
+				return apikey.ErrRoleExceedsOwner
+			}
+		}
+
+		for _, farmID := range input.FarmIDs {
+			exists, err := uc.farmRepo.ExistsByID(ctx, farmID)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return farm.ErrFarmNotFound
+			}
+		}
+
+		if key, plain, err = apikey.NewKey(owner.ID(), kind, input.Name, role, input.FarmIDs, input.ExpiresAt, time.Now()); err != nil {
+			return err
+		}
+		return uc.apiKeyRepo.Save(ctx, key)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return key, plain, nil
+}
+
+// ListKeys returns the keys of the caller; administrators see the keys of the
+// given owner, or every key when ownerID is zero
+func (uc *APIKeyUseCase) ListKeys(ctx context.Context, ownerID int64) ([]*apikey.Key, error) {
+	caller, ok := identity.FromContext(ctx)
+	if !ok {
+		return nil, identity.ErrUnauthenticated
+	}
+	if !caller.Role().IsAdmin() {
+		ownerID = caller.PersonID()
+	}
+	return uc.apiKeyRepo.List(ctx, apikey.Filter{OwnerID: ownerID})
+}
+
+// GetKey retrieves a key of the caller, or any key for administrators
+func (uc *APIKeyUseCase) GetKey(ctx context.Context, id int64) (*apikey.Key, error) {
+	caller, ok := identity.FromContext(ctx)
+	if !ok {
+		return nil, identity.ErrUnauthenticated
+	}
+
+	key, err := uc.apiKeyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Keys of other persons are reported as not found so their existence is not disclosed
+	if !caller.Role().IsAdmin() && key.OwnerID() != caller.PersonID() {
+		return nil, apikey.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+// RevokeKey stops a key from being accepted; service keys can only be revoked by administrators
+func (uc *APIKeyUseCase) RevokeKey(ctx context.Context, id int64) error {
+	key, err := uc.GetKey(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	caller, _ := identity.FromContext(ctx)
+	if key.Kind() == apikey.Service && !caller.Role().IsAdmin() {
+		return apikey.ErrServiceKeyForAdmin
+	}
+
+	key.Revoke(time.Now())
+	return uc.apiKeyRepo.Save(ctx, key)
+}
+
+// Authenticate validates an API key and returns the identity of its owner,
+// limited to the role and farms of the key
+func (uc *APIKeyUseCase) Authenticate(ctx context.Context, plain string) (identity.Identity, error) {
+	prefix, err := apikey.ParsePrefix(plain)
+	if err != nil {
+		return identity.Identity{}, err
+	}
+	key, err := uc.apiKeyRepo.FindByPrefix(ctx, prefix)
+	if err != nil {
+		return identity.Identity{}, err
+	}
+
+	now := time.Now()
+	if err := key.Verify(plain, now); err != nil {
+		return identity.Identity{}, err
+	}
+
+	owner, err := uc.personRepo.FindByID(ctx, key.OwnerID())
+	if errors.Is(err, person.ErrPersonNotFound) {
+		return identity.Identity{}, apikey.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return identity.Identity{}, err
+	}
+
+	if key.MarkUsed(now) {
+		if err := uc.apiKeyRepo.Touch(ctx, key.ID(), now); err != nil {
+			return identity.Identity{}, err
+		}
+	}
+	return identity.NewForAPIKey(owner.ID(), key.ID(), owner.Username(), key.EffectiveRole(owner.Role()), key.FarmIDs()), nil
+}
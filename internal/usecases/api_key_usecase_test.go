@@ -0,0 +1,185 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cropflow/api/internal/adapters/database/memory"
+	"github.com/cropflow/api/internal/domain/apikey"
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyUseCase_CreateKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     person.Role
+		byKey    bool
+		input    func(other int64) usecases.APIKeyInput
+		expected error
+	}{
+		{
+			name:     "should create a personal key with the role of the caller",
+			role:     person.RoleManager,
+			input:    func(int64) usecases.APIKeyInput { return usecases.APIKeyInput{Name: "ci"} },
+			expected: nil,
+		},
+		{
+			name: "should create a personal key with a lower role",
+			role: person.RoleManager,
+			input: func(int64) usecases.APIKeyInput {
+				return usecases.APIKeyInput{Name: "ci", Role: person.RoleUser.String()}
+			},
+			expected: nil,
+		},
+		{
+			name: "should not create a key with a role above the owner",
+			role: person.RoleManager,
+			input: func(int64) usecases.APIKeyInput {
+				return usecases.APIKeyInput{Name: "ci", Role: person.RoleAdmin.String()}
+			},
+			expected: apikey.ErrRoleExceedsOwner,
+		},
+		{
+			name:     "should not let a key mint more keys",
+			role:     person.RoleAdmin,
+			byKey:    true,
+			input:    func(int64) usecases.APIKeyInput { return usecases.APIKeyInput{Name: "ci"} },
+			expected: apikey.ErrKeyManagedByKey,
+		},
+		{
+			name:     "should not create a personal key for another person",
+			role:     person.RoleAdmin,
+			input:    func(other int64) usecases.APIKeyInput { return usecases.APIKeyInput{Name: "ci", OwnerID: other} },
+			expected: apikey.ErrInvalidOwner,
+		},
+		{
+			name: "should let an administrator create a service key for another person",
+			role: person.RoleAdmin,
+			input: func(other int64) usecases.APIKeyInput {
+				return usecases.APIKeyInput{Name: "ingest", Kind: apikey.Service.String(), OwnerID: other}
+			},
+			expected: nil,
+		},
+		{
+			name: "should not let a manager create a service key",
+			role: person.RoleManager,
+			input: func(other int64) usecases.APIKeyInput {
+				return usecases.APIKeyInput{Name: "ingest", Kind: apikey.Service.String(), OwnerID: other}
+			},
+			expected: apikey.ErrServiceKeyForAdmin,
+		},
+		{
+			name: "should not create a service key without an owner",
+			role: person.RoleAdmin,
+			input: func(int64) usecases.APIKeyInput {
+				return usecases.APIKeyInput{Name: "ingest", Kind: apikey.Service.String()}
+			},
+			expected: apikey.ErrInvalidOwner,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := memory.NewStore()
+			uc := newAPIKeyUseCase(store)
+			ctx := callerContext(t, store, "john_doe", tt.role)
+			other, _ := identity.FromContext(callerContext(t, store, "ingest_bot", person.RoleUser))
+			if tt.byKey {
+				caller, _ := identity.FromContext(ctx)
+				ctx = identity.NewContext(ctx, identity.NewForAPIKey(caller.PersonID(), 1, caller.Username(), caller.Role(), nil))
+			}
+			input := tt.input(other.PersonID())
+
+			// Act
+			key, plain, err := uc.CreateKey(ctx, input)
+
+			// Assert
+			require.ErrorIs(t, err, tt.expected)
+			if tt.expected != nil {
+				keys, err := memory.NewAPIKeyRepository(store).List(context.Background(), apikey.Filter{})
+				require.NoError(t, err)
+				assert.Empty(t, keys)
+				return
+			}
+			id, err := uc.Authenticate(context.Background(), plain)
+			require.NoError(t, err)
+			assert.Equal(t, key.OwnerID(), id.PersonID())
+			assert.Equal(t, key.ID(), id.APIKeyID())
+			assert.Equal(t, key.Role(), id.Role())
+		})
+	}
+}
+
+func TestAPIKeyUseCase_Authenticate(t *testing.T) {
+	t.Run("should cap the role of a key at the current role of its owner", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := newAPIKeyUseCase(store)
+		ctx := callerContext(t, store, "john_doe", person.RoleManager)
+		caller, _ := identity.FromContext(ctx)
+		_, plain, err := uc.CreateKey(ctx, usecases.APIKeyInput{Name: "ci"})
+		require.NoError(t, err)
+		_, err = memory.NewPersonRepository(store).UpdateRole(ctx, caller.PersonID(), func(p *person.Person, _ int) error {
+			return p.PromoteToRole(person.RoleUser.String(), 0)
+		})
+		require.NoError(t, err)
+
+		// Act
+		id, err := uc.Authenticate(context.Background(), plain)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, person.RoleUser, id.Role())
+	})
+
+	t.Run("should reject a revoked key", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := newAPIKeyUseCase(store)
+		ctx := callerContext(t, store, "john_doe", person.RoleUser)
+		key, plain, err := uc.CreateKey(ctx, usecases.APIKeyInput{Name: "ci"})
+		require.NoError(t, err)
+		require.NoError(t, uc.RevokeKey(ctx, key.ID()))
+
+		// Act
+		_, err = uc.Authenticate(context.Background(), plain)
+
+		// Assert
+		assert.ErrorIs(t, err, apikey.ErrAPIKeyRevoked)
+	})
+}
+
+func TestAPIKeyUseCase_RevokeKey(t *testing.T) {
+	t.Run("should not let the owner of a service key revoke it", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := newAPIKeyUseCase(store)
+		adminCtx := callerContext(t, store, "john_doe", person.RoleAdmin)
+		ownerCtx := callerContext(t, store, "ingest_bot", person.RoleUser)
+		owner, _ := identity.FromContext(ownerCtx)
+		key, _, err := uc.CreateKey(adminCtx, usecases.APIKeyInput{Name: "ingest", Kind: apikey.Service.String(), OwnerID: owner.PersonID()})
+		require.NoError(t, err)
+
+		// Act
+		err = uc.RevokeKey(ownerCtx, key.ID())
+
+		// Assert
+		assert.ErrorIs(t, err, apikey.ErrServiceKeyForAdmin)
+		assert.NoError(t, uc.RevokeKey(adminCtx, key.ID()))
+	})
+}
+
+// newAPIKeyUseCase wires an APIKeyUseCase to the store
+func newAPIKeyUseCase(store *memory.Store) *usecases.APIKeyUseCase {
+	return usecases.NewAPIKeyUseCase(
+		memory.NewAPIKeyRepository(store),
+		memory.NewPersonRepository(store),
+		memory.NewFarmRepository(store),
+		memory.NewTransactionManager(store),
+	)
+}
@@ -0,0 +1,25 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/cropflow/api/internal/domain/audit"
+	"github.com/cropflow/api/internal/domain/query"
+)
+
+// AuditUseCase handles reading the audit log
+type AuditUseCase struct {
+	auditRepo audit.Repository
+}
+
+// NewAuditUseCase creates a new audit use case
+func NewAuditUseCase(auditRepo audit.Repository) *AuditUseCase {
+	return &AuditUseCase{
+		auditRepo: auditRepo,
+	}
+}
+
+// ListEntries retrieves one page of audit entries matching the filter
+func (uc *AuditUseCase) ListEntries(ctx context.Context, filter audit.Filter, page query.Page) (query.Result[*audit.Entry], error) {
+	return uc.auditRepo.List(ctx, filter, page)
+}
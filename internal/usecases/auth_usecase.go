@@ -1,78 +1,467 @@
 package usecases
 
 import (
+	"context"
 	"errors"
+	"time"
 
-	"github.com/cropflow/api/internal/domain/entities"
-	"github.com/cropflow/api/internal/domain/repositories"
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/lockout"
+	"github.com/cropflow/api/internal/domain/mfa"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/session"
+	"github.com/cropflow/api/internal/domain/transaction"
 	"github.com/cropflow/api/internal/infrastructure/security"
 )
 
-var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
-)
+// Tokens holds the credentials handed to a client after login or refresh
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+// loginChallengeTTL is how long a client has to complete the second step of a login
+const loginChallengeTTL = 5 * time.Minute
+
+// LoginResult holds either the issued tokens or, when the person uses
+// two-factor authentication, the challenge that must be completed first
+type LoginResult struct {
+	Tokens    Tokens
+	Challenge *LoginChallenge
+}
+
+// LoginChallenge is the second step of a login handed to the client
+type LoginChallenge struct {
+	Token     string
+	Kind      mfa.ChallengeKind
+	ExpiresIn time.Duration
+}
 
 // AuthUseCase handles authentication business logic
 type AuthUseCase struct {
-	personRepo      repositories.PersonRepository
-	passwordService *security.PasswordService
+	personRepo      person.Repository
+	sessionRepo     session.Repository
+	mfaRepo         mfa.Repository
+	lockoutRepo     lockout.Repository
 	jwtService      *security.JWTService
+	refreshTokenTTL time.Duration
+	twoFactor       mfa.Settings
+	lockouts        lockout.Settings
+	passwords       person.PasswordPolicy
+	tx              transaction.Manager
 }
 
 // NewAuthUseCase creates a new auth use case
 func NewAuthUseCase(
-	personRepo repositories.PersonRepository,
-	passwordService *security.PasswordService,
+	personRepo person.Repository,
+	sessionRepo session.Repository,
+	mfaRepo mfa.Repository,
+	lockoutRepo lockout.Repository,
 	jwtService *security.JWTService,
+	refreshTokenTTL time.Duration,
+	twoFactor mfa.Settings,
+	lockouts lockout.Settings,
+	passwords person.PasswordPolicy,
+	tx transaction.Manager,
 ) *AuthUseCase {
 	return &AuthUseCase{
 		personRepo:      personRepo,
-		passwordService: passwordService,
+		sessionRepo:     sessionRepo,
+		mfaRepo:         mfaRepo,
+		lockoutRepo:     lockoutRepo,
 		jwtService:      jwtService,
+		refreshTokenTTL: refreshTokenTTL,
+		twoFactor:       twoFactor,
+		lockouts:        lockouts,
+		passwords:       passwords,
+		tx:              tx,
 	}
 }
 
-// Login authenticates a user and returns a JWT token
-func (uc *AuthUseCase) Login(username, password string) (string, error) {
+// Login authenticates a user and starts a session. Persons with a confirmed
+// second factor, or whose role requires one, get a challenge instead.
+// Repeated failures lock the username and the client IP address for a while;
+// unknown usernames are handled exactly like wrong passwords so neither the
+// response nor its timing reveals whether a username exists.
+func (uc *AuthUseCase) Login(ctx context.Context, username, password, clientIP string) (LoginResult, error) {
+	now := time.Now()
+
+	ipStatus, err := uc.lockoutRepo.Find(ctx, lockout.IPKey(clientIP))
+	if err != nil {
+		return LoginResult{}, err
+	}
+	if err := ipStatus.Check(now); err != nil {
+		return LoginResult{}, err
+	}
+
 	// Find user by username
-	person, err := uc.personRepo.FindByUsername(username)
+	p, err := uc.personRepo.FindByUsername(ctx, username)
+	if errors.Is(err, person.ErrPersonNotFound) {
+		return LoginResult{}, uc.rejectUnknownUsername(ctx, username, password, clientIP, now)
+	}
 	if err != nil {
-		return "", err
+		return LoginResult{}, err
 	}
-	if person == nil {
-		return "", ErrInvalidCredentials
+
+	if err := p.LoginStatus().Check(now); err != nil {
+		return LoginResult{}, err
 	}
 
 	// Verify password
-	if !uc.passwordService.CheckPassword(person.Password, password) {
-		return "", ErrInvalidCredentials
+	if err := p.Authenticate(password); err != nil {
+		if err := uc.recordFailedLogin(ctx, p.ID(), clientIP, now); err != nil {
+			return LoginResult{}, err
+		}
+		return LoginResult{}, person.ErrInvalidCredentials
 	}
 
-	// Generate JWT token
-	token, err := uc.jwtService.GenerateToken(person.Username, string(person.Role))
+	// Move an outdated hash to the current algorithm while the plain password is at hand
+	if uc.passwords.NeedsRehash(p.Password()) {
+		if p, err = uc.personRepo.Update(ctx, p.ID(), func(p *person.Person) error {
+			return p.UpgradePassword(password, uc.passwords)
+		}); err != nil {
+			return LoginResult{}, err
+		}
+	}
+
+	return uc.LoginVerified(ctx, p)
+}
+
+// LoginVerified continues a login once the person proved who they are, with
+// the password or through an identity provider: persons with a confirmed
+// second factor, or whose role requires one, still get a challenge.
+func (uc *AuthUseCase) LoginVerified(ctx context.Context, p *person.Person) (LoginResult, error) {
+	kind, err := uc.challengeKind(ctx, p)
 	if err != nil {
-		return "", err
+		return LoginResult{}, err
+	}
+	if kind != "" {
+		challenge, token, err := mfa.NewChallenge(p.ID(), kind, loginChallengeTTL, time.Now())
+		if err != nil {
+			return LoginResult{}, err
+		}
+		if err := uc.mfaRepo.SaveChallenge(ctx, challenge); err != nil {
+			return LoginResult{}, err
+		}
+		return LoginResult{Challenge: &LoginChallenge{Token: token, Kind: kind, ExpiresIn: loginChallengeTTL}}, nil
+	}
+
+	tokens, err := uc.startSession(ctx, p)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	return LoginResult{Tokens: tokens}, nil
+}
+
+// EnrollForLogin starts the TOTP enrolment of a person whose role requires
+// two-factor authentication, authorised by an ENROLL login challenge
+func (uc *AuthUseCase) EnrollForLogin(ctx context.Context, challengeToken string) (TOTPProvisioning, error) {
+	challenge, err := uc.openChallenge(ctx, challengeToken)
+	if err != nil {
+		return TOTPProvisioning{}, err
+	}
+	if challenge.Kind() != mfa.ChallengeEnroll {
+		return TOTPProvisioning{}, mfa.ErrInvalidChallenge
+	}
+
+	p, err := uc.personRepo.FindByID(ctx, challenge.PersonID())
+	if err != nil {
+		return TOTPProvisioning{}, err
+	}
+	return enroll(ctx, uc.tx, uc.mfaRepo, uc.twoFactor, p)
+}
+
+// CompleteLogin finishes a login challenge with a TOTP or recovery code and
+// starts the session. Completing an ENROLL challenge confirms the enrolment,
+// so the recovery codes are returned as well. The challenge, the enrolment and
+// the session are saved in one transaction. A wrong code counts as a failed
+// login of the person and the client IP address, like a wrong password.
+func (uc *AuthUseCase) CompleteLogin(ctx context.Context, challengeToken, code, clientIP string) (Tokens, []string, error) {
+	now := time.Now()
+	var (
+		tokens        Tokens
+		recoveryCodes []string
+		rejected      *mfa.Challenge
+	)
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ipStatus, err := uc.lockoutRepo.Find(ctx, lockout.IPKey(clientIP))
+		if err != nil {
+			return err
+		}
+		if err := ipStatus.Check(now); err != nil {
+			return err
+		}
+
+		challenge, err := uc.openChallenge(ctx, challengeToken)
+		if err != nil {
+			return err
+		}
+		p, err := uc.personRepo.FindByID(ctx, challenge.PersonID())
+		if err != nil {
+			return err
+		}
+		if err := p.LoginStatus().Check(now); err != nil {
+			return err
+		}
+
+		enrollment, err := uc.mfaRepo.FindEnrollmentByPersonID(ctx, p.ID())
+		if err != nil {
+			if errors.Is(err, mfa.ErrEnrollmentNotFound) {
+				// ENROLL challenges must go through EnrollForLogin first
+				return mfa.ErrTOTPNotConfirmed
+			}
+			return err
+		}
+
+		if challenge.Kind() == mfa.ChallengeEnroll {
+			recoveryCodes, err = enrollment.Confirm(code, now)
+		} else {
+			err = enrollment.Verify(code, now)
+		}
+		if err != nil {
+			if errors.Is(err, mfa.ErrInvalidCode) {
+				rejected = challenge
+			}
+			return err
+		}
+
+		challenge.Complete(now)
+		if err := uc.mfaRepo.SaveChallenge(ctx, challenge); err != nil {
+			return err
+		}
+		if err := uc.mfaRepo.SaveEnrollment(ctx, enrollment); err != nil {
+			return err
+		}
+		tokens, err = uc.startSession(ctx, p)
+		return err
+	})
+	// A rejected code is recorded once the transaction rolled back, so it sticks
+	if rejected != nil {
+		rejected.RecordFailure()
+		if saveErr := uc.mfaRepo.SaveChallenge(ctx, rejected); saveErr != nil {
+			return Tokens{}, nil, saveErr
+		}
+		if failErr := uc.recordFailedLogin(ctx, rejected.PersonID(), clientIP, now); failErr != nil {
+			return Tokens{}, nil, failErr
+		}
+	}
+	if err != nil {
+		return Tokens{}, nil, err
 	}
+	return tokens, recoveryCodes, nil
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token pair.
+// Presenting a refresh token twice revokes its session. The session is locked
+// from its rotation to its save, so a concurrent revocation cannot be undone.
+func (uc *AuthUseCase) Refresh(ctx context.Context, refreshToken string) (Tokens, error) {
+	var tokens Tokens
+	reused := false
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		s, token, err := uc.sessionRepo.FindByRefreshToken(ctx, session.HashToken(refreshToken))
+		if err != nil {
+			return err
+		}
+
+		next, err := s.Rotate(token, uc.refreshTokenTTL, time.Now())
+		if errors.Is(err, session.ErrRefreshTokenReused) {
+			reused = true
+			return uc.revokeReused(ctx, s.ID())
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := uc.sessionRepo.Save(ctx, s); err != nil {
+			if errors.Is(err, session.ErrRefreshTokenReused) {
+				reused = true
+				return uc.revokeReused(ctx, s.ID())
+			}
+			return err
+		}
 
-	return token, nil
+		p, err := uc.personRepo.FindByID(ctx, s.PersonID())
+		if err != nil {
+			return err
+		}
+		tokens, err = uc.issue(p, s, next)
+		return err
+	})
+	if err != nil {
+		return Tokens{}, err
+	}
+	// The revocation is committed before the reuse is reported
+	if reused {
+		return Tokens{}, session.ErrRefreshTokenReused
+	}
+	return tokens, nil
 }
 
-// ValidateToken validates a JWT token and returns the person
-func (uc *AuthUseCase) ValidateToken(tokenString string) (*entities.Person, error) {
-	// Validate token
+// Logout revokes the session of the caller
+func (uc *AuthUseCase) Logout(ctx context.Context) error {
+	caller, ok := identity.FromContext(ctx)
+	if !ok {
+		return identity.ErrUnauthenticated
+	}
+
+	s, err := uc.sessionRepo.FindByID(ctx, caller.SessionID())
+	if err != nil {
+		return err
+	}
+	s.Revoke("logout", time.Now())
+	return uc.sessionRepo.Save(ctx, s)
+}
+
+// RevokeAllSessions revokes every active session of a person and returns how many were revoked
+func (uc *AuthUseCase) RevokeAllSessions(ctx context.Context, personID int64) (int64, error) {
+	if _, err := uc.personRepo.FindByID(ctx, personID); err != nil {
+		return 0, err
+	}
+	return uc.sessionRepo.RevokeAllByPersonID(ctx, personID, "revoked by administrator", time.Now())
+}
+
+// Authenticate validates an access token and returns the identity behind it,
+// rejecting tokens whose session has been revoked. The username and role come
+// from the person rather than the token, so a role change applies from the
+// next request on.
+func (uc *AuthUseCase) Authenticate(ctx context.Context, tokenString string) (identity.Identity, error) {
 	claims, err := uc.jwtService.ValidateToken(tokenString)
 	if err != nil {
-		return nil, err
+		return identity.Identity{}, err
+	}
+
+	s, err := uc.sessionRepo.FindByID(ctx, claims.SessionID)
+	if err != nil {
+		if errors.Is(err, session.ErrSessionNotFound) {
+			return identity.Identity{}, security.ErrInvalidToken
+		}
+		return identity.Identity{}, err
+	}
+	if !s.IsActive() || s.PersonID() != claims.PersonID {
+		return identity.Identity{}, session.ErrSessionRevoked
+	}
+
+	p, err := uc.personRepo.FindByID(ctx, claims.PersonID)
+	if err != nil {
+		if errors.Is(err, person.ErrPersonNotFound) {
+			return identity.Identity{}, security.ErrInvalidToken
+		}
+		return identity.Identity{}, err
 	}
+	return identity.New(p.ID(), claims.SessionID, p.Username(), p.Role()), nil
+}
 
-	// Find person by username
-	person, err := uc.personRepo.FindByUsername(claims.Username)
+// rejectUnknownUsername fails a login for a username without a person the same
+// way a wrong password fails, tracking the username in the lockout repository
+func (uc *AuthUseCase) rejectUnknownUsername(ctx context.Context, username, password, clientIP string, now time.Time) error {
+	status, err := uc.lockoutRepo.Find(ctx, lockout.UsernameKey(username))
 	if err != nil {
+		return err
+	}
+	if err := status.Check(now); err != nil {
+		return err
+	}
+
+	uc.passwords.CompareDummy(password)
+	if err := uc.recordFailure(ctx, lockout.UsernameKey(username), uc.lockouts.Username, now); err != nil {
+		return err
+	}
+	if err := uc.recordFailure(ctx, lockout.IPKey(clientIP), uc.lockouts.IP, now); err != nil {
+		return err
+	}
+	return person.ErrInvalidCredentials
+}
+
+// recordFailedLogin counts a failed login of a person against them and the client IP address
+func (uc *AuthUseCase) recordFailedLogin(ctx context.Context, personID int64, clientIP string, now time.Time) error {
+	if _, err := uc.personRepo.Update(ctx, personID, func(p *person.Person) error {
+		p.RecordFailedLogin(uc.lockouts.Username, now)
+		return nil
+	}); err != nil {
+		return err
+	}
+	return uc.recordFailure(ctx, lockout.IPKey(clientIP), uc.lockouts.IP, now)
+}
+
+func (uc *AuthUseCase) recordFailure(ctx context.Context, key string, policy lockout.Policy, now time.Time) error {
+	_, err := uc.lockoutRepo.Update(ctx, key, func(s lockout.Status) lockout.Status {
+		return s.RecordFailure(policy, now)
+	})
+	return err
+}
+
+// challengeKind decides whether a login needs a second step
+func (uc *AuthUseCase) challengeKind(ctx context.Context, p *person.Person) (mfa.ChallengeKind, error) {
+	enrollment, err := uc.mfaRepo.FindEnrollmentByPersonID(ctx, p.ID())
+	if err != nil && !errors.Is(err, mfa.ErrEnrollmentNotFound) {
+		return "", err
+	}
+
+	switch {
+	case enrollment != nil && enrollment.IsConfirmed():
+		return mfa.ChallengeTOTP, nil
+	case uc.twoFactor.IsRequired(p.Role()):
+		return mfa.ChallengeEnroll, nil
+	default:
+		return "", nil
+	}
+}
+
+func (uc *AuthUseCase) openChallenge(ctx context.Context, challengeToken string) (*mfa.Challenge, error) {
+	challenge, err := uc.mfaRepo.FindChallenge(ctx, mfa.HashCode(challengeToken))
+	if err != nil {
+		return nil, err
+	}
+	if err := challenge.EnsureOpen(time.Now()); err != nil {
 		return nil, err
 	}
-	if person == nil {
-		return nil, ErrPersonNotFound
+	return challenge, nil
+}
+
+// startSession completes a login, clearing the past failures of the person
+// now that every step succeeded
+func (uc *AuthUseCase) startSession(ctx context.Context, p *person.Person) (Tokens, error) {
+	if !p.LoginStatus().IsZero() {
+		var err error
+		if p, err = uc.personRepo.Update(ctx, p.ID(), func(p *person.Person) error {
+			p.Unlock()
+			return nil
+		}); err != nil {
+			return Tokens{}, err
+		}
 	}
 
-	return person, nil
+	s, refreshToken, err := session.NewSession(p.ID(), uc.refreshTokenTTL, time.Now())
+	if err != nil {
+		return Tokens{}, err
+	}
+	if err := uc.sessionRepo.Save(ctx, s); err != nil {
+		return Tokens{}, err
+	}
+	return uc.issue(p, s, refreshToken)
+}
+
+func (uc *AuthUseCase) issue(p *person.Person, s *session.Session, refreshToken string) (Tokens, error) {
+	// Generate JWT token
+	accessToken, err := uc.jwtService.GenerateToken(p.ID(), s.ID(), p.Username(), p.Role().String())
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	return Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    uc.jwtService.TTL(),
+	}, nil
+}
+
+// revokeReused revokes a session whose refresh token was presented twice
+func (uc *AuthUseCase) revokeReused(ctx context.Context, sessionID int64) error {
+	s, err := uc.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	s.Revoke("refresh token reuse", time.Now())
+	return uc.sessionRepo.Save(ctx, s)
 }
@@ -0,0 +1,260 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/adapters/database/memory"
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/lockout"
+	"github.com/cropflow/api/internal/domain/mfa"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/session"
+	"github.com/cropflow/api/internal/infrastructure/security"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const clientIP = "203.0.113.7"
+
+func TestAuthUseCase(t *testing.T) {
+	t.Run("should apply a role change to access tokens already issued", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := newAuthUseCase(t, store, mfa.Settings{})
+		personUC := usecases.NewPersonUseCase(memory.NewPersonRepository(store), person.DefaultPasswordPolicy(), memory.NewTransactionManager(store))
+		ctx := context.Background()
+		admin := savePassword(t, store, "john_doe", "SecurePass123!", person.RoleAdmin)
+		adminCtx := identity.NewContext(ctx, identity.New(admin.ID(), 0, admin.Username(), admin.Role()))
+		demoted := savePassword(t, store, "jane_doe", "SecurePass123!", person.RoleAdmin)
+		result, err := uc.Login(ctx, "jane_doe", "SecurePass123!", clientIP)
+		require.NoError(t, err)
+
+		// Act
+		_, err = personUC.ChangeRole(adminCtx, demoted.ID(), person.RoleUser.String())
+		require.NoError(t, err)
+		id, err := uc.Authenticate(ctx, result.Tokens.AccessToken)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, person.RoleUser, id.Role())
+	})
+
+	t.Run("should lock the person after repeated wrong second-factor codes", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := newAuthUseCase(t, store, mfa.Settings{})
+		ctx := context.Background()
+		p := savePassword(t, store, "john_doe", "SecurePass123!", person.RoleUser)
+		secret := saveTOTP(t, store, p)
+		result, err := uc.Login(ctx, "john_doe", "SecurePass123!", clientIP)
+		require.NoError(t, err)
+		require.NotNil(t, result.Challenge)
+
+		// Act
+		for i := 0; i < 3; i++ {
+			_, _, err = uc.CompleteLogin(ctx, result.Challenge.Token, "wrong-code", clientIP)
+			require.ErrorIs(t, err, mfa.ErrInvalidCode)
+		}
+		_, _, err = uc.CompleteLogin(ctx, result.Challenge.Token, totpCode(t, secret, time.Now()), clientIP)
+
+		// Assert
+		assert.ErrorIs(t, err, lockout.ErrLocked)
+		_, err = uc.Login(ctx, "john_doe", "SecurePass123!", "198.51.100.1")
+		assert.ErrorIs(t, err, lockout.ErrLocked)
+	})
+
+	t.Run("should start a session once the second-factor code is accepted", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := newAuthUseCase(t, store, mfa.Settings{})
+		ctx := context.Background()
+		secret := saveTOTP(t, store, savePassword(t, store, "john_doe", "SecurePass123!", person.RoleUser))
+		result, err := uc.Login(ctx, "john_doe", "SecurePass123!", clientIP)
+		require.NoError(t, err)
+		require.NotNil(t, result.Challenge)
+
+		// Act
+		tokens, _, err := uc.CompleteLogin(ctx, result.Challenge.Token, totpCode(t, secret, time.Now()), clientIP)
+
+		// Assert
+		require.NoError(t, err)
+		id, err := uc.Authenticate(ctx, tokens.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "john_doe", id.Username())
+		_, _, err = uc.CompleteLogin(ctx, result.Challenge.Token, totpCode(t, secret, time.Now()), clientIP)
+		assert.ErrorIs(t, err, mfa.ErrInvalidChallenge)
+	})
+
+	t.Run("should revoke the session when a refresh token is presented twice", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := newAuthUseCase(t, store, mfa.Settings{})
+		ctx := context.Background()
+		savePassword(t, store, "john_doe", "SecurePass123!", person.RoleUser)
+		result, err := uc.Login(ctx, "john_doe", "SecurePass123!", clientIP)
+		require.NoError(t, err)
+		rotated, err := uc.Refresh(ctx, result.Tokens.RefreshToken)
+		require.NoError(t, err)
+
+		// Act
+		_, err = uc.Refresh(ctx, result.Tokens.RefreshToken)
+
+		// Assert
+		assert.ErrorIs(t, err, session.ErrRefreshTokenReused)
+		_, err = uc.Refresh(ctx, rotated.RefreshToken)
+		assert.ErrorIs(t, err, session.ErrSessionRevoked)
+		_, err = uc.Authenticate(ctx, rotated.AccessToken)
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthUseCase_Lockout(t *testing.T) {
+	tests := []struct {
+		name      string
+		usernames []string
+		ips       []string
+		username  string
+		ip        string
+	}{
+		{
+			name:      "should lock a username after repeated wrong passwords",
+			usernames: []string{"john_doe", "john_doe", "john_doe"},
+			ips:       []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"},
+			username:  "john_doe",
+			ip:        "198.51.100.4",
+		},
+		{
+			name:      "should lock an unknown username like a known one",
+			usernames: []string{"ghost", "ghost", "ghost"},
+			ips:       []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"},
+			username:  "ghost",
+			ip:        "198.51.100.4",
+		},
+		{
+			name:      "should lock an IP address trying several usernames",
+			usernames: []string{"jane_doe", "ghost", "john_doe"},
+			ips:       []string{clientIP, clientIP, clientIP},
+			username:  "john_doe",
+			ip:        clientIP,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := memory.NewStore()
+			uc := newAuthUseCase(t, store, mfa.Settings{})
+			ctx := context.Background()
+			savePassword(t, store, "john_doe", "SecurePass123!", person.RoleUser)
+			savePassword(t, store, "jane_doe", "SecurePass123!", person.RoleUser)
+
+			// Act
+			for i, username := range tt.usernames {
+				_, err := uc.Login(ctx, username, "WrongPass123!", tt.ips[i])
+				// Unknown usernames fail exactly like wrong passwords
+				require.ErrorIs(t, err, person.ErrInvalidCredentials)
+			}
+			_, err := uc.Login(ctx, tt.username, "SecurePass123!", tt.ip)
+
+			// Assert
+			assert.ErrorIs(t, err, lockout.ErrLocked)
+		})
+	}
+}
+
+func TestAuthUseCase_Challenge(t *testing.T) {
+	required, err := mfa.NewSettings("CropFlow", []string{person.RoleAdmin.String()})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		role     person.Role
+		totp     bool
+		expected mfa.ChallengeKind
+	}{
+		{"should ask a person with a confirmed second factor for a code", person.RoleUser, true, mfa.ChallengeTOTP},
+		{"should ask a person whose role requires a second factor to enrol", person.RoleAdmin, false, mfa.ChallengeEnroll},
+		{"should ask an enrolled person whose role requires a second factor for a code", person.RoleAdmin, true, mfa.ChallengeTOTP},
+		{"should start a session right away without a second factor", person.RoleUser, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := memory.NewStore()
+			uc := newAuthUseCase(t, store, required)
+			p := savePassword(t, store, "john_doe", "SecurePass123!", tt.role)
+			if tt.totp {
+				saveTOTP(t, store, p)
+			}
+
+			// Act
+			result, err := uc.Login(context.Background(), "john_doe", "SecurePass123!", clientIP)
+
+			// Assert
+			require.NoError(t, err)
+			if tt.expected == "" {
+				assert.Nil(t, result.Challenge)
+				assert.NotEmpty(t, result.Tokens.AccessToken)
+				return
+			}
+			require.NotNil(t, result.Challenge)
+			assert.Equal(t, tt.expected, result.Challenge.Kind)
+			assert.Empty(t, result.Tokens.AccessToken)
+		})
+	}
+}
+
+// newAuthUseCase wires an AuthUseCase to the store, locking usernames and IP
+// addresses after three failures
+func newAuthUseCase(t *testing.T, store *memory.Store, twoFactor mfa.Settings) *usecases.AuthUseCase {
+	t.Helper()
+	key, err := security.GenerateSigningKey("EdDSA")
+	require.NoError(t, err)
+	policy, err := lockout.NewPolicy(3, time.Minute, time.Hour, time.Hour)
+	require.NoError(t, err)
+	return usecases.NewAuthUseCase(
+		memory.NewPersonRepository(store),
+		memory.NewSessionRepository(store),
+		memory.NewMFARepository(store),
+		memory.NewLockoutRepository(store),
+		security.NewJWTService(security.NewKeySet(key), "cropflow", 15*time.Minute),
+		24*time.Hour,
+		twoFactor,
+		lockout.Settings{Username: policy, IP: policy},
+		person.DefaultPasswordPolicy(),
+		memory.NewTransactionManager(store),
+	)
+}
+
+// savePassword stores a person who logs in with the given password
+func savePassword(t *testing.T, store *memory.Store, username, password string, role person.Role) *person.Person {
+	t.Helper()
+	p, err := person.NewPerson(username, password, role.String(), person.DefaultPasswordPolicy())
+	require.NoError(t, err)
+	require.NoError(t, memory.NewPersonRepository(store).Save(context.Background(), p))
+	return p
+}
+
+// saveTOTP confirms a TOTP enrolment for the person and returns its secret
+func saveTOTP(t *testing.T, store *memory.Store, p *person.Person) string {
+	t.Helper()
+	now := time.Now().Add(-time.Minute)
+	enrollment, err := mfa.NewEnrollment(p.ID(), now)
+	require.NoError(t, err)
+	_, err = enrollment.Confirm(totpCode(t, enrollment.Secret(), now), now)
+	require.NoError(t, err)
+	require.NoError(t, memory.NewMFARepository(store).SaveEnrollment(context.Background(), enrollment))
+	return enrollment.Secret()
+}
+
+// totpCode computes the TOTP code an authenticator app shows at the given time
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	code, err := mfa.Code(secret, at.Unix()/30)
+	require.NoError(t, err)
+	return code
+}
@@ -0,0 +1,32 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cropflow/api/internal/domain/apikey"
+	"github.com/cropflow/api/internal/domain/identity"
+)
+
+// CredentialAuthenticator resolves the identity behind a bearer credential:
+// API keys, recognised by their prefix, or JWT access tokens
+type CredentialAuthenticator struct {
+	authUseCase   *AuthUseCase
+	apiKeyUseCase *APIKeyUseCase
+}
+
+// NewCredentialAuthenticator creates a new credential authenticator
+func NewCredentialAuthenticator(authUseCase *AuthUseCase, apiKeyUseCase *APIKeyUseCase) *CredentialAuthenticator {
+	return &CredentialAuthenticator{
+		authUseCase:   authUseCase,
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+// Authenticate validates the credential and returns the identity behind it
+func (a *CredentialAuthenticator) Authenticate(ctx context.Context, credential string) (identity.Identity, error) {
+	if strings.HasPrefix(credential, apikey.Prefix) {
+		return a.apiKeyUseCase.Authenticate(ctx, credential)
+	}
+	return a.authUseCase.Authenticate(ctx, credential)
+}
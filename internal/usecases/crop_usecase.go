@@ -1,136 +1,319 @@
 package usecases
 
 import (
+	"context"
 	"errors"
+	"time"
 
-	"github.com/cropflow/api/internal/domain/entities"
-	"github.com/cropflow/api/internal/domain/repositories"
-)
-
-var (
-	ErrCropNotFound = errors.New("crop not found")
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/cropflow/api/internal/domain/farm"
+	"github.com/cropflow/api/internal/domain/fertilizer"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/domain/transaction"
 )
 
 // CropUseCase handles crop business logic
 type CropUseCase struct {
-	cropRepo       repositories.CropRepository
-	farmRepo       repositories.FarmRepository
-	fertilizerRepo repositories.FertilizerRepository
+	cropRepo       crop.Repository
+	farmRepo       farm.Repository
+	fertilizerRepo fertilizer.Repository
+	personRepo     person.Repository
+	tx             transaction.Manager
+	access         farmAccess
 }
 
 // NewCropUseCase creates a new crop use case
 func NewCropUseCase(
-	cropRepo repositories.CropRepository,
-	farmRepo repositories.FarmRepository,
-	fertilizerRepo repositories.FertilizerRepository,
+	cropRepo crop.Repository,
+	farmRepo farm.Repository,
+	fertilizerRepo fertilizer.Repository,
+	personRepo person.Repository,
+	tx transaction.Manager,
 ) *CropUseCase {
 	return &CropUseCase{
 		cropRepo:       cropRepo,
 		farmRepo:       farmRepo,
 		fertilizerRepo: fertilizerRepo,
+		personRepo:     personRepo,
+		tx:             tx,
+		access:         farmAccess{farmRepo: farmRepo},
 	}
 }
 
 // CreateCrop creates a new crop
-func (uc *CropUseCase) CreateCrop(crop *entities.Crop) error {
-	// Validate that farm exists
-	farm, err := uc.farmRepo.FindByID(crop.FarmID)
+func (uc *CropUseCase) CreateCrop(ctx context.Context, farmID int64, name string, plantedArea float64, plantedDate, harvestDate *time.Time) (*crop.Crop, error) {
+	c, err := crop.NewCrop(name, plantedArea, farmID, plantedDate, harvestDate)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if farm == nil {
-		return ErrFarmNotFound
+
+	// The check does not lock the farm: a farm deleted before the insert is
+	// caught by the foreign key of the crop, which Save reports as not found
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.requireFarm(ctx, farmID, farm.MemberManager); err != nil {
+			return err
+		}
+		return uc.cropRepo.Save(ctx, c)
+	})
+	if err != nil {
+		return nil, err
 	}
-	return uc.cropRepo.Create(crop)
+	return c, nil
 }
 
-// GetAllCrops retrieves all crops
-func (uc *CropUseCase) GetAllCrops() ([]entities.Crop, error) {
-	return uc.cropRepo.FindAll()
+// ListCrops retrieves one page of the crops matching the filter that the caller can see
+func (uc *CropUseCase) ListCrops(ctx context.Context, filter crop.Filter, page query.Page) (query.Result[*crop.Crop], error) {
+	memberID, farmIDs, err := uc.access.listScope(ctx)
+	if err != nil {
+		return query.Result[*crop.Crop]{}, err
+	}
+	filter.MemberID = memberID
+	filter.FarmIDs = farmIDs
+	return uc.cropRepo.List(ctx, filter, page)
 }
 
 // GetCropByID retrieves a crop by ID
-func (uc *CropUseCase) GetCropByID(id int64) (*entities.Crop, error) {
-	crop, err := uc.cropRepo.FindByID(id)
+func (uc *CropUseCase) GetCropByID(ctx context.Context, id int64) (*crop.Crop, error) {
+	return uc.access.requireCrop(ctx, uc.cropRepo, id, farm.MemberViewer)
+}
+
+// ListCropsByFarmID retrieves one page of the crops of a specific farm
+func (uc *CropUseCase) ListCropsByFarmID(ctx context.Context, farmID int64, filter crop.Filter, page query.Page) (query.Result[*crop.Crop], error) {
+	if err := uc.requireFarm(ctx, farmID, farm.MemberViewer); err != nil {
+		return query.Result[*crop.Crop]{}, err
+	}
+	filter.FarmID = farmID
+	return uc.cropRepo.List(ctx, filter, page)
+}
+
+// UpdateCrop changes the name and planted area of a crop. The dates must be
+// the ones the crop already has: only its transitions change them.
+func (uc *CropUseCase) UpdateCrop(ctx context.Context, id int64, name string, plantedArea float64, plantedDate, harvestDate *time.Time) (*crop.Crop, error) {
+	var c *crop.Crop
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if c, err = uc.access.lockCrop(ctx, uc.cropRepo, id, farm.MemberManager); err != nil {
+			return err
+		}
+		if err := c.ChangeName(name); err != nil {
+			return err
+		}
+		if err := c.ChangePlantedArea(plantedArea); err != nil {
+			return err
+		}
+		if err := c.KeepDates(plantedDate, harvestDate); err != nil {
+			return err
+		}
+		return uc.cropRepo.Save(ctx, c)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if crop == nil {
-		return nil, ErrCropNotFound
-	}
-	return crop, nil
+	return c, nil
+}
+
+// DeleteCrop deletes a crop
+func (uc *CropUseCase) DeleteCrop(ctx context.Context, id int64) error {
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := uc.access.requireCrop(ctx, uc.cropRepo, id, farm.MemberManager); err != nil {
+			return err
+		}
+		return uc.cropRepo.Delete(ctx, id)
+	})
 }
 
-// GetCropsByFarmID retrieves all crops for a specific farm
-func (uc *CropUseCase) GetCropsByFarmID(farmID int64) ([]entities.Crop, error) {
-	// Validate that farm exists
-	farm, err := uc.farmRepo.FindByID(farmID)
+// TransitionCrop moves a crop to the given lifecycle status.
+// When occurredAt is nil the transition is dated now.
+func (uc *CropUseCase) TransitionCrop(ctx context.Context, id int64, status string, occurredAt *time.Time, note string) (*crop.Crop, error) {
+	to, err := crop.NewStatus(status)
 	if err != nil {
 		return nil, err
 	}
-	if farm == nil {
-		return nil, ErrFarmNotFound
+
+	at := time.Now()
+	if occurredAt != nil {
+		at = *occurredAt
 	}
-	return uc.cropRepo.FindByFarmID(farmID)
-}
 
-// UpdateCrop updates a crop
-func (uc *CropUseCase) UpdateCrop(crop *entities.Crop) error {
-	existing, err := uc.cropRepo.FindByID(crop.ID)
+	var c *crop.Crop
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if c, err = uc.access.lockCrop(ctx, uc.cropRepo, id, farm.MemberManager); err != nil {
+			return err
+		}
+		if err := c.TransitionTo(to, at, note); err != nil {
+			return err
+		}
+		return uc.cropRepo.Save(ctx, c)
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if existing == nil {
-		return ErrCropNotFound
+	return c, nil
+}
+
+// GetCropTransitions retrieves the status history of a crop in the order it was recorded
+func (uc *CropUseCase) GetCropTransitions(ctx context.Context, id int64) ([]crop.Transition, error) {
+	if _, err := uc.access.requireCrop(ctx, uc.cropRepo, id, farm.MemberViewer); err != nil {
+		return nil, err
 	}
-	return uc.cropRepo.Update(crop)
+	return uc.cropRepo.FindTransitions(ctx, id)
 }
 
-// DeleteCrop deletes a crop
-func (uc *CropUseCase) DeleteCrop(id int64) error {
-	existing, err := uc.cropRepo.FindByID(id)
+// RecordHarvest records a harvest of a crop.
+// When harvestedAt is nil the harvest is dated now.
+func (uc *CropUseCase) RecordHarvest(ctx context.Context, cropID int64, harvestedAt *time.Time, quantity float64, unit string, moisture *float64, grade string, partial bool) (*crop.Harvest, error) {
+	u, err := crop.NewUnit(unit)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	at := time.Now()
+	if harvestedAt != nil {
+		at = *harvestedAt
 	}
-	if existing == nil {
-		return ErrCropNotFound
+
+	var h *crop.Harvest
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		c, err := uc.access.lockCrop(ctx, uc.cropRepo, cropID, farm.MemberManager)
+		if err != nil {
+			return err
+		}
+		if h, err = c.RecordHarvest(at, quantity, u, moisture, grade, partial); err != nil {
+			return err
+		}
+		return uc.cropRepo.Save(ctx, c)
+	})
+	if err != nil {
+		return nil, err
 	}
-	return uc.cropRepo.Delete(id)
+	return h, nil
 }
 
-// AddFertilizerToCrop associates a fertilizer with a crop
-func (uc *CropUseCase) AddFertilizerToCrop(cropID, fertilizerID int64) error {
-	// Validate crop exists
-	crop, err := uc.cropRepo.FindByID(cropID)
+// ListHarvests retrieves all harvests of a crop, oldest first
+func (uc *CropUseCase) ListHarvests(ctx context.Context, cropID int64) ([]*crop.Harvest, error) {
+	if _, err := uc.access.requireCrop(ctx, uc.cropRepo, cropID, farm.MemberViewer); err != nil {
+		return nil, err
+	}
+	return uc.cropRepo.FindHarvests(ctx, cropID)
+}
+
+// GetHarvestByID retrieves a harvest of a crop
+func (uc *CropUseCase) GetHarvestByID(ctx context.Context, cropID, harvestID int64) (*crop.Harvest, error) {
+	if _, err := uc.access.requireCrop(ctx, uc.cropRepo, cropID, farm.MemberViewer); err != nil {
+		return nil, err
+	}
+	return uc.cropRepo.FindHarvestByID(ctx, cropID, harvestID)
+}
+
+// DeleteHarvest deletes a harvest recorded by mistake
+func (uc *CropUseCase) DeleteHarvest(ctx context.Context, cropID, harvestID int64) error {
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := uc.access.requireCrop(ctx, uc.cropRepo, cropID, farm.MemberManager); err != nil {
+			return err
+		}
+		return uc.cropRepo.DeleteHarvest(ctx, cropID, harvestID)
+	})
+}
+
+// GetCropYield computes the yield of a crop from its harvests
+func (uc *CropUseCase) GetCropYield(ctx context.Context, cropID int64) (crop.Yield, error) {
+	c, err := uc.access.requireCrop(ctx, uc.cropRepo, cropID, farm.MemberViewer)
 	if err != nil {
-		return err
+		return crop.Yield{}, err
 	}
-	if crop == nil {
-		return ErrCropNotFound
+
+	harvests, err := uc.cropRepo.FindHarvests(ctx, cropID)
+	if err != nil {
+		return crop.Yield{}, err
 	}
+	return c.Yield(harvests), nil
+}
 
-	// Validate fertilizer exists
-	fertilizer, err := uc.fertilizerRepo.FindByID(fertilizerID)
+// RecordApplication records a fertilizer application on a crop.
+// When appliedAt is nil the application is dated now, and when appliedArea
+// is nil the whole planted area is assumed.
+func (uc *CropUseCase) RecordApplication(ctx context.Context, cropID, fertilizerID int64, appliedAt *time.Time, dose float64, doseUnit string, appliedArea *float64, operatorID *int64, notes string) (*crop.Application, error) {
+	unit, err := crop.NewDoseUnit(doseUnit)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if fertilizer == nil {
-		return errors.New("fertilizer not found")
+
+	at := time.Now()
+	if appliedAt != nil {
+		at = *appliedAt
 	}
 
-	return uc.cropRepo.AddFertilizer(cropID, fertilizerID)
-}
+	// The crop, the fertilizer and the operator are checked in the transaction
+	// of the insert, so none of them can be deleted in between
+	var a *crop.Application
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		c, err := uc.access.lockCrop(ctx, uc.cropRepo, cropID, farm.MemberManager)
+		if err != nil {
+			return err
+		}
 
-// GetFertilizersByCropID retrieves all fertilizers for a specific crop
-func (uc *CropUseCase) GetFertilizersByCropID(cropID int64) ([]entities.Fertilizer, error) {
-	// Validate crop exists
-	crop, err := uc.cropRepo.FindByID(cropID)
+		exists, err := uc.fertilizerRepo.ExistsByID(ctx, fertilizerID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return crop.ErrFertilizerNotFound
+		}
+
+		if operatorID != nil {
+			if _, err := uc.personRepo.FindByID(ctx, *operatorID); err != nil {
+				if errors.Is(err, person.ErrPersonNotFound) {
+					return crop.ErrOperatorNotFound
+				}
+				return err
+			}
+		}
+
+		area := c.PlantedArea()
+		if appliedArea != nil {
+			area = *appliedArea
+		}
+		if a, err = c.RecordApplication(fertilizerID, at, dose, unit, area, operatorID, notes); err != nil {
+			return err
+		}
+		return uc.cropRepo.Save(ctx, c)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if crop == nil {
-		return nil, ErrCropNotFound
+	return a, nil
+}
+
+// ListApplications retrieves all fertilizer applications of a crop, oldest first
+func (uc *CropUseCase) ListApplications(ctx context.Context, cropID int64) ([]*crop.Application, error) {
+	if _, err := uc.access.requireCrop(ctx, uc.cropRepo, cropID, farm.MemberViewer); err != nil {
+		return nil, err
+	}
+	return uc.cropRepo.FindApplications(ctx, crop.ApplicationFilter{CropID: cropID})
+}
+
+// GetFertilizersByCropID retrieves the distinct fertilizers applied to a crop
+func (uc *CropUseCase) GetFertilizersByCropID(ctx context.Context, cropID int64) ([]*fertilizer.Fertilizer, error) {
+	if _, err := uc.access.requireCrop(ctx, uc.cropRepo, cropID, farm.MemberViewer); err != nil {
+		return nil, err
+	}
+
+	ids, err := uc.cropRepo.FindFertilizersByCropID(ctx, cropID)
+	if err != nil {
+		return nil, err
 	}
+	return uc.fertilizerRepo.FindByIDs(ctx, ids)
+}
 
-	return uc.cropRepo.FindFertilizersByCropID(cropID)
+// requireFarm checks the caller holds the required role in the farm a crop is planted on
+func (uc *CropUseCase) requireFarm(ctx context.Context, farmID int64, required farm.MemberRole) error {
+	if err := uc.access.requireFarm(ctx, farmID, required); err != nil {
+		if errors.Is(err, farm.ErrFarmNotFound) {
+			return crop.ErrFarmNotFound
+		}
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,143 @@
+package usecases_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/cropflow/api/internal/adapters/database/memory"
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/cropflow/api/internal/domain/farm"
+	"github.com/cropflow/api/internal/domain/fertilizer"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCropUseCase(t *testing.T) {
+	newUseCases := func(store *memory.Store) (*usecases.FarmUseCase, *usecases.CropUseCase) {
+		farms := memory.NewFarmRepository(store)
+		persons := memory.NewPersonRepository(store)
+		tx := memory.NewTransactionManager(store)
+		return usecases.NewFarmUseCase(farms, persons, tx),
+			usecases.NewCropUseCase(memory.NewCropRepository(store), farms, memory.NewFertilizerRepository(store), persons, tx)
+	}
+
+	t.Run("should create a crop on a farm the caller manages", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		farmUC, cropUC := newUseCases(store)
+		ctx := callerContext(t, store, "john_doe", person.RoleManager)
+		f, err := farmUC.CreateFarm(ctx, "Fazenda Boa Vista", 150.5)
+		require.NoError(t, err)
+
+		// Act
+		c, err := cropUC.CreateCrop(ctx, f.ID(), "Soja", 100, nil, nil)
+
+		// Assert
+		require.NoError(t, err)
+		found, err := cropUC.GetCropByID(ctx, c.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "Soja", found.Name())
+		assert.Equal(t, f.ID(), found.FarmID())
+	})
+
+	t.Run("should report a farm the caller is not a member of as not found", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		farmUC, cropUC := newUseCases(store)
+		f, err := farmUC.CreateFarm(callerContext(t, store, "john_doe", person.RoleManager), "Fazenda Boa Vista", 150.5)
+		require.NoError(t, err)
+
+		// Act
+		_, err = cropUC.CreateCrop(callerContext(t, store, "jane_doe", person.RoleManager), f.ID(), "Soja", 100, nil, nil)
+
+		// Assert
+		assert.ErrorIs(t, err, crop.ErrFarmNotFound)
+	})
+
+	t.Run("should not leave a crop on a farm deleted at the same time", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		farmUC, cropUC := newUseCases(store)
+		ctx := callerContext(t, store, "john_doe", person.RoleManager)
+		f, err := farmUC.CreateFarm(ctx, "Fazenda Boa Vista", 150.5)
+		require.NoError(t, err)
+		var wg sync.WaitGroup
+		var createErr, deleteErr error
+		wg.Add(2)
+
+		// Act
+		go func() {
+			defer wg.Done()
+			_, createErr = cropUC.CreateCrop(ctx, f.ID(), "Soja", 100, nil, nil)
+		}()
+		go func() {
+			defer wg.Done()
+			deleteErr = farmUC.DeleteFarm(ctx, f.ID())
+		}()
+		wg.Wait()
+
+		// Assert
+		if deleteErr == nil {
+			assert.ErrorIs(t, createErr, crop.ErrFarmNotFound)
+		} else {
+			assert.ErrorIs(t, deleteErr, farm.ErrFarmHasCrops)
+			assert.NoError(t, createErr)
+		}
+	})
+
+	t.Run("should record a fertilizer application on a crop", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		farmUC, cropUC := newUseCases(store)
+		ctx := callerContext(t, store, "john_doe", person.RoleManager)
+		f, err := farmUC.CreateFarm(ctx, "Fazenda Boa Vista", 150.5)
+		require.NoError(t, err)
+		c, err := cropUC.CreateCrop(ctx, f.ID(), "Soja", 100, nil, nil)
+		require.NoError(t, err)
+		fert := saveFertilizer(t, store)
+
+		// Act
+		a, err := cropUC.RecordApplication(ctx, c.ID(), fert.ID(), nil, 200, crop.DoseKilogramsPerHectare.String(), nil, nil, "")
+
+		// Assert
+		require.NoError(t, err)
+		assert.NotZero(t, a.ID())
+		applications, err := cropUC.ListApplications(ctx, c.ID())
+		require.NoError(t, err)
+		require.Len(t, applications, 1)
+		assert.Equal(t, fert.ID(), applications[0].FertilizerID())
+	})
+
+	t.Run("should reject an application of an unknown fertilizer", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		farmUC, cropUC := newUseCases(store)
+		ctx := callerContext(t, store, "john_doe", person.RoleManager)
+		f, err := farmUC.CreateFarm(ctx, "Fazenda Boa Vista", 150.5)
+		require.NoError(t, err)
+		c, err := cropUC.CreateCrop(ctx, f.ID(), "Soja", 100, nil, nil)
+		require.NoError(t, err)
+
+		// Act
+		_, err = cropUC.RecordApplication(ctx, c.ID(), 999, nil, 200, crop.DoseKilogramsPerHectare.String(), nil, nil, "")
+
+		// Assert
+		assert.ErrorIs(t, err, crop.ErrFertilizerNotFound)
+		applications, err := cropUC.ListApplications(ctx, c.ID())
+		require.NoError(t, err)
+		assert.Empty(t, applications)
+	})
+}
+
+func saveFertilizer(t *testing.T, store *memory.Store) *fertilizer.Fertilizer {
+	t.Helper()
+	composition, err := fertilizer.ParseComposition("10-10-10")
+	require.NoError(t, err)
+	f, err := fertilizer.NewFertilizer("NPK", "Yara", composition)
+	require.NoError(t, err)
+	require.NoError(t, memory.NewFertilizerRepository(store).Save(context.Background(), f))
+	return f
+}
@@ -0,0 +1,111 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/cropflow/api/internal/domain/farm"
+	"github.com/cropflow/api/internal/domain/identity"
+)
+
+// farmAccess enforces farm membership for the identity carried by the context.
+// Administrators can access every farm; everybody else only the farms they are
+// a member of, with at least the required member role. API keys limited to some
+// farms cannot access any other. Farms the caller is not a member of are
+// reported as not found so their existence is not disclosed.
+type farmAccess struct {
+	farmRepo farm.Repository
+}
+
+// caller returns the identity performing the operation
+func (a farmAccess) caller(ctx context.Context) (identity.Identity, error) {
+	id, ok := identity.FromContext(ctx)
+	if !ok {
+		return identity.Identity{}, identity.ErrUnauthenticated
+	}
+	return id, nil
+}
+
+// listScope returns the person listings must be restricted to, or zero for
+// administrators, and the farms the caller is limited to, if any
+func (a farmAccess) listScope(ctx context.Context) (int64, []int64, error) {
+	id, err := a.caller(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if id.Role().IsAdmin() {
+		return 0, id.FarmIDs(), nil
+	}
+	return id.PersonID(), id.FarmIDs(), nil
+}
+
+// requireFarm checks the caller holds at least the required role in the farm
+func (a farmAccess) requireFarm(ctx context.Context, farmID int64, required farm.MemberRole) error {
+	id, err := a.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if !id.CanAccessFarm(farmID) {
+		return farm.ErrFarmNotFound
+	}
+
+	if id.Role().IsAdmin() {
+		exists, err := a.farmRepo.ExistsByID(ctx, farmID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return farm.ErrFarmNotFound
+		}
+		return nil
+	}
+
+	m, err := a.farmRepo.FindMember(ctx, farmID, id.PersonID())
+	if err != nil {
+		if errors.Is(err, farm.ErrMemberNotFound) {
+			return farm.ErrFarmNotFound
+		}
+		return err
+	}
+	if !m.Role().HasPermission(required) {
+		return farm.ErrFarmAccessDenied
+	}
+	return nil
+}
+
+// requireCrop checks the caller holds at least the required role in the farm
+// the crop belongs to, and returns the crop
+func (a farmAccess) requireCrop(ctx context.Context, cropRepo crop.Repository, cropID int64, required farm.MemberRole) (*crop.Crop, error) {
+	c, err := cropRepo.FindByID(ctx, cropID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.requireCropFarm(ctx, c, required); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// lockCrop is requireCrop for changes: the crop stays locked until the
+// transaction in ctx ends, so concurrent changes cannot overwrite each other
+func (a farmAccess) lockCrop(ctx context.Context, cropRepo crop.Repository, cropID int64, required farm.MemberRole) (*crop.Crop, error) {
+	c, err := cropRepo.FindByIDForUpdate(ctx, cropID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.requireCropFarm(ctx, c, required); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (a farmAccess) requireCropFarm(ctx context.Context, c *crop.Crop, required farm.MemberRole) error {
+	if err := a.requireFarm(ctx, c.FarmID(), required); err != nil {
+		if errors.Is(err, farm.ErrFarmNotFound) {
+			return crop.ErrCropNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -1,70 +1,226 @@
 package usecases
 
 import (
-	"errors"
+	"context"
 
-	"github.com/cropflow/api/internal/domain/entities"
-	"github.com/cropflow/api/internal/domain/repositories"
-)
-
-var (
-	ErrFarmNotFound = errors.New("farm not found")
+	"github.com/cropflow/api/internal/domain/farm"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/domain/transaction"
 )
 
 // FarmUseCase handles farm business logic
 type FarmUseCase struct {
-	farmRepo repositories.FarmRepository
+	farmRepo   farm.Repository
+	personRepo person.Repository
+	tx         transaction.Manager
+	access     farmAccess
 }
 
 // NewFarmUseCase creates a new farm use case
-func NewFarmUseCase(farmRepo repositories.FarmRepository) *FarmUseCase {
+func NewFarmUseCase(farmRepo farm.Repository, personRepo person.Repository, tx transaction.Manager) *FarmUseCase {
 	return &FarmUseCase{
-		farmRepo: farmRepo,
+		farmRepo:   farmRepo,
+		personRepo: personRepo,
+		tx:         tx,
+		access:     farmAccess{farmRepo: farmRepo},
 	}
 }
 
-// CreateFarm creates a new farm
-func (uc *FarmUseCase) CreateFarm(farm *entities.Farm) error {
-	return uc.farmRepo.Create(farm)
+// CreateFarm creates a new farm owned by the caller
+func (uc *FarmUseCase) CreateFarm(ctx context.Context, name string, size float64) (*farm.Farm, error) {
+	caller, err := uc.access.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// A new farm would fall outside the farms an API key is limited to
+	if len(caller.FarmIDs()) > 0 {
+		return nil, farm.ErrFarmAccessDenied
+	}
+
+	f, err := farm.NewFarm(name, size)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.AddMember(caller.PersonID(), farm.MemberOwner); err != nil {
+		return nil, err
+	}
+	if err := uc.farmRepo.Save(ctx, f); err != nil {
+		return nil, err
+	}
+	return f, nil
 }
 
-// GetAllFarms retrieves all farms
-func (uc *FarmUseCase) GetAllFarms() ([]entities.Farm, error) {
-	return uc.farmRepo.FindAll()
+// ListFarms retrieves one page of the farms matching the filter that the caller can see
+func (uc *FarmUseCase) ListFarms(ctx context.Context, filter farm.Filter, page query.Page) (query.Result[*farm.Farm], error) {
+	memberID, farmIDs, err := uc.access.listScope(ctx)
+	if err != nil {
+		return query.Result[*farm.Farm]{}, err
+	}
+	filter.MemberID = memberID
+	filter.FarmIDs = farmIDs
+	return uc.farmRepo.List(ctx, filter, page)
 }
 
 // GetFarmByID retrieves a farm by ID
-func (uc *FarmUseCase) GetFarmByID(id int64) (*entities.Farm, error) {
-	farm, err := uc.farmRepo.FindByID(id)
+func (uc *FarmUseCase) GetFarmByID(ctx context.Context, id int64) (*farm.Farm, error) {
+	if err := uc.access.requireFarm(ctx, id, farm.MemberViewer); err != nil {
+		return nil, err
+	}
+	return uc.farmRepo.FindByID(ctx, id)
+}
+
+// UpdateFarm updates a farm
+func (uc *FarmUseCase) UpdateFarm(ctx context.Context, id int64, name string, size float64) (*farm.Farm, error) {
+	var f *farm.Farm
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.access.requireFarm(ctx, id, farm.MemberManager); err != nil {
+			return err
+		}
+
+		var err error
+		if f, err = uc.farmRepo.FindByID(ctx, id); err != nil {
+			return err
+		}
+		if err := f.ChangeName(name); err != nil {
+			return err
+		}
+		if err := f.ChangeSize(size); err != nil {
+			return err
+		}
+		return uc.farmRepo.Save(ctx, f)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// DeleteFarm deletes a farm
+func (uc *FarmUseCase) DeleteFarm(ctx context.Context, id int64) error {
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.access.requireFarm(ctx, id, farm.MemberOwner); err != nil {
+			return err
+		}
+		return uc.farmRepo.Delete(ctx, id)
+	})
+}
+
+// ListMembers retrieves the members of a farm
+func (uc *FarmUseCase) ListMembers(ctx context.Context, farmID int64) ([]*farm.Member, error) {
+	if err := uc.access.requireFarm(ctx, farmID, farm.MemberViewer); err != nil {
+		return nil, err
+	}
+	return uc.farmRepo.FindMembers(ctx, farmID)
+}
+
+// AddMember grants a person a role in a farm
+func (uc *FarmUseCase) AddMember(ctx context.Context, farmID, personID int64, role string) (*farm.Member, error) {
+	r, err := farm.NewMemberRole(role)
 	if err != nil {
 		return nil, err
 	}
-	if farm == nil {
-		return nil, ErrFarmNotFound
+
+	var m *farm.Member
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.access.requireFarm(ctx, farmID, farm.MemberOwner); err != nil {
+			return err
+		}
+
+		f, err := uc.farmRepo.FindByID(ctx, farmID)
+		if err != nil {
+			return err
+		}
+		if _, err := uc.personRepo.FindByID(ctx, personID); err != nil {
+			return err
+		}
+
+		if m, err = f.AddMember(personID, r); err != nil {
+			return err
+		}
+		return uc.farmRepo.Save(ctx, f)
+	})
+	if err != nil {
+		return nil, err
 	}
-	return farm, nil
+	return m, nil
 }
 
-// UpdateFarm updates a farm
-func (uc *FarmUseCase) UpdateFarm(farm *entities.Farm) error {
-	existing, err := uc.farmRepo.FindByID(farm.ID)
+// ChangeMemberRole changes the role of a member of a farm
+func (uc *FarmUseCase) ChangeMemberRole(ctx context.Context, farmID, personID int64, role string) (*farm.Member, error) {
+	r, err := farm.NewMemberRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	var m *farm.Member
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.access.requireFarm(ctx, farmID, farm.MemberOwner); err != nil {
+			return err
+		}
+
+		var err error
+		if m, err = uc.farmRepo.FindMember(ctx, farmID, personID); err != nil {
+			return err
+		}
+		if m.IsOwner() && r != farm.MemberOwner {
+			if err := uc.ensureAnotherOwner(ctx, farmID, personID); err != nil {
+				return err
+			}
+		}
+
+		if err := m.ChangeRole(r); err != nil {
+			return err
+		}
+		return uc.farmRepo.UpdateMember(ctx, m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RemoveMember revokes the membership of a person in a farm.
+// Owners can remove anybody; any member can leave the farm.
+func (uc *FarmUseCase) RemoveMember(ctx context.Context, farmID, personID int64) error {
+	caller, err := uc.access.caller(ctx)
 	if err != nil {
 		return err
 	}
-	if existing == nil {
-		return ErrFarmNotFound
+
+	required := farm.MemberOwner
+	if caller.PersonID() == personID {
+		required = farm.MemberViewer
 	}
-	return uc.farmRepo.Update(farm)
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.access.requireFarm(ctx, farmID, required); err != nil {
+			return err
+		}
+
+		m, err := uc.farmRepo.FindMember(ctx, farmID, personID)
+		if err != nil {
+			return err
+		}
+		if m.IsOwner() {
+			if err := uc.ensureAnotherOwner(ctx, farmID, personID); err != nil {
+				return err
+			}
+		}
+		return uc.farmRepo.DeleteMember(ctx, farmID, personID)
+	})
 }
 
-// DeleteFarm deletes a farm
-func (uc *FarmUseCase) DeleteFarm(id int64) error {
-	existing, err := uc.farmRepo.FindByID(id)
+// ensureAnotherOwner checks the farm keeps an owner other than the given
+// person, locking the owners so a concurrent change cannot remove the other one
+func (uc *FarmUseCase) ensureAnotherOwner(ctx context.Context, farmID, personID int64) error {
+	owners, err := uc.farmRepo.LockOwners(ctx, farmID)
 	if err != nil {
 		return err
 	}
-	if existing == nil {
-		return ErrFarmNotFound
+	for _, id := range owners {
+		if id != personID {
+			return nil
+		}
 	}
-	return uc.farmRepo.Delete(id)
+	return farm.ErrLastOwner
 }
@@ -0,0 +1,136 @@
+package usecases_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/adapters/database/memory"
+	"github.com/cropflow/api/internal/domain/farm"
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/lockout"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFarmUseCase(t *testing.T) {
+	t.Run("should make the caller the owner of a new farm", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := usecases.NewFarmUseCase(memory.NewFarmRepository(store), memory.NewPersonRepository(store), memory.NewTransactionManager(store))
+		ctx := callerContext(t, store, "john_doe", person.RoleManager)
+
+		// Act
+		f, err := uc.CreateFarm(ctx, "Fazenda Boa Vista", 150.5)
+
+		// Assert
+		require.NoError(t, err)
+		members, err := uc.ListMembers(ctx, f.ID())
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		assert.True(t, members[0].IsOwner())
+	})
+
+	t.Run("should hide a farm from a person who is not a member", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := usecases.NewFarmUseCase(memory.NewFarmRepository(store), memory.NewPersonRepository(store), memory.NewTransactionManager(store))
+		f, err := uc.CreateFarm(callerContext(t, store, "john_doe", person.RoleManager), "Fazenda Boa Vista", 150.5)
+		require.NoError(t, err)
+
+		// Act
+		_, err = uc.GetFarmByID(callerContext(t, store, "jane_doe", person.RoleManager), f.ID())
+
+		// Assert
+		assert.ErrorIs(t, err, farm.ErrFarmNotFound)
+	})
+
+	t.Run("should let a member see the farm once added", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := usecases.NewFarmUseCase(memory.NewFarmRepository(store), memory.NewPersonRepository(store), memory.NewTransactionManager(store))
+		ownerCtx := callerContext(t, store, "john_doe", person.RoleManager)
+		memberCtx := callerContext(t, store, "jane_doe", person.RoleUser)
+		f, err := uc.CreateFarm(ownerCtx, "Fazenda Boa Vista", 150.5)
+		require.NoError(t, err)
+		member, _ := identity.FromContext(memberCtx)
+
+		// Act
+		_, err = uc.AddMember(ownerCtx, f.ID(), member.PersonID(), farm.MemberViewer.String())
+
+		// Assert
+		require.NoError(t, err)
+		found, err := uc.GetFarmByID(memberCtx, f.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "Fazenda Boa Vista", found.Name())
+	})
+
+	t.Run("should not let the last owner leave the farm", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := usecases.NewFarmUseCase(memory.NewFarmRepository(store), memory.NewPersonRepository(store), memory.NewTransactionManager(store))
+		ctx := callerContext(t, store, "john_doe", person.RoleManager)
+		owner, _ := identity.FromContext(ctx)
+		f, err := uc.CreateFarm(ctx, "Fazenda Boa Vista", 150.5)
+		require.NoError(t, err)
+
+		// Act
+		err = uc.RemoveMember(ctx, f.ID(), owner.PersonID())
+
+		// Assert
+		assert.ErrorIs(t, err, farm.ErrLastOwner)
+	})
+
+	t.Run("should keep an owner when two owners leave at the same time", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := usecases.NewFarmUseCase(memory.NewFarmRepository(store), memory.NewPersonRepository(store), memory.NewTransactionManager(store))
+		firstCtx := callerContext(t, store, "john_doe", person.RoleManager)
+		secondCtx := callerContext(t, store, "jane_doe", person.RoleManager)
+		second, _ := identity.FromContext(secondCtx)
+		f, err := uc.CreateFarm(firstCtx, "Fazenda Boa Vista", 150.5)
+		require.NoError(t, err)
+		_, err = uc.AddMember(firstCtx, f.ID(), second.PersonID(), farm.MemberOwner.String())
+		require.NoError(t, err)
+		errs := make([]error, 2)
+		var wg sync.WaitGroup
+		wg.Add(2)
+
+		// Act
+		for i, ctx := range []context.Context{firstCtx, secondCtx} {
+			go func(i int, ctx context.Context) {
+				defer wg.Done()
+				caller, _ := identity.FromContext(ctx)
+				errs[i] = uc.RemoveMember(ctx, f.ID(), caller.PersonID())
+			}(i, ctx)
+		}
+		wg.Wait()
+
+		// Assert
+		var failed []error
+		for _, err := range errs {
+			if err != nil {
+				failed = append(failed, err)
+			}
+		}
+		require.Len(t, failed, 1)
+		assert.ErrorIs(t, failed[0], farm.ErrLastOwner)
+		members, err := memory.NewFarmRepository(store).FindMembers(context.Background(), f.ID())
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		assert.True(t, members[0].IsOwner())
+	})
+}
+
+// callerContext stores a person with the given role and returns a context
+// carrying their identity
+func callerContext(t *testing.T, store *memory.Store, username string, role person.Role) context.Context {
+	t.Helper()
+	now := time.Now()
+	p := person.Restore(0, username, "$2a$04$hash", role, now, now, lockout.Status{})
+	require.NoError(t, memory.NewPersonRepository(store).Save(context.Background(), p))
+	return identity.NewContext(context.Background(), identity.New(p.ID(), 0, username, role))
+}
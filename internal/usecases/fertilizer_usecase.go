@@ -1,70 +1,75 @@
 package usecases
 
 import (
-	"errors"
+	"context"
 
-	"github.com/cropflow/api/internal/domain/entities"
-	"github.com/cropflow/api/internal/domain/repositories"
-)
-
-var (
-	ErrFertilizerNotFound = errors.New("fertilizer not found")
+	"github.com/cropflow/api/internal/domain/fertilizer"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/domain/transaction"
 )
 
 // FertilizerUseCase handles fertilizer business logic
 type FertilizerUseCase struct {
-	fertilizerRepo repositories.FertilizerRepository
+	fertilizerRepo fertilizer.Repository
+	tx             transaction.Manager
 }
 
 // NewFertilizerUseCase creates a new fertilizer use case
-func NewFertilizerUseCase(fertilizerRepo repositories.FertilizerRepository) *FertilizerUseCase {
+func NewFertilizerUseCase(fertilizerRepo fertilizer.Repository, tx transaction.Manager) *FertilizerUseCase {
 	return &FertilizerUseCase{
 		fertilizerRepo: fertilizerRepo,
+		tx:             tx,
 	}
 }
 
 // CreateFertilizer creates a new fertilizer
-func (uc *FertilizerUseCase) CreateFertilizer(fertilizer *entities.Fertilizer) error {
-	return uc.fertilizerRepo.Create(fertilizer)
+func (uc *FertilizerUseCase) CreateFertilizer(ctx context.Context, name, brand string, composition fertilizer.Composition) (*fertilizer.Fertilizer, error) {
+	f, err := fertilizer.NewFertilizer(name, brand, composition)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.fertilizerRepo.Save(ctx, f); err != nil {
+		return nil, err
+	}
+	return f, nil
 }
 
-// GetAllFertilizers retrieves all fertilizers
-func (uc *FertilizerUseCase) GetAllFertilizers() ([]entities.Fertilizer, error) {
-	return uc.fertilizerRepo.FindAll()
+// ListFertilizers retrieves one page of fertilizers matching the filter
+func (uc *FertilizerUseCase) ListFertilizers(ctx context.Context, filter fertilizer.Filter, page query.Page) (query.Result[*fertilizer.Fertilizer], error) {
+	return uc.fertilizerRepo.List(ctx, filter, page)
 }
 
 // GetFertilizerByID retrieves a fertilizer by ID
-func (uc *FertilizerUseCase) GetFertilizerByID(id int64) (*entities.Fertilizer, error) {
-	fertilizer, err := uc.fertilizerRepo.FindByID(id)
-	if err != nil {
-		return nil, err
-	}
-	if fertilizer == nil {
-		return nil, ErrFertilizerNotFound
-	}
-	return fertilizer, nil
+func (uc *FertilizerUseCase) GetFertilizerByID(ctx context.Context, id int64) (*fertilizer.Fertilizer, error) {
+	return uc.fertilizerRepo.FindByID(ctx, id)
 }
 
 // UpdateFertilizer updates a fertilizer
-func (uc *FertilizerUseCase) UpdateFertilizer(fertilizer *entities.Fertilizer) error {
-	existing, err := uc.fertilizerRepo.FindByID(fertilizer.ID)
+func (uc *FertilizerUseCase) UpdateFertilizer(ctx context.Context, id int64, name, brand string, composition fertilizer.Composition) (*fertilizer.Fertilizer, error) {
+	var f *fertilizer.Fertilizer
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if f, err = uc.fertilizerRepo.FindByID(ctx, id); err != nil {
+			return err
+		}
+		if err := f.ChangeName(name); err != nil {
+			return err
+		}
+		if err := f.ChangeBrand(brand); err != nil {
+			return err
+		}
+		if err := f.ChangeComposition(composition); err != nil {
+			return err
+		}
+		return uc.fertilizerRepo.Save(ctx, f)
+	})
 	if err != nil {
-		return err
-	}
-	if existing == nil {
-		return ErrFertilizerNotFound
+		return nil, err
 	}
-	return uc.fertilizerRepo.Update(fertilizer)
+	return f, nil
 }
 
 // DeleteFertilizer deletes a fertilizer
-func (uc *FertilizerUseCase) DeleteFertilizer(id int64) error {
-	existing, err := uc.fertilizerRepo.FindByID(id)
-	if err != nil {
-		return err
-	}
-	if existing == nil {
-		return ErrFertilizerNotFound
-	}
-	return uc.fertilizerRepo.Delete(id)
+func (uc *FertilizerUseCase) DeleteFertilizer(ctx context.Context, id int64) error {
+	return uc.fertilizerRepo.Delete(ctx, id)
 }
@@ -0,0 +1,133 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/crop"
+	"github.com/cropflow/api/internal/domain/farm"
+	"github.com/cropflow/api/internal/domain/fertilizer"
+	"github.com/cropflow/api/internal/domain/nutrition"
+)
+
+// NutrientBalanceUseCase computes the nutrient inputs of crops and farms
+type NutrientBalanceUseCase struct {
+	cropRepo       crop.Repository
+	fertilizerRepo fertilizer.Repository
+	targets        nutrition.Targets
+	access         farmAccess
+}
+
+// NewNutrientBalanceUseCase creates a new nutrient balance use case
+func NewNutrientBalanceUseCase(
+	cropRepo crop.Repository,
+	farmRepo farm.Repository,
+	fertilizerRepo fertilizer.Repository,
+	targets nutrition.Targets,
+) *NutrientBalanceUseCase {
+	return &NutrientBalanceUseCase{
+		cropRepo:       cropRepo,
+		fertilizerRepo: fertilizerRepo,
+		targets:        targets,
+		access:         farmAccess{farmRepo: farmRepo},
+	}
+}
+
+// GetCropBalance computes the nutrients applied to a crop within the period
+func (uc *NutrientBalanceUseCase) GetCropBalance(ctx context.Context, cropID int64, from, to *time.Time) (*nutrition.Balance, error) {
+	period, err := nutrition.NewPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := uc.access.requireCrop(ctx, uc.cropRepo, cropID, farm.MemberViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	applications, err := uc.cropRepo.FindApplications(ctx, crop.ApplicationFilter{
+		CropID:      cropID,
+		AppliedFrom: period.From(),
+		AppliedTo:   period.To(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fertilizers, err := uc.findFertilizers(ctx, applications)
+	if err != nil {
+		return nil, err
+	}
+	return nutrition.CropBalance(c, applications, fertilizers, uc.targets), nil
+}
+
+// GetFarmBalance computes the nutrients applied to every crop of a farm within
+// the period. Abandoned crops are left out.
+func (uc *NutrientBalanceUseCase) GetFarmBalance(ctx context.Context, farmID int64, from, to *time.Time) (*nutrition.Balance, error) {
+	period, err := nutrition.NewPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.access.requireFarm(ctx, farmID, farm.MemberViewer); err != nil {
+		return nil, err
+	}
+
+	crops, err := uc.cropRepo.FindByFarmID(ctx, farmID)
+	if err != nil {
+		return nil, err
+	}
+
+	applications, err := uc.cropRepo.FindApplications(ctx, crop.ApplicationFilter{
+		FarmID:      farmID,
+		AppliedFrom: period.From(),
+		AppliedTo:   period.To(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fertilizers, err := uc.findFertilizers(ctx, applications)
+	if err != nil {
+		return nil, err
+	}
+
+	byCrop := make(map[int64][]*crop.Application)
+	for _, a := range applications {
+		byCrop[a.CropID()] = append(byCrop[a.CropID()], a)
+	}
+
+	parts := make([]*nutrition.Balance, 0, len(crops))
+	for _, c := range crops {
+		if c.Status() == crop.StatusAbandoned {
+			continue
+		}
+		parts = append(parts, nutrition.CropBalance(c, byCrop[c.ID()], fertilizers, uc.targets))
+	}
+	return nutrition.FarmBalance(parts), nil
+}
+
+func (uc *NutrientBalanceUseCase) findFertilizers(ctx context.Context, applications []*crop.Application) (map[int64]*fertilizer.Fertilizer, error) {
+	seen := make(map[int64]bool)
+	var ids []int64
+	for _, a := range applications {
+		if !seen[a.FertilizerID()] {
+			seen[a.FertilizerID()] = true
+			ids = append(ids, a.FertilizerID())
+		}
+	}
+
+	fertilizers := make(map[int64]*fertilizer.Fertilizer, len(ids))
+	if len(ids) == 0 {
+		return fertilizers, nil
+	}
+
+	found, err := uc.fertilizerRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range found {
+		fertilizers[f.ID()] = f
+	}
+	return fertilizers, nil
+}
@@ -0,0 +1,167 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/oidc"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/transaction"
+)
+
+// oidcLoginTTL is how long a person has to log in at the identity provider
+const oidcLoginTTL = 10 * time.Minute
+
+// OIDCUseCase logs persons in through an external OpenID Connect identity
+// provider, next to local passwords. Persons are linked to their provider
+// account by issuer and subject; the first login creates the person, and every
+// login sets the role mapped from the provider groups.
+type OIDCUseCase struct {
+	provider    oidc.Provider
+	oidcRepo    oidc.Repository
+	personRepo  person.Repository
+	authUseCase *AuthUseCase
+	roles       oidc.RoleMapping
+	passwords   person.PasswordPolicy
+	tx          transaction.Manager
+}
+
+// NewOIDCUseCase creates a new OIDC use case; a nil provider disables OIDC logins
+func NewOIDCUseCase(
+	provider oidc.Provider,
+	oidcRepo oidc.Repository,
+	personRepo person.Repository,
+	authUseCase *AuthUseCase,
+	roles oidc.RoleMapping,
+	passwords person.PasswordPolicy,
+	tx transaction.Manager,
+) *OIDCUseCase {
+	return &OIDCUseCase{
+		provider:    provider,
+		oidcRepo:    oidcRepo,
+		personRepo:  personRepo,
+		authUseCase: authUseCase,
+		roles:       roles,
+		passwords:   passwords,
+		tx:          tx,
+	}
+}
+
+// StartLogin records a pending login and returns the identity provider URL
+// the client has to visit
+func (uc *OIDCUseCase) StartLogin(ctx context.Context) (string, error) {
+	if uc.provider == nil {
+		return "", oidc.ErrNotConfigured
+	}
+
+	request, state, err := oidc.NewLoginRequest(oidcLoginTTL, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if err := uc.oidcRepo.SaveLoginRequest(ctx, request); err != nil {
+		return "", err
+	}
+	return uc.provider.AuthorizationURL(ctx, state, request.Nonce(), request.CodeChallenge())
+}
+
+// CompleteLogin handles the redirect back from the identity provider: it
+// redeems the code, provisions or updates the person and continues the login
+// like a password login would, including the second factor
+func (uc *OIDCUseCase) CompleteLogin(ctx context.Context, state, code, providerError string) (LoginResult, error) {
+	if uc.provider == nil {
+		return LoginResult{}, oidc.ErrNotConfigured
+	}
+
+	request, err := uc.oidcRepo.FindLoginRequest(ctx, oidc.HashState(state))
+	if err != nil {
+		return LoginResult{}, err
+	}
+	// The state is spent whatever the outcome, so a callback cannot be replayed
+	if err := request.Consume(time.Now()); err != nil {
+		return LoginResult{}, err
+	}
+	if err := uc.oidcRepo.SaveLoginRequest(ctx, request); err != nil {
+		return LoginResult{}, err
+	}
+
+	if providerError != "" {
+		return LoginResult{}, fmt.Errorf("%w: %s", oidc.ErrProviderDenied, providerError)
+	}
+	if code == "" {
+		return LoginResult{}, oidc.ErrProviderDenied
+	}
+
+	claims, err := uc.provider.Exchange(ctx, code, request.CodeVerifier(), request.Nonce())
+	if err != nil {
+		return LoginResult{}, err
+	}
+	role, err := uc.roles.Role(claims.Groups)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	p, err := uc.provision(ctx, claims, role)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	return uc.authUseCase.LoginVerified(ctx, p)
+}
+
+// provision finds the person linked to the provider account, keeping their
+// role in line with the provider groups, or creates one on the first login.
+// A new person is saved with their link in one transaction: without the link
+// they could never log in again and would hold the username.
+func (uc *OIDCUseCase) provision(ctx context.Context, claims oidc.Claims, role person.Role) (*person.Person, error) {
+	var p *person.Person
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		link, err := uc.oidcRepo.FindLink(ctx, claims.Issuer, claims.Subject)
+		if err == nil {
+			p, err = uc.personRepo.FindByID(ctx, link.PersonID())
+			if err != nil || p.Role() == role {
+				return err
+			}
+			p, err = uc.personRepo.UpdateRole(ctx, p.ID(), func(p *person.Person, admins int) error {
+				return changeRole(p, role, 0, admins)
+			})
+			return err
+		}
+		if !errors.Is(err, oidc.ErrLinkNotFound) {
+			return err
+		}
+
+		if claims.Username == "" {
+			return oidc.ErrMissingUsername
+		}
+		// Existing local accounts are never taken over by a provider account with the same name
+		exists, err := uc.personRepo.UsernameExists(ctx, claims.Username)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return oidc.ErrUsernameTaken
+		}
+
+		p, err = person.NewExternalPerson(claims.Username, role.String(), uc.passwords)
+		if err != nil {
+			return err
+		}
+		if err := uc.personRepo.Save(ctx, p); err != nil {
+			if errors.Is(err, person.ErrUsernameAlreadyExists) {
+				return oidc.ErrUsernameTaken
+			}
+			return err
+		}
+
+		link, err = oidc.NewLink(p.ID(), claims.Issuer, claims.Subject, time.Now())
+		if err != nil {
+			return err
+		}
+		return uc.oidcRepo.SaveLink(ctx, link)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
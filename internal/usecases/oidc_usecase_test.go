@@ -0,0 +1,160 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cropflow/api/internal/adapters/database/memory"
+	"github.com/cropflow/api/internal/domain/mfa"
+	"github.com/cropflow/api/internal/domain/oidc"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const oidcIssuer = "https://idp.example.com"
+
+// fakeProvider hands out the claims it holds for any code and remembers the
+// state of the last login it was asked to start
+type fakeProvider struct {
+	claims oidc.Claims
+	state  string
+}
+
+func (p *fakeProvider) AuthorizationURL(_ context.Context, state, _, _ string) (string, error) {
+	p.state = state
+	return oidcIssuer + "/authorize?state=" + state, nil
+}
+
+func (p *fakeProvider) Exchange(context.Context, string, string, string) (oidc.Claims, error) {
+	return p.claims, nil
+}
+
+func TestOIDCUseCase_CompleteLogin(t *testing.T) {
+	tests := []struct {
+		name          string
+		local         bool
+		groups        []string
+		providerError string
+		expected      error
+		role          person.Role
+	}{
+		{"should create the person on the first login", false, nil, "", nil, person.RoleUser},
+		{"should give the person the role mapped from their groups", false, []string{"farm-managers"}, "", nil, person.RoleManager},
+		{"should not take over a local account with the same username", true, nil, "", oidc.ErrUsernameTaken, ""},
+		{"should report an error sent back by the provider", false, nil, "access_denied", oidc.ErrProviderDenied, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := memory.NewStore()
+			provider := &fakeProvider{claims: oidc.Claims{Issuer: oidcIssuer, Subject: "248289761001", Username: "john_doe", Groups: tt.groups}}
+			uc := newOIDCUseCase(t, store, provider, mfa.Settings{})
+			ctx := context.Background()
+			if tt.local {
+				savePassword(t, store, "john_doe", "SecurePass123!", person.RoleAdmin)
+			}
+			_, err := uc.StartLogin(ctx)
+			require.NoError(t, err)
+
+			// Act
+			result, err := uc.CompleteLogin(ctx, provider.state, "code", tt.providerError)
+
+			// Assert
+			_, linkErr := memory.NewOIDCRepository(store).FindLink(ctx, oidcIssuer, "248289761001")
+			require.ErrorIs(t, err, tt.expected)
+			if tt.expected != nil {
+				assert.ErrorIs(t, linkErr, oidc.ErrLinkNotFound)
+				return
+			}
+			require.NoError(t, linkErr)
+			assert.NotEmpty(t, result.Tokens.AccessToken)
+			p, err := memory.NewPersonRepository(store).FindByUsername(ctx, "john_doe")
+			require.NoError(t, err)
+			assert.Equal(t, tt.role, p.Role())
+		})
+	}
+
+	t.Run("should keep the role of a linked person in line with their groups", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		provider := &fakeProvider{claims: oidc.Claims{Issuer: oidcIssuer, Subject: "248289761001", Username: "john_doe"}}
+		uc := newOIDCUseCase(t, store, provider, mfa.Settings{})
+		ctx := context.Background()
+		_, err := uc.StartLogin(ctx)
+		require.NoError(t, err)
+		_, err = uc.CompleteLogin(ctx, provider.state, "code", "")
+		require.NoError(t, err)
+		provider.claims.Groups = []string{"farm-managers"}
+		provider.claims.Username = "renamed"
+		_, err = uc.StartLogin(ctx)
+		require.NoError(t, err)
+
+		// Act
+		_, err = uc.CompleteLogin(ctx, provider.state, "code", "")
+
+		// Assert
+		require.NoError(t, err)
+		p, err := memory.NewPersonRepository(store).FindByUsername(ctx, "john_doe")
+		require.NoError(t, err)
+		assert.Equal(t, person.RoleManager, p.Role())
+	})
+
+	t.Run("should not accept the same state twice", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		provider := &fakeProvider{claims: oidc.Claims{Issuer: oidcIssuer, Subject: "248289761001", Username: "john_doe"}}
+		uc := newOIDCUseCase(t, store, provider, mfa.Settings{})
+		ctx := context.Background()
+		_, err := uc.StartLogin(ctx)
+		require.NoError(t, err)
+		_, err = uc.CompleteLogin(ctx, provider.state, "code", "")
+		require.NoError(t, err)
+
+		// Act
+		_, err = uc.CompleteLogin(ctx, provider.state, "code", "")
+
+		// Assert
+		assert.ErrorIs(t, err, oidc.ErrLoginRequestConsumed)
+	})
+
+	t.Run("should ask for a second factor like a password login", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		required, err := mfa.NewSettings("CropFlow", []string{person.RoleManager.String()})
+		require.NoError(t, err)
+		provider := &fakeProvider{claims: oidc.Claims{Issuer: oidcIssuer, Subject: "248289761001", Username: "john_doe", Groups: []string{"farm-managers"}}}
+		uc := newOIDCUseCase(t, store, provider, required)
+		ctx := context.Background()
+		_, err = uc.StartLogin(ctx)
+		require.NoError(t, err)
+
+		// Act
+		result, err := uc.CompleteLogin(ctx, provider.state, "code", "")
+
+		// Assert
+		require.NoError(t, err)
+		require.NotNil(t, result.Challenge)
+		assert.Equal(t, mfa.ChallengeEnroll, result.Challenge.Kind)
+		assert.Empty(t, result.Tokens.AccessToken)
+	})
+}
+
+// newOIDCUseCase wires an OIDCUseCase to the store and the provider, mapping
+// the farm-managers group to ROLE_MANAGER and everybody else to ROLE_USER
+func newOIDCUseCase(t *testing.T, store *memory.Store, provider oidc.Provider, twoFactor mfa.Settings) *usecases.OIDCUseCase {
+	t.Helper()
+	roles, err := oidc.NewRoleMapping(map[string]string{"farm-managers": person.RoleManager.String()}, person.RoleUser.String())
+	require.NoError(t, err)
+	return usecases.NewOIDCUseCase(
+		provider,
+		memory.NewOIDCRepository(store),
+		memory.NewPersonRepository(store),
+		newAuthUseCase(t, store, twoFactor),
+		roles,
+		person.DefaultPasswordPolicy(),
+		memory.NewTransactionManager(store),
+	)
+}
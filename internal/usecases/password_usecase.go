@@ -0,0 +1,130 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/passwordreset"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/session"
+	"github.com/cropflow/api/internal/domain/transaction"
+)
+
+// PasswordUseCase handles password changes and administrator initiated resets.
+// Both end every session of the person, so a leaked token stops working.
+type PasswordUseCase struct {
+	personRepo  person.Repository
+	sessionRepo session.Repository
+	resetRepo   passwordreset.Repository
+	notifier    passwordreset.Notifier
+	resetTTL    time.Duration
+	passwords   person.PasswordPolicy
+	tx          transaction.Manager
+}
+
+// NewPasswordUseCase creates a new password use case
+func NewPasswordUseCase(personRepo person.Repository, sessionRepo session.Repository, resetRepo passwordreset.Repository, notifier passwordreset.Notifier, resetTTL time.Duration, passwords person.PasswordPolicy, tx transaction.Manager) *PasswordUseCase {
+	return &PasswordUseCase{
+		personRepo:  personRepo,
+		sessionRepo: sessionRepo,
+		resetRepo:   resetRepo,
+		notifier:    notifier,
+		resetTTL:    resetTTL,
+		passwords:   passwords,
+		tx:          tx,
+	}
+}
+
+// ChangePassword changes the password of the caller after checking the current one
+func (uc *PasswordUseCase) ChangePassword(ctx context.Context, currentPassword, newPassword string) error {
+	caller, ok := identity.FromContext(ctx)
+	if !ok {
+		return identity.ErrUnauthenticated
+	}
+
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := uc.personRepo.Update(ctx, caller.PersonID(), func(p *person.Person) error {
+			return p.ChangePassword(currentPassword, newPassword, uc.passwords)
+		})
+		if err != nil {
+			return err
+		}
+		return uc.endSessions(ctx, caller.PersonID(), "password changed")
+	})
+}
+
+// RequestReset issues a one-time reset token for a person and delivers it
+// through the notifier. Tokens issued before stop working.
+func (uc *PasswordUseCase) RequestReset(ctx context.Context, personID int64) (time.Time, error) {
+	var p *person.Person
+	var token *passwordreset.Token
+	var plain string
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if p, err = uc.personRepo.FindByID(ctx, personID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := uc.resetRepo.InvalidateAllByPersonID(ctx, p.ID(), now); err != nil {
+			return err
+		}
+		if token, plain, err = passwordreset.NewToken(p.ID(), uc.resetTTL, now); err != nil {
+			return err
+		}
+		return uc.resetRepo.Save(ctx, token)
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// The token is delivered once stored, outside the transaction
+	recipient := passwordreset.Recipient{PersonID: p.ID(), Username: p.Username()}
+	if err := uc.notifier.SendResetToken(ctx, recipient, plain, token.ExpiresAt()); err != nil {
+		return time.Time{}, err
+	}
+	return token.ExpiresAt(), nil
+}
+
+// ResetPassword sets a new password with a reset token, which is spent
+func (uc *PasswordUseCase) ResetPassword(ctx context.Context, plainToken, newPassword string) error {
+	// The token is spent only together with the password change, so a failure
+	// in between cannot spend it and keep the old password
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		token, err := uc.resetRepo.FindByHash(ctx, passwordreset.HashToken(plainToken))
+		if err != nil {
+			return err
+		}
+
+		// A rejected password must not spend the token
+		if err := uc.passwords.Validate(newPassword); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := token.Redeem(now); err != nil {
+			return err
+		}
+		if err := uc.resetRepo.Save(ctx, token); err != nil {
+			return err
+		}
+
+		_, err = uc.personRepo.Update(ctx, token.PersonID(), func(p *person.Person) error {
+			return p.ResetPassword(newPassword, uc.passwords)
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := uc.resetRepo.InvalidateAllByPersonID(ctx, token.PersonID(), now); err != nil {
+			return err
+		}
+		return uc.endSessions(ctx, token.PersonID(), "password reset")
+	})
+}
+
+func (uc *PasswordUseCase) endSessions(ctx context.Context, personID int64, reason string) error {
+	_, err := uc.sessionRepo.RevokeAllByPersonID(ctx, personID, reason, time.Now())
+	return err
+}
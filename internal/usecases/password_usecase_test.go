@@ -0,0 +1,145 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cropflow/api/internal/adapters/database/memory"
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/mfa"
+	"github.com/cropflow/api/internal/domain/passwordreset"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/session"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenNotifier keeps the last reset token instead of delivering it
+type tokenNotifier struct {
+	token string
+}
+
+func (n *tokenNotifier) SendResetToken(_ context.Context, _ passwordreset.Recipient, token string, _ time.Time) error {
+	n.token = token
+	return nil
+}
+
+func TestPasswordUseCase_ChangePassword(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   string
+		next      string
+		expected  error
+		loginWith string
+	}{
+		{"should change the password and end every session", "SecurePass123!", "NewSecure456!", nil, "NewSecure456!"},
+		{"should reject a wrong current password", "WrongPass123!", "NewSecure456!", person.ErrInvalidCredentials, "SecurePass123!"},
+		{"should reject the same password", "SecurePass123!", "SecurePass123!", person.ErrSamePassword, "SecurePass123!"},
+		{"should reject a password the policy refuses", "SecurePass123!", "short", person.ErrInvalidPassword, "SecurePass123!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := memory.NewStore()
+			uc := newPasswordUseCase(store, &tokenNotifier{})
+			auth := newAuthUseCase(t, store, mfa.Settings{})
+			p := savePassword(t, store, "john_doe", "SecurePass123!", person.RoleUser)
+			result, err := auth.Login(context.Background(), "john_doe", "SecurePass123!", clientIP)
+			require.NoError(t, err)
+			ctx := identity.NewContext(context.Background(), identity.New(p.ID(), 0, p.Username(), p.Role()))
+
+			// Act
+			err = uc.ChangePassword(ctx, tt.current, tt.next)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.expected)
+			_, err = auth.Login(context.Background(), "john_doe", tt.loginWith, clientIP)
+			assert.NoError(t, err)
+			_, err = auth.Refresh(context.Background(), result.Tokens.RefreshToken)
+			if tt.expected == nil {
+				assert.ErrorIs(t, err, session.ErrSessionRevoked)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPasswordUseCase_ResetPassword(t *testing.T) {
+	t.Run("should set the new password with the token delivered to the person", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		notifier := &tokenNotifier{}
+		uc := newPasswordUseCase(store, notifier)
+		auth := newAuthUseCase(t, store, mfa.Settings{})
+		ctx := context.Background()
+		p := savePassword(t, store, "john_doe", "SecurePass123!", person.RoleUser)
+		_, err := uc.RequestReset(ctx, p.ID())
+		require.NoError(t, err)
+
+		// Act
+		err = uc.ResetPassword(ctx, notifier.token, "NewSecure456!")
+
+		// Assert
+		require.NoError(t, err)
+		_, err = auth.Login(ctx, "john_doe", "NewSecure456!", clientIP)
+		assert.NoError(t, err)
+		err = uc.ResetPassword(ctx, notifier.token, "Another789!x")
+		assert.ErrorIs(t, err, passwordreset.ErrResetTokenUsed)
+	})
+
+	t.Run("should not spend the token on a password the policy refuses", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		notifier := &tokenNotifier{}
+		uc := newPasswordUseCase(store, notifier)
+		ctx := context.Background()
+		p := savePassword(t, store, "john_doe", "SecurePass123!", person.RoleUser)
+		_, err := uc.RequestReset(ctx, p.ID())
+		require.NoError(t, err)
+
+		// Act
+		err = uc.ResetPassword(ctx, notifier.token, "short")
+
+		// Assert
+		assert.ErrorIs(t, err, person.ErrInvalidPassword)
+		assert.NoError(t, uc.ResetPassword(ctx, notifier.token, "NewSecure456!"))
+	})
+
+	t.Run("should invalidate the tokens issued before", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		notifier := &tokenNotifier{}
+		uc := newPasswordUseCase(store, notifier)
+		ctx := context.Background()
+		p := savePassword(t, store, "john_doe", "SecurePass123!", person.RoleUser)
+		_, err := uc.RequestReset(ctx, p.ID())
+		require.NoError(t, err)
+		first := notifier.token
+		_, err = uc.RequestReset(ctx, p.ID())
+		require.NoError(t, err)
+
+		// Act
+		err = uc.ResetPassword(ctx, first, "NewSecure456!")
+
+		// Assert
+		assert.ErrorIs(t, err, passwordreset.ErrResetTokenUsed)
+		assert.NoError(t, uc.ResetPassword(ctx, notifier.token, "NewSecure456!"))
+	})
+}
+
+// newPasswordUseCase wires a PasswordUseCase to the store, handing reset tokens to the notifier
+func newPasswordUseCase(store *memory.Store, notifier passwordreset.Notifier) *usecases.PasswordUseCase {
+	return usecases.NewPasswordUseCase(
+		memory.NewPersonRepository(store),
+		memory.NewSessionRepository(store),
+		memory.NewPasswordResetRepository(store),
+		notifier,
+		time.Hour,
+		person.DefaultPasswordPolicy(),
+		memory.NewTransactionManager(store),
+	)
+}
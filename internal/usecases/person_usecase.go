@@ -1,115 +1,160 @@
 package usecases
 
 import (
-	"errors"
+	"context"
 
-	"github.com/cropflow/api/internal/domain/entities"
-	"github.com/cropflow/api/internal/domain/repositories"
-	"github.com/cropflow/api/internal/infrastructure/security"
-)
-
-var (
-	ErrPersonNotFound      = errors.New("person not found")
-	ErrUsernameAlreadyUsed = errors.New("username already in use")
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/query"
+	"github.com/cropflow/api/internal/domain/transaction"
 )
 
 // PersonUseCase handles person business logic
 type PersonUseCase struct {
-	personRepo      repositories.PersonRepository
-	passwordService *security.PasswordService
+	personRepo person.Repository
+	passwords  person.PasswordPolicy
+	tx         transaction.Manager
 }
 
 // NewPersonUseCase creates a new person use case
-func NewPersonUseCase(
-	personRepo repositories.PersonRepository,
-	passwordService *security.PasswordService,
-) *PersonUseCase {
+func NewPersonUseCase(personRepo person.Repository, passwords person.PasswordPolicy, tx transaction.Manager) *PersonUseCase {
 	return &PersonUseCase{
-		personRepo:      personRepo,
-		passwordService: passwordService,
+		personRepo: personRepo,
+		passwords:  passwords,
+		tx:         tx,
 	}
 }
 
-// CreatePerson creates a new person
-func (uc *PersonUseCase) CreatePerson(person *entities.Person) error {
-	// Check if username already exists
-	existing, err := uc.personRepo.FindByUsername(person.Username)
+// CreatePerson registers a new person. Everyone starts as ROLE_USER; only an
+// admin can grant a higher role, through ChangeRole.
+func (uc *PersonUseCase) CreatePerson(ctx context.Context, username, password string) (*person.Person, error) {
+	p, err := person.NewPerson(username, password, person.RoleUser.String(), uc.passwords)
 	if err != nil {
-		return err
-	}
-	if existing != nil {
-		return ErrUsernameAlreadyUsed
+		return nil, err
 	}
 
-	// Hash password
-	hashedPassword, err := uc.passwordService.HashPassword(person.Password)
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Check if username already exists
+		if err := uc.ensureUsernameAvailable(ctx, username); err != nil {
+			return err
+		}
+		return uc.personRepo.Save(ctx, p)
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	person.Password = hashedPassword
-
-	return uc.personRepo.Create(person)
+	return p, nil
 }
 
-// GetAllPersons retrieves all persons
-func (uc *PersonUseCase) GetAllPersons() ([]entities.Person, error) {
-	return uc.personRepo.FindAll()
+// ListPersons retrieves one page of persons matching the filter
+func (uc *PersonUseCase) ListPersons(ctx context.Context, filter person.Filter, page query.Page) (query.Result[*person.Person], error) {
+	return uc.personRepo.List(ctx, filter, page)
 }
 
 // GetPersonByID retrieves a person by ID
-func (uc *PersonUseCase) GetPersonByID(id int64) (*entities.Person, error) {
-	person, err := uc.personRepo.FindByID(id)
-	if err != nil {
-		return nil, err
-	}
-	if person == nil {
-		return nil, ErrPersonNotFound
-	}
-	return person, nil
+func (uc *PersonUseCase) GetPersonByID(ctx context.Context, id int64) (*person.Person, error) {
+	return uc.personRepo.FindByID(ctx, id)
 }
 
 // GetPersonByUsername retrieves a person by username
-func (uc *PersonUseCase) GetPersonByUsername(username string) (*entities.Person, error) {
-	person, err := uc.personRepo.FindByUsername(username)
+func (uc *PersonUseCase) GetPersonByUsername(ctx context.Context, username string) (*person.Person, error) {
+	return uc.personRepo.FindByUsername(ctx, username)
+}
+
+// UpdatePerson updates a person's username. The person is locked while it
+// changes, so a concurrent role, password or lockout change is not undone;
+// a username taken by someone else is rejected by the repository.
+func (uc *PersonUseCase) UpdatePerson(ctx context.Context, id int64, username string) (*person.Person, error) {
+	return uc.personRepo.Update(ctx, id, func(p *person.Person) error {
+		if username == p.Username() {
+			return nil
+		}
+		return p.ChangeUsername(username)
+	})
+}
+
+// ChangeRole promotes or demotes a person, recording the caller as the one who
+// did it. Setting the role the person already has changes nothing.
+func (uc *PersonUseCase) ChangeRole(ctx context.Context, id int64, roleStr string) (*person.Person, error) {
+	caller, ok := identity.FromContext(ctx)
+	if !ok {
+		return nil, identity.ErrUnauthenticated
+	}
+	role, err := person.NewRole(roleStr)
 	if err != nil {
 		return nil, err
 	}
-	if person == nil {
-		return nil, ErrPersonNotFound
-	}
-	return person, nil
+
+	return uc.personRepo.UpdateRole(ctx, id, func(p *person.Person, admins int) error {
+		return changeRole(p, role, caller.PersonID(), admins)
+	})
 }
 
-// UpdatePerson updates a person
-func (uc *PersonUseCase) UpdatePerson(person *entities.Person) error {
-	existing, err := uc.personRepo.FindByID(person.ID)
-	if err != nil {
-		return err
-	}
-	if existing == nil {
-		return ErrPersonNotFound
+// GetRoleChanges retrieves the role history of a person in the order it was recorded
+func (uc *PersonUseCase) GetRoleChanges(ctx context.Context, id int64) ([]person.RoleChange, error) {
+	if _, err := uc.personRepo.FindByID(ctx, id); err != nil {
+		return nil, err
 	}
+	return uc.personRepo.FindRoleChanges(ctx, id)
+}
 
-	// If password is being updated, hash it
-	if person.Password != "" && person.Password != existing.Password {
-		hashedPassword, err := uc.passwordService.HashPassword(person.Password)
+// BootstrapAdmin promotes the named person to ROLE_ADMIN while there is no
+// admin at all, so a new installation can be administered. It reports whether
+// the person was promoted.
+func (uc *PersonUseCase) BootstrapAdmin(ctx context.Context, username string) (bool, error) {
+	promoted := false
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := uc.personRepo.FindByUsername(ctx, username)
 		if err != nil {
 			return err
 		}
-		person.Password = hashedPassword
-	}
 
-	return uc.personRepo.Update(person)
+		_, err = uc.personRepo.UpdateRole(ctx, p.ID(), func(p *person.Person, admins int) error {
+			if admins > 0 {
+				return nil
+			}
+			promoted = true
+			return p.PromoteToRole(person.RoleAdmin.String(), 0)
+		})
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return promoted, nil
 }
 
 // DeletePerson deletes a person
-func (uc *PersonUseCase) DeletePerson(id int64) error {
-	existing, err := uc.personRepo.FindByID(id)
+func (uc *PersonUseCase) DeletePerson(ctx context.Context, id int64) error {
+	return uc.personRepo.Delete(ctx, id)
+}
+
+// UnlockPerson clears the failed logins of a person so it can log in again right away
+func (uc *PersonUseCase) UnlockPerson(ctx context.Context, id int64) (*person.Person, error) {
+	return uc.personRepo.Update(ctx, id, func(p *person.Person) error {
+		p.Unlock()
+		return nil
+	})
+}
+
+func (uc *PersonUseCase) ensureUsernameAvailable(ctx context.Context, username string) error {
+	exists, err := uc.personRepo.UsernameExists(ctx, username)
 	if err != nil {
 		return err
 	}
-	if existing == nil {
-		return ErrPersonNotFound
+	if exists {
+		return person.ErrUsernameAlreadyExists
+	}
+	return nil
+}
+
+// changeRole sets the role unless it is already set, refusing to demote the last admin
+func changeRole(p *person.Person, role person.Role, changedBy int64, admins int) error {
+	if p.Role() == role {
+		return nil
+	}
+	if p.Role().IsAdmin() && admins <= 1 {
+		return person.ErrLastAdmin
 	}
-	return uc.personRepo.Delete(id)
+	return p.PromoteToRole(role.String(), changedBy)
 }
@@ -0,0 +1,57 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cropflow/api/internal/adapters/database/memory"
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/mfa"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonUseCase_UpdatePerson(t *testing.T) {
+	t.Run("should rename a person without touching their role or password", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := newPersonUseCase(store)
+		auth := newAuthUseCase(t, store, mfa.Settings{})
+		adminCtx := callerContext(t, store, "root_admin", person.RoleAdmin)
+		p := savePassword(t, store, "john_doe", "SecurePass123!", person.RoleUser)
+		_, err := uc.ChangeRole(adminCtx, p.ID(), person.RoleManager.String())
+		require.NoError(t, err)
+
+		// Act
+		updated, err := uc.UpdatePerson(adminCtx, p.ID(), "johnny_doe")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "johnny_doe", updated.Username())
+		assert.Equal(t, person.RoleManager, updated.Role())
+		_, err = auth.Login(context.Background(), "johnny_doe", "SecurePass123!", clientIP)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject a username taken by another person", func(t *testing.T) {
+		// Arrange
+		store := memory.NewStore()
+		uc := newPersonUseCase(store)
+		ctx := callerContext(t, store, "root_admin", person.RoleAdmin)
+		caller, _ := identity.FromContext(ctx)
+		savePassword(t, store, "john_doe", "SecurePass123!", person.RoleUser)
+
+		// Act
+		_, err := uc.UpdatePerson(ctx, caller.PersonID(), "john_doe")
+
+		// Assert
+		assert.ErrorIs(t, err, person.ErrUsernameAlreadyExists)
+	})
+}
+
+// newPersonUseCase wires a PersonUseCase to the store
+func newPersonUseCase(store *memory.Store) *usecases.PersonUseCase {
+	return usecases.NewPersonUseCase(memory.NewPersonRepository(store), person.DefaultPasswordPolicy(), memory.NewTransactionManager(store))
+}
@@ -0,0 +1,159 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cropflow/api/internal/domain/identity"
+	"github.com/cropflow/api/internal/domain/mfa"
+	"github.com/cropflow/api/internal/domain/person"
+	"github.com/cropflow/api/internal/domain/transaction"
+)
+
+// TOTPProvisioning holds what an authenticator app needs to generate codes
+type TOTPProvisioning struct {
+	Secret string
+	URI    string
+}
+
+// TwoFactorUseCase handles the TOTP enrolment of the caller
+type TwoFactorUseCase struct {
+	personRepo person.Repository
+	mfaRepo    mfa.Repository
+	twoFactor  mfa.Settings
+	tx         transaction.Manager
+}
+
+// NewTwoFactorUseCase creates a new two-factor use case
+func NewTwoFactorUseCase(personRepo person.Repository, mfaRepo mfa.Repository, twoFactor mfa.Settings, tx transaction.Manager) *TwoFactorUseCase {
+	return &TwoFactorUseCase{
+		personRepo: personRepo,
+		mfaRepo:    mfaRepo,
+		twoFactor:  twoFactor,
+		tx:         tx,
+	}
+}
+
+// Enroll starts the TOTP enrolment of the caller; it takes effect once confirmed
+func (uc *TwoFactorUseCase) Enroll(ctx context.Context) (TOTPProvisioning, error) {
+	p, err := uc.caller(ctx)
+	if err != nil {
+		return TOTPProvisioning{}, err
+	}
+	return enroll(ctx, uc.tx, uc.mfaRepo, uc.twoFactor, p)
+}
+
+// Confirm activates the enrolment of the caller with a code from the
+// authenticator app and returns the recovery codes
+func (uc *TwoFactorUseCase) Confirm(ctx context.Context, code string) ([]string, error) {
+	var recoveryCodes []string
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		enrollment, err := uc.enrollment(ctx)
+		if err != nil {
+			return err
+		}
+
+		if recoveryCodes, err = enrollment.Confirm(code, time.Now()); err != nil {
+			return err
+		}
+		return uc.mfaRepo.SaveEnrollment(ctx, enrollment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the caller after checking a code
+func (uc *TwoFactorUseCase) RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error) {
+	var recoveryCodes []string
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		enrollment, err := uc.enrollment(ctx)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := enrollment.Verify(code, now); err != nil {
+			return err
+		}
+		if recoveryCodes, err = enrollment.RegenerateRecoveryCodes(now); err != nil {
+			return err
+		}
+		return uc.mfaRepo.SaveEnrollment(ctx, enrollment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// Disable removes the second factor of the caller after checking a code.
+// Roles that require two-factor authentication cannot disable it.
+func (uc *TwoFactorUseCase) Disable(ctx context.Context, code string) error {
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := uc.caller(ctx)
+		if err != nil {
+			return err
+		}
+		if uc.twoFactor.IsRequired(p.Role()) {
+			return mfa.ErrTOTPRequired
+		}
+
+		enrollment, err := uc.mfaRepo.FindEnrollmentByPersonID(ctx, p.ID())
+		if err != nil {
+			return err
+		}
+		if enrollment.IsConfirmed() {
+			if err := enrollment.Verify(code, time.Now()); err != nil {
+				return err
+			}
+		}
+		return uc.mfaRepo.DeleteEnrollment(ctx, p.ID())
+	})
+}
+
+func (uc *TwoFactorUseCase) caller(ctx context.Context) (*person.Person, error) {
+	id, ok := identity.FromContext(ctx)
+	if !ok {
+		return nil, identity.ErrUnauthenticated
+	}
+	return uc.personRepo.FindByID(ctx, id.PersonID())
+}
+
+func (uc *TwoFactorUseCase) enrollment(ctx context.Context) (*mfa.Enrollment, error) {
+	id, ok := identity.FromContext(ctx)
+	if !ok {
+		return nil, identity.ErrUnauthenticated
+	}
+	return uc.mfaRepo.FindEnrollmentByPersonID(ctx, id.PersonID())
+}
+
+// enroll creates an unconfirmed enrolment for a person, replacing the secret
+// of a previous unconfirmed one
+func enroll(ctx context.Context, tx transaction.Manager, mfaRepo mfa.Repository, twoFactor mfa.Settings, p *person.Person) (TOTPProvisioning, error) {
+	var enrollment *mfa.Enrollment
+	err := tx.WithinTx(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		var err error
+		enrollment, err = mfaRepo.FindEnrollmentByPersonID(ctx, p.ID())
+		switch {
+		case errors.Is(err, mfa.ErrEnrollmentNotFound):
+			enrollment, err = mfa.NewEnrollment(p.ID(), now)
+		case err == nil:
+			err = enrollment.ResetSecret(now)
+		}
+		if err != nil {
+			return err
+		}
+		return mfaRepo.SaveEnrollment(ctx, enrollment)
+	})
+	if err != nil {
+		return TOTPProvisioning{}, err
+	}
+	return TOTPProvisioning{
+		Secret: enrollment.Secret(),
+		URI:    enrollment.ProvisioningURI(twoFactor.Issuer, p.Username()),
+	}, nil
+}